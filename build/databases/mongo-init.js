@@ -7,16 +7,30 @@ if (!dbName || !username || !password) {
     process.exit(1);
 }
 
-db = db.getSiblingDB(dbName);
-db.createUser({
-    'user': username,
-    'pwd': password,
-    'roles': [
-        {
-            'role': 'readWrite',
-            'db': dbName
-        }
-    ]
-});
+function createUser(dbName, username, password) {
+    db.getSiblingDB(dbName).createUser({
+        'user': username,
+        'pwd': password,
+        'roles': [
+            {
+                'role': 'readWrite',
+                'db': dbName
+            }
+        ]
+    });
+}
+
+createUser(dbName, username, password);
+console.log('MongoDB user created');
 
-console.log('MongoDB user created');
\ No newline at end of file
+// notifications service keeps scheduled notifications in its own database
+const notificationsDbName = process.env.NOTIFICATIONS_MONGO_DATABASE;
+const notificationsUsername = process.env.NOTIFICATIONS_MONGO_USER;
+const notificationsPassword = process.env.NOTIFICATIONS_MONGO_PASSWORD;
+
+if (notificationsDbName && notificationsUsername && notificationsPassword) {
+    createUser(notificationsDbName, notificationsUsername, notificationsPassword);
+    console.log('MongoDB notifications user created');
+} else {
+    console.warn('NOTIFICATIONS_MONGO_* variables are not set, notifications user is not created');
+}
@@ -44,6 +44,8 @@ func main() {
 	validator := validator.NewValidationProvider()
 	credentialRepo := memory.NewCredentialStorage()
 	sessionRepo := memory.NewSessionStorage()
+	grantRepo := memory.NewGrantStorage()
+	profileRepo := memory.NewProfileStorage()
 	hasher := password.NewPasswordHasherProvider()
 
 	app := &application.AuthApplication{
@@ -64,9 +66,67 @@ func main() {
 		Registration: application.NewRegistrationService(
 			credentialRepo,
 			sessionRepo,
+			grantRepo,
 			validator,
 			hasher,
 		),
+		InviteCaregiver: application.NewInviteCaregiverService(
+			grantRepo,
+			credentialRepo,
+			validator,
+		),
+		AcceptCare: application.NewAcceptCareService(
+			grantRepo,
+			validator,
+		),
+		RevokeCare: application.NewRevokeCareService(
+			grantRepo,
+			validator,
+		),
+		ListCare: application.NewListCareService(
+			grantRepo,
+			credentialRepo,
+			validator,
+		),
+		CheckCareAccess: application.NewCheckCareAccessService(
+			grantRepo,
+			validator,
+		),
+		ListCaregivers: application.NewListCaregiversService(
+			grantRepo,
+			profileRepo,
+			credentialRepo,
+			validator,
+		),
+		CreateProfile: application.NewCreateProfileService(
+			profileRepo,
+			validator,
+		),
+		RenameProfile: application.NewRenameProfileService(
+			profileRepo,
+			validator,
+		),
+		DeleteProfile: application.NewDeleteProfileService(
+			profileRepo,
+			validator,
+		),
+		ListProfiles: application.NewListProfilesService(
+			profileRepo,
+			validator,
+		),
+		CheckProfileAccess: application.NewCheckProfileAccessService(
+			profileRepo,
+			grantRepo,
+			validator,
+		),
+		GetProfile: application.NewGetProfileService(
+			profileRepo,
+			validator,
+		),
+		GetContact: application.NewGetContactService(
+			credentialRepo,
+			validator,
+		),
 	}
 
 	handlers := http.NewAuthHandlers(
@@ -76,7 +136,11 @@ func main() {
 
 	router := http.NewRouter(handlers)
 
-	server := http.NewServerHTTP(conf.Server, router, logger)
+	server := http.NewServerHTTP(conf.Server, router.GetRouter(), logger)
+
+	// internal router, it is not published outside the private network
+	internalRouter := http.NewInternalRouter(handlers)
+	internalServer := http.NewServerHTTP(conf.Internal, internalRouter.GetRouter(), logger)
 
 	var wg sync.WaitGroup
 
@@ -95,6 +159,21 @@ func main() {
 		if err != nil {
 			logger.Errorf("server shutdown: %v", err)
 		}
+
+		err = internalServer.Shutdown()
+		if err != nil {
+			logger.Errorf("internal server shutdown: %v", err)
+		}
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		err := internalServer.ListenAndServe()
+		if err != nil {
+			logger.Fatal(err)
+		}
 	}()
 
 	err = server.ListenAndServe()
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"expvar"
 	"os"
 	"os/signal"
 	"sync"
@@ -14,6 +15,7 @@ import (
 	"github.com/FSO-VK/final-project-vk-backend/internal/medication/infrastructure/datamatrix"
 	instructionAssistant "github.com/FSO-VK/final-project-vk-backend/internal/medication/infrastructure/llm_chat_bot"
 	notifyProvider "github.com/FSO-VK/final-project-vk-backend/internal/medication/infrastructure/notification"
+	planClient "github.com/FSO-VK/final-project-vk-backend/internal/medication/infrastructure/planning_client"
 	"github.com/FSO-VK/final-project-vk-backend/internal/medication/infrastructure/storage/memory"
 	"github.com/FSO-VK/final-project-vk-backend/internal/medication/infrastructure/vidal"
 	"github.com/FSO-VK/final-project-vk-backend/internal/medication/infrastructure/vidal/client"
@@ -26,12 +28,14 @@ import (
 	"github.com/FSO-VK/final-project-vk-backend/internal/utils/validator"
 	auth "github.com/FSO-VK/final-project-vk-backend/pkg/auth/client"
 	"github.com/FSO-VK/final-project-vk-backend/pkg/llm/gigachat"
+	"github.com/FSO-VK/final-project-vk-backend/pkg/workerpool"
 	"github.com/sirupsen/logrus"
 )
 
 const (
 	notificationsInterval = 24 * time.Hour
 	timeDelta             = 7 * 24 * time.Hour
+	forecastHorizon       = 90 * 24 * time.Hour
 )
 
 func main() {
@@ -75,6 +79,8 @@ func main() {
 		vidalClient,
 	)
 	medicationBoxRepo := memory.NewMedicationBoxStorage()
+	stockOperationRepo := memory.NewStockOperationStorage()
+	planningClient := planClient.NewPlanningClient(conf.Planning, logger)
 	instructionLLMProvider := gigachat.NewGigachatLLMProvider(conf.Gigachat)
 	instructionLLM := instructionAssistant.NewLLMChatBot(
 		instructionLLMProvider,
@@ -86,6 +92,8 @@ func main() {
 			medicationRepo, medicationBoxRepo, validator),
 		GetMedicationByID: application.NewGetMedicationByIDService(
 			medicationRepo, medicationBoxRepo, validator),
+		GetMedicationNames: application.NewGetMedicationNamesService(
+			medicationRepo, medicationBoxRepo, validator),
 		AddMedication: application.NewAddMedicationService(
 			medicationRepo, medicationBoxRepo, validator),
 		UpdateMedication: application.NewUpdateMedicationService(
@@ -115,13 +123,26 @@ func main() {
 			medicationBoxRepo,
 			validator,
 		),
+		ChangeStock: application.NewChangeStockService(
+			medicationRepo,
+			medicationBoxRepo,
+			stockOperationRepo,
+			validator,
+		),
+		GetForecast: application.NewGetForecastService(
+			medicationRepo,
+			medicationBoxRepo,
+			planningClient,
+			validator,
+			forecastHorizon,
+		),
 	}
 
 	medicationHandlers := http.NewHandlers(app, logger)
 
 	authChecker := auth.NewHTTPAuthChecker(conf.Auth, logger)
 
-	authMw := httputil.NewAuthMiddleware(authChecker)
+	authMw := httputil.NewDelegatingAuthMiddleware(authChecker)
 
 	loggingMw := httputil.NewLoggingMiddleware(logger)
 
@@ -135,7 +156,7 @@ func main() {
 	internalServer := http.NewHTTPServer(&conf.Internal, logger)
 	internalServer.Router(internalRouter)
 
-	// daemon expiration notifications
+	// daemon digest notifications
 	loc, err := time.LoadLocation("Europe/Moscow")
 	if err != nil {
 		logger.Fatal(err)
@@ -148,13 +169,20 @@ func main() {
 		12, 0, 0, 0,
 		loc,
 	).Add(24 * time.Hour)
-	daemonExpirationNotification := daemon.NewDaemon(notificationsInterval, noon, logger)
+	daemonDigestNotification := daemon.NewDaemon(notificationsInterval, noon, logger)
 	notificationProvider := notifyClient.NewNotificationClient(conf.Notification, logger)
 	notificationAdapter := notifyProvider.NewNotificationProvider(notificationProvider)
-	expirationNotificationService := application.NewExpirationNotificationService(
+	digestMetrics := workerpool.NewMetrics()
+	expvar.Publish("medicationDigests", digestMetrics)
+	digestNotificationService := application.NewDigestNotificationService(
 		medicationRepo,
 		medicationBoxRepo,
+		memory.NewDigestStorage(),
+		planningClient,
 		notificationAdapter,
+		workerpool.New(conf.Jobs.Parallelism, digestMetrics),
+		timeDelta,
+		conf.Digest.RefillDelta,
 	)
 
 	stop := make(chan os.Signal, 1)
@@ -185,10 +213,8 @@ func main() {
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		logger.Info("Daemon started (expiation notifications)")
-		daemonExpirationNotification.Run(ctx, func(ctx context.Context) error {
-			return expirationNotificationService.GenerateExpirationNotifications(ctx, timeDelta)
-		})
+		logger.Info("Daemon started (digest notifications)")
+		daemonDigestNotification.Run(ctx, digestNotificationService.GenerateDigestNotifications)
 	}()
 
 	go func() {
@@ -1,19 +1,49 @@
 package main
 
 import (
+	"context"
+	"errors"
+	httpErr "net/http"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+	_ "time/tzdata"
+
 	"github.com/FSO-VK/final-project-vk-backend/internal/notifications/application"
+	emailProvider "github.com/FSO-VK/final-project-vk-backend/internal/notifications/application/email_provider"
 	"github.com/FSO-VK/final-project-vk-backend/internal/notifications/infrastructure/config"
+	contactClient "github.com/FSO-VK/final-project-vk-backend/internal/notifications/infrastructure/contact_client"
+	emailClient "github.com/FSO-VK/final-project-vk-backend/internal/notifications/infrastructure/email_client"
+	"github.com/FSO-VK/final-project-vk-backend/internal/notifications/infrastructure/i18n"
+	planningClient "github.com/FSO-VK/final-project-vk-backend/internal/notifications/infrastructure/planning_client"
+	profileClient "github.com/FSO-VK/final-project-vk-backend/internal/notifications/infrastructure/profile_client"
 	client "github.com/FSO-VK/final-project-vk-backend/internal/notifications/infrastructure/push_provider"
 	"github.com/FSO-VK/final-project-vk-backend/internal/notifications/infrastructure/storage/memory"
+	"github.com/FSO-VK/final-project-vk-backend/internal/notifications/infrastructure/storage/mongo"
+	telegramClient "github.com/FSO-VK/final-project-vk-backend/internal/notifications/infrastructure/telegram_client"
 	"github.com/FSO-VK/final-project-vk-backend/internal/notifications/presentation/http"
 	"github.com/FSO-VK/final-project-vk-backend/internal/utils/configuration"
+	"github.com/FSO-VK/final-project-vk-backend/internal/utils/daemon"
 	"github.com/FSO-VK/final-project-vk-backend/internal/utils/httputil"
 	"github.com/FSO-VK/final-project-vk-backend/internal/utils/validator"
 	auth "github.com/FSO-VK/final-project-vk-backend/pkg/auth/client"
 	"github.com/sirupsen/logrus"
 )
 
+const (
+	// deferredInterval is how often notifications held back during quiet hours are checked.
+	deferredInterval = 1 * time.Minute
+	// scheduledInterval is how often notifications scheduled by other services are checked.
+	scheduledInterval = 10 * time.Second
+	// telegramRetryInterval is a pause before polling updates of the bot again after failure.
+	telegramRetryInterval = 5 * time.Second
+)
+
 func main() {
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
 	l := logrus.New()
 	l.SetFormatter(
 		&logrus.TextFormatter{
@@ -39,12 +69,51 @@ func main() {
 	}
 
 	subscriptionsRepo := memory.NewSubscriptionsStorage()
+	preferencesRepo := memory.NewPreferencesStorage()
+	deferredRepo := memory.NewDeferredStorage()
+	historyRepo := memory.NewHistoryStorage()
+	telegramRepo := memory.NewTelegramStorage()
+	scheduledRepo, err := mongo.NewScheduledStorage(&conf.Storage, logger)
+	if err != nil {
+		logger.Fatal(err)
+	}
+	dedupeRepo := memory.NewDedupeStorage()
 	validator := validator.NewValidationProvider()
 
 	pushProvider := client.NewPushNotificationProvider(
 		conf.PushClient,
 		logger,
 	)
+	profileProvider := profileClient.NewProfileClient(conf.Profile, logger)
+	templates, err := i18n.NewRenderer()
+	if err != nil {
+		logger.Fatal(err)
+	}
+	telegramProvider := telegramClient.NewClient(conf.Telegram, logger)
+	// deep links are not given out while the bot doesn't poll updates
+	var botName application.BotName
+	if conf.Telegram.Token != "" {
+		botName = application.BotName(conf.Telegram.BotName)
+	}
+	notifier := application.NewNotifier(
+		subscriptionsRepo,
+		pushProvider,
+		telegramRepo,
+		telegramProvider,
+		contactClient.NewContactClient(conf.Contact, logger),
+		newEmailProvider(conf.Email, logger),
+	)
+
+	sendNotificationService := application.NewSendNotificationService(
+		preferencesRepo,
+		deferredRepo,
+		historyRepo,
+		dedupeRepo,
+		notifier,
+		profileProvider,
+		templates,
+		validator,
+	)
 
 	app := &application.NotificationsApplication{
 		GetVapidPublicKey: application.NewGetVapidPublicKeyService(
@@ -57,24 +126,171 @@ func main() {
 			subscriptionsRepo,
 			validator,
 		),
-		SendNotification: application.NewSendNotificationService(
+		SendNotification: sendNotificationService,
+		GetPreferences: application.NewGetPreferencesService(
+			preferencesRepo,
+			validator,
+		),
+		UpdatePreferences: application.NewUpdatePreferencesService(
+			preferencesRepo,
 			subscriptionsRepo,
-			pushProvider,
+			validator,
+		),
+		ListNotifications: application.NewListNotificationsService(historyRepo, validator),
+		CountUnreadNotifications: application.NewCountUnreadNotificationsService(
+			historyRepo,
+			validator,
+		),
+		MarkNotificationsRead: application.NewMarkNotificationsReadService(historyRepo, validator),
+		CreateTelegramLink: application.NewCreateTelegramLinkService(
+			telegramRepo,
+			botName,
+			validator,
+		),
+		GetTelegramLink:    application.NewGetTelegramLinkService(telegramRepo, validator),
+		DeleteTelegramLink: application.NewDeleteTelegramLinkService(telegramRepo, validator),
+		ScheduleNotification: application.NewScheduleNotificationService(
+			scheduledRepo,
+			validator,
+		),
+		RescheduleNotification: application.NewRescheduleNotificationService(
+			scheduledRepo,
+			validator,
+		),
+		CancelScheduledNotification: application.NewCancelScheduledNotificationService(
+			scheduledRepo,
 			validator,
 		),
 	}
 	notificationsHandlers := http.NewHandlers(app, logger)
 
+	// Service and daemon for notifications deferred during quiet hours
+	deliverDeferredService := application.NewDeliverDeferredService(
+		deferredRepo,
+		historyRepo,
+		preferencesRepo,
+		notifier,
+		templates,
+	)
+	daemonDeferred := daemon.NewDaemon(
+		deferredInterval,
+		time.Now().Truncate(deferredInterval).Add(deferredInterval),
+		logger,
+	)
+
+	// Service and daemon for notifications scheduled by other services
+	deliverScheduledService := application.NewDeliverScheduledService(
+		scheduledRepo,
+		sendNotificationService,
+	)
+	daemonScheduled := daemon.NewDaemon(
+		scheduledInterval,
+		time.Now().Truncate(scheduledInterval).Add(scheduledInterval),
+		logger,
+	)
+
+	// Service for updates of the bot: linking chats and replies to reminders
+	telegramBotService := application.NewTelegramBotService(
+		telegramRepo,
+		historyRepo,
+		telegramProvider,
+		planningClient.NewPlanningClient(conf.Planning, logger),
+	)
+
 	authChecker := auth.NewHTTPAuthChecker(conf.Auth, logger)
 
 	authMw := httputil.NewAuthMiddleware(authChecker)
 
-	router := http.Router(notificationsHandlers, authMw)
+	serviceAuthMw := httputil.NewServiceAuthMiddlewareFromConfig(conf.ServiceAuth, logger)
+
+	router := http.Router(notificationsHandlers, authMw, serviceAuthMw)
 
 	server := http.NewGINServer(&conf.Server, logger)
 	server.Router(router)
+
+	var wg sync.WaitGroup
+
+	// Shutdown goroutine
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		<-ctx.Done()
+		logger.Info("Shutdown signal received")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			logger.Errorf("graceful shutdown failed: %v", err)
+		}
+	}()
+
+	// Daemon goroutine - deliver deferred notifications
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		logger.Info("Daemon started (deferred notifications delivery)")
+		daemonDeferred.Run(ctx, deliverDeferredService.DeliverDeferred)
+	}()
+
+	// Daemon goroutine - deliver scheduled notifications
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		logger.Info("Daemon started (scheduled notifications delivery)")
+		daemonScheduled.Run(ctx, deliverScheduledService.DeliverScheduled)
+	}()
+
+	// Telegram goroutine - poll updates of the bot
+	if conf.Telegram.Token != "" {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			logger.Info("Telegram bot started")
+			pollTelegramUpdates(ctx, telegramBotService, logger)
+		}()
+	}
+
 	err = server.ListenAndServe()
-	if err != nil {
+	if err != nil && !errors.Is(err, httpErr.ErrServerClosed) {
 		logger.Fatal(err)
 	}
+
+	wg.Wait()
+	closeCtx, closeCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer closeCancel()
+	if err := scheduledRepo.Close(closeCtx); err != nil {
+		logger.Errorf("failed to close storage: %v", err)
+	}
+	logger.Info("Server stopped")
+}
+
+// pollTelegramUpdates handles updates of the bot until ctx is done.
+func pollTelegramUpdates(
+	ctx context.Context,
+	bot application.TelegramUpdatesHandler,
+	logger *logrus.Entry,
+) {
+	for ctx.Err() == nil {
+		err := bot.PollUpdates(ctx)
+		if err == nil {
+			continue
+		}
+		logger.WithError(err).Error("Failed to handle telegram updates")
+		select {
+		case <-ctx.Done():
+		case <-time.After(telegramRetryInterval):
+		}
+	}
+}
+
+// newEmailProvider returns provider writing letters to files if sink directory is set,
+// otherwise letters are sent by SMTP server.
+func newEmailProvider(
+	cfg emailClient.EmailClient,
+	logger *logrus.Entry,
+) emailProvider.EmailProvider {
+	if cfg.SinkDir != "" {
+		logger.WithField("dir", cfg.SinkDir).Info("Letters are written to sink directory")
+		return emailClient.NewFileEmailProvider(cfg, logger)
+	}
+	return emailClient.NewSMTPEmailProvider(cfg, logger)
 }
@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"errors"
+	"expvar"
 	httpErr "net/http"
 	"os/signal"
 	"sync"
@@ -11,6 +12,7 @@ import (
 	_ "time/tzdata"
 
 	"github.com/FSO-VK/final-project-vk-backend/internal/planning/application"
+	careClient "github.com/FSO-VK/final-project-vk-backend/internal/planning/infrastructure/care_client"
 	"github.com/FSO-VK/final-project-vk-backend/internal/planning/infrastructure/config"
 	medClient "github.com/FSO-VK/final-project-vk-backend/internal/planning/infrastructure/medication_client"
 	notifyProvider "github.com/FSO-VK/final-project-vk-backend/internal/planning/infrastructure/notification"
@@ -22,13 +24,16 @@ import (
 	notifyClient "github.com/FSO-VK/final-project-vk-backend/internal/utils/notification_client"
 	"github.com/FSO-VK/final-project-vk-backend/internal/utils/validator"
 	auth "github.com/FSO-VK/final-project-vk-backend/pkg/auth/client"
+	"github.com/FSO-VK/final-project-vk-backend/pkg/workerpool"
 	"github.com/sirupsen/logrus"
 )
 
 const (
-	creationShift         = 24 * time.Hour
-	batchSize             = 1000
-	tickerInterval        = 24 * time.Hour
+	creationShift = 24 * time.Hour
+	batchSize     = 1000
+	// records are generated at local midnight of every plan time zone,
+	// some zones have offsets of 30 and 45 minutes.
+	recordsInterval       = 15 * time.Minute
 	notificationsInterval = 1 * time.Minute
 )
 
@@ -48,21 +53,8 @@ func main() {
 	logger := logrus.NewEntry(l)
 
 	now := time.Now()
-
-	loc, err := time.LoadLocation("Europe/Moscow")
-	if err != nil {
-		logger.Fatal(err)
-	}
-
-	nowUTC := time.Now().In(loc)
-
-	midnight := time.Date(
-		nowUTC.Year(), nowUTC.Month(), nowUTC.Day(),
-		0, 0, 0, 0,
-		loc,
-	).Add(24 * time.Hour)
-
 	quickStart := now.Add(2 * time.Minute)
+	nextQuarter := now.Truncate(recordsInterval).Add(recordsInterval)
 
 	confPath, err := configuration.ReadConfigPathFlag("config/planning-conf.yaml")
 	if err != nil {
@@ -72,23 +64,43 @@ func main() {
 	if err := configuration.KoanfLoad(confPath, &conf); err != nil {
 		logger.Fatal(err)
 	}
+	defaultLocation, err := time.LoadLocation(conf.Schedule.DefaultTimeZone)
+	if err != nil {
+		logger.Fatal(err)
+	}
 
 	planRepo := memory.NewPlanStorage()
 	recordsRepo := memory.NewRecordStorage()
+	feedRepo := memory.NewFeedStorage()
 	medicationClient := medClient.NewMedicationClient(conf.Medication, logger)
 
 	// Service and daemon for generating records
 	generateRecordsService := application.NewGenerateRecordService(recordsRepo, planRepo)
-	daemonRecordsGenerator := daemon.NewDaemon(tickerInterval, midnight, logger)
+	daemonRecordsGenerator := daemon.NewDaemon(recordsInterval, nextQuarter, logger)
 
 	// Service and daemon for intake notifications
 	notificationProvider := notifyClient.NewNotificationClient(conf.Notification, logger)
 	notificationAdapter := notifyProvider.NewNotificationProvider(notificationProvider)
+	remindersMetrics := workerpool.NewMetrics()
+	expvar.Publish("intakeReminders", remindersMetrics)
 	intakeNotificationService := application.NewIntakeNotificationService(
 		recordsRepo,
 		planRepo,
 		notificationAdapter,
 		medicationClient,
+		workerpool.New(conf.Jobs.Parallelism, remindersMetrics),
+	)
+
+	escalationsMetrics := workerpool.NewMetrics()
+	expvar.Publish("intakeEscalations", escalationsMetrics)
+
+	intakeEscalationService := application.NewIntakeEscalationService(
+		recordsRepo,
+		planRepo,
+		notificationAdapter,
+		medicationClient,
+		careClient.NewCareClient(conf.Care, logger),
+		workerpool.New(conf.Jobs.Parallelism, escalationsMetrics),
 	)
 
 	daemonIntakeNotification := daemon.NewDaemon(notificationsInterval, quickStart, logger)
@@ -99,49 +111,92 @@ func main() {
 	}
 
 	validator := validator.NewValidationProvider()
+	addPlanService := application.NewAddPlanService(
+		planRepo,
+		generateRecordsService,
+		validator,
+		medicationClient,
+		creationShift,
+		defaultLocation,
+	)
+	finishPlanService := application.NewFinishPlanService(planRepo, validator)
 	app := &application.PlanningApplication{
 		GetAllPlans: application.NewGetAllPlansService(planRepo, validator),
 		GetPlan:     application.NewGetPlanService(planRepo, validator),
-		AddPlan: application.NewAddPlanService(
-			planRepo,
-			generateRecordsService,
-			validator,
-			medicationClient,
-			creationShift,
-		),
+		AddPlan:     addPlanService,
 		ShowSchedule: application.NewShowScheduleService(
 			planRepo,
 			recordsRepo,
 			validator,
 			medicationClient,
-			creationShift,
 		),
-		DeletePlan: application.NewFinishPlanService(planRepo, validator),
+		DeletePlan: finishPlanService,
 		TakeMedication: application.NewTakeMedicationService(
 			recordsRepo,
 			planRepo,
 			validator,
+			medicationClient,
 		),
 		ChangeTakeMedication: application.NewChangeTakeMedicationService(
 			recordsRepo,
 			planRepo,
 			validator,
+			medicationClient,
 		),
 		CancelMedicationTake: application.NewCancelMedicationTakeService(
 			recordsRepo,
 			planRepo,
 			validator,
+			medicationClient,
 		),
+		ScheduledDoses: application.NewScheduledDosesService(planRepo, validator),
+		TakeAsNeeded: application.NewTakeAsNeededService(
+			recordsRepo,
+			planRepo,
+			validator,
+			medicationClient,
+		),
+		ExportPlanCalendar: application.NewExportPlanCalendarService(
+			planRepo,
+			validator,
+			medicationClient,
+		),
+		CalendarFeed: application.NewCalendarFeedService(
+			feedRepo,
+			planRepo,
+			validator,
+			medicationClient,
+		),
+		RotateFeedToken: application.NewRotateFeedTokenService(feedRepo, validator),
+		RevokeFeedToken: application.NewRevokeFeedTokenService(feedRepo, validator),
+		ImportPlans: application.NewImportPlansService(
+			addPlanService,
+			finishPlanService,
+			validator,
+			medicationClient,
+			defaultLocation,
+		),
+		AddPlanExceptions: application.NewAddPlanExceptionsService(
+			planRepo,
+			recordsRepo,
+			validator,
+		),
+		SetPlanEscalation: application.NewSetPlanEscalationService(planRepo, validator),
+		SnoozeIntake:      application.NewSnoozeIntakeService(recordsRepo, planRepo, validator),
 	}
 	planningHandlers := http.NewHandlers(app, logger)
 
 	authChecker := auth.NewHTTPAuthChecker(conf.Auth, logger)
-	authMw := httputil.NewAuthMiddleware(authChecker)
+	authMw := httputil.NewDelegatingAuthMiddleware(authChecker)
 
 	router := http.Router(planningHandlers, authMw)
 	server := http.NewGINServer(&conf.Server, logger)
 	server.Router(router)
 
+	internalRouter := http.InternalRouter(planningHandlers)
+	internalServer := http.NewGINServer(&conf.Internal, logger)
+	internalServer.Router(internalRouter)
+
 	var wg sync.WaitGroup
 
 	// Shutdown goroutine
@@ -155,6 +210,19 @@ func main() {
 		if err := server.Shutdown(shutdownCtx); err != nil {
 			logger.Errorf("graceful shutdown failed: %v", err)
 		}
+		if err := internalServer.Shutdown(shutdownCtx); err != nil {
+			logger.Errorf("graceful shutdown of internal server failed: %v", err)
+		}
+	}()
+
+	// Internal server goroutine
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		err := internalServer.ListenAndServe()
+		if err != nil && !errors.Is(err, httpErr.ErrServerClosed) {
+			logger.Fatal(err)
+		}
 	}()
 
 	// Daemon goroutine - generate records
@@ -173,7 +241,10 @@ func main() {
 		defer wg.Done()
 		logger.Info("Daemon started (intake notifications generation)")
 		daemonIntakeNotification.Run(ctx, func(ctx context.Context) error {
-			return intakeNotificationService.GenerateIntakeNotifications(ctx)
+			return errors.Join(
+				intakeNotificationService.GenerateIntakeNotifications(ctx),
+				intakeEscalationService.EscalateMissedIntakes(ctx),
+			)
 		})
 	}()
 
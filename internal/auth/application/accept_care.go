@@ -0,0 +1,83 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/FSO-VK/final-project-vk-backend/internal/auth/domain/care"
+	"github.com/FSO-VK/final-project-vk-backend/internal/utils/validator"
+	"github.com/google/uuid"
+)
+
+type AcceptCare interface {
+	Execute(ctx context.Context, cmd *AcceptCareCommand) (*AcceptCareResult, error)
+}
+
+// AcceptCareCommand represents the command of the caregiver to accept the invitation.
+type AcceptCareCommand struct {
+	UserID  string `validate:"required,uuid"`
+	GrantID string `validate:"required,uuid"`
+}
+
+type AcceptCareResult struct {
+	OwnerID string
+	Role    string
+}
+
+type AcceptCareService struct {
+	grantRepo care.GrantRepository
+	valid     validator.Validator
+}
+
+func NewAcceptCareService(
+	grantRepo care.GrantRepository,
+	valid validator.Validator,
+) *AcceptCareService {
+	return &AcceptCareService{
+		grantRepo: grantRepo,
+		valid:     valid,
+	}
+}
+
+func (s *AcceptCareService) Execute(
+	ctx context.Context,
+	cmd *AcceptCareCommand,
+) (*AcceptCareResult, error) {
+	valErr := s.valid.ValidateStruct(cmd)
+	if valErr != nil {
+		return nil, ErrInvalidCareCmd
+	}
+	userID, err := uuid.Parse(cmd.UserID)
+	if err != nil {
+		return nil, ErrInvalidCareCmd
+	}
+	grantID, err := uuid.Parse(cmd.GrantID)
+	if err != nil {
+		return nil, ErrInvalidCareCmd
+	}
+
+	grant, err := s.grantRepo.FindByID(ctx, grantID)
+	if errors.Is(err, care.ErrNoGrantFound) {
+		return nil, ErrNoGrant
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to find grant: %w", err)
+	}
+	err = grant.Accept(userID, time.Now())
+	if errors.Is(err, care.ErrNotInvitee) {
+		// invitation of another user is not disclosed
+		return nil, ErrNoGrant
+	} else if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrCareNotAllowed, err)
+	}
+
+	err = s.grantRepo.Save(ctx, grant)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save grant: %w", err)
+	}
+	return &AcceptCareResult{
+		OwnerID: grant.OwnerID.String(),
+		Role:    string(grant.Role),
+	}, nil
+}
@@ -0,0 +1,72 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/FSO-VK/final-project-vk-backend/internal/auth/domain/care"
+	"github.com/FSO-VK/final-project-vk-backend/internal/utils/validator"
+	"github.com/google/uuid"
+)
+
+type CheckCareAccess interface {
+	Execute(ctx context.Context, cmd *CheckCareAccessCommand) (*CheckCareAccessResult, error)
+}
+
+// CheckCareAccessCommand represents the command to check
+// whether the caregiver may act on behalf of the owner.
+type CheckCareAccessCommand struct {
+	CaregiverID string `validate:"required,uuid"`
+	OwnerID     string `validate:"required,uuid"`
+}
+
+type CheckCareAccessResult struct {
+	Role string
+}
+
+type CheckCareAccessService struct {
+	grantRepo care.GrantRepository
+	valid     validator.Validator
+}
+
+func NewCheckCareAccessService(
+	grantRepo care.GrantRepository,
+	valid validator.Validator,
+) *CheckCareAccessService {
+	return &CheckCareAccessService{
+		grantRepo: grantRepo,
+		valid:     valid,
+	}
+}
+
+func (s *CheckCareAccessService) Execute(
+	ctx context.Context,
+	cmd *CheckCareAccessCommand,
+) (*CheckCareAccessResult, error) {
+	valErr := s.valid.ValidateStruct(cmd)
+	if valErr != nil {
+		return nil, ErrInvalidCareCmd
+	}
+	caregiverID, err := uuid.Parse(cmd.CaregiverID)
+	if err != nil {
+		return nil, ErrInvalidCareCmd
+	}
+	ownerID, err := uuid.Parse(cmd.OwnerID)
+	if err != nil {
+		return nil, ErrInvalidCareCmd
+	}
+
+	grant, err := s.grantRepo.FindByPair(ctx, ownerID, caregiverID)
+	if errors.Is(err, care.ErrNoGrantFound) {
+		return nil, ErrNoCareAccess
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to find grant: %w", err)
+	}
+	if !grant.IsAccepted() {
+		return nil, ErrNoCareAccess
+	}
+	return &CheckCareAccessResult{
+		Role: string(grant.Role),
+	}, nil
+}
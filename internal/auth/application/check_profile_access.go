@@ -0,0 +1,96 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/FSO-VK/final-project-vk-backend/internal/auth/domain/care"
+	"github.com/FSO-VK/final-project-vk-backend/internal/auth/domain/profile"
+	"github.com/FSO-VK/final-project-vk-backend/internal/utils/validator"
+	"github.com/google/uuid"
+)
+
+type CheckProfileAccess interface {
+	Execute(
+		ctx context.Context,
+		cmd *CheckProfileAccessCommand,
+	) (*CheckProfileAccessResult, error)
+}
+
+// CheckProfileAccessCommand represents the command to check whether
+// the user may access the profile. The account of the profile has access,
+// caregivers of the account have access by their role.
+type CheckProfileAccessCommand struct {
+	UserID    string `validate:"required,uuid"`
+	ProfileID string `validate:"required,uuid"`
+}
+
+// CheckProfileAccessResult represents the profile and the caregiver role,
+// Role is empty when the user is the account of the profile.
+type CheckProfileAccessResult struct {
+	ProfileResult
+	Role string
+}
+
+type CheckProfileAccessService struct {
+	profileRepo profile.ProfileRepository
+	grantRepo   care.GrantRepository
+	valid       validator.Validator
+}
+
+func NewCheckProfileAccessService(
+	profileRepo profile.ProfileRepository,
+	grantRepo care.GrantRepository,
+	valid validator.Validator,
+) *CheckProfileAccessService {
+	return &CheckProfileAccessService{
+		profileRepo: profileRepo,
+		grantRepo:   grantRepo,
+		valid:       valid,
+	}
+}
+
+func (s *CheckProfileAccessService) Execute(
+	ctx context.Context,
+	cmd *CheckProfileAccessCommand,
+) (*CheckProfileAccessResult, error) {
+	valErr := s.valid.ValidateStruct(cmd)
+	if valErr != nil {
+		return nil, ErrInvalidProfileCmd
+	}
+	userID, err := uuid.Parse(cmd.UserID)
+	if err != nil {
+		return nil, ErrInvalidProfileCmd
+	}
+	profileID, err := uuid.Parse(cmd.ProfileID)
+	if err != nil {
+		return nil, ErrInvalidProfileCmd
+	}
+
+	p, err := s.profileRepo.FindByID(ctx, profileID)
+	if errors.Is(err, profile.ErrNoProfileFound) {
+		return nil, ErrNoProfileAccess
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to find profile: %w", err)
+	}
+	result := &CheckProfileAccessResult{
+		ProfileResult: *profileResult(p),
+		Role:          "",
+	}
+	if p.AccountID == userID {
+		return result, nil
+	}
+
+	grant, err := s.grantRepo.FindByPair(ctx, p.AccountID, userID)
+	if errors.Is(err, care.ErrNoGrantFound) {
+		return nil, ErrNoProfileAccess
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to find grant: %w", err)
+	}
+	if !grant.IsAccepted() {
+		return nil, ErrNoProfileAccess
+	}
+	result.Role = string(grant.Role)
+	return result, nil
+}
@@ -0,0 +1,83 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/FSO-VK/final-project-vk-backend/internal/auth/domain/profile"
+	"github.com/FSO-VK/final-project-vk-backend/internal/utils/validator"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidProfileCmd  = errors.New("invalid profile command")
+	ErrInvalidProfileName = errors.New("invalid profile name")
+	ErrNoProfile          = errors.New("no profile")
+	ErrNoProfileAccess    = errors.New("user has no access to the profile")
+)
+
+type CreateProfile interface {
+	Execute(ctx context.Context, cmd *CreateProfileCommand) (*ProfileResult, error)
+}
+
+// CreateProfileCommand represents the command to add a dependant profile to the account.
+type CreateProfileCommand struct {
+	AccountID string `validate:"required,uuid"`
+	Name      string `validate:"required"`
+}
+
+// ProfileResult represents a profile returned by profile use cases.
+type ProfileResult struct {
+	ProfileID string
+	AccountID string
+	Name      string
+}
+
+type CreateProfileService struct {
+	profileRepo profile.ProfileRepository
+	valid       validator.Validator
+}
+
+func NewCreateProfileService(
+	profileRepo profile.ProfileRepository,
+	valid validator.Validator,
+) *CreateProfileService {
+	return &CreateProfileService{
+		profileRepo: profileRepo,
+		valid:       valid,
+	}
+}
+
+func (s *CreateProfileService) Execute(
+	ctx context.Context,
+	cmd *CreateProfileCommand,
+) (*ProfileResult, error) {
+	valErr := s.valid.ValidateStruct(cmd)
+	if valErr != nil {
+		return nil, ErrInvalidProfileCmd
+	}
+	accountID, err := uuid.Parse(cmd.AccountID)
+	if err != nil {
+		return nil, ErrInvalidProfileCmd
+	}
+
+	p, err := profile.NewProfile(accountID, cmd.Name, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidProfileName, err)
+	}
+	err = s.profileRepo.Save(ctx, p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save profile: %w", err)
+	}
+	return profileResult(p), nil
+}
+
+func profileResult(p *profile.Profile) *ProfileResult {
+	return &ProfileResult{
+		ProfileID: p.ID.String(),
+		AccountID: p.AccountID.String(),
+		Name:      p.Name,
+	}
+}
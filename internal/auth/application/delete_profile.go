@@ -0,0 +1,65 @@
+package application
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/FSO-VK/final-project-vk-backend/internal/auth/domain/profile"
+	"github.com/FSO-VK/final-project-vk-backend/internal/utils/validator"
+	"github.com/google/uuid"
+)
+
+type DeleteProfile interface {
+	Execute(ctx context.Context, cmd *DeleteProfileCommand) (*DeleteProfileResult, error)
+}
+
+// DeleteProfileCommand represents the command to remove the profile from the account.
+type DeleteProfileCommand struct {
+	AccountID string `validate:"required,uuid"`
+	ProfileID string `validate:"required,uuid"`
+}
+
+type DeleteProfileResult struct{}
+
+type DeleteProfileService struct {
+	profileRepo profile.ProfileRepository
+	valid       validator.Validator
+}
+
+func NewDeleteProfileService(
+	profileRepo profile.ProfileRepository,
+	valid validator.Validator,
+) *DeleteProfileService {
+	return &DeleteProfileService{
+		profileRepo: profileRepo,
+		valid:       valid,
+	}
+}
+
+func (s *DeleteProfileService) Execute(
+	ctx context.Context,
+	cmd *DeleteProfileCommand,
+) (*DeleteProfileResult, error) {
+	valErr := s.valid.ValidateStruct(cmd)
+	if valErr != nil {
+		return nil, ErrInvalidProfileCmd
+	}
+	accountID, err := uuid.Parse(cmd.AccountID)
+	if err != nil {
+		return nil, ErrInvalidProfileCmd
+	}
+	profileID, err := uuid.Parse(cmd.ProfileID)
+	if err != nil {
+		return nil, ErrInvalidProfileCmd
+	}
+
+	p, err := findAccountProfile(ctx, s.profileRepo, accountID, profileID)
+	if err != nil {
+		return nil, err
+	}
+	err = s.profileRepo.Delete(ctx, p.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete profile: %w", err)
+	}
+	return &DeleteProfileResult{}, nil
+}
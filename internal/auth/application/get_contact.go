@@ -0,0 +1,75 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/FSO-VK/final-project-vk-backend/internal/auth/domain/credential"
+	"github.com/FSO-VK/final-project-vk-backend/internal/utils/validator"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidContactCmd = errors.New("invalid contact command")
+	ErrNoContact         = errors.New("no contact")
+)
+
+type GetContact interface {
+	Execute(ctx context.Context, cmd *GetContactCommand) (*ContactResult, error)
+}
+
+// GetContactCommand represents the command of other services
+// to get the email of the account to send notifications to.
+type GetContactCommand struct {
+	UserID string `validate:"required,uuid"`
+}
+
+// ContactResult represents contacts of the account.
+type ContactResult struct {
+	UserID string
+	Email  string
+}
+
+type GetContactService struct {
+	credentialRepo credential.CredentialRepository
+	valid          validator.Validator
+}
+
+func NewGetContactService(
+	credentialRepo credential.CredentialRepository,
+	valid validator.Validator,
+) *GetContactService {
+	return &GetContactService{
+		credentialRepo: credentialRepo,
+		valid:          valid,
+	}
+}
+
+func (s *GetContactService) Execute(
+	ctx context.Context,
+	cmd *GetContactCommand,
+) (*ContactResult, error) {
+	valErr := s.valid.ValidateStruct(cmd)
+	if valErr != nil {
+		return nil, ErrInvalidContactCmd
+	}
+	userID, err := uuid.Parse(cmd.UserID)
+	if err != nil {
+		return nil, ErrInvalidContactCmd
+	}
+
+	cred, err := s.credentialRepo.FindByID(ctx, userID)
+	if errors.Is(err, credential.ErrNoCredentialFound) {
+		return nil, ErrNoContact
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to find credential: %w", err)
+	}
+	if !cred.IsTypeEmail() {
+		return nil, ErrNoContact
+	}
+	return &ContactResult{
+		UserID: userID.String(),
+		Email:  cred.Identifier,
+	}, nil
+}
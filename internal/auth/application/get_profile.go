@@ -0,0 +1,58 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/FSO-VK/final-project-vk-backend/internal/auth/domain/profile"
+	"github.com/FSO-VK/final-project-vk-backend/internal/utils/validator"
+	"github.com/google/uuid"
+)
+
+type GetProfile interface {
+	Execute(ctx context.Context, cmd *GetProfileCommand) (*ProfileResult, error)
+}
+
+// GetProfileCommand represents the command of other services
+// to get the account and the name of the profile.
+type GetProfileCommand struct {
+	ProfileID string `validate:"required,uuid"`
+}
+
+type GetProfileService struct {
+	profileRepo profile.ProfileRepository
+	valid       validator.Validator
+}
+
+func NewGetProfileService(
+	profileRepo profile.ProfileRepository,
+	valid validator.Validator,
+) *GetProfileService {
+	return &GetProfileService{
+		profileRepo: profileRepo,
+		valid:       valid,
+	}
+}
+
+func (s *GetProfileService) Execute(
+	ctx context.Context,
+	cmd *GetProfileCommand,
+) (*ProfileResult, error) {
+	valErr := s.valid.ValidateStruct(cmd)
+	if valErr != nil {
+		return nil, ErrInvalidProfileCmd
+	}
+	profileID, err := uuid.Parse(cmd.ProfileID)
+	if err != nil {
+		return nil, ErrInvalidProfileCmd
+	}
+
+	p, err := s.profileRepo.FindByID(ctx, profileID)
+	if errors.Is(err, profile.ErrNoProfileFound) {
+		return nil, ErrNoProfile
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to find profile: %w", err)
+	}
+	return profileResult(p), nil
+}
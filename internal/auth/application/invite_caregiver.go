@@ -0,0 +1,142 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/FSO-VK/final-project-vk-backend/internal/auth/domain/care"
+	"github.com/FSO-VK/final-project-vk-backend/internal/auth/domain/credential"
+	"github.com/FSO-VK/final-project-vk-backend/internal/utils/validator"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidCareCmd  = errors.New("invalid care command")
+	ErrNoGrant         = errors.New("no caregiver grant")
+	ErrNoCareAccess    = errors.New("user has no access to data of the owner")
+	ErrCareNotAllowed  = errors.New("action with the grant is not allowed")
+	ErrInvalidCareRole = errors.New("invalid caregiver role")
+)
+
+type InviteCaregiver interface {
+	Execute(ctx context.Context, cmd *InviteCaregiverCommand) (*InviteCaregiverResult, error)
+}
+
+// InviteCaregiverCommand represents the command to share data of the owner
+// with the user registered by the email. Invitation to an email without account
+// waits for registration, so the result doesn't tell if the email is registered.
+type InviteCaregiverCommand struct {
+	OwnerID string `validate:"required,uuid"`
+	Email   string `validate:"required,email"`
+	Role    string `validate:"required,oneof=view manage"`
+}
+
+// InviteCaregiverResult represents the result of an invitation.
+// Inviting the caregiver again changes the role of the existing grant.
+type InviteCaregiverResult struct {
+	GrantID string
+	Status  string
+}
+
+type InviteCaregiverService struct {
+	grantRepo      care.GrantRepository
+	credentialRepo credential.CredentialRepository
+	valid          validator.Validator
+}
+
+func NewInviteCaregiverService(
+	grantRepo care.GrantRepository,
+	credentialRepo credential.CredentialRepository,
+	valid validator.Validator,
+) *InviteCaregiverService {
+	return &InviteCaregiverService{
+		grantRepo:      grantRepo,
+		credentialRepo: credentialRepo,
+		valid:          valid,
+	}
+}
+
+func (s *InviteCaregiverService) Execute(
+	ctx context.Context,
+	cmd *InviteCaregiverCommand,
+) (*InviteCaregiverResult, error) {
+	valErr := s.valid.ValidateStruct(cmd)
+	if valErr != nil {
+		return nil, ErrInvalidCareCmd
+	}
+	ownerID, err := uuid.Parse(cmd.OwnerID)
+	if err != nil {
+		return nil, ErrInvalidCareCmd
+	}
+
+	now := time.Now()
+	invitee, err := s.credentialRepo.FindByEmail(ctx, cmd.Email)
+	var grant *care.Grant
+	switch {
+	case err == nil:
+		grant, err = s.inviteUser(ctx, ownerID, invitee.ID, care.Role(cmd.Role), now)
+	case errors.Is(err, credential.ErrNoCredentialFound):
+		grant, err = s.inviteEmail(ctx, ownerID, cmd.Email, care.Role(cmd.Role), now)
+	default:
+		return nil, fmt.Errorf("failed to find credential by email: %w", err)
+	}
+	switch {
+	case errors.Is(err, care.ErrSelfGrant):
+		return nil, ErrCareNotAllowed
+	case errors.Is(err, care.ErrInvalidRole):
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCareRole, err)
+	case err != nil:
+		return nil, err
+	}
+
+	err = s.grantRepo.Save(ctx, grant)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save grant: %w", err)
+	}
+	return &InviteCaregiverResult{
+		GrantID: grant.ID.String(),
+		Status:  string(grant.Status),
+	}, nil
+}
+
+// inviteUser creates the grant for the registered caregiver or changes its role.
+func (s *InviteCaregiverService) inviteUser(
+	ctx context.Context,
+	ownerID uuid.UUID,
+	caregiverID uuid.UUID,
+	role care.Role,
+	now time.Time,
+) (*care.Grant, error) {
+	grant, err := s.grantRepo.FindByPair(ctx, ownerID, caregiverID)
+	switch {
+	case err == nil:
+		return grant, grant.ChangeRole(role, now)
+	case errors.Is(err, care.ErrNoGrantFound):
+		return care.NewGrant(ownerID, caregiverID, role, now)
+	default:
+		return nil, fmt.Errorf("failed to find grant: %w", err)
+	}
+}
+
+// inviteEmail creates the invitation waiting for registration of the email
+// or changes role of the invitation sent before.
+func (s *InviteCaregiverService) inviteEmail(
+	ctx context.Context,
+	ownerID uuid.UUID,
+	email string,
+	role care.Role,
+	now time.Time,
+) (*care.Grant, error) {
+	invitations, err := s.grantRepo.FindByInvitee(ctx, email)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find invitations: %w", err)
+	}
+	for _, grant := range invitations {
+		if grant.OwnerID == ownerID {
+			return grant, grant.ChangeRole(role, now)
+		}
+	}
+	return care.NewInvitation(ownerID, email, role, now)
+}
@@ -0,0 +1,110 @@
+package application
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/FSO-VK/final-project-vk-backend/internal/auth/domain/care"
+	"github.com/FSO-VK/final-project-vk-backend/internal/auth/domain/credential"
+	"github.com/FSO-VK/final-project-vk-backend/internal/utils/validator"
+	"github.com/google/uuid"
+)
+
+type ListCare interface {
+	Execute(ctx context.Context, cmd *ListCareCommand) (*ListCareResult, error)
+}
+
+// ListCareCommand represents the command to list grants of the user.
+type ListCareCommand struct {
+	UserID string `validate:"required,uuid"`
+}
+
+// CareItem is a grant with emails of both parties.
+type CareItem struct {
+	GrantID        string
+	OwnerID        string
+	OwnerEmail     string
+	CaregiverID    string
+	CaregiverEmail string
+	Role           string
+	Status         string
+}
+
+// ListCareResult contains caregivers of the user (Caregivers)
+// and users the user takes care of (Dependants).
+type ListCareResult struct {
+	Caregivers []CareItem
+	Dependants []CareItem
+}
+
+type ListCareService struct {
+	grantRepo      care.GrantRepository
+	credentialRepo credential.CredentialRepository
+	valid          validator.Validator
+}
+
+func NewListCareService(
+	grantRepo care.GrantRepository,
+	credentialRepo credential.CredentialRepository,
+	valid validator.Validator,
+) *ListCareService {
+	return &ListCareService{
+		grantRepo:      grantRepo,
+		credentialRepo: credentialRepo,
+		valid:          valid,
+	}
+}
+
+func (s *ListCareService) Execute(
+	ctx context.Context,
+	cmd *ListCareCommand,
+) (*ListCareResult, error) {
+	valErr := s.valid.ValidateStruct(cmd)
+	if valErr != nil {
+		return nil, ErrInvalidCareCmd
+	}
+	userID, err := uuid.Parse(cmd.UserID)
+	if err != nil {
+		return nil, ErrInvalidCareCmd
+	}
+
+	grants, err := s.grantRepo.FindByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find grants: %w", err)
+	}
+	result := &ListCareResult{
+		Caregivers: make([]CareItem, 0),
+		Dependants: make([]CareItem, 0),
+	}
+	for _, grant := range grants {
+		item := CareItem{
+			GrantID:    grant.ID.String(),
+			OwnerID:    grant.OwnerID.String(),
+			OwnerEmail: s.email(ctx, grant.OwnerID),
+			Role:       string(grant.Role),
+			Status:     string(grant.Status),
+		}
+		// invitation to the email without account has no caregiver yet
+		if grant.IsBound() {
+			item.CaregiverID = grant.CaregiverID.String()
+			item.CaregiverEmail = s.email(ctx, grant.CaregiverID)
+		} else {
+			item.CaregiverEmail = grant.InviteeEmail
+		}
+		if grant.OwnerID == userID {
+			result.Caregivers = append(result.Caregivers, item)
+		} else {
+			result.Dependants = append(result.Dependants, item)
+		}
+	}
+	return result, nil
+}
+
+// email returns email of the user or empty string if user is not found.
+func (s *ListCareService) email(ctx context.Context, userID uuid.UUID) string {
+	cred, err := s.credentialRepo.FindByID(ctx, userID)
+	if err != nil {
+		return ""
+	}
+	return cred.Identifier
+}
@@ -0,0 +1,107 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/FSO-VK/final-project-vk-backend/internal/auth/domain/care"
+	"github.com/FSO-VK/final-project-vk-backend/internal/auth/domain/credential"
+	"github.com/FSO-VK/final-project-vk-backend/internal/auth/domain/profile"
+	"github.com/FSO-VK/final-project-vk-backend/internal/utils/validator"
+	"github.com/google/uuid"
+)
+
+type ListCaregivers interface {
+	Execute(ctx context.Context, cmd *ListCaregiversCommand) (*ListCaregiversResult, error)
+}
+
+// ListCaregiversCommand represents the command of other services to get
+// caregivers to notify about the user. UserID may be an id of a profile,
+// then caregivers of the account of the profile are returned.
+type ListCaregiversCommand struct {
+	UserID string `validate:"required,uuid"`
+}
+
+// ListCaregiversResult contains name of the user to show to caregivers
+// and ids of caregivers with accepted grants. Name is the profile name,
+// it is empty for accounts, login of the account is never disclosed.
+type ListCaregiversResult struct {
+	Name         string
+	CaregiverIDs []string
+}
+
+type ListCaregiversService struct {
+	grantRepo      care.GrantRepository
+	profileRepo    profile.ProfileRepository
+	credentialRepo credential.CredentialRepository
+	valid          validator.Validator
+}
+
+func NewListCaregiversService(
+	grantRepo care.GrantRepository,
+	profileRepo profile.ProfileRepository,
+	credentialRepo credential.CredentialRepository,
+	valid validator.Validator,
+) *ListCaregiversService {
+	return &ListCaregiversService{
+		grantRepo:      grantRepo,
+		profileRepo:    profileRepo,
+		credentialRepo: credentialRepo,
+		valid:          valid,
+	}
+}
+
+func (s *ListCaregiversService) Execute(
+	ctx context.Context,
+	cmd *ListCaregiversCommand,
+) (*ListCaregiversResult, error) {
+	valErr := s.valid.ValidateStruct(cmd)
+	if valErr != nil {
+		return nil, ErrInvalidCareCmd
+	}
+	userID, err := uuid.Parse(cmd.UserID)
+	if err != nil {
+		return nil, ErrInvalidCareCmd
+	}
+
+	ownerID, name, err := s.owner(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	grants, err := s.grantRepo.FindByUser(ctx, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find grants: %w", err)
+	}
+	result := &ListCaregiversResult{
+		Name:         name,
+		CaregiverIDs: make([]string, 0, len(grants)),
+	}
+	for _, grant := range grants {
+		if grant.OwnerID == ownerID && grant.IsAccepted() {
+			result.CaregiverIDs = append(result.CaregiverIDs, grant.CaregiverID.String())
+		}
+	}
+	return result, nil
+}
+
+// owner returns the account whose caregivers are notified and the name of the user.
+func (s *ListCaregiversService) owner(
+	ctx context.Context,
+	userID uuid.UUID,
+) (uuid.UUID, string, error) {
+	p, err := s.profileRepo.FindByID(ctx, userID)
+	if err == nil {
+		return p.AccountID, p.Name, nil
+	} else if !errors.Is(err, profile.ErrNoProfileFound) {
+		return uuid.Nil, "", fmt.Errorf("failed to find profile: %w", err)
+	}
+
+	_, err = s.credentialRepo.FindByID(ctx, userID)
+	if errors.Is(err, credential.ErrNoCredentialFound) {
+		return uuid.Nil, "", ErrNoGrant
+	} else if err != nil {
+		return uuid.Nil, "", fmt.Errorf("failed to find credential: %w", err)
+	}
+	return userID, "", nil
+}
@@ -0,0 +1,64 @@
+package application
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/FSO-VK/final-project-vk-backend/internal/auth/domain/profile"
+	"github.com/FSO-VK/final-project-vk-backend/internal/utils/validator"
+	"github.com/google/uuid"
+)
+
+type ListProfiles interface {
+	Execute(ctx context.Context, cmd *ListProfilesCommand) (*ListProfilesResult, error)
+}
+
+// ListProfilesCommand represents the command to list dependant profiles of the account.
+type ListProfilesCommand struct {
+	AccountID string `validate:"required,uuid"`
+}
+
+type ListProfilesResult struct {
+	Profiles []*ProfileResult
+}
+
+type ListProfilesService struct {
+	profileRepo profile.ProfileRepository
+	valid       validator.Validator
+}
+
+func NewListProfilesService(
+	profileRepo profile.ProfileRepository,
+	valid validator.Validator,
+) *ListProfilesService {
+	return &ListProfilesService{
+		profileRepo: profileRepo,
+		valid:       valid,
+	}
+}
+
+func (s *ListProfilesService) Execute(
+	ctx context.Context,
+	cmd *ListProfilesCommand,
+) (*ListProfilesResult, error) {
+	valErr := s.valid.ValidateStruct(cmd)
+	if valErr != nil {
+		return nil, ErrInvalidProfileCmd
+	}
+	accountID, err := uuid.Parse(cmd.AccountID)
+	if err != nil {
+		return nil, ErrInvalidProfileCmd
+	}
+
+	profiles, err := s.profileRepo.FindByAccount(ctx, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find profiles: %w", err)
+	}
+	result := &ListProfilesResult{
+		Profiles: make([]*ProfileResult, 0, len(profiles)),
+	}
+	for _, p := range profiles {
+		result.Profiles = append(result.Profiles, profileResult(p))
+	}
+	return result, nil
+}
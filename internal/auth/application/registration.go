@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/FSO-VK/final-project-vk-backend/internal/auth/domain/care"
 	"github.com/FSO-VK/final-project-vk-backend/internal/auth/domain/credential"
 	"github.com/FSO-VK/final-project-vk-backend/internal/auth/domain/session"
 	"github.com/FSO-VK/final-project-vk-backend/internal/utils/password"
@@ -41,6 +42,7 @@ type RegistrationResult struct {
 type RegistrationService struct {
 	credentialRepo credential.CredentialRepository
 	sessionRepo    session.SessionRepository
+	grantRepo      care.GrantRepository
 	valid          validator.Validator
 	passwordHasher password.PasswordHasher
 }
@@ -48,12 +50,14 @@ type RegistrationService struct {
 func NewRegistrationService(
 	credentialRepo credential.CredentialRepository,
 	sessionRepo session.SessionRepository,
+	grantRepo care.GrantRepository,
 	valid validator.Validator,
 	passwordHasher password.PasswordHasher,
 ) *RegistrationService {
 	return &RegistrationService{
 		credentialRepo: credentialRepo,
 		sessionRepo:    sessionRepo,
+		grantRepo:      grantRepo,
 		valid:          valid,
 		passwordHasher: passwordHasher,
 	}
@@ -100,6 +104,11 @@ func (s *RegistrationService) Execute(
 		return nil, fmt.Errorf("failed to find credential by email: %w", err)
 	}
 
+	err = s.bindInvitations(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
 	sess := session.NewSession(user.ID)
 	err = s.sessionRepo.Create(ctx, sess)
 	if err != nil {
@@ -113,6 +122,28 @@ func (s *RegistrationService) Execute(
 	}, nil
 }
 
+// bindInvitations makes invitations sent to the email before registration
+// pending for the new user, so the user can accept them.
+func (s *RegistrationService) bindInvitations(
+	ctx context.Context,
+	user *credential.Credential,
+) error {
+	invitations, err := s.grantRepo.FindByInvitee(ctx, user.Identifier)
+	if err != nil {
+		return fmt.Errorf("failed to find invitations: %w", err)
+	}
+	now := time.Now()
+	for _, grant := range invitations {
+		if err := grant.Bind(user.ID, now); err != nil {
+			return fmt.Errorf("failed to bind invitation: %w", err)
+		}
+		if err := s.grantRepo.Save(ctx, grant); err != nil {
+			return fmt.Errorf("failed to save invitation: %w", err)
+		}
+	}
+	return nil
+}
+
 // handleValidationError handles validation errors and returns an error.
 func (s *RegistrationService) handleValidationError(
 	cmd *RegistrationCommand,
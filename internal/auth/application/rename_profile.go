@@ -0,0 +1,90 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/FSO-VK/final-project-vk-backend/internal/auth/domain/profile"
+	"github.com/FSO-VK/final-project-vk-backend/internal/utils/validator"
+	"github.com/google/uuid"
+)
+
+type RenameProfile interface {
+	Execute(ctx context.Context, cmd *RenameProfileCommand) (*ProfileResult, error)
+}
+
+// RenameProfileCommand represents the command to change name of the profile.
+type RenameProfileCommand struct {
+	AccountID string `validate:"required,uuid"`
+	ProfileID string `validate:"required,uuid"`
+	Name      string `validate:"required"`
+}
+
+type RenameProfileService struct {
+	profileRepo profile.ProfileRepository
+	valid       validator.Validator
+}
+
+func NewRenameProfileService(
+	profileRepo profile.ProfileRepository,
+	valid validator.Validator,
+) *RenameProfileService {
+	return &RenameProfileService{
+		profileRepo: profileRepo,
+		valid:       valid,
+	}
+}
+
+func (s *RenameProfileService) Execute(
+	ctx context.Context,
+	cmd *RenameProfileCommand,
+) (*ProfileResult, error) {
+	valErr := s.valid.ValidateStruct(cmd)
+	if valErr != nil {
+		return nil, ErrInvalidProfileCmd
+	}
+	accountID, err := uuid.Parse(cmd.AccountID)
+	if err != nil {
+		return nil, ErrInvalidProfileCmd
+	}
+	profileID, err := uuid.Parse(cmd.ProfileID)
+	if err != nil {
+		return nil, ErrInvalidProfileCmd
+	}
+
+	p, err := findAccountProfile(ctx, s.profileRepo, accountID, profileID)
+	if err != nil {
+		return nil, err
+	}
+	err = p.Rename(cmd.Name, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidProfileName, err)
+	}
+	err = s.profileRepo.Save(ctx, p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save profile: %w", err)
+	}
+	return profileResult(p), nil
+}
+
+// findAccountProfile returns the profile if it belongs to the account,
+// profile of another account is not disclosed.
+func findAccountProfile(
+	ctx context.Context,
+	profileRepo profile.ProfileRepository,
+	accountID uuid.UUID,
+	profileID uuid.UUID,
+) (*profile.Profile, error) {
+	p, err := profileRepo.FindByID(ctx, profileID)
+	if errors.Is(err, profile.ErrNoProfileFound) {
+		return nil, ErrNoProfile
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to find profile: %w", err)
+	}
+	if p.AccountID != accountID {
+		return nil, ErrNoProfile
+	}
+	return p, nil
+}
@@ -0,0 +1,73 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/FSO-VK/final-project-vk-backend/internal/auth/domain/care"
+	"github.com/FSO-VK/final-project-vk-backend/internal/utils/validator"
+	"github.com/google/uuid"
+)
+
+type RevokeCare interface {
+	Execute(ctx context.Context, cmd *RevokeCareCommand) (*RevokeCareResult, error)
+}
+
+// RevokeCareCommand represents the command to stop sharing,
+// both the owner and the caregiver can revoke the grant.
+type RevokeCareCommand struct {
+	UserID  string `validate:"required,uuid"`
+	GrantID string `validate:"required,uuid"`
+}
+
+type RevokeCareResult struct{}
+
+type RevokeCareService struct {
+	grantRepo care.GrantRepository
+	valid     validator.Validator
+}
+
+func NewRevokeCareService(
+	grantRepo care.GrantRepository,
+	valid validator.Validator,
+) *RevokeCareService {
+	return &RevokeCareService{
+		grantRepo: grantRepo,
+		valid:     valid,
+	}
+}
+
+func (s *RevokeCareService) Execute(
+	ctx context.Context,
+	cmd *RevokeCareCommand,
+) (*RevokeCareResult, error) {
+	valErr := s.valid.ValidateStruct(cmd)
+	if valErr != nil {
+		return nil, ErrInvalidCareCmd
+	}
+	userID, err := uuid.Parse(cmd.UserID)
+	if err != nil {
+		return nil, ErrInvalidCareCmd
+	}
+	grantID, err := uuid.Parse(cmd.GrantID)
+	if err != nil {
+		return nil, ErrInvalidCareCmd
+	}
+
+	grant, err := s.grantRepo.FindByID(ctx, grantID)
+	if errors.Is(err, care.ErrNoGrantFound) {
+		return nil, ErrNoGrant
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to find grant: %w", err)
+	}
+	if !grant.IsParty(userID) {
+		return nil, ErrNoGrant
+	}
+
+	err = s.grantRepo.Delete(ctx, grant.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete grant: %w", err)
+	}
+	return &RevokeCareResult{}, nil
+}
@@ -5,4 +5,20 @@ type AuthApplication struct {
 	LoginByEmail LoginByEmail
 	Logout       Logout
 	Registration Registration
+
+	InviteCaregiver InviteCaregiver
+	AcceptCare      AcceptCare
+	RevokeCare      RevokeCare
+	ListCare        ListCare
+	CheckCareAccess CheckCareAccess
+	ListCaregivers  ListCaregivers
+
+	CreateProfile      CreateProfile
+	RenameProfile      RenameProfile
+	DeleteProfile      DeleteProfile
+	ListProfiles       ListProfiles
+	CheckProfileAccess CheckProfileAccess
+	GetProfile         GetProfile
+
+	GetContact GetContact
 }
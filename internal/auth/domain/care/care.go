@@ -0,0 +1,137 @@
+// Package care implements sharing of user data with caregivers.
+package care
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Role defines what caregiver may do with data of the owner.
+type Role string
+
+const (
+	// RoleView allows to browse medication box and schedule.
+	RoleView Role = "view"
+	// RoleManage also allows to change data and mark intakes on behalf of the owner.
+	RoleManage Role = "manage"
+)
+
+// GrantStatus is a status of the invitation.
+type GrantStatus string
+
+const (
+	StatusPending  GrantStatus = "pending"
+	StatusAccepted GrantStatus = "accepted"
+)
+
+var (
+	ErrInvalidRole     = errors.New("invalid caregiver role")
+	ErrSelfGrant       = errors.New("user can't be their own caregiver")
+	ErrNotInvitee      = errors.New("invitation is sent to another user")
+	ErrAlreadyAccepted = errors.New("invitation is already accepted")
+	ErrAlreadyBound    = errors.New("invitation is already bound to the user")
+)
+
+// Grant gives a caregiver access to data of the owner (dependant).
+// Grant is effective after the caregiver accepts the invitation.
+// Invitation to an email without account has nil CaregiverID,
+// it is bound to the user registered with InviteeEmail.
+type Grant struct {
+	ID           uuid.UUID
+	OwnerID      uuid.UUID
+	CaregiverID  uuid.UUID
+	InviteeEmail string
+	Role         Role
+	Status       GrantStatus
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// NewGrant creates a pending invitation of the caregiver.
+func NewGrant(ownerID, caregiverID uuid.UUID, role Role, now time.Time) (*Grant, error) {
+	if role != RoleView && role != RoleManage {
+		return nil, ErrInvalidRole
+	}
+	if ownerID == caregiverID {
+		return nil, ErrSelfGrant
+	}
+	return &Grant{
+		ID:          uuid.New(),
+		OwnerID:     ownerID,
+		CaregiverID: caregiverID,
+		Role:        role,
+		Status:      StatusPending,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}, nil
+}
+
+// NewInvitation creates a pending invitation of the caregiver who has no account yet.
+func NewInvitation(ownerID uuid.UUID, email string, role Role, now time.Time) (*Grant, error) {
+	if role != RoleView && role != RoleManage {
+		return nil, ErrInvalidRole
+	}
+	return &Grant{
+		ID:           uuid.New(),
+		OwnerID:      ownerID,
+		CaregiverID:  uuid.Nil,
+		InviteeEmail: email,
+		Role:         role,
+		Status:       StatusPending,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}, nil
+}
+
+// Bind makes the invitation sent by email pending for the registered caregiver.
+func (g *Grant) Bind(caregiverID uuid.UUID, now time.Time) error {
+	if g.IsBound() {
+		return ErrAlreadyBound
+	}
+	if g.OwnerID == caregiverID {
+		return ErrSelfGrant
+	}
+	g.CaregiverID = caregiverID
+	g.UpdatedAt = now
+	return nil
+}
+
+// IsBound checks if the invitation is sent to the registered user.
+func (g *Grant) IsBound() bool {
+	return g.CaregiverID != uuid.Nil
+}
+
+// Accept makes the grant effective, only the invited caregiver can accept it.
+func (g *Grant) Accept(caregiverID uuid.UUID, now time.Time) error {
+	if g.CaregiverID != caregiverID {
+		return ErrNotInvitee
+	}
+	if g.IsAccepted() {
+		return ErrAlreadyAccepted
+	}
+	g.Status = StatusAccepted
+	g.UpdatedAt = now
+	return nil
+}
+
+// ChangeRole changes the role, the caregiver keeps the accepted grant.
+func (g *Grant) ChangeRole(role Role, now time.Time) error {
+	if role != RoleView && role != RoleManage {
+		return ErrInvalidRole
+	}
+	g.Role = role
+	g.UpdatedAt = now
+	return nil
+}
+
+// IsAccepted checks if the caregiver accepted the invitation.
+func (g *Grant) IsAccepted() bool {
+	return g.Status == StatusAccepted
+}
+
+// IsParty checks if the user is the owner or the caregiver of the grant.
+func (g *Grant) IsParty(userID uuid.UUID) bool {
+	return g.OwnerID == userID || g.CaregiverID == userID
+}
@@ -0,0 +1,22 @@
+package care
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+var ErrNoGrantFound = errors.New("no caregiver grant found")
+
+type GrantRepository interface {
+	Save(ctx context.Context, grant *Grant) error
+	FindByID(ctx context.Context, grantID uuid.UUID) (*Grant, error)
+	// FindByPair returns the grant of the caregiver to data of the owner.
+	FindByPair(ctx context.Context, ownerID, caregiverID uuid.UUID) (*Grant, error)
+	// FindByUser returns grants where the user is the owner or the caregiver.
+	FindByUser(ctx context.Context, userID uuid.UUID) ([]*Grant, error)
+	// FindByInvitee returns invitations sent to the email which are not bound to a user.
+	FindByInvitee(ctx context.Context, email string) ([]*Grant, error)
+	Delete(ctx context.Context, grantID uuid.UUID) error
+}
@@ -0,0 +1,60 @@
+// Package profile implements dependant profiles managed under one account.
+package profile
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+const maxNameLength = 64
+
+var ErrInvalidName = errors.New("invalid profile name")
+
+// Profile is a person without own account (e.g. a child) whose medicines
+// are managed by the account. Data of the profile in other services is
+// scoped by the profile ID, the account itself is scoped by the account ID.
+type Profile struct {
+	ID        uuid.UUID
+	AccountID uuid.UUID
+	Name      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// NewProfile creates a profile under the account.
+func NewProfile(accountID uuid.UUID, name string, now time.Time) (*Profile, error) {
+	name, err := validateName(name)
+	if err != nil {
+		return nil, err
+	}
+	return &Profile{
+		ID:        uuid.New(),
+		AccountID: accountID,
+		Name:      name,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+// Rename changes the name of the profile.
+func (p *Profile) Rename(name string, now time.Time) error {
+	name, err := validateName(name)
+	if err != nil {
+		return err
+	}
+	p.Name = name
+	p.UpdatedAt = now
+	return nil
+}
+
+func validateName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > maxNameLength {
+		return "", ErrInvalidName
+	}
+	return name, nil
+}
@@ -0,0 +1,17 @@
+package profile
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+var ErrNoProfileFound = errors.New("no profile found")
+
+type ProfileRepository interface {
+	Save(ctx context.Context, profile *Profile) error
+	FindByID(ctx context.Context, profileID uuid.UUID) (*Profile, error)
+	FindByAccount(ctx context.Context, accountID uuid.UUID) ([]*Profile, error)
+	Delete(ctx context.Context, profileID uuid.UUID) error
+}
@@ -4,4 +4,7 @@ import "github.com/FSO-VK/final-project-vk-backend/internal/auth/presentation/ht
 
 type Config struct {
 	Server http.ServerConfig
+	// Internal is the listener of endpoints for other services,
+	// it must not be published outside the private network.
+	Internal http.ServerConfig
 }
@@ -0,0 +1,70 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/FSO-VK/final-project-vk-backend/internal/auth/domain/care"
+	"github.com/FSO-VK/final-project-vk-backend/internal/utils/cache"
+	"github.com/google/uuid"
+)
+
+type GrantStorage struct {
+	data *cache.Cache[*care.Grant]
+}
+
+func NewGrantStorage() *GrantStorage {
+	return &GrantStorage{
+		data: cache.NewCache[*care.Grant](),
+	}
+}
+
+func (s *GrantStorage) Save(ctx context.Context, grant *care.Grant) error {
+	s.data.Set(grant.ID.String(), grant)
+	return nil
+}
+
+func (s *GrantStorage) FindByID(ctx context.Context, grantID uuid.UUID) (*care.Grant, error) {
+	grant, ok := s.data.Get(grantID.String())
+	if !ok {
+		return nil, care.ErrNoGrantFound
+	}
+	return grant, nil
+}
+
+func (s *GrantStorage) FindByPair(
+	ctx context.Context,
+	ownerID uuid.UUID,
+	caregiverID uuid.UUID,
+) (*care.Grant, error) {
+	for _, grant := range s.data.GetAll() {
+		if grant.OwnerID == ownerID && grant.CaregiverID == caregiverID {
+			return grant, nil
+		}
+	}
+	return nil, care.ErrNoGrantFound
+}
+
+func (s *GrantStorage) FindByUser(ctx context.Context, userID uuid.UUID) ([]*care.Grant, error) {
+	var grants []*care.Grant
+	for _, grant := range s.data.GetAll() {
+		if grant.IsParty(userID) {
+			grants = append(grants, grant)
+		}
+	}
+	return grants, nil
+}
+
+func (s *GrantStorage) FindByInvitee(ctx context.Context, email string) ([]*care.Grant, error) {
+	var grants []*care.Grant
+	for _, grant := range s.data.GetAll() {
+		if !grant.IsBound() && grant.InviteeEmail == email {
+			grants = append(grants, grant)
+		}
+	}
+	return grants, nil
+}
+
+func (s *GrantStorage) Delete(ctx context.Context, grantID uuid.UUID) error {
+	s.data.Delete(grantID.String())
+	return nil
+}
@@ -0,0 +1,57 @@
+package memory
+
+import (
+	"context"
+	"slices"
+
+	"github.com/FSO-VK/final-project-vk-backend/internal/auth/domain/profile"
+	"github.com/FSO-VK/final-project-vk-backend/internal/utils/cache"
+	"github.com/google/uuid"
+)
+
+type ProfileStorage struct {
+	data *cache.Cache[*profile.Profile]
+}
+
+func NewProfileStorage() *ProfileStorage {
+	return &ProfileStorage{
+		data: cache.NewCache[*profile.Profile](),
+	}
+}
+
+func (s *ProfileStorage) Save(ctx context.Context, p *profile.Profile) error {
+	s.data.Set(p.ID.String(), p)
+	return nil
+}
+
+func (s *ProfileStorage) FindByID(
+	ctx context.Context,
+	profileID uuid.UUID,
+) (*profile.Profile, error) {
+	p, ok := s.data.Get(profileID.String())
+	if !ok {
+		return nil, profile.ErrNoProfileFound
+	}
+	return p, nil
+}
+
+func (s *ProfileStorage) FindByAccount(
+	ctx context.Context,
+	accountID uuid.UUID,
+) ([]*profile.Profile, error) {
+	var profiles []*profile.Profile
+	for _, p := range s.data.GetAll() {
+		if p.AccountID == accountID {
+			profiles = append(profiles, p)
+		}
+	}
+	slices.SortFunc(profiles, func(a, b *profile.Profile) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	return profiles, nil
+}
+
+func (s *ProfileStorage) Delete(ctx context.Context, profileID uuid.UUID) error {
+	s.data.Delete(profileID.String())
+	return nil
+}
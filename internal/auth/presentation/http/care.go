@@ -0,0 +1,316 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/FSO-VK/final-project-vk-backend/internal/auth/application"
+	"github.com/FSO-VK/final-project-vk-backend/internal/utils/httputil"
+	"github.com/FSO-VK/final-project-vk-backend/pkg/api"
+	"github.com/valyala/fasthttp"
+)
+
+type InviteCaregiverRequest struct {
+	Email string `json:"email"`
+	Role  string `json:"role"`
+}
+
+type InviteCaregiverResponse struct {
+	GrantID string `json:"grantId"`
+	Status  string `json:"status"`
+}
+
+func (h *AuthHandlers) InviteCaregiver(ctx *fasthttp.RequestCtx) {
+	userID, ok := h.authenticate(ctx)
+	if !ok {
+		return
+	}
+
+	var req InviteCaregiverRequest
+	if !h.readBody(ctx, &req) {
+		return
+	}
+
+	serviceRequest := &application.InviteCaregiverCommand{
+		OwnerID: userID,
+		Email:   req.Email,
+		Role:    req.Role,
+	}
+
+	serviceResult, err := h.app.InviteCaregiver.Execute(ctx, serviceRequest)
+	if err != nil {
+		h.writeCareError(ctx, err)
+		return
+	}
+
+	ctx.SetStatusCode(fasthttp.StatusOK)
+	_ = httputil.FastHTTPWriteJSON(ctx, &api.Response[*InviteCaregiverResponse]{
+		StatusCode: fasthttp.StatusOK,
+		Body: &InviteCaregiverResponse{
+			GrantID: serviceResult.GrantID,
+			Status:  serviceResult.Status,
+		},
+		Error: "",
+	})
+}
+
+type GrantRequest struct {
+	GrantID string `json:"grantId"`
+}
+
+type AcceptCareResponse struct {
+	OwnerID string `json:"ownerId"`
+	Role    string `json:"role"`
+}
+
+func (h *AuthHandlers) AcceptCare(ctx *fasthttp.RequestCtx) {
+	userID, ok := h.authenticate(ctx)
+	if !ok {
+		return
+	}
+
+	var req GrantRequest
+	if !h.readBody(ctx, &req) {
+		return
+	}
+
+	serviceRequest := &application.AcceptCareCommand{
+		UserID:  userID,
+		GrantID: req.GrantID,
+	}
+
+	serviceResult, err := h.app.AcceptCare.Execute(ctx, serviceRequest)
+	if err != nil {
+		h.writeCareError(ctx, err)
+		return
+	}
+
+	ctx.SetStatusCode(fasthttp.StatusOK)
+	_ = httputil.FastHTTPWriteJSON(ctx, &api.Response[*AcceptCareResponse]{
+		StatusCode: fasthttp.StatusOK,
+		Body: &AcceptCareResponse{
+			OwnerID: serviceResult.OwnerID,
+			Role:    serviceResult.Role,
+		},
+		Error: "",
+	})
+}
+
+func (h *AuthHandlers) RevokeCare(ctx *fasthttp.RequestCtx) {
+	userID, ok := h.authenticate(ctx)
+	if !ok {
+		return
+	}
+
+	var req GrantRequest
+	if !h.readBody(ctx, &req) {
+		return
+	}
+
+	serviceRequest := &application.RevokeCareCommand{
+		UserID:  userID,
+		GrantID: req.GrantID,
+	}
+
+	_, err := h.app.RevokeCare.Execute(ctx, serviceRequest)
+	if err != nil {
+		h.writeCareError(ctx, err)
+		return
+	}
+
+	ctx.SetStatusCode(fasthttp.StatusOK)
+	_ = httputil.FastHTTPWriteJSON(ctx, &api.Response[struct{}]{
+		StatusCode: fasthttp.StatusOK,
+		Body:       struct{}{},
+		Error:      "",
+	})
+}
+
+type CareItem struct {
+	GrantID        string `json:"grantId"`
+	OwnerID        string `json:"ownerId"`
+	OwnerEmail     string `json:"ownerEmail"`
+	CaregiverID    string `json:"caregiverId"`
+	CaregiverEmail string `json:"caregiverEmail"`
+	Role           string `json:"role"`
+	Status         string `json:"status"`
+}
+
+type ListCareResponse struct {
+	Caregivers []CareItem `json:"caregivers"`
+	Dependants []CareItem `json:"dependants"`
+}
+
+func (h *AuthHandlers) ListCare(ctx *fasthttp.RequestCtx) {
+	userID, ok := h.authenticate(ctx)
+	if !ok {
+		return
+	}
+
+	serviceRequest := &application.ListCareCommand{
+		UserID: userID,
+	}
+
+	serviceResult, err := h.app.ListCare.Execute(ctx, serviceRequest)
+	if err != nil {
+		h.writeCareError(ctx, err)
+		return
+	}
+
+	ctx.SetStatusCode(fasthttp.StatusOK)
+	_ = httputil.FastHTTPWriteJSON(ctx, &api.Response[*ListCareResponse]{
+		StatusCode: fasthttp.StatusOK,
+		Body: &ListCareResponse{
+			Caregivers: careItemsToHTTP(serviceResult.Caregivers),
+			Dependants: careItemsToHTTP(serviceResult.Dependants),
+		},
+		Error: "",
+	})
+}
+
+type CheckCareAccessResponse struct {
+	UserID  string `json:"userId"`
+	ActorID string `json:"actorId"`
+	Role    string `json:"role"`
+}
+
+// CheckCareAccess is used by other services to check that user of the session
+// may act on behalf of the user passed in ownerId query parameter.
+func (h *AuthHandlers) CheckCareAccess(ctx *fasthttp.RequestCtx) {
+	userID, ok := h.authenticate(ctx)
+	if !ok {
+		return
+	}
+
+	serviceRequest := &application.CheckCareAccessCommand{
+		CaregiverID: userID,
+		OwnerID:     string(ctx.QueryArgs().Peek("ownerId")),
+	}
+
+	serviceResult, err := h.app.CheckCareAccess.Execute(ctx, serviceRequest)
+	if err != nil {
+		h.writeCareError(ctx, err)
+		return
+	}
+
+	ctx.SetStatusCode(fasthttp.StatusOK)
+	_ = httputil.FastHTTPWriteJSON(ctx, &api.Response[*CheckCareAccessResponse]{
+		StatusCode: fasthttp.StatusOK,
+		Body: &CheckCareAccessResponse{
+			UserID:  serviceRequest.OwnerID,
+			ActorID: userID,
+			Role:    serviceResult.Role,
+		},
+		Error: "",
+	})
+}
+
+// authenticate returns id of the user of the session cookie,
+// response is written if user is not authenticated.
+func (h *AuthHandlers) authenticate(ctx *fasthttp.RequestCtx) (string, bool) {
+	sessionID := ctx.Request.Header.Cookie(SessionCookieKey)
+	if len(sessionID) == 0 {
+		h.writeError(ctx, fasthttp.StatusUnauthorized, MsgUnauthorized)
+		return "", false
+	}
+
+	serviceResult, err := h.app.CheckAuth.Execute(ctx, &application.CheckAuthCommand{
+		SessionID: string(sessionID),
+	})
+	if errors.Is(err, application.ErrNoValidSession) ||
+		errors.Is(err, application.ErrNoSessionFound) {
+		h.writeError(ctx, fasthttp.StatusUnauthorized, MsgUnauthorized)
+		return "", false
+	} else if err != nil {
+		h.logger.WithError(err).Error("Failed to check auth")
+		h.writeError(ctx, fasthttp.StatusInternalServerError, api.MsgServerError)
+		return "", false
+	}
+	if !serviceResult.IsAuthenticated {
+		h.writeError(ctx, fasthttp.StatusUnauthorized, MsgUnauthorized)
+		return "", false
+	}
+	return serviceResult.UserID, true
+}
+
+// readBody decodes JSON body into req, response is written on failure.
+func (h *AuthHandlers) readBody(ctx *fasthttp.RequestCtx, req any) bool {
+	body := ctx.PostBody()
+	if len(body) == 0 {
+		h.writeError(ctx, fasthttp.StatusBadRequest, api.MsgNoBody)
+		return false
+	}
+	err := json.Unmarshal(body, req)
+	if err != nil {
+		h.logger.WithError(err).Errorf("Failed to read request body: %v", err)
+		h.writeError(ctx, fasthttp.StatusBadRequest, api.MsgNoBody)
+		return false
+	}
+	return true
+}
+
+func (h *AuthHandlers) writeCareError(ctx *fasthttp.RequestCtx, err error) {
+	var (
+		statusCode int
+		errMsg     api.ErrorType
+	)
+	switch {
+	case errors.Is(err, application.ErrInvalidCareCmd),
+		errors.Is(err, application.ErrInvalidCareRole):
+		statusCode, errMsg = fasthttp.StatusBadRequest, MsgInvalidCare
+	case errors.Is(err, application.ErrNoGrant):
+		statusCode, errMsg = fasthttp.StatusNotFound, MsgNoGrant
+	case errors.Is(err, application.ErrNoCareAccess):
+		statusCode, errMsg = fasthttp.StatusForbidden, MsgNoCareAccess
+	case errors.Is(err, application.ErrCareNotAllowed):
+		statusCode, errMsg = fasthttp.StatusConflict, MsgCareNotAllowed
+	default:
+		h.logger.WithError(err).Error("Failed to process caregiver request")
+		statusCode, errMsg = fasthttp.StatusInternalServerError, api.MsgServerError
+	}
+	h.writeError(ctx, statusCode, errMsg)
+}
+
+func (h *AuthHandlers) writeError(ctx *fasthttp.RequestCtx, statusCode int, errMsg api.ErrorType) {
+	ctx.SetStatusCode(statusCode)
+	_ = httputil.FastHTTPWriteJSON(ctx, &api.Response[struct{}]{
+		StatusCode: statusCode,
+		Body:       struct{}{},
+		Error:      errMsg,
+	})
+}
+
+func careItemsToHTTP(items []application.CareItem) []CareItem {
+	result := make([]CareItem, 0, len(items))
+	for _, item := range items {
+		result = append(result, CareItem(item))
+	}
+	return result
+}
+
+type ListCaregiversResponse struct {
+	Name         string   `json:"name"`
+	CaregiverIDs []string `json:"caregiverIds"`
+}
+
+// ListCaregivers returns caregivers of the user to other services,
+// it is served by InternalRouter only.
+func (h *AuthHandlers) ListCaregivers(ctx *fasthttp.RequestCtx) {
+	serviceResult, err := h.app.ListCaregivers.Execute(ctx, &application.ListCaregiversCommand{
+		UserID: string(ctx.QueryArgs().Peek("userId")),
+	})
+	if err != nil {
+		h.writeCareError(ctx, err)
+		return
+	}
+
+	ctx.SetStatusCode(fasthttp.StatusOK)
+	_ = httputil.FastHTTPWriteJSON(ctx, &api.Response[*ListCaregiversResponse]{
+		StatusCode: fasthttp.StatusOK,
+		Body: &ListCaregiversResponse{
+			Name:         serviceResult.Name,
+			CaregiverIDs: serviceResult.CaregiverIDs,
+		},
+		Error: "",
+	})
+}
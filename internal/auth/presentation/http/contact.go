@@ -0,0 +1,50 @@
+package http
+
+import (
+	"errors"
+
+	"github.com/FSO-VK/final-project-vk-backend/internal/auth/application"
+	"github.com/FSO-VK/final-project-vk-backend/internal/utils/httputil"
+	"github.com/FSO-VK/final-project-vk-backend/pkg/api"
+	"github.com/valyala/fasthttp"
+)
+
+type ContactResponse struct {
+	UserID string `json:"userId"`
+	Email  string `json:"email"`
+}
+
+// GetContact returns the email of the account to other services,
+// it is served by InternalRouter only.
+func (h *AuthHandlers) GetContact(ctx *fasthttp.RequestCtx) {
+	serviceResult, err := h.app.GetContact.Execute(ctx, &application.GetContactCommand{
+		UserID: string(ctx.QueryArgs().Peek("userId")),
+	})
+	if err != nil {
+		var (
+			statusCode int
+			errMsg     api.ErrorType
+		)
+		switch {
+		case errors.Is(err, application.ErrInvalidContactCmd):
+			statusCode, errMsg = fasthttp.StatusBadRequest, MsgInvalidContact
+		case errors.Is(err, application.ErrNoContact):
+			statusCode, errMsg = fasthttp.StatusNotFound, MsgNoContact
+		default:
+			h.logger.WithError(err).Error("Failed to get contact")
+			statusCode, errMsg = fasthttp.StatusInternalServerError, api.MsgServerError
+		}
+		h.writeError(ctx, statusCode, errMsg)
+		return
+	}
+
+	ctx.SetStatusCode(fasthttp.StatusOK)
+	_ = httputil.FastHTTPWriteJSON(ctx, &api.Response[*ContactResponse]{
+		StatusCode: fasthttp.StatusOK,
+		Body: &ContactResponse{
+			UserID: serviceResult.UserID,
+			Email:  serviceResult.Email,
+		},
+		Error: "",
+	})
+}
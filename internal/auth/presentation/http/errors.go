@@ -3,11 +3,21 @@ package http
 import "github.com/FSO-VK/final-project-vk-backend/pkg/api"
 
 const (
-	MsgWrongCredentials api.ErrorType = "Wrong credentials"
-	MsgLogoutFailed     api.ErrorType = "Logout failed"
-	MsgUnauthorized     api.ErrorType = "Session does not exist"
-	MsgSetCookieFail    api.ErrorType = "Unable to set cookie"
-	MsgInvalidEmail     api.ErrorType = "Invalid email"
-	MsgInvalidPassword  api.ErrorType = "Invalid password"
-	MsgUserAlreadyExist api.ErrorType = "User with this email already exist"
+	MsgWrongCredentials   api.ErrorType = "Wrong credentials"
+	MsgLogoutFailed       api.ErrorType = "Logout failed"
+	MsgUnauthorized       api.ErrorType = "Session does not exist"
+	MsgSetCookieFail      api.ErrorType = "Unable to set cookie"
+	MsgInvalidEmail       api.ErrorType = "Invalid email"
+	MsgInvalidPassword    api.ErrorType = "Invalid password"
+	MsgUserAlreadyExist   api.ErrorType = "User with this email already exist"
+	MsgInvalidCare        api.ErrorType = "Invalid caregiver request"
+	MsgNoGrant            api.ErrorType = "No such caregiver grant"
+	MsgNoCareAccess       api.ErrorType = "No access to data of this user"
+	MsgCareNotAllowed     api.ErrorType = "Action with caregiver grant is not allowed"
+	MsgInvalidProfile     api.ErrorType = "Invalid profile request"
+	MsgInvalidProfileName api.ErrorType = "Invalid profile name"
+	MsgNoProfile          api.ErrorType = "No such profile"
+	MsgNoProfileAccess    api.ErrorType = "No access to this profile"
+	MsgInvalidContact     api.ErrorType = "Invalid contact request"
+	MsgNoContact          api.ErrorType = "No contact of this user"
 )
@@ -0,0 +1,35 @@
+package http
+
+import (
+	"github.com/valyala/fasthttp"
+)
+
+// InternalRouter serves endpoints for other services of the project.
+// They are not authenticated, so the router must be listened on a separate
+// port which is not published outside the private network.
+type InternalRouter struct {
+	*Router
+}
+
+func NewInternalRouter(handlers *AuthHandlers) *InternalRouter {
+	return &InternalRouter{
+		Router: NewRouter(handlers),
+	}
+}
+
+func (r *InternalRouter) GetRouter() fasthttp.RequestHandler {
+	return r.router
+}
+
+func (r *InternalRouter) router(ctx *fasthttp.RequestCtx) {
+	switch string(ctx.Path()) {
+	case "/internal/profile":
+		r.withMethod(r.handlers.GetProfile, MethodGet)(ctx)
+	case "/internal/caregivers":
+		r.withMethod(r.handlers.ListCaregivers, MethodGet)(ctx)
+	case "/internal/contact":
+		r.withMethod(r.handlers.GetContact, MethodGet)(ctx)
+	default:
+		r.handlerNotFound(ctx)
+	}
+}
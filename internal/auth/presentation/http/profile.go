@@ -0,0 +1,215 @@
+package http
+
+import (
+	"errors"
+
+	"github.com/FSO-VK/final-project-vk-backend/internal/auth/application"
+	"github.com/FSO-VK/final-project-vk-backend/internal/utils/httputil"
+	"github.com/FSO-VK/final-project-vk-backend/pkg/api"
+	"github.com/valyala/fasthttp"
+)
+
+type ProfileRequest struct {
+	ProfileID string `json:"profileId"`
+	Name      string `json:"name"`
+}
+
+type ProfileResponse struct {
+	ProfileID string `json:"profileId"`
+	AccountID string `json:"accountId"`
+	Name      string `json:"name"`
+}
+
+type ListProfilesResponse struct {
+	Profiles []*ProfileResponse `json:"profiles"`
+}
+
+func (h *AuthHandlers) CreateProfile(ctx *fasthttp.RequestCtx) {
+	userID, ok := h.authenticate(ctx)
+	if !ok {
+		return
+	}
+
+	var req ProfileRequest
+	if !h.readBody(ctx, &req) {
+		return
+	}
+
+	serviceResult, err := h.app.CreateProfile.Execute(ctx, &application.CreateProfileCommand{
+		AccountID: userID,
+		Name:      req.Name,
+	})
+	if err != nil {
+		h.writeProfileError(ctx, err)
+		return
+	}
+	h.writeProfile(ctx, serviceResult)
+}
+
+func (h *AuthHandlers) RenameProfile(ctx *fasthttp.RequestCtx) {
+	userID, ok := h.authenticate(ctx)
+	if !ok {
+		return
+	}
+
+	var req ProfileRequest
+	if !h.readBody(ctx, &req) {
+		return
+	}
+
+	serviceResult, err := h.app.RenameProfile.Execute(ctx, &application.RenameProfileCommand{
+		AccountID: userID,
+		ProfileID: req.ProfileID,
+		Name:      req.Name,
+	})
+	if err != nil {
+		h.writeProfileError(ctx, err)
+		return
+	}
+	h.writeProfile(ctx, serviceResult)
+}
+
+func (h *AuthHandlers) DeleteProfile(ctx *fasthttp.RequestCtx) {
+	userID, ok := h.authenticate(ctx)
+	if !ok {
+		return
+	}
+
+	var req ProfileRequest
+	if !h.readBody(ctx, &req) {
+		return
+	}
+
+	_, err := h.app.DeleteProfile.Execute(ctx, &application.DeleteProfileCommand{
+		AccountID: userID,
+		ProfileID: req.ProfileID,
+	})
+	if err != nil {
+		h.writeProfileError(ctx, err)
+		return
+	}
+
+	ctx.SetStatusCode(fasthttp.StatusOK)
+	_ = httputil.FastHTTPWriteJSON(ctx, &api.Response[struct{}]{
+		StatusCode: fasthttp.StatusOK,
+		Body:       struct{}{},
+		Error:      "",
+	})
+}
+
+func (h *AuthHandlers) ListProfiles(ctx *fasthttp.RequestCtx) {
+	userID, ok := h.authenticate(ctx)
+	if !ok {
+		return
+	}
+
+	serviceResult, err := h.app.ListProfiles.Execute(ctx, &application.ListProfilesCommand{
+		AccountID: userID,
+	})
+	if err != nil {
+		h.writeProfileError(ctx, err)
+		return
+	}
+
+	response := &ListProfilesResponse{
+		Profiles: make([]*ProfileResponse, 0, len(serviceResult.Profiles)),
+	}
+	for _, p := range serviceResult.Profiles {
+		response.Profiles = append(response.Profiles, profileToHTTP(p))
+	}
+
+	ctx.SetStatusCode(fasthttp.StatusOK)
+	_ = httputil.FastHTTPWriteJSON(ctx, &api.Response[*ListProfilesResponse]{
+		StatusCode: fasthttp.StatusOK,
+		Body:       response,
+		Error:      "",
+	})
+}
+
+type CheckProfileAccessResponse struct {
+	ProfileResponse
+	ActorID string `json:"actorId"`
+	Role    string `json:"role"`
+}
+
+// CheckProfileAccess is used by other services to check that user of the session
+// may access the profile passed in profileId query parameter.
+func (h *AuthHandlers) CheckProfileAccess(ctx *fasthttp.RequestCtx) {
+	userID, ok := h.authenticate(ctx)
+	if !ok {
+		return
+	}
+
+	serviceResult, err := h.app.CheckProfileAccess.Execute(
+		ctx,
+		&application.CheckProfileAccessCommand{
+			UserID:    userID,
+			ProfileID: string(ctx.QueryArgs().Peek("profileId")),
+		},
+	)
+	if err != nil {
+		h.writeProfileError(ctx, err)
+		return
+	}
+
+	ctx.SetStatusCode(fasthttp.StatusOK)
+	_ = httputil.FastHTTPWriteJSON(ctx, &api.Response[*CheckProfileAccessResponse]{
+		StatusCode: fasthttp.StatusOK,
+		Body: &CheckProfileAccessResponse{
+			ProfileResponse: *profileToHTTP(&serviceResult.ProfileResult),
+			ActorID:         userID,
+			Role:            serviceResult.Role,
+		},
+		Error: "",
+	})
+}
+
+// GetProfile returns the profile to other services, it is served by InternalRouter only.
+func (h *AuthHandlers) GetProfile(ctx *fasthttp.RequestCtx) {
+	serviceResult, err := h.app.GetProfile.Execute(ctx, &application.GetProfileCommand{
+		ProfileID: string(ctx.QueryArgs().Peek("profileId")),
+	})
+	if err != nil {
+		h.writeProfileError(ctx, err)
+		return
+	}
+	h.writeProfile(ctx, serviceResult)
+}
+
+func (h *AuthHandlers) writeProfile(ctx *fasthttp.RequestCtx, p *application.ProfileResult) {
+	ctx.SetStatusCode(fasthttp.StatusOK)
+	_ = httputil.FastHTTPWriteJSON(ctx, &api.Response[*ProfileResponse]{
+		StatusCode: fasthttp.StatusOK,
+		Body:       profileToHTTP(p),
+		Error:      "",
+	})
+}
+
+func (h *AuthHandlers) writeProfileError(ctx *fasthttp.RequestCtx, err error) {
+	var (
+		statusCode int
+		errMsg     api.ErrorType
+	)
+	switch {
+	case errors.Is(err, application.ErrInvalidProfileCmd):
+		statusCode, errMsg = fasthttp.StatusBadRequest, MsgInvalidProfile
+	case errors.Is(err, application.ErrInvalidProfileName):
+		statusCode, errMsg = fasthttp.StatusBadRequest, MsgInvalidProfileName
+	case errors.Is(err, application.ErrNoProfile):
+		statusCode, errMsg = fasthttp.StatusNotFound, MsgNoProfile
+	case errors.Is(err, application.ErrNoProfileAccess):
+		statusCode, errMsg = fasthttp.StatusForbidden, MsgNoProfileAccess
+	default:
+		h.logger.WithError(err).Error("Failed to process profile request")
+		statusCode, errMsg = fasthttp.StatusInternalServerError, api.MsgServerError
+	}
+	h.writeError(ctx, statusCode, errMsg)
+}
+
+func profileToHTTP(p *application.ProfileResult) *ProfileResponse {
+	return &ProfileResponse{
+		ProfileID: p.ProfileID,
+		AccountID: p.AccountID,
+		Name:      p.Name,
+	}
+}
@@ -55,6 +55,36 @@ func (r *Router) router(ctx *fasthttp.RequestCtx) {
 		default:
 			r.handlerMethodNotAllowed(ctx)
 		}
+	case "/care":
+		switch method {
+		case string(MethodGet):
+			r.withMethod(r.handlers.ListCare, MethodGet)(ctx)
+		case string(MethodPost):
+			r.withMethod(r.handlers.InviteCaregiver, MethodPost)(ctx)
+		case string(MethodDelete):
+			r.withMethod(r.handlers.RevokeCare, MethodDelete)(ctx)
+		default:
+			r.handlerMethodNotAllowed(ctx)
+		}
+	case "/care/accept":
+		r.withMethod(r.handlers.AcceptCare, MethodPost)(ctx)
+	case "/care/access":
+		r.withMethod(r.handlers.CheckCareAccess, MethodGet)(ctx)
+	case "/profile":
+		switch method {
+		case string(MethodGet):
+			r.withMethod(r.handlers.ListProfiles, MethodGet)(ctx)
+		case string(MethodPost):
+			r.withMethod(r.handlers.CreateProfile, MethodPost)(ctx)
+		case string(MethodPut):
+			r.withMethod(r.handlers.RenameProfile, MethodPut)(ctx)
+		case string(MethodDelete):
+			r.withMethod(r.handlers.DeleteProfile, MethodDelete)(ctx)
+		default:
+			r.handlerMethodNotAllowed(ctx)
+		}
+	case "/profile/access":
+		r.withMethod(r.handlers.CheckProfileAccess, MethodGet)(ctx)
 	default:
 		r.handlerNotFound(ctx)
 	}
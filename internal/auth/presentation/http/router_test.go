@@ -0,0 +1,72 @@
+package http_test
+
+import (
+	"testing"
+
+	"github.com/FSO-VK/final-project-vk-backend/internal/auth/presentation/http"
+	"github.com/valyala/fasthttp"
+)
+
+func TestRouters_InternalEndpoints(t *testing.T) {
+	t.Parallel()
+	handlers := http.NewAuthHandlers(nil, nil)
+
+	tests := []struct {
+		name string // description of this test case
+		// Named input parameters for target function.
+		handler    fasthttp.RequestHandler
+		method     string
+		path       string
+		wantStatus int
+	}{
+		{
+			name:       "Should not serve profile lookup on public listener",
+			handler:    http.NewRouter(handlers).GetRouter(),
+			method:     fasthttp.MethodGet,
+			path:       "/internal/profile?profileId=1",
+			wantStatus: fasthttp.StatusNotFound,
+		},
+		{
+			name:       "Should not serve caregivers lookup on public listener",
+			handler:    http.NewRouter(handlers).GetRouter(),
+			method:     fasthttp.MethodGet,
+			path:       "/internal/caregivers?userId=1",
+			wantStatus: fasthttp.StatusNotFound,
+		},
+		{
+			name:       "Should not serve account email on public listener",
+			handler:    http.NewRouter(handlers).GetRouter(),
+			method:     fasthttp.MethodGet,
+			path:       "/internal/contact?userId=1",
+			wantStatus: fasthttp.StatusNotFound,
+		},
+		{
+			name:       "Should not serve public endpoints on internal listener",
+			handler:    http.NewInternalRouter(handlers).GetRouter(),
+			method:     fasthttp.MethodPost,
+			path:       "/session",
+			wantStatus: fasthttp.StatusNotFound,
+		},
+		{
+			name:       "Should check method of internal endpoint",
+			handler:    http.NewInternalRouter(handlers).GetRouter(),
+			method:     fasthttp.MethodPost,
+			path:       "/internal/profile",
+			wantStatus: fasthttp.StatusMethodNotAllowed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var ctx fasthttp.RequestCtx
+			ctx.Request.Header.SetMethod(tt.method)
+			ctx.Request.SetRequestURI(tt.path)
+
+			tt.handler(&ctx)
+
+			if got := ctx.Response.StatusCode(); got != tt.wantStatus {
+				t.Errorf("%s %s: status = %d, want %d", tt.method, tt.path, got, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -22,12 +22,16 @@ type ServerHTTP struct {
 	logger *logrus.Entry
 }
 
-func NewServerHTTP(conf ServerConfig, router *Router, logger *logrus.Entry) *ServerHTTP {
+func NewServerHTTP(
+	conf ServerConfig,
+	handler fasthttp.RequestHandler,
+	logger *logrus.Entry,
+) *ServerHTTP {
 	return &ServerHTTP{
 		conf: &conf,
 		srv: &fasthttp.Server{
 			Logger:  logger,
-			Handler: RequestInfoMiddleware(router.GetRouter(), logger),
+			Handler: RequestInfoMiddleware(handler, logger),
 		},
 		logger: logger,
 	}
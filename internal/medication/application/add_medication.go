@@ -47,6 +47,9 @@ type AddMedicationCommand struct {
 
 	BarCode string `validate:"omitempty,len=13,numeric"`
 	UserID  string `validate:"required,uuid"`
+	// ActorID is an optional user who performs the action, owner of the box if empty.
+	// It differs from UserID when caregiver acts for the user.
+	ActorID string `validate:"omitempty,uuid"`
 }
 
 // AddMedicationResponse is a response to add a medication.
@@ -78,6 +81,15 @@ func (s *AddMedicationService) Execute(
 		}
 	}
 
+	uuidUserID, err := uuid.Parse(req.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+	createdBy, err := parseActor(req.ActorID, uuidUserID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid actor ID: %w", err)
+	}
+
 	id, err := uuid.NewV7()
 	if err != nil {
 		return nil, fmt.Errorf("failed to create uuid v7: %w", err)
@@ -103,6 +115,8 @@ func (s *AddMedicationService) Execute(
 			ReleaseDate:     release,
 			CreatedAt:       time.Now(),
 			UpdatedAt:       time.Now(),
+			CreatedBy:       createdBy,
+			UpdatedBy:       createdBy,
 			BarCode:         req.BarCode,
 		},
 	)
@@ -110,7 +124,7 @@ func (s *AddMedicationService) Execute(
 		return nil, fmt.Errorf("failed to create medication: %w", err)
 	}
 
-	addedMedication, err := repositoryModifications(ctx, s, req, drug)
+	addedMedication, err := repositoryModifications(ctx, s, uuidUserID, drug)
 	if err != nil {
 		return nil, fmt.Errorf("failed to add medication: %w", err)
 	}
@@ -122,16 +136,12 @@ func (s *AddMedicationService) Execute(
 
 func repositoryModifications(
 	ctx context.Context, s *AddMedicationService,
-	req *AddMedicationCommand, drug *medication.Medication,
+	uuidUserID uuid.UUID, drug *medication.Medication,
 ) (*medication.Medication, error) {
 	addedMedication, err := s.medicationRepo.Create(ctx, drug)
 	if err != nil || addedMedication == nil {
 		return nil, fmt.Errorf("failed to save medication: %w", err)
 	}
-	uuidUserID, err := uuid.Parse(req.UserID)
-	if err != nil {
-		return nil, fmt.Errorf("invalid user ID: %w", err)
-	}
 
 	medicationBox, err := s.medicationBoxRepo.GetMedicationBox(ctx, uuidUserID)
 	if err != nil {
@@ -141,7 +151,7 @@ func repositoryModifications(
 			return nil, fmt.Errorf("failed to create medication box: %w", err)
 		}
 	}
-	medicationBox.AddMedication(addedMedication.GetID())
+	medicationBox.AddMedication(addedMedication.GetID(), addedMedication.GetCreatedBy())
 	err = s.medicationBoxRepo.SetMedicationBox(ctx, medicationBox)
 	if err != nil {
 		return nil, fmt.Errorf("failed to add medication to box: %w", err)
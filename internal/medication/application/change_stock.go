@@ -0,0 +1,190 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/FSO-VK/final-project-vk-backend/internal/medication/domain/medbox"
+	"github.com/FSO-VK/final-project-vk-backend/internal/medication/domain/medication"
+	"github.com/FSO-VK/final-project-vk-backend/internal/medication/domain/stock"
+	"github.com/FSO-VK/final-project-vk-backend/internal/utils/validator"
+	"github.com/google/uuid"
+)
+
+// Stock operations supported by ChangeStock.
+const (
+	StockOperationTake    = "take"
+	StockOperationRestore = "restore"
+)
+
+var (
+	// ErrIncompatibleDoseUnit represents an error when dose unit can't be converted to stock unit.
+	ErrIncompatibleDoseUnit = errors.New("incompatible dose unit")
+	// ErrIdempotencyKeyReused represents an error when the key was used for another operation.
+	ErrIdempotencyKeyReused = errors.New("idempotency key is used for another operation")
+)
+
+// ChangeStock is an interface for changing medication amount by a dose
+// on behalf of other services.
+type ChangeStock interface {
+	Execute(
+		ctx context.Context,
+		cmd *ChangeStockCommand,
+	) (*ChangeStockResponse, error)
+}
+
+// ChangeStockService is a service for changing medication amount by a dose.
+type ChangeStockService struct {
+	medicationRepo    medication.Repository
+	medicationBoxRepo medbox.Repository
+	operationRepo     stock.Repository
+	validator         validator.Validator
+
+	// mu serializes read-modify-write of the medication amount
+	// and the check of idempotency key.
+	mu *sync.Mutex
+}
+
+// NewChangeStockService returns a new ChangeStockService.
+func NewChangeStockService(
+	medicationRepo medication.Repository,
+	medicationBoxRepo medbox.Repository,
+	operationRepo stock.Repository,
+	valid validator.Validator,
+) *ChangeStockService {
+	return &ChangeStockService{
+		medicationRepo:    medicationRepo,
+		medicationBoxRepo: medicationBoxRepo,
+		operationRepo:     operationRepo,
+		validator:         valid,
+		mu:                &sync.Mutex{},
+	}
+}
+
+// ChangeStockCommand is a request to take or restore a dose of medication.
+type ChangeStockCommand struct {
+	UserID         string  `validate:"required,uuid"`
+	ID             string  `validate:"required,uuid"`
+	Operation      string  `validate:"required,oneof=take restore"`
+	Value          float32 `validate:"required,gt=0"`
+	Unit           string  `validate:"required"`
+	IdempotencyKey string  `validate:"required,max=100"`
+	// ActorID is an optional user who takes or restores the dose, owner of the box if empty.
+	ActorID string `validate:"omitempty,uuid"`
+}
+
+// ChangeStockResponse is a response to take or restore a dose of medication.
+type ChangeStockResponse struct {
+	// embedded struct
+	ResponseBase
+
+	// Replayed tells that operation with the key was already applied before.
+	Replayed bool
+}
+
+// Execute applies the dose to the medication amount exactly once per idempotency key.
+func (s *ChangeStockService) Execute(
+	ctx context.Context,
+	req *ChangeStockCommand,
+) (*ChangeStockResponse, error) {
+	valErr := s.validator.ValidateStruct(req)
+	if valErr != nil {
+		return nil, fmt.Errorf("%w: %w", ErrValidationFail, valErr)
+	}
+
+	medicationID, err := uuid.Parse(req.ID)
+	if err != nil {
+		return nil, ErrValidationFail
+	}
+	userID, err := uuid.Parse(req.UserID)
+	if err != nil {
+		return nil, ErrValidationFail
+	}
+	actorID, err := parseActor(req.ActorID, userID)
+	if err != nil {
+		return nil, ErrValidationFail
+	}
+	unit, err := medication.NewMedicationUnit(req.Unit)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrValidationFail, err)
+	}
+	kind := stock.KindTake
+	if req.Operation == StockOperationRestore {
+		kind = stock.KindRestore
+	}
+
+	medBox, err := s.medicationBoxRepo.GetMedicationBox(ctx, userID)
+	if err != nil || !medBox.HasMedication(medicationID) {
+		return nil, ErrNoMedication
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m, err := s.medicationRepo.GetByID(ctx, medicationID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrNoMedication, err)
+	}
+
+	applied, err := s.operationRepo.GetByKey(ctx, req.IdempotencyKey)
+	switch {
+	case err == nil:
+		if applied.MedicationID() != medicationID || applied.Kind() != kind {
+			return nil, ErrIdempotencyKeyReused
+		}
+		return &ChangeStockResponse{
+			ResponseBase: responseBaseMapper(m),
+			Replayed:     true,
+		}, nil
+	case !errors.Is(err, stock.ErrNoOperationFound):
+		return nil, fmt.Errorf("failed to get stock operation: %w", err)
+	}
+
+	if kind == stock.KindTake {
+		err = m.TakeDose(req.Value, unit)
+	} else {
+		err = m.RestoreDose(req.Value, unit)
+	}
+	switch {
+	case errors.Is(err, medication.ErrNotEnoughStock):
+		return nil, fmt.Errorf("%w: %w", ErrNotEnoughMedication, err)
+	case errors.Is(err, medication.ErrIncompatibleUnits):
+		return nil, fmt.Errorf("%w: %w", ErrIncompatibleDoseUnit, err)
+	case err != nil:
+		return nil, fmt.Errorf("failed to change stock: %w", err)
+	}
+	m.SetUpdatedAt(time.Now())
+	m.SetUpdatedBy(actorID)
+
+	// the operation is saved before the medication, so the changed stock
+	// always has its operation and a retry with the key is replayed
+	err = s.operationRepo.Save(ctx, stock.NewOperation(
+		req.IdempotencyKey,
+		medicationID,
+		kind,
+		req.Value,
+		unit.String(),
+		actorID,
+		time.Now(),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to save stock operation: %w", err)
+	}
+
+	savedMedication, err := s.medicationRepo.Update(ctx, m)
+	if err != nil {
+		// release the key, the operation is not applied
+		if delErr := s.operationRepo.Delete(ctx, req.IdempotencyKey); delErr != nil {
+			err = errors.Join(err, delErr)
+		}
+		return nil, fmt.Errorf("failed to update medication: %w", err)
+	}
+
+	return &ChangeStockResponse{
+		ResponseBase: responseBaseMapper(savedMedication),
+		Replayed:     false,
+	}, nil
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/FSO-VK/final-project-vk-backend/internal/medication/domain/medication"
+	"github.com/google/uuid"
 )
 
 // CommandBase contains common fields for commands.
@@ -104,3 +105,12 @@ func GroupsToStrings(groups []medication.Group) []string {
 	}
 	return result
 }
+
+// parseActor returns the user who performs the action,
+// it is the owner of the box if actor is empty.
+func parseActor(actorID string, owner uuid.UUID) (uuid.UUID, error) {
+	if actorID == "" {
+		return owner, nil
+	}
+	return uuid.Parse(actorID)
+}
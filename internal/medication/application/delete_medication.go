@@ -46,6 +46,9 @@ func NewDeleteMedicationService(
 type DeleteMedicationCommand struct {
 	UserID string `validate:"required,uuid"`
 	ID     string `validate:"required,uuid"`
+	// ActorID is an optional user who performs the action, owner of the box if empty.
+	// It differs from UserID when caregiver acts for the user.
+	ActorID string `validate:"omitempty,uuid"`
 }
 
 // DeleteMedicationResponse is a response to delete a medication.
@@ -70,11 +73,15 @@ func (s *DeleteMedicationService) Execute(
 	if err != nil {
 		return nil, fmt.Errorf("invalid user ID: %w", err)
 	}
+	deletedBy, err := parseActor(req.ActorID, uuidUserID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid actor ID: %w", err)
+	}
 	medicationBox, err := s.medicationBoxRepo.GetMedicationBox(ctx, uuidUserID)
 	if err != nil {
 		return nil, fmt.Errorf("user does not have a medication box: %w", err)
 	}
-	err = medicationBox.RemoveMedication(parsedUUID)
+	err = medicationBox.RemoveMedication(parsedUUID, deletedBy)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %w", ErrNoMedication, err)
 	}
@@ -0,0 +1,155 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/FSO-VK/final-project-vk-backend/internal/medication/application/planning"
+	"github.com/FSO-VK/final-project-vk-backend/internal/medication/domain/medbox"
+	"github.com/FSO-VK/final-project-vk-backend/internal/medication/domain/medication"
+	"github.com/FSO-VK/final-project-vk-backend/internal/utils/validator"
+	"github.com/google/uuid"
+)
+
+// ErrFailedToForecast occurs when planned intakes can't be received.
+var ErrFailedToForecast = errors.New("failed to forecast medication stock")
+
+// GetForecast provides a way to get the date when medication runs out.
+type GetForecast interface {
+	Execute(
+		ctx context.Context,
+		command *GetForecastCommand,
+	) (*GetForecastResponse, error)
+}
+
+// GetForecastService is a application service implementing GetForecast interface.
+type GetForecastService struct {
+	medicationRepo    medication.Repository
+	medicationBoxRepo medbox.Repository
+	planningProvider  planning.PlanningService
+	validator         validator.Validator
+	horizon           time.Duration
+}
+
+// NewGetForecastService creates GetForecastService.
+// Horizon limits how far the consumption is projected.
+func NewGetForecastService(
+	medicationRepo medication.Repository,
+	medicationBoxRepo medbox.Repository,
+	planningProvider planning.PlanningService,
+	valid validator.Validator,
+	horizon time.Duration,
+) *GetForecastService {
+	return &GetForecastService{
+		medicationRepo:    medicationRepo,
+		medicationBoxRepo: medicationBoxRepo,
+		planningProvider:  planningProvider,
+		validator:         valid,
+		horizon:           horizon,
+	}
+}
+
+// GetForecastCommand is a command for GetForecast usecase.
+type GetForecastCommand struct {
+	UserID string `validate:"required,uuid"`
+	ID     string `validate:"required,uuid"`
+}
+
+// GetForecastResponse is a response for GetForecast usecase.
+type GetForecastResponse struct {
+	ID          string
+	AmountValue float32
+	AmountUnit  string
+	// RunOutAt is the time of the first planned intake that the stock can't cover.
+	// It is zero if the stock is enough till the horizon.
+	RunOutAt time.Time
+	// DaysLeft is a number of whole days till RunOutAt.
+	DaysLeft int
+	Horizon  time.Time
+}
+
+// Execute runs GetForecast usecase.
+func (s *GetForecastService) Execute(
+	ctx context.Context,
+	req *GetForecastCommand,
+) (*GetForecastResponse, error) {
+	valErr := s.validator.ValidateStruct(req)
+	if valErr != nil {
+		return nil, ErrValidationFail
+	}
+
+	userID, err := uuid.Parse(req.UserID)
+	if err != nil {
+		return nil, ErrValidationFail
+	}
+	medicationID, err := uuid.Parse(req.ID)
+	if err != nil {
+		return nil, ErrValidationFail
+	}
+
+	medBox, err := s.medicationBoxRepo.GetMedicationBox(ctx, userID)
+	if err != nil || !medBox.HasMedication(medicationID) {
+		return nil, ErrNoMedication
+	}
+
+	m, err := s.medicationRepo.GetByID(ctx, medicationID)
+	if err != nil {
+		return nil, ErrFailedToGetMedication
+	}
+
+	now := time.Now()
+	horizon := now.Add(s.horizon)
+	runOutAt, err := forecastRunOut(ctx, s.planningProvider, m, userID, horizon)
+	if err != nil {
+		return nil, err
+	}
+
+	var daysLeft int
+	if !runOutAt.IsZero() {
+		daysLeft = int(runOutAt.Sub(now) / (24 * time.Hour))
+	}
+
+	return &GetForecastResponse{
+		ID:          m.GetID().String(),
+		AmountValue: m.GetAmount().GetValue(),
+		AmountUnit:  m.GetAmount().GetUnit().String(),
+		RunOutAt:    runOutAt,
+		DaysLeft:    daysLeft,
+		Horizon:     horizon,
+	}, nil
+}
+
+// forecastRunOut projects planned intakes till horizon against the medication amount.
+func forecastRunOut(
+	ctx context.Context,
+	planningProvider planning.PlanningService,
+	m *medication.Medication,
+	userID uuid.UUID,
+	horizon time.Time,
+) (time.Time, error) {
+	scheduled, err := planningProvider.ScheduledDoses(ctx, m.GetID(), userID, horizon)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %w", ErrFailedToForecast, err)
+	}
+
+	doses := make([]medication.PlannedDose, 0, len(scheduled))
+	for _, d := range scheduled {
+		unit, err := medication.NewMedicationUnit(d.Unit)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("%w: %w", ErrIncompatibleDoseUnit, err)
+		}
+		doses = append(doses, medication.PlannedDose{
+			TakeAt: d.TakeAt,
+			Value:  d.Value,
+			Unit:   unit,
+		})
+	}
+
+	runOutAt, err := m.RunOutAt(doses)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %w", ErrIncompatibleDoseUnit, err)
+	}
+	return runOutAt, nil
+}
@@ -0,0 +1,107 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/FSO-VK/final-project-vk-backend/internal/medication/domain/medbox"
+	"github.com/FSO-VK/final-project-vk-backend/internal/medication/domain/medication"
+	"github.com/FSO-VK/final-project-vk-backend/internal/utils/validator"
+	"github.com/google/uuid"
+)
+
+// GetMedicationNames provides a way to get names of many medications at once
+// on behalf of other services.
+type GetMedicationNames interface {
+	Execute(
+		ctx context.Context,
+		command *GetMedicationNamesCommand,
+	) (*GetMedicationNamesResponse, error)
+}
+
+// GetMedicationNamesService is a application service implementing GetMedicationNames interface.
+type GetMedicationNamesService struct {
+	medicationRepo    medication.Repository
+	medicationBoxRepo medbox.Repository
+	validator         validator.Validator
+}
+
+// NewGetMedicationNamesService creates GetMedicationNamesService.
+func NewGetMedicationNamesService(
+	medicationRepo medication.Repository,
+	medicationBoxRepo medbox.Repository,
+	valid validator.Validator,
+) *GetMedicationNamesService {
+	return &GetMedicationNamesService{
+		medicationRepo:    medicationRepo,
+		medicationBoxRepo: medicationBoxRepo,
+		validator:         valid,
+	}
+}
+
+// GetMedicationNamesCommand is a command for GetMedicationNames usecase,
+// at most 1000 medications may be requested at once.
+type GetMedicationNamesCommand struct {
+	Medications []MedicationRef `validate:"max=1000,dive"`
+}
+
+// MedicationRef is a medication in the box of the user.
+type MedicationRef struct {
+	UserID string `validate:"required,uuid"`
+	ID     string `validate:"required,uuid"`
+}
+
+// GetMedicationNamesResponse is a response for GetMedicationNames usecase.
+// Names are by medication id, medications missing in boxes of users are omitted.
+type GetMedicationNamesResponse struct {
+	Names map[string]string
+}
+
+// Execute runs GetMedicationNames usecase.
+func (s *GetMedicationNamesService) Execute(
+	ctx context.Context,
+	req *GetMedicationNamesCommand,
+) (*GetMedicationNamesResponse, error) {
+	valErr := s.validator.ValidateStruct(req)
+	if valErr != nil {
+		return nil, fmt.Errorf("%w: %w", ErrValidationFail, valErr)
+	}
+
+	boxes := make(map[uuid.UUID]*medbox.MedicationBox)
+	names := make(map[string]string, len(req.Medications))
+	for _, ref := range req.Medications {
+		userID, err := uuid.Parse(ref.UserID)
+		if err != nil {
+			return nil, ErrValidationFail
+		}
+		medicationID, err := uuid.Parse(ref.ID)
+		if err != nil {
+			return nil, ErrValidationFail
+		}
+
+		box, ok := boxes[userID]
+		if !ok {
+			box, err = s.medicationBoxRepo.GetMedicationBox(ctx, userID)
+			if errors.Is(err, medbox.ErrNoMedicationBoxFound) {
+				continue
+			} else if err != nil {
+				return nil, ErrFailedToGetMedication
+			}
+			boxes[userID] = box
+		}
+		if !slices.Contains(box.GetMedicationsID(), medicationID) {
+			continue
+		}
+
+		m, err := s.medicationRepo.GetByID(ctx, medicationID)
+		if errors.Is(err, medication.ErrNoMedicationFound) {
+			continue
+		} else if err != nil {
+			return nil, ErrFailedToGetMedication
+		}
+		names[ref.ID] = m.GetName().GetName()
+	}
+	return &GetMedicationNamesResponse{Names: names}, nil
+}
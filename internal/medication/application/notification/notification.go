@@ -6,14 +6,27 @@ import (
 	"github.com/google/uuid"
 )
 
+const (
+	// CategoryExpiration is a category of notifications about expiring medications.
+	CategoryExpiration = "expiration"
+	// CategoryRefill is a category of notifications about medications running out.
+	CategoryRefill = "refill"
+)
+
 // NotificationService is service for sending notifications.
 type NotificationService interface {
 	SendNotification(ctx context.Context, notificationInfo NotificationInfo) error
 }
 
 // NotificationInfo contains information of notification.
+// Category lets the user turn notifications off in preferences.
+// Title and Body are rendered by notifications service from Template
+// with Params in the language of the user.
 type NotificationInfo struct {
-	UserID uuid.UUID
-	Title  string
-	Body   string
+	UserID   uuid.UUID
+	Category string
+	Title    string
+	Body     string
+	Template string
+	Params   map[string]any
 }
@@ -2,62 +2,203 @@ package application
 
 import (
 	"context"
+	"fmt"
+	"slices"
 	"time"
 
 	"github.com/FSO-VK/final-project-vk-backend/internal/medication/application/notification"
+	"github.com/FSO-VK/final-project-vk-backend/internal/medication/application/planning"
+	"github.com/FSO-VK/final-project-vk-backend/internal/medication/domain/digest"
 	"github.com/FSO-VK/final-project-vk-backend/internal/medication/domain/medbox"
 	"github.com/FSO-VK/final-project-vk-backend/internal/medication/domain/medication"
+	"github.com/FSO-VK/final-project-vk-backend/pkg/workerpool"
+	"github.com/google/uuid"
 )
 
-// ExpirationNotificationGenerator is an interface for generating notifications for expiration date of medication.
-type ExpirationNotificationGenerator interface {
-	GenerateExpirationNotifications(ctx context.Context) error
+// digestTemplate is a template of digest notification in notifications service.
+const digestTemplate = "medication_digest"
+
+// DigestNotificationGenerator is an interface for generating daily digests of medication boxes.
+type DigestNotificationGenerator interface {
+	GenerateDigestNotifications(ctx context.Context) error
 }
 
-// ExpirationNotificationService implements ExpirationNotification.
-type ExpirationNotificationService struct {
+// DigestNotificationService sends one notification per user about expired,
+// expiring and running out medications. Every medication is announced once.
+type DigestNotificationService struct {
 	medicationRepo       medication.Repository
 	medBoxRepo           medbox.Repository
+	digestRepo           digest.Repository
+	planningProvider     planning.PlanningService
 	notificationProvider notification.NotificationService
+	pool                 *workerpool.Pool
+	expirationDelta      time.Duration
+	refillDelta          time.Duration
 }
 
-// NewExpirationNotificationService creates a new ExpirationNotificationService.
-func NewExpirationNotificationService(
+// NewDigestNotificationService creates a new DigestNotificationService.
+// Medications expiring within expirationDelta and running out within
+// refillDelta from now are in the digest. Digests are sent by workers of the pool.
+func NewDigestNotificationService(
 	medicationRepo medication.Repository,
 	medBoxRepo medbox.Repository,
+	digestRepo digest.Repository,
+	planningProvider planning.PlanningService,
 	notificationProvider notification.NotificationService,
-) *ExpirationNotificationService {
-	return &ExpirationNotificationService{
+	pool *workerpool.Pool,
+	expirationDelta time.Duration,
+	refillDelta time.Duration,
+) *DigestNotificationService {
+	return &DigestNotificationService{
 		medicationRepo:       medicationRepo,
 		medBoxRepo:           medBoxRepo,
+		digestRepo:           digestRepo,
+		planningProvider:     planningProvider,
 		notificationProvider: notificationProvider,
+		pool:                 pool,
+		expirationDelta:      expirationDelta,
+		refillDelta:          refillDelta,
 	}
 }
 
-// GenerateExpirationNotifications generates notifications for expiration date of medication.
-func (g *ExpirationNotificationService) GenerateExpirationNotifications(
-	ctx context.Context,
-	timeDelta time.Duration,
-) error {
-	medications, err := g.medicationRepo.MedicationByExpiration(ctx, timeDelta)
+// GenerateDigestNotifications sends digests of all medication boxes.
+// Boxes are processed in parallel and failed digests don't stop others.
+func (g *DigestNotificationService) GenerateDigestNotifications(ctx context.Context) error {
+	boxes, err := g.medBoxRepo.AllMedicationBoxes(ctx)
 	if err != nil {
 		return err
 	}
 
-	for m := range medications {
-		userID, err := g.medBoxRepo.GetUserByMedicationID(ctx, m.GetID())
+	now := time.Now()
+	// storage of boxes is not locked while digests are sent
+	return workerpool.Run(
+		ctx,
+		g.pool,
+		slices.Collect(boxes),
+		func(ctx context.Context, box *medbox.MedicationBox) error {
+			items, unchecked := g.attentionItems(ctx, box, now)
+			return g.announce(ctx, box.GetUserID(), items, unchecked)
+		},
+	)
+}
+
+// attentionItems returns medications of the box which need attention of the user
+// and unchecked items, medications and reasons which are not checked as lookup failed.
+func (g *DigestNotificationService) attentionItems(
+	ctx context.Context,
+	box *medbox.MedicationBox,
+	now time.Time,
+) ([]digest.Item, []digest.Item) {
+	var items, unchecked []digest.Item
+	for _, medicationID := range box.GetMedicationsID() {
+		m, err := g.medicationRepo.GetByID(ctx, medicationID)
+		if err != nil {
+			for _, reason := range []digest.Reason{
+				digest.ReasonExpired,
+				digest.ReasonExpiring,
+				digest.ReasonLowStock,
+			} {
+				unchecked = append(unchecked, digest.Item{
+					MedicationID: medicationID,
+					Reason:       reason,
+				})
+			}
+			continue
+		}
+		name := m.GetName().GetName()
+
+		expiresAt := m.GetExpirationDate()
+		switch {
+		case expiresAt.IsZero():
+		case expiresAt.Before(now):
+			items = append(items, digest.Item{
+				MedicationID: medicationID,
+				Name:         name,
+				Reason:       digest.ReasonExpired,
+				Date:         expiresAt,
+			})
+		case !expiresAt.After(now.Add(g.expirationDelta)):
+			items = append(items, digest.Item{
+				MedicationID: medicationID,
+				Name:         name,
+				Reason:       digest.ReasonExpiring,
+				Date:         expiresAt,
+			})
+		}
+
+		runOutAt, err := forecastRunOut(
+			ctx,
+			g.planningProvider,
+			m,
+			box.GetUserID(),
+			now.Add(g.refillDelta),
+		)
 		if err != nil {
+			unchecked = append(unchecked, digest.Item{
+				MedicationID: medicationID,
+				Reason:       digest.ReasonLowStock,
+			})
 			continue
 		}
-		info := notification.NotificationInfo{
-			UserID: userID,
-			Title:  "Истекает срок годности " + string(m.GetInternationalName()),
-			Body: "Срок годности препарата " + string(m.GetInternationalName()) +
-				" истекает " + m.GetExpirationDate().Format("02.01.2006"),
+		if runOutAt.IsZero() {
+			continue
 		}
-		if err := g.notificationProvider.SendNotification(ctx, info); err != nil {
+		items = append(items, digest.Item{
+			MedicationID: medicationID,
+			Name:         name,
+			Reason:       digest.ReasonLowStock,
+			Date:         runOutAt,
+		})
+	}
+	return items, unchecked
+}
+
+// announce sends items which were not announced before and remembers all current items.
+// Announced unchecked items are remembered too, so they are not announced again.
+func (g *DigestNotificationService) announce(
+	ctx context.Context,
+	userID uuid.UUID,
+	items []digest.Item,
+	unchecked []digest.Item,
+) error {
+	announced, err := g.digestRepo.GetAnnounced(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get announced medications: %w", err)
+	}
+
+	d := digest.NewDigest(userID, items, announced)
+	if !d.IsEmpty() {
+		if err := g.notificationProvider.SendNotification(ctx, digestNotification(d)); err != nil {
 			return err
 		}
 	}
-	return nil
+	remembered := digest.RememberedKeys(items, unchecked, announced)
+	return g.digestRepo.SetAnnounced(ctx, userID, remembered)
+}
+
+// digestNotification makes a notification of the digest. Digest is sent in expiration
+// category if it has expiration items, so the user can't miss expired medications.
+func digestNotification(d *digest.Digest) notification.NotificationInfo {
+	items := make([]map[string]any, 0, len(d.GetItems()))
+	for _, item := range d.GetItems() {
+		items = append(items, map[string]any{
+			"name":   item.Name,
+			"reason": string(item.Reason),
+			"date":   item.Date.Format(time.RFC3339),
+		})
+	}
+
+	category := notification.CategoryRefill
+	if d.HasReason(digest.ReasonExpired) || d.HasReason(digest.ReasonExpiring) {
+		category = notification.CategoryExpiration
+	}
+	return notification.NotificationInfo{
+		UserID:   d.GetUserID(),
+		Category: category,
+		Template: digestTemplate,
+		Params: map[string]any{
+			"count": len(items),
+			"items": items,
+		},
+	}
 }
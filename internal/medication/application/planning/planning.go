@@ -0,0 +1,28 @@
+// Package planning describes access to medication intake plans.
+package planning
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PlanningService provides access to planned intakes of medications.
+type PlanningService interface {
+	// ScheduledDoses returns intakes of all active plans of the medication
+	// from now till the given time sorted by intake time.
+	ScheduledDoses(
+		ctx context.Context,
+		medicationID uuid.UUID,
+		userID uuid.UUID,
+		to time.Time,
+	) ([]ScheduledDose, error)
+}
+
+// ScheduledDose is a planned intake of a medication.
+type ScheduledDose struct {
+	TakeAt time.Time
+	Value  float32
+	Unit   string
+}
@@ -6,6 +6,7 @@ package application
 type MedicationApplication struct {
 	GetMedicationBox             GetMedicationBox
 	GetMedicationByID            GetMedicationByID
+	GetMedicationNames           GetMedicationNames
 	AddMedication                AddMedication
 	UpdateMedication             UpdateMedication
 	DeleteMedication             DeleteMedication
@@ -13,4 +14,6 @@ type MedicationApplication struct {
 	InstructionAssistant         InstructionAssistant
 	GetInstructionByMedicationID GetInstructionByMedicationID
 	TakeMedication               TakeMedication
+	ChangeStock                  ChangeStock
+	GetForecast                  GetForecast
 }
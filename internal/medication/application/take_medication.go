@@ -53,6 +53,9 @@ type TakeMedicationCommand struct {
 	UserID string  `validate:"required,uuid"`
 	ID     string  `validate:"required,uuid"`
 	Value  float32 `validate:"required,gte=0"`
+	// ActorID is an optional user who performs the action, owner of the box if empty.
+	// It differs from UserID when caregiver acts for the user.
+	ActorID string `validate:"omitempty,uuid"`
 }
 
 // TakeMedicationResponse is a response to take a medication (reduce the amount).
@@ -80,6 +83,10 @@ func (s *TakeMedicationService) Execute(
 	if err != nil {
 		return nil, fmt.Errorf("invalid user ID: %w", err)
 	}
+	takenBy, err := parseActor(req.ActorID, uuidUserID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid actor ID: %w", err)
+	}
 
 	oldMedication, err := s.medicationRepo.GetByID(ctx, id)
 	if err != nil {
@@ -97,6 +104,7 @@ func (s *TakeMedicationService) Execute(
 		return nil, fmt.Errorf("failed to get amount: %w", err)
 	}
 	oldMedication.SetAmount(amount)
+	oldMedication.SetUpdatedBy(takenBy)
 
 	savedMedication, err := s.medicationRepo.Update(ctx, oldMedication)
 	if err != nil {
@@ -54,6 +54,9 @@ type UpdateMedicationCommand struct {
 
 	UserID string `validate:"required,uuid"`
 	ID     string `validate:"required,uuid"`
+	// ActorID is an optional user who performs the action, owner of the box if empty.
+	// It differs from UserID when caregiver acts for the user.
+	ActorID string `validate:"omitempty,uuid"`
 }
 
 // UpdateMedicationResponse is a response to update a medication.
@@ -77,6 +80,15 @@ func (s *UpdateMedicationService) Execute(
 		return nil, fmt.Errorf("%w: %w", ErrUpdateInvalidUUID, err)
 	}
 
+	uuidUserID, err := uuid.Parse(req.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+	updatedBy, err := parseActor(req.ActorID, uuidUserID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid actor ID: %w", err)
+	}
+
 	oldMedication, err := s.medicationRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %w", ErrNoMedication, err)
@@ -86,17 +98,13 @@ func (s *UpdateMedicationService) Execute(
 	if err != nil {
 		return nil, fmt.Errorf("failed to update medication entity: %w", err)
 	}
+	updatedMedication.SetUpdatedBy(updatedBy)
 
 	savedMedication, err := s.medicationRepo.Update(ctx, updatedMedication)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update medication: %w", err)
 	}
 
-	uuidUserID, err := uuid.Parse(req.UserID)
-	if err != nil {
-		return nil, fmt.Errorf("invalid user ID: %w", err)
-	}
-
 	medicationBox, err := s.medicationBoxRepo.GetMedicationBox(ctx, uuidUserID)
 	if err != nil {
 		return nil, fmt.Errorf("user has no medication box: %w", err)
@@ -0,0 +1,120 @@
+// Package digest implements domain layer for daily digest of medication box.
+package digest
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Reason is why medication is in the digest.
+type Reason string
+
+const (
+	// ReasonExpired is for medications past expiration date.
+	ReasonExpired Reason = "expired"
+	// ReasonExpiring is for medications which expire soon.
+	ReasonExpiring Reason = "expiring"
+	// ReasonLowStock is for medications which run out soon according to intake plans.
+	ReasonLowStock Reason = "low_stock"
+)
+
+// Item is a medication which needs attention of the user.
+type Item struct {
+	MedicationID uuid.UUID
+	Name         string
+	Reason       Reason
+	// Date is the expiration date or the date the stock runs out.
+	Date time.Time
+}
+
+// Key identifies what is announced to the user. Expiration of the same
+// medication with another expiration date is another pack. Low stock is
+// announced once while stock is low, the forecast date is not a part of key.
+func (i Item) Key() string {
+	if i.Reason == ReasonLowStock {
+		return i.keyPrefix()
+	}
+	return i.keyPrefix() + ":" + i.Date.Format(time.DateOnly)
+}
+
+// keyPrefix is a part of key shared by items of the medication with the reason.
+func (i Item) keyPrefix() string {
+	return string(i.Reason) + ":" + i.MedicationID.String()
+}
+
+// Digest is a summary of medications of a user to announce at once.
+type Digest struct {
+	userID uuid.UUID
+	items  []Item
+}
+
+// NewDigest creates a digest of items which were not announced yet.
+func NewDigest(userID uuid.UUID, items []Item, announced []string) *Digest {
+	seen := make(map[string]struct{}, len(announced))
+	for _, key := range announced {
+		seen[key] = struct{}{}
+	}
+	fresh := make([]Item, 0, len(items))
+	for _, item := range items {
+		if _, ok := seen[item.Key()]; !ok {
+			fresh = append(fresh, item)
+		}
+	}
+	return &Digest{
+		userID: userID,
+		items:  fresh,
+	}
+}
+
+// GetUserID returns the unique identifier of the user.
+func (d *Digest) GetUserID() uuid.UUID {
+	return d.userID
+}
+
+// GetItems returns medications to announce.
+func (d *Digest) GetItems() []Item {
+	return d.items
+}
+
+// IsEmpty tells whether there is nothing to announce.
+func (d *Digest) IsEmpty() bool {
+	return len(d.items) == 0
+}
+
+// HasReason tells whether the digest has items with the reason.
+func (d *Digest) HasReason(reason Reason) bool {
+	for _, item := range d.items {
+		if item.Reason == reason {
+			return true
+		}
+	}
+	return false
+}
+
+// Keys returns keys of items, they are remembered as announced.
+func Keys(items []Item) []string {
+	keys := make([]string, 0, len(items))
+	for _, item := range items {
+		keys = append(keys, item.Key())
+	}
+	return keys
+}
+
+// RememberedKeys returns keys to remember after the digest of items.
+// Unchecked items are medications and reasons whose state is unknown,
+// e.g. the medication is not got, their announced keys are kept,
+// so a failed check doesn't announce them again as new.
+func RememberedKeys(items []Item, unchecked []Item, announced []string) []string {
+	keys := Keys(items)
+	for _, key := range announced {
+		for _, item := range unchecked {
+			if strings.HasPrefix(key, item.keyPrefix()) {
+				keys = append(keys, key)
+				break
+			}
+		}
+	}
+	return keys
+}
@@ -0,0 +1,158 @@
+package digest_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/FSO-VK/final-project-vk-backend/internal/medication/domain/digest"
+	"github.com/google/uuid"
+)
+
+func TestNewDigest(t *testing.T) {
+	t.Parallel()
+	medicationID := uuid.New()
+	expiresAt := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	expiring := digest.Item{
+		MedicationID: medicationID,
+		Name:         "Аспирин",
+		Reason:       digest.ReasonExpiring,
+		Date:         expiresAt,
+	}
+	lowStock := digest.Item{
+		MedicationID: medicationID,
+		Name:         "Аспирин",
+		Reason:       digest.ReasonLowStock,
+		Date:         expiresAt.AddDate(0, 0, -10),
+	}
+	tests := []struct {
+		name string // description of this test case
+		// Named input parameters for target function.
+		items     []digest.Item
+		announced []digest.Item
+		want      []digest.Reason
+	}{
+		{
+			name:  "Should announce all items first time",
+			items: []digest.Item{expiring, lowStock},
+			want:  []digest.Reason{digest.ReasonExpiring, digest.ReasonLowStock},
+		},
+		{
+			name:      "Should not announce the same pack again",
+			items:     []digest.Item{expiring, lowStock},
+			announced: []digest.Item{expiring, lowStock},
+			want:      nil,
+		},
+		{
+			name: "Should not announce low stock again when forecast moves",
+			items: []digest.Item{{
+				MedicationID: medicationID,
+				Reason:       digest.ReasonLowStock,
+				Date:         lowStock.Date.AddDate(0, 0, 1),
+			}},
+			announced: []digest.Item{lowStock},
+			want:      nil,
+		},
+		{
+			name: "Should announce expired pack after it was expiring",
+			items: []digest.Item{{
+				MedicationID: medicationID,
+				Reason:       digest.ReasonExpired,
+				Date:         expiresAt,
+			}},
+			announced: []digest.Item{expiring},
+			want:      []digest.Reason{digest.ReasonExpired},
+		},
+		{
+			name: "Should announce new pack with another expiration date",
+			items: []digest.Item{{
+				MedicationID: medicationID,
+				Reason:       digest.ReasonExpiring,
+				Date:         expiresAt.AddDate(1, 0, 0),
+			}},
+			announced: []digest.Item{expiring},
+			want:      []digest.Reason{digest.ReasonExpiring},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			d := digest.NewDigest(uuid.New(), tt.items, digest.Keys(tt.announced))
+			var got []digest.Reason
+			for _, item := range d.GetItems() {
+				got = append(got, item.Reason)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("NewDigest() items = %v, want %v", got, tt.want)
+			}
+			if d.IsEmpty() != (len(tt.want) == 0) {
+				t.Errorf("IsEmpty() = %v, want %v", d.IsEmpty(), len(tt.want) == 0)
+			}
+		})
+	}
+}
+
+func TestRememberedKeys(t *testing.T) {
+	t.Parallel()
+	checkedID, uncheckedID := uuid.New(), uuid.New()
+	expiresAt := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	checked := digest.Item{
+		MedicationID: checkedID,
+		Reason:       digest.ReasonExpiring,
+		Date:         expiresAt,
+	}
+	uncheckedExpiring := digest.Item{
+		MedicationID: uncheckedID,
+		Reason:       digest.ReasonExpiring,
+		Date:         expiresAt,
+	}
+	uncheckedLowStock := digest.Item{
+		MedicationID: uncheckedID,
+		Reason:       digest.ReasonLowStock,
+		Date:         expiresAt,
+	}
+	tests := []struct {
+		name string // description of this test case
+		// Named input parameters for target function.
+		items     []digest.Item
+		unchecked []digest.Item
+		announced []digest.Item
+		want      []digest.Item
+	}{
+		{
+			name:      "Should forget announced items which are gone",
+			items:     []digest.Item{checked},
+			unchecked: nil,
+			announced: []digest.Item{checked, uncheckedExpiring},
+			want:      []digest.Item{checked},
+		},
+		{
+			name:  "Should keep announced items of unchecked medication",
+			items: []digest.Item{checked},
+			unchecked: []digest.Item{
+				{MedicationID: uncheckedID, Reason: digest.ReasonExpiring},
+				{MedicationID: uncheckedID, Reason: digest.ReasonLowStock},
+			},
+			announced: []digest.Item{checked, uncheckedExpiring, uncheckedLowStock},
+			want:      []digest.Item{checked, uncheckedExpiring, uncheckedLowStock},
+		},
+		{
+			name:  "Should keep only announced items of unchecked reason",
+			items: []digest.Item{uncheckedExpiring},
+			unchecked: []digest.Item{
+				{MedicationID: uncheckedID, Reason: digest.ReasonLowStock},
+			},
+			announced: []digest.Item{checked, uncheckedLowStock},
+			want:      []digest.Item{uncheckedExpiring, uncheckedLowStock},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := digest.RememberedKeys(tt.items, tt.unchecked, digest.Keys(tt.announced))
+			if want := digest.Keys(tt.want); !slices.Equal(got, want) {
+				t.Errorf("RememberedKeys() = %v, want %v", got, want)
+			}
+		})
+	}
+}
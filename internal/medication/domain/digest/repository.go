@@ -0,0 +1,17 @@
+package digest
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Repository is a domain repository interface that defines data access
+// contract for medications already announced to users.
+type Repository interface {
+	// GetAnnounced returns keys of announced items, empty if nothing was announced.
+	GetAnnounced(ctx context.Context, userID uuid.UUID) ([]string, error)
+	// SetAnnounced replaces keys of announced items, items which are not in
+	// the digest anymore are forgotten and may be announced again.
+	SetAnnounced(ctx context.Context, userID uuid.UUID, keys []string) error
+}
@@ -16,6 +16,9 @@ type MedicationBox struct {
 	id            uuid.UUID
 	userID        uuid.UUID
 	medicationsID []uuid.UUID
+	// updatedBy is the user who last added or removed a medication,
+	// it differs from the owner when caregiver acts for the user.
+	updatedBy uuid.UUID
 }
 
 // NewMedicationBox creates a new medication box aggregate root.
@@ -24,6 +27,7 @@ func NewMedicationBox(userID uuid.UUID) *MedicationBox {
 		id:            uuid.New(),
 		userID:        userID,
 		medicationsID: []uuid.UUID{},
+		updatedBy:     userID,
 	}
 }
 
@@ -42,9 +46,15 @@ func (m *MedicationBox) GetMedicationsID() []uuid.UUID {
 	return m.medicationsID
 }
 
-// AddMedication adds a medication to the medication box.
-func (m *MedicationBox) AddMedication(medicationID uuid.UUID) {
+// GetUpdatedBy returns the user who last changed the medication box.
+func (m *MedicationBox) GetUpdatedBy() uuid.UUID {
+	return m.updatedBy
+}
+
+// AddMedication adds a medication to the medication box on behalf of the actor.
+func (m *MedicationBox) AddMedication(medicationID uuid.UUID, actorID uuid.UUID) {
 	m.medicationsID = append(m.medicationsID, medicationID)
+	m.updatedBy = actorID
 }
 
 // HasMedication checks if a medication is in the medication box.
@@ -54,13 +64,14 @@ func (m *MedicationBox) HasMedication(medicationID uuid.UUID) bool {
 	})
 }
 
-// RemoveMedication removes a medication from the medication box.
-func (m *MedicationBox) RemoveMedication(medicationID uuid.UUID) error {
+// RemoveMedication removes a medication from the medication box on behalf of the actor.
+func (m *MedicationBox) RemoveMedication(medicationID uuid.UUID, actorID uuid.UUID) error {
 	if !m.HasMedication(medicationID) {
 		return ErrNoMedication
 	}
 	m.medicationsID = slices.DeleteFunc(m.medicationsID, func(id uuid.UUID) bool {
 		return id == medicationID
 	})
+	m.updatedBy = actorID
 	return nil
 }
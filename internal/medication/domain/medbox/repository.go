@@ -3,6 +3,7 @@ package medbox
 import (
 	"context"
 	"errors"
+	"iter"
 
 	"github.com/google/uuid"
 )
@@ -17,4 +18,5 @@ type Repository interface {
 	CreateMedicationBox(ctx context.Context, medicationBox *MedicationBox) (*MedicationBox, error)
 	GetMedicationBox(ctx context.Context, UserID uuid.UUID) (*MedicationBox, error)
 	GetUserByMedicationID(ctx context.Context, medicationID uuid.UUID) (uuid.UUID, error)
+	AllMedicationBoxes(ctx context.Context) (iter.Seq[*MedicationBox], error)
 }
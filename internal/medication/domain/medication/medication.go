@@ -27,7 +27,11 @@ type Medication struct {
 	expirationDate  time.Time // срок годности
 	createdAt       time.Time
 	updatedAt       time.Time
-	barCode         string
+	// createdBy and updatedBy are users who added and last changed the medication,
+	// they differ from the owner of the box when caregiver acts for the user.
+	createdBy uuid.UUID
+	updatedBy uuid.UUID
+	barCode   string
 }
 
 // NewMedication creates a new medication.
@@ -45,6 +49,8 @@ func NewMedication(
 	commentary Commentary,
 	createdAt time.Time,
 	updatedAt time.Time,
+	createdBy uuid.UUID,
+	updatedBy uuid.UUID,
 	barCode string,
 ) *Medication {
 	return &Medication{
@@ -61,6 +67,8 @@ func NewMedication(
 		commentary:        commentary,
 		createdAt:         createdAt,
 		updatedAt:         updatedAt,
+		createdBy:         createdBy,
+		updatedBy:         updatedBy,
 		barCode:           barCode,
 	}
 }
@@ -93,6 +101,9 @@ type MedicationDraft struct {
 	CreatedAt time.Time
 	UpdatedAt time.Time
 
+	CreatedBy uuid.UUID
+	UpdatedBy uuid.UUID
+
 	BarCode string
 }
 
@@ -214,10 +225,101 @@ func Parse(draft MedicationDraft) (*Medication, error) {
 		expirationDate:    draft.ExpirationDate,
 		createdAt:         draft.CreatedAt,
 		updatedAt:         draft.UpdatedAt,
+		createdBy:         draft.CreatedBy,
+		updatedBy:         draft.UpdatedBy,
 		barCode:           draft.BarCode,
 	}, nil
 }
 
+// ErrNotEnoughStock is an error when there is less medication than dose requires.
+var ErrNotEnoughStock = errors.New("not enough medication in stock")
+
+// DoseToStockUnits converts a dose in the given unit to the unit of medication amount.
+// Mass units are converted to each other, mass dose of a piece-counted medication
+// is converted using the mass of its single active substance.
+func (m *Medication) DoseToStockUnits(value float32, unit Unit) (float32, error) {
+	stockUnit := m.amount.GetUnit()
+	if unit == stockUnit {
+		return value, nil
+	}
+
+	doseMg, ok := unit.milligrams()
+	if !ok {
+		return 0, ErrIncompatibleUnits
+	}
+	doseMg *= value
+
+	if stockMg, ok := stockUnit.milligrams(); ok {
+		return doseMg / stockMg, nil
+	}
+
+	if stockUnit != Piece || len(m.activeSubstance) != 1 {
+		return 0, ErrIncompatibleUnits
+	}
+	pieceDose := m.activeSubstance[0].GetDose()
+	pieceMg, ok := pieceDose.GetUnit().milligrams()
+	if !ok || pieceDose.GetValue() <= 0 {
+		return 0, ErrIncompatibleUnits
+	}
+	return doseMg / (pieceMg * pieceDose.GetValue()), nil
+}
+
+// TakeDose decreases the medication amount by the dose.
+func (m *Medication) TakeDose(value float32, unit Unit) error {
+	stockValue, err := m.DoseToStockUnits(value, unit)
+	if err != nil {
+		return err
+	}
+	if m.amount.GetValue() < stockValue {
+		return ErrNotEnoughStock
+	}
+	m.amount = Amount{
+		value: m.amount.GetValue() - stockValue,
+		unit:  m.amount.GetUnit(),
+	}
+	return nil
+}
+
+// RestoreDose increases the medication amount by the dose,
+// it compensates the dose taken by mistake.
+func (m *Medication) RestoreDose(value float32, unit Unit) error {
+	stockValue, err := m.DoseToStockUnits(value, unit)
+	if err != nil {
+		return err
+	}
+	m.amount = Amount{
+		value: m.amount.GetValue() + stockValue,
+		unit:  m.amount.GetUnit(),
+	}
+	return nil
+}
+
+// PlannedDose is a dose of medication planned to be taken at the time.
+type PlannedDose struct {
+	TakeAt time.Time
+	Value  float32
+	Unit   Unit
+}
+
+// RunOutAt projects planned doses against the medication amount
+// and returns the time of the first dose that the stock can't cover.
+// Doses are expected to be sorted by time.
+// If the stock covers all doses, it returns the zero time.
+func (m *Medication) RunOutAt(doses []PlannedDose) (time.Time, error) {
+	left := m.amount.GetValue()
+	for _, d := range doses {
+		stockValue, err := m.DoseToStockUnits(d.Value, d.Unit)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if left < stockValue {
+			return d.TakeAt, nil
+		}
+		left -= stockValue
+	}
+	return time.Time{}, nil
+}
+
 // GetID returns the unique identifier of the medication.
 func (m *Medication) GetID() uuid.UUID { return m.id }
 
@@ -278,6 +380,11 @@ func (m *Medication) SetUpdatedAt(date time.Time) {
 	m.updatedAt = date
 }
 
+// SetUpdatedBy updates the user who last changed the medication.
+func (m *Medication) SetUpdatedBy(actorID uuid.UUID) {
+	m.updatedBy = actorID
+}
+
 // GetName returns the name Value Object of the medication.
 func (m *Medication) GetName() Name {
 	return m.name
@@ -338,6 +445,16 @@ func (m *Medication) GetUpdatedAt() time.Time {
 	return m.updatedAt
 }
 
+// GetCreatedBy returns the user who added the medication.
+func (m *Medication) GetCreatedBy() uuid.UUID {
+	return m.createdBy
+}
+
+// GetUpdatedBy returns the user who last changed the medication.
+func (m *Medication) GetUpdatedBy() uuid.UUID {
+	return m.updatedBy
+}
+
 // GetBarCode returns the bar code of the medication.
 func (m *Medication) GetBarCode() string {
 	return m.barCode
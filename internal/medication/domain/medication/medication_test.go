@@ -0,0 +1,181 @@
+package medication_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/FSO-VK/final-project-vk-backend/internal/medication/domain/medication"
+	"github.com/google/uuid"
+)
+
+func TestMedication_TakeDose(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string // description of this test case
+		// Named input parameters for target function.
+		amountValue float32
+		amountUnit  string
+		substance   []medication.ActiveSubstanceDraft
+		doseValue   float32
+		doseUnit    string
+		want        float32
+		wantErr     error
+	}{
+		{
+			name:        "Should take dose in the same unit",
+			amountValue: 20,
+			amountUnit:  "шт.",
+			doseValue:   2,
+			doseUnit:    "шт.",
+			want:        18,
+			wantErr:     nil,
+		},
+		{
+			name:        "Should convert milligrams to grams",
+			amountValue: 10,
+			amountUnit:  "г.",
+			doseValue:   500,
+			doseUnit:    "мг.",
+			want:        9.5,
+			wantErr:     nil,
+		},
+		{
+			name:        "Should convert milligrams to pieces by active substance",
+			amountValue: 20,
+			amountUnit:  "шт.",
+			substance: []medication.ActiveSubstanceDraft{
+				{Name: "Парацетамол", Value: 500, Unit: "мг."},
+			},
+			doseValue: 1000,
+			doseUnit:  "мг.",
+			want:      18,
+			wantErr:   nil,
+		},
+		{
+			name:        "Should reject mass dose of pieces without active substance",
+			amountValue: 20,
+			amountUnit:  "шт.",
+			doseValue:   500,
+			doseUnit:    "мг.",
+			want:        20,
+			wantErr:     medication.ErrIncompatibleUnits,
+		},
+		{
+			name:        "Should reject dose bigger than stock",
+			amountValue: 1,
+			amountUnit:  "шт.",
+			doseValue:   2,
+			doseUnit:    "шт.",
+			want:        1,
+			wantErr:     medication.ErrNotEnoughStock,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			m, err := medication.Parse(medication.MedicationDraft{
+				ID:              uuid.New(),
+				Name:            "Парацетамол",
+				ReleaseForm:     "таблетки",
+				AmountValue:     tt.amountValue,
+				AmountUnit:      tt.amountUnit,
+				ExpirationDate:  time.Now().AddDate(1, 0, 0),
+				ActiveSubstance: tt.substance,
+			})
+			if err != nil {
+				t.Fatalf("could not construct receiver type: %v", err)
+			}
+			unit, err := medication.NewMedicationUnit(tt.doseUnit)
+			if err != nil {
+				t.Fatalf("could not construct dose unit: %v", err)
+			}
+			gotErr := m.TakeDose(tt.doseValue, unit)
+			if !errors.Is(gotErr, tt.wantErr) {
+				t.Fatalf("TakeDose() error = %v, want %v", gotErr, tt.wantErr)
+			}
+			if got := m.GetAmount().GetValue(); got != tt.want {
+				t.Errorf("TakeDose() amount = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMedication_RunOutAt(t *testing.T) {
+	t.Parallel()
+	start := time.Date(2025, time.January, 1, 9, 0, 0, 0, time.UTC)
+	daily := func(days int, value float32, unit medication.Unit) []medication.PlannedDose {
+		doses := make([]medication.PlannedDose, 0, days)
+		for i := range days {
+			doses = append(doses, medication.PlannedDose{
+				TakeAt: start.AddDate(0, 0, i),
+				Value:  value,
+				Unit:   unit,
+			})
+		}
+		return doses
+	}
+	tests := []struct {
+		name string // description of this test case
+		// Named input parameters for target function.
+		amountValue float32
+		doses       []medication.PlannedDose
+		want        time.Time
+		wantErr     error
+	}{
+		{
+			name:        "Should return the first uncovered dose",
+			amountValue: 5,
+			doses:       daily(10, 2, medication.Piece),
+			want:        start.AddDate(0, 0, 2),
+			wantErr:     nil,
+		},
+		{
+			name:        "Should return zero time when stock covers all doses",
+			amountValue: 20,
+			doses:       daily(10, 2, medication.Piece),
+			want:        time.Time{},
+			wantErr:     nil,
+		},
+		{
+			name:        "Should convert doses to stock units",
+			amountValue: 3,
+			doses:       daily(10, 1000, medication.Milligram),
+			want:        start.AddDate(0, 0, 1),
+			wantErr:     nil,
+		},
+		{
+			name:        "Should reject incompatible dose unit",
+			amountValue: 20,
+			doses:       daily(1, 5, medication.Milliliter),
+			want:        time.Time{},
+			wantErr:     medication.ErrIncompatibleUnits,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			m, err := medication.Parse(medication.MedicationDraft{
+				ID:             uuid.New(),
+				Name:           "Парацетамол",
+				ReleaseForm:    "таблетки",
+				AmountValue:    tt.amountValue,
+				AmountUnit:     "шт.",
+				ExpirationDate: time.Now().AddDate(1, 0, 0),
+				ActiveSubstance: []medication.ActiveSubstanceDraft{
+					{Name: "Парацетамол", Value: 500, Unit: "мг."},
+				},
+			})
+			if err != nil {
+				t.Fatalf("could not construct receiver type: %v", err)
+			}
+			got, gotErr := m.RunOutAt(tt.doses)
+			if !errors.Is(gotErr, tt.wantErr) {
+				t.Fatalf("RunOutAt() error = %v, want %v", gotErr, tt.wantErr)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("RunOutAt() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -30,6 +30,7 @@ var (
 	ErrInvalidCommentary        = errors.New("invalid commentary")
 	ErrInvalidExpirationTime    = errors.New("invalid expiration time")
 	ErrInvalidDateRange         = errors.New("expiration date must be greater than release date")
+	ErrIncompatibleUnits        = errors.New("dose unit can't be converted to stock unit")
 )
 
 // Name is a Value Object representing the name of a medication.
@@ -206,6 +207,20 @@ func (u Unit) String() string {
 	return unitToString[u]
 }
 
+// milligrams returns the number of milligrams in one unit.
+// It returns false if unit is not a unit of mass.
+func (u Unit) milligrams() (float32, bool) {
+	switch u {
+	case Milligram:
+		return 1, true
+	case Gram:
+		return 1000, true
+	case UnsetUnit, UnknownUnit, Piece, Milliliter:
+		return 0, false
+	}
+	return 0, false
+}
+
 // Amount is a VO representing the quantity of a medication
 // with its unit of measurement.
 type Amount struct {
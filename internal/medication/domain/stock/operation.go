@@ -0,0 +1,83 @@
+// Package stock implements domain layer for stock operations on medications.
+package stock
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Kind is a VO representing the kind of stock operation.
+type Kind uint
+
+// Enum of stock operation kinds.
+const (
+	KindTake Kind = iota
+	KindRestore
+)
+
+// Operation is an aggregate that represents an applied change of medication amount.
+// It is identified by the idempotency key given by the caller,
+// so the same change is never applied twice.
+type Operation struct {
+	key          string
+	medicationID uuid.UUID
+	kind         Kind
+	value        float32
+	unit         string
+	// actorID is the user who made the change, it differs
+	// from the owner of the medication when caregiver acts for the user.
+	actorID   uuid.UUID
+	createdAt time.Time
+}
+
+// NewOperation creates a new stock operation.
+func NewOperation(
+	key string,
+	medicationID uuid.UUID,
+	kind Kind,
+	value float32,
+	unit string,
+	actorID uuid.UUID,
+	createdAt time.Time,
+) *Operation {
+	return &Operation{
+		key:          key,
+		medicationID: medicationID,
+		kind:         kind,
+		value:        value,
+		unit:         unit,
+		actorID:      actorID,
+		createdAt:    createdAt,
+	}
+}
+
+// Key returns the idempotency key of the operation.
+func (o *Operation) Key() string {
+	return o.key
+}
+
+// MedicationID returns the id of changed medication.
+func (o *Operation) MedicationID() uuid.UUID {
+	return o.medicationID
+}
+
+// Kind returns the kind of the operation.
+func (o *Operation) Kind() Kind {
+	return o.kind
+}
+
+// Dose returns the dose value and unit of the operation.
+func (o *Operation) Dose() (float32, string) {
+	return o.value, o.unit
+}
+
+// ActorID returns the user who made the change.
+func (o *Operation) ActorID() uuid.UUID {
+	return o.actorID
+}
+
+// CreatedAt returns the time the operation was applied.
+func (o *Operation) CreatedAt() time.Time {
+	return o.createdAt
+}
@@ -0,0 +1,25 @@
+package stock
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	// ErrNoOperationFound is an error when an operation is not found.
+	ErrNoOperationFound = errors.New("stock operation not found")
+	// ErrOperationExists is an error when an operation with the same key is already saved.
+	ErrOperationExists = errors.New("stock operation already exists")
+)
+
+// Repository is a domain repository interface that defines
+// data access contract for stock operation aggregate.
+type Repository interface {
+	GetByKey(ctx context.Context, key string) (*Operation, error)
+	// Save saves the operation and returns ErrOperationExists
+	// if an operation with the same key is already saved.
+	Save(ctx context.Context, operation *Operation) error
+	// Delete deletes the operation, it is used to release the key
+	// if the operation is not applied to the medication.
+	Delete(ctx context.Context, key string) error
+}
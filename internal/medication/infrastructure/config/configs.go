@@ -2,14 +2,18 @@
 package config
 
 import (
+	"time"
+
 	dataMatrixClient "github.com/FSO-VK/final-project-vk-backend/internal/medication/infrastructure/datamatrix"
 	llm "github.com/FSO-VK/final-project-vk-backend/internal/medication/infrastructure/llm_chat_bot"
+	planning "github.com/FSO-VK/final-project-vk-backend/internal/medication/infrastructure/planning_client"
 	vidalclient "github.com/FSO-VK/final-project-vk-backend/internal/medication/infrastructure/vidal/client"
 	vidalstorage "github.com/FSO-VK/final-project-vk-backend/internal/medication/infrastructure/vidal/storage/mongo"
 	"github.com/FSO-VK/final-project-vk-backend/internal/medication/presentation/http"
 	notification "github.com/FSO-VK/final-project-vk-backend/internal/utils/notification_client"
 	auth "github.com/FSO-VK/final-project-vk-backend/pkg/auth/client"
 	"github.com/FSO-VK/final-project-vk-backend/pkg/llm/gigachat"
+	"github.com/FSO-VK/final-project-vk-backend/pkg/workerpool"
 )
 
 // Config is a configuration for the medication service.
@@ -22,6 +26,15 @@ type Config struct {
 	Gigachat     gigachat.ClientConfig
 	Assistant    llm.InstructionAssistantConfig
 	Notification notification.ClientConfig
+	Planning     planning.ClientConfig
+	Jobs         workerpool.Config
+	Digest       digest
+}
+
+// digest is a configuration of daily digests of medication boxes.
+type digest struct {
+	// RefillDelta is how long before medication runs out the user is warned.
+	RefillDelta time.Duration
 }
 
 type vidal struct {
@@ -21,8 +21,11 @@ func (a *NotificationProvider) SendNotification(
 	info notification.NotificationInfo,
 ) error {
 	return a.client.SendNotification(ctx, client.NotificationInfo{
-		UserID: info.UserID,
-		Title:  info.Title,
-		Body:   info.Body,
+		UserID:   info.UserID,
+		Category: info.Category,
+		Title:    info.Title,
+		Body:     info.Body,
+		Template: info.Template,
+		Params:   info.Params,
 	})
 }
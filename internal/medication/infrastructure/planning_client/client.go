@@ -0,0 +1,102 @@
+// Package planningclient implements PlanningService interface for getting intake plans from planning service.
+package planningclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/FSO-VK/final-project-vk-backend/internal/medication/application/planning"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// PlanningClient implements PlanningService.
+type PlanningClient struct {
+	client *http.Client
+	cfg    ClientConfig
+	logger *logrus.Entry
+}
+
+// NewPlanningClient creates a new PlanningClient.
+func NewPlanningClient(cfg ClientConfig, logger *logrus.Entry) *PlanningClient {
+	client := &http.Client{
+		Timeout:       cfg.Timeout,
+		Transport:     nil,
+		CheckRedirect: nil,
+		Jar:           nil,
+	}
+	return &PlanningClient{client: client, cfg: cfg, logger: logger}
+}
+
+type scheduledDosesResponse struct {
+	StatusCode int                `json:"statusCode"`
+	Body       scheduledDosesBody `json:"body"`
+}
+
+type scheduledDosesBody struct {
+	Doses []scheduledDose `json:"doses"`
+}
+
+type scheduledDose struct {
+	TakeAt time.Time `json:"takeAt"`
+	Amount struct {
+		Value float32 `json:"value"`
+		Unit  string  `json:"unit"`
+	} `json:"amount"`
+}
+
+// ScheduledDoses implements PlanningService interface.
+func (h *PlanningClient) ScheduledDoses(
+	ctx context.Context,
+	medicationID uuid.UUID,
+	userID uuid.UUID,
+	to time.Time,
+) ([]planning.ScheduledDose, error) {
+	if h.cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, h.cfg.Timeout)
+		defer cancel()
+	}
+
+	query := url.Values{}
+	query.Set("to", to.UTC().Format(time.RFC3339))
+	reqURL := h.cfg.Endpoint + medicationID.String() + "/" + userID.String() +
+		"?" + query.Encode()
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.client.Do(httpReq)
+	if err != nil {
+		h.logger.WithError(err).Warn("planning API request failed")
+		return nil, ErrPlanningServiceUnavailable
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		h.logger.Warnf("planning service responded with %d", resp.StatusCode)
+		return nil, ErrBadResponse
+	}
+
+	var parsedResponse scheduledDosesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsedResponse); err != nil {
+		h.logger.WithError(err).Error("failed to decode planning API response")
+		return nil, fmt.Errorf("%w: %w", ErrBadResponse, err)
+	}
+
+	doses := make([]planning.ScheduledDose, 0, len(parsedResponse.Body.Doses))
+	for _, d := range parsedResponse.Body.Doses {
+		doses = append(doses, planning.ScheduledDose{
+			TakeAt: d.TakeAt,
+			Value:  d.Amount.Value,
+			Unit:   d.Amount.Unit,
+		})
+	}
+	return doses, nil
+}
@@ -0,0 +1,9 @@
+package planningclient
+
+import "time"
+
+// ClientConfig is configuration for planning client.
+type ClientConfig struct {
+	Endpoint string
+	Timeout  time.Duration
+}
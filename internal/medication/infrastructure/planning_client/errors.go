@@ -0,0 +1,12 @@
+package planningclient
+
+import "errors"
+
+var (
+	// ErrPlanningServiceUnavailable is returned when the planning api is unavailable.
+	ErrPlanningServiceUnavailable = errors.New("planning api: service unavailable")
+	// ErrBadResponse is returned when the response status code is not 200 or body is not like expected.
+	ErrBadResponse = errors.New(
+		"planning api: invalid response not 200 or body is not like expected",
+	)
+)
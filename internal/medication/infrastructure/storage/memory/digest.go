@@ -0,0 +1,38 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/FSO-VK/final-project-vk-backend/internal/utils/cache"
+	"github.com/google/uuid"
+)
+
+// DigestStorage is a storage for medications announced in digests.
+type DigestStorage struct {
+	data *cache.Cache[[]string]
+}
+
+// NewDigestStorage returns a new DigestStorage.
+func NewDigestStorage() *DigestStorage {
+	return &DigestStorage{
+		data: cache.NewCache[[]string](),
+	}
+}
+
+// GetAnnounced returns keys of announced items of the user.
+func (s *DigestStorage) GetAnnounced(_ context.Context, userID uuid.UUID) ([]string, error) {
+	keys, _ := s.data.Get(userID.String())
+	return keys, nil
+}
+
+// SetAnnounced replaces keys of announced items of the user.
+func (s *DigestStorage) SetAnnounced(_ context.Context, userID uuid.UUID, keys []string) error {
+	if len(keys) == 0 {
+		if _, ok := s.data.Get(userID.String()); ok {
+			s.data.Delete(userID.String())
+		}
+		return nil
+	}
+	s.data.Set(userID.String(), keys)
+	return nil
+}
@@ -2,6 +2,7 @@ package memory
 
 import (
 	"context"
+	"iter"
 	"sync"
 
 	"github.com/FSO-VK/final-project-vk-backend/internal/medication/domain/medbox"
@@ -87,3 +88,19 @@ func (s *MedicationBoxStorage) GetUserByMedicationID(
 	}
 	return uuid.Nil, medbox.ErrNoMedicationBoxFound
 }
+
+// AllMedicationBoxes returns all medication boxes.
+func (s *MedicationBoxStorage) AllMedicationBoxes(
+	_ context.Context,
+) (iter.Seq[*medbox.MedicationBox], error) {
+	return func(yield func(*medbox.MedicationBox) bool) {
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+
+		for _, medBox := range s.data.GetAll() {
+			if !yield(medBox) {
+				return
+			}
+		}
+	}, nil
+}
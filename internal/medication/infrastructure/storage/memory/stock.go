@@ -0,0 +1,63 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/FSO-VK/final-project-vk-backend/internal/medication/domain/stock"
+	"github.com/FSO-VK/final-project-vk-backend/internal/utils/cache"
+)
+
+// StockOperationStorage is a storage for stock operations.
+type StockOperationStorage struct {
+	data *cache.Cache[*stock.Operation]
+
+	mu *sync.Mutex
+}
+
+// NewStockOperationStorage returns a new StockOperationStorage.
+func NewStockOperationStorage() *StockOperationStorage {
+	return &StockOperationStorage{
+		data: cache.NewCache[*stock.Operation](),
+		mu:   &sync.Mutex{},
+	}
+}
+
+// GetByKey returns an operation by idempotency key.
+func (s *StockOperationStorage) GetByKey(
+	_ context.Context,
+	key string,
+) (*stock.Operation, error) {
+	operation, ok := s.data.Get(key)
+	if !ok {
+		return nil, stock.ErrNoOperationFound
+	}
+	return operation, nil
+}
+
+// Save saves an operation if there is no operation with the same key.
+func (s *StockOperationStorage) Save(
+	_ context.Context,
+	operation *stock.Operation,
+) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.data.Get(operation.Key()); exists {
+		return stock.ErrOperationExists
+	}
+	s.data.Set(operation.Key(), operation)
+	return nil
+}
+
+// Delete deletes an operation by idempotency key.
+func (s *StockOperationStorage) Delete(
+	_ context.Context,
+	key string,
+) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.data.Delete(key)
+	return nil
+}
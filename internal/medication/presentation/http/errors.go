@@ -23,6 +23,18 @@ const (
 	MsgFailedToGetInstructions api.ErrorType = "Failed to get instructions"
 	// MsgFailedToGetInfoFromLLM is a message for failed to get info from LLM.
 	MsgFailedToGetInfoFromLLM api.ErrorType = "Failed to get info from LLM"
+	// MsgNotEnoughMedication is a message for not enough medication to take a dose.
+	MsgNotEnoughMedication api.ErrorType = "Not enough medication"
+	// MsgIncompatibleDoseUnit is a message for dose unit which can't be converted to stock unit.
+	MsgIncompatibleDoseUnit api.ErrorType = "Dose unit is incompatible with medication unit"
+	// MsgIdempotencyKeyReused is a message for idempotency key used for another operation.
+	MsgIdempotencyKeyReused api.ErrorType = "Idempotency key is used for another operation"
+	// MsgMissingIdempotencyKey is a message for missing idempotency key header.
+	MsgMissingIdempotencyKey api.ErrorType = "Missing Idempotency-Key header"
+	// MsgFailedToChangeStock is a message for failed to change medication stock.
+	MsgFailedToChangeStock api.ErrorType = "Failed to change medication stock"
+	// MsgFailedToGetForecast is a message for failed to forecast medication stock.
+	MsgFailedToGetForecast api.ErrorType = "Failed to get medication forecast"
 	// MsgInstructionRestricted is a message for instruction restricted.
 	MsgInstructionRestricted api.ErrorType = "Instruction restricted"
 )
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"time"
 
 	"github.com/FSO-VK/final-project-vk-backend/internal/medication/application"
 	"github.com/FSO-VK/final-project-vk-backend/internal/utils/httputil"
@@ -99,7 +100,8 @@ func (h *MedicationHandlers) AddMedication(w http.ResponseWriter, r *http.Reques
 	logger.Debugf("request json: %+v", reqJSON)
 
 	serviceRequest := &application.AddMedicationCommand{
-		UserID: auth.UserID,
+		UserID:  auth.ProfileID,
+		ActorID: auth.ActorID,
 		CommandBase: application.CommandBase{
 			Name:                reqJSON.Name,
 			InternationalName:   reqJSON.InternationalName,
@@ -221,8 +223,9 @@ func (h *MedicationHandlers) UpdateMedication(w http.ResponseWriter, r *http.Req
 		return
 	}
 	serviceRequest := &application.UpdateMedicationCommand{
-		UserID: auth.UserID,
-		ID:     id,
+		UserID:  auth.ProfileID,
+		ActorID: auth.ActorID,
+		ID:      id,
 		CommandBase: application.CommandBase{
 			Name:                reqJSON.Name,
 			InternationalName:   reqJSON.InternationalName,
@@ -296,8 +299,9 @@ func (h *MedicationHandlers) DeleteMedication(w http.ResponseWriter, r *http.Req
 	id := vars[SlugID]
 
 	serviceRequest := &application.DeleteMedicationCommand{
-		UserID: auth.UserID,
-		ID:     id,
+		UserID:  auth.ProfileID,
+		ActorID: auth.ActorID,
+		ID:      id,
 	}
 
 	_, err = h.app.DeleteMedication.Execute(
@@ -346,7 +350,7 @@ func (h *MedicationHandlers) GetMedicationBox(w http.ResponseWriter, r *http.Req
 		return
 	}
 	command := &application.GetMedicationBoxCommand{
-		UserID: authorization.UserID,
+		UserID: authorization.ProfileID,
 	}
 
 	serviceResponse, err := h.app.GetMedicationBox.Execute(
@@ -491,7 +495,7 @@ func (h *MedicationHandlers) GetMedicationByID(w http.ResponseWriter, r *http.Re
 	id := vars[SlugID]
 
 	command := &application.GetMedicationByIDCommand{
-		UserID: authorization.UserID,
+		UserID: authorization.ProfileID,
 		ID:     id,
 	}
 
@@ -600,6 +604,66 @@ func (h *MedicationHandlers) InternalGetMedicationByID(w http.ResponseWriter, r
 	})
 }
 
+// GetForecastJSONResponse is a response for GetForecast.
+type GetForecastJSONResponse struct {
+	ID     string       `json:"id"`
+	Amount AmountObject `json:"amount"`
+	// RunOutAt and DaysLeft are omitted if the stock is enough till the horizon.
+	RunOutAt string `json:"runOutAt,omitempty"`
+	DaysLeft *int   `json:"daysLeft,omitempty"`
+	Horizon  string `json:"horizon"`
+}
+
+// GetForecast returns the date when medication runs out according to intake plans.
+func (h *MedicationHandlers) GetForecast(w http.ResponseWriter, r *http.Request) {
+	logger := h.getLogger(r)
+
+	authorization, err := httputil.GetAuthFromCtx(r)
+	if err != nil {
+		h.writeResponseUnauthorized(w)
+		return
+	}
+
+	vars := mux.Vars(r)
+	command := &application.GetForecastCommand{
+		UserID: authorization.ProfileID,
+		ID:     vars[SlugID],
+	}
+
+	forecast, err := h.app.GetForecast.Execute(r.Context(), command)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get medication forecast")
+
+		status, body := h.handleForecastServiceError(err)
+
+		w.WriteHeader(status)
+		_ = httputil.NetHTTPWriteJSON(w, body)
+		return
+	}
+
+	response := &GetForecastJSONResponse{
+		ID: forecast.ID,
+		Amount: AmountObject{
+			Value: forecast.AmountValue,
+			Unit:  forecast.AmountUnit,
+		},
+		RunOutAt: "",
+		DaysLeft: nil,
+		Horizon:  forecast.Horizon.UTC().Format(time.RFC3339),
+	}
+	if !forecast.RunOutAt.IsZero() {
+		response.RunOutAt = forecast.RunOutAt.UTC().Format(time.RFC3339)
+		response.DaysLeft = &forecast.DaysLeft
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_ = httputil.NetHTTPWriteJSON(w, &api.Response[any]{
+		StatusCode: http.StatusOK,
+		Body:       response,
+		Error:      "",
+	})
+}
+
 // InstructionAssistantJSONResponse is a response for InstructionAssistant.
 type InstructionAssistantJSONResponse struct {
 	LLMAnswer string `json:"llmAnswer"`
@@ -635,7 +699,7 @@ func (h *MedicationHandlers) InstructionAssistant(w http.ResponseWriter, r *http
 	serviceRequest := &application.InstructionAssistantCommand{
 		UserQuestion: question,
 		MedicationID: id,
-		UserID:       authorization.UserID,
+		UserID:       authorization.ProfileID,
 	}
 
 	serviceResponse, err := h.app.InstructionAssistant.Execute(
@@ -681,7 +745,7 @@ func (h *MedicationHandlers) GetInstruction(w http.ResponseWriter, r *http.Reque
 	id := vars[SlugID]
 
 	command := &application.GetInstructionByMedicationIDCommand{
-		UserID: authorization.UserID,
+		UserID: authorization.ProfileID,
 		ID:     id,
 	}
 
@@ -784,9 +848,10 @@ func (h *MedicationHandlers) TakeMedication(w http.ResponseWriter, r *http.Reque
 		return
 	}
 	serviceRequest := &application.TakeMedicationCommand{
-		UserID: auth.UserID,
-		ID:     id,
-		Value:  reqJSON.Value,
+		UserID:  auth.ProfileID,
+		ActorID: auth.ActorID,
+		ID:      id,
+		Value:   reqJSON.Value,
 	}
 
 	serviceResponse, err := h.app.TakeMedication.Execute(
@@ -833,6 +898,171 @@ func (h *MedicationHandlers) TakeMedication(w http.ResponseWriter, r *http.Reque
 	})
 }
 
+// InternalGetMedicationNamesJSONRequest is a request for InternalGetMedicationNames handler.
+type InternalGetMedicationNamesJSONRequest struct {
+	Medications []MedicationRefObject `json:"medications"`
+}
+
+// MedicationRefObject is a medication in the box of the user.
+type MedicationRefObject struct {
+	ID     string `json:"id"`
+	UserID string `json:"userId"`
+}
+
+// InternalGetMedicationNamesJSONResponse is a response for InternalGetMedicationNames handler,
+// names are by medication id, unknown medications are omitted.
+type InternalGetMedicationNamesJSONResponse struct {
+	Names map[string]string `json:"names"`
+}
+
+// InternalGetMedicationNames is a handler for getting names of many medications at once.
+func (h *MedicationHandlers) InternalGetMedicationNames(w http.ResponseWriter, r *http.Request) {
+	logger := h.getLogger(r)
+
+	var reqJSON InternalGetMedicationNamesJSONRequest
+	defer func() {
+		_ = r.Body.Close()
+	}()
+	if err := json.NewDecoder(r.Body).Decode(&reqJSON); err != nil {
+		logger.WithError(err).Error("Failed to unmarshal request body")
+		w.WriteHeader(http.StatusBadRequest)
+		_ = httputil.NetHTTPWriteJSON(w, &api.Response[any]{
+			StatusCode: http.StatusBadRequest,
+			Body:       struct{}{},
+			Error:      api.MsgBadBody,
+		})
+		return
+	}
+
+	command := &application.GetMedicationNamesCommand{
+		Medications: make([]application.MedicationRef, 0, len(reqJSON.Medications)),
+	}
+	for _, ref := range reqJSON.Medications {
+		command.Medications = append(command.Medications, application.MedicationRef{
+			UserID: ref.UserID,
+			ID:     ref.ID,
+		})
+	}
+
+	serviceResponse, err := h.app.GetMedicationNames.Execute(r.Context(), command)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get medication names")
+
+		status, body := h.handleGetByIDServiceError(err)
+
+		w.WriteHeader(status)
+		_ = httputil.NetHTTPWriteJSON(w, body)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_ = httputil.NetHTTPWriteJSON(w, &api.Response[any]{
+		StatusCode: http.StatusOK,
+		Body: &InternalGetMedicationNamesJSONResponse{
+			Names: serviceResponse.Names,
+		},
+		Error: "",
+	})
+}
+
+// IdempotencyKeyHeader is a header with idempotency key of a stock operation.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// InternalChangeStockJSONRequest is a request for InternalTakeDose and InternalRestoreDose.
+type InternalChangeStockJSONRequest struct {
+	Value float32 `json:"value"`
+	Unit  string  `json:"unit"`
+	// ActorID is the user who takes or restores the dose, owner if empty.
+	ActorID string `json:"actorId"`
+}
+
+// InternalChangeStockJSONResponse is a response for InternalTakeDose and InternalRestoreDose.
+type InternalChangeStockJSONResponse struct {
+	ID       string       `json:"id"`
+	Amount   AmountObject `json:"amount"`
+	Replayed bool         `json:"replayed"`
+}
+
+// InternalTakeDose decreases medication amount by a dose taken by plan.
+func (h *MedicationHandlers) InternalTakeDose(w http.ResponseWriter, r *http.Request) {
+	h.internalChangeStock(w, r, application.StockOperationTake)
+}
+
+// InternalRestoreDose restores medication amount by a dose of canceled intake.
+func (h *MedicationHandlers) InternalRestoreDose(w http.ResponseWriter, r *http.Request) {
+	h.internalChangeStock(w, r, application.StockOperationRestore)
+}
+
+func (h *MedicationHandlers) internalChangeStock(
+	w http.ResponseWriter,
+	r *http.Request,
+	operation string,
+) {
+	logger := h.getLogger(r)
+
+	key := r.Header.Get(IdempotencyKeyHeader)
+	if key == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = httputil.NetHTTPWriteJSON(w, &api.Response[any]{
+			StatusCode: http.StatusBadRequest,
+			Body:       struct{}{},
+			Error:      MsgMissingIdempotencyKey,
+		})
+		return
+	}
+
+	var reqJSON InternalChangeStockJSONRequest
+	defer func() {
+		_ = r.Body.Close()
+	}()
+	if err := json.NewDecoder(r.Body).Decode(&reqJSON); err != nil {
+		logger.WithError(err).Error("Failed to unmarshal request body")
+		w.WriteHeader(http.StatusBadRequest)
+		_ = httputil.NetHTTPWriteJSON(w, &api.Response[any]{
+			StatusCode: http.StatusBadRequest,
+			Body:       struct{}{},
+			Error:      api.MsgBadBody,
+		})
+		return
+	}
+
+	vars := mux.Vars(r)
+	command := &application.ChangeStockCommand{
+		UserID:         vars[SlugUserID],
+		ID:             vars[SlugID],
+		Operation:      operation,
+		Value:          reqJSON.Value,
+		Unit:           reqJSON.Unit,
+		IdempotencyKey: key,
+		ActorID:        reqJSON.ActorID,
+	}
+
+	serviceResponse, err := h.app.ChangeStock.Execute(r.Context(), command)
+	if err != nil {
+		logger.WithError(err).Error("Failed to change medication stock")
+
+		status, body := h.handleChangeStockServiceError(err)
+
+		w.WriteHeader(status)
+		_ = httputil.NetHTTPWriteJSON(w, body)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_ = httputil.NetHTTPWriteJSON(w, &api.Response[any]{
+		StatusCode: http.StatusOK,
+		Body: &InternalChangeStockJSONResponse{
+			ID: serviceResponse.ID,
+			Amount: AmountObject{
+				Value: serviceResponse.AmountValue,
+				Unit:  serviceResponse.AmountUnit,
+			},
+			Replayed: serviceResponse.Replayed,
+		},
+		Error: "",
+	})
+}
+
 // handleAddServiceError maps service errors to HTTP status and API responses using switch.
 func (h *MedicationHandlers) handleAddServiceError(err error) (int, *api.Response[any]) {
 	switch {
@@ -1037,6 +1267,48 @@ func (h *MedicationHandlers) handleGetInstructionByIDServiceError(
 	}
 }
 
+// handleChangeStockServiceError maps stock service errors to HTTP status and API responses.
+func (h *MedicationHandlers) handleChangeStockServiceError(err error) (int, *api.Response[any]) {
+	switch {
+	case errors.Is(err, application.ErrValidationFail):
+		return http.StatusBadRequest, &api.Response[any]{
+			StatusCode: http.StatusBadRequest,
+			Body:       struct{}{},
+			Error:      api.MsgBadBody,
+		}
+	case errors.Is(err, application.ErrNoMedication):
+		return http.StatusNotFound, &api.Response[any]{
+			StatusCode: http.StatusNotFound,
+			Body:       struct{}{},
+			Error:      MsgNoMedication,
+		}
+	case errors.Is(err, application.ErrNotEnoughMedication):
+		return http.StatusConflict, &api.Response[any]{
+			StatusCode: http.StatusConflict,
+			Body:       struct{}{},
+			Error:      MsgNotEnoughMedication,
+		}
+	case errors.Is(err, application.ErrIncompatibleDoseUnit):
+		return http.StatusUnprocessableEntity, &api.Response[any]{
+			StatusCode: http.StatusUnprocessableEntity,
+			Body:       struct{}{},
+			Error:      MsgIncompatibleDoseUnit,
+		}
+	case errors.Is(err, application.ErrIdempotencyKeyReused):
+		return http.StatusConflict, &api.Response[any]{
+			StatusCode: http.StatusConflict,
+			Body:       struct{}{},
+			Error:      MsgIdempotencyKeyReused,
+		}
+	default:
+		return http.StatusInternalServerError, &api.Response[any]{
+			StatusCode: http.StatusInternalServerError,
+			Body:       struct{}{},
+			Error:      MsgFailedToChangeStock,
+		}
+	}
+}
+
 // getLogger returns a logger from the context if exists,
 // otherwise returns a default logger.
 func (h *MedicationHandlers) getLogger(r *http.Request) *logrus.Entry {
@@ -1100,3 +1372,33 @@ func convertToClPhPointers(substances []application.ClPhPointer) []ClPhPointer {
 	}
 	return result
 }
+
+// handleForecastServiceError maps GetForecast service errors to HTTP status and API responses.
+func (h *MedicationHandlers) handleForecastServiceError(err error) (int, *api.Response[any]) {
+	switch {
+	case errors.Is(err, application.ErrValidationFail):
+		return http.StatusBadRequest, &api.Response[any]{
+			StatusCode: http.StatusBadRequest,
+			Body:       struct{}{},
+			Error:      api.MsgBadBody,
+		}
+	case errors.Is(err, application.ErrNoMedication):
+		return http.StatusNotFound, &api.Response[any]{
+			StatusCode: http.StatusNotFound,
+			Body:       struct{}{},
+			Error:      MsgNoMedication,
+		}
+	case errors.Is(err, application.ErrIncompatibleDoseUnit):
+		return http.StatusUnprocessableEntity, &api.Response[any]{
+			StatusCode: http.StatusUnprocessableEntity,
+			Body:       struct{}{},
+			Error:      MsgIncompatibleDoseUnit,
+		}
+	default:
+		return http.StatusInternalServerError, &api.Response[any]{
+			StatusCode: http.StatusInternalServerError,
+			Body:       struct{}{},
+			Error:      MsgFailedToGetForecast,
+		}
+	}
+}
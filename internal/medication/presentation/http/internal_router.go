@@ -1,6 +1,8 @@
 package http
 
 import (
+	"expvar"
+
 	"github.com/FSO-VK/final-project-vk-backend/internal/utils/httputil"
 	"github.com/gorilla/mux"
 )
@@ -11,10 +13,24 @@ func InternalRouter(
 	loggingMw *httputil.LoggingMiddleware,
 ) *mux.Router {
 	r := mux.NewRouter()
+	r.HandleFunc(
+		"/internal/medication/names",
+		medicationHandlers.InternalGetMedicationNames,
+	).Methods("POST")
 	r.HandleFunc(
 		"/internal/medication/{id}/{user_id}",
 		medicationHandlers.InternalGetMedicationByID,
 	).Methods("GET")
+	r.HandleFunc(
+		"/internal/medication/{id}/{user_id}/take",
+		medicationHandlers.InternalTakeDose,
+	).Methods("POST")
+	r.HandleFunc(
+		"/internal/medication/{id}/{user_id}/restore",
+		medicationHandlers.InternalRestoreDose,
+	).Methods("POST")
+	// metrics of notification jobs
+	r.Handle("/debug/vars", expvar.Handler()).Methods("GET")
 	panicMiddleware := httputil.NewPanicRecoveryMiddleware()
 	r.Use(panicMiddleware.Middleware)
 	r.Use(loggingMw.MiddlewareNetHTTP)
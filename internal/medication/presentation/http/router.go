@@ -23,6 +23,7 @@ func Router(
 		Methods("GET")
 	r.HandleFunc("/medication/{id}/instruction", medicationHandlers.GetInstruction).Methods("GET")
 	r.HandleFunc("/medication/{id}/take", medicationHandlers.TakeMedication).Methods("POST")
+	r.HandleFunc("/medication/{id}/forecast", medicationHandlers.GetForecast).Methods("GET")
 
 	panicMiddleware := httputil.NewPanicRecoveryMiddleware()
 	r.Use(panicMiddleware.Middleware)
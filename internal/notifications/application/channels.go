@@ -0,0 +1,276 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	contactProvider "github.com/FSO-VK/final-project-vk-backend/internal/notifications/application/contact_provider"
+	emailProvider "github.com/FSO-VK/final-project-vk-backend/internal/notifications/application/email_provider"
+	provider "github.com/FSO-VK/final-project-vk-backend/internal/notifications/application/notification_provider"
+	telegramProvider "github.com/FSO-VK/final-project-vk-backend/internal/notifications/application/telegram_provider"
+	"github.com/FSO-VK/final-project-vk-backend/internal/notifications/domain/history"
+	"github.com/FSO-VK/final-project-vk-backend/internal/notifications/domain/notification"
+	"github.com/FSO-VK/final-project-vk-backend/internal/notifications/domain/preferences"
+	"github.com/FSO-VK/final-project-vk-backend/internal/notifications/domain/subscriptions"
+	"github.com/FSO-VK/final-project-vk-backend/internal/notifications/domain/telegram"
+	"github.com/google/uuid"
+)
+
+const (
+	// ChannelPush is a channel of push subscriptions of devices of the user.
+	ChannelPush = "push"
+	// ChannelEmail is a channel of email of the user.
+	ChannelEmail = "email"
+	// ChannelTelegram is a channel of Telegram chat linked to the account.
+	ChannelTelegram = "telegram"
+)
+
+// channel delivers notification to the user in one way.
+// Channel which is not available to the user returns no deliveries.
+type channel interface {
+	deliver(
+		ctx context.Context,
+		prefs *preferences.Preferences,
+		content notification.Content,
+	) ([]history.Delivery, error)
+}
+
+// Notifier routes notifications to channels available to the user according
+// to preferences: push goes to every device which is not muted and to linked
+// Telegram chat, email is sent always or only when nothing else is delivered.
+type Notifier struct {
+	push     channel
+	telegram channel
+	email    channel
+}
+
+// NewNotifier returns a new Notifier.
+func NewNotifier(
+	subscriptionsRepo subscriptions.Repository,
+	pushProvider provider.NotificationProvider,
+	telegramRepo telegram.Repository,
+	telegramProvider telegramProvider.TelegramProvider,
+	contactProvider contactProvider.ContactProvider,
+	emailProvider emailProvider.EmailProvider,
+) *Notifier {
+	return &Notifier{
+		push: &pushChannel{
+			subscriptionsRepo: subscriptionsRepo,
+			provider:          pushProvider,
+		},
+		telegram: &telegramChannel{
+			telegramRepo: telegramRepo,
+			provider:     telegramProvider,
+		},
+		email: &emailChannel{
+			contactProvider: contactProvider,
+			provider:        emailProvider,
+		},
+	}
+}
+
+// Notify delivers notification by channels of the user. Failure of one channel
+// or device doesn't stop delivery by others. It returns error along with
+// deliveries if notification is not delivered at all because of failures.
+func (n *Notifier) Notify(
+	ctx context.Context,
+	prefs *preferences.Preferences,
+	content notification.Content,
+) ([]history.Delivery, error) {
+	var (
+		deliveries []history.Delivery
+		errs       []error
+	)
+	for _, c := range []channel{n.push, n.telegram} {
+		channelDeliveries, err := c.deliver(ctx, prefs, content)
+		deliveries = append(deliveries, channelDeliveries...)
+		errs = append(errs, err)
+	}
+
+	mode := prefs.EmailMode()
+	if mode == preferences.EmailAlways ||
+		mode == preferences.EmailFallback && !isDelivered(deliveries) {
+		emailDeliveries, err := n.email.deliver(ctx, prefs, content)
+		deliveries = append(deliveries, emailDeliveries...)
+		errs = append(errs, err)
+	}
+
+	if err := errors.Join(errs...); err != nil && !isDelivered(deliveries) {
+		return deliveries, fmt.Errorf("%w: %w", ErrNotDelivered, err)
+	}
+	return deliveries, nil
+}
+
+// isDelivered tells whether notification is delivered by any channel.
+func isDelivered(deliveries []history.Delivery) bool {
+	for _, delivery := range deliveries {
+		if delivery.Status == DeliverySent {
+			return true
+		}
+	}
+	return false
+}
+
+// pushChannel pushes notification to devices of the user except muted ones,
+// subscriptions which push service doesn't accept anymore are deleted.
+type pushChannel struct {
+	subscriptionsRepo subscriptions.Repository
+	provider          provider.NotificationProvider
+}
+
+func (c *pushChannel) deliver(
+	ctx context.Context,
+	prefs *preferences.Preferences,
+	content notification.Content,
+) ([]history.Delivery, error) {
+	userSubscriptions, err := c.subscriptionsRepo.GetSubscriptionsByUserID(ctx, prefs.UserID())
+	if errors.Is(err, subscriptions.ErrNoSubscriptionsFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get subscriptions: %w", err)
+	}
+
+	var (
+		results = make([]history.Delivery, 0, len(userSubscriptions))
+		errs    []error
+	)
+	for _, subscription := range userSubscriptions {
+		result := history.Delivery{
+			Channel:        ChannelPush,
+			SubscriptionID: subscription.GetID(),
+			Status:         DeliverySent,
+		}
+		if prefs.IsDeviceMuted(subscription.GetID()) {
+			result.Status = DeliveryMuted
+			results = append(results, result)
+			continue
+		}
+
+		notificationToSend := provider.NewNotification(
+			uuid.New(),
+			subscription.GetID(),
+			prefs.UserID(),
+			content,
+		)
+		err := c.provider.PushNotification(ctx, notificationToSend, subscription)
+		switch {
+		case err == nil:
+		case errors.Is(err, provider.ErrSubscriptionGone):
+			result.Status = DeliveryRemoved
+			err = c.subscriptionsRepo.DeleteSubscription(ctx, subscription.GetID())
+			if err != nil && !errors.Is(err, subscriptions.ErrNoSubscriptionsFound) {
+				errs = append(errs, fmt.Errorf("failed to delete subscription: %w", err))
+			}
+		default:
+			result.Status = DeliveryFailed
+			errs = append(errs, err)
+		}
+		results = append(results, result)
+	}
+	return results, errors.Join(errs...)
+}
+
+// emailChannel sends notification to email of the user if the user has one.
+type emailChannel struct {
+	contactProvider contactProvider.ContactProvider
+	provider        emailProvider.EmailProvider
+}
+
+func (c *emailChannel) deliver(
+	ctx context.Context,
+	prefs *preferences.Preferences,
+	content notification.Content,
+) ([]history.Delivery, error) {
+	email, err := c.contactProvider.Email(ctx, prefs.UserID())
+	if errors.Is(err, contactProvider.ErrNoContact) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get email: %w", err)
+	}
+
+	result := history.Delivery{
+		Channel:        ChannelEmail,
+		SubscriptionID: uuid.Nil,
+		Status:         DeliverySent,
+	}
+	if err := c.provider.SendEmail(ctx, email, content); err != nil {
+		result.Status = DeliveryFailed
+		return []history.Delivery{result}, fmt.Errorf("failed to send email: %w", err)
+	}
+	return []history.Delivery{result}, nil
+}
+
+// telegramChannel sends notification to Telegram chat linked to the account,
+// actions of notification become inline buttons. Chat is unlinked
+// if the bot can't write to it anymore.
+type telegramChannel struct {
+	telegramRepo telegram.Repository
+	provider     telegramProvider.TelegramProvider
+}
+
+func (c *telegramChannel) deliver(
+	ctx context.Context,
+	prefs *preferences.Preferences,
+	content notification.Content,
+) ([]history.Delivery, error) {
+	link, err := c.telegramRepo.GetLinkByUserID(ctx, prefs.UserID())
+	if errors.Is(err, telegram.ErrNoLinkFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get telegram link: %w", err)
+	}
+
+	result := history.Delivery{
+		Channel:        ChannelTelegram,
+		SubscriptionID: uuid.Nil,
+		Status:         DeliverySent,
+	}
+	err = c.provider.SendMessage(ctx, link.ChatID(), content, buttonsOf(content))
+	switch {
+	case err == nil:
+	case errors.Is(err, telegramProvider.ErrChatGone):
+		result.Status = DeliveryRemoved
+		err = c.telegramRepo.DeleteLink(ctx, prefs.UserID())
+		if err != nil && !errors.Is(err, telegram.ErrNoLinkFound) {
+			return []history.Delivery{result}, fmt.Errorf("failed to unlink chat: %w", err)
+		}
+	default:
+		result.Status = DeliveryFailed
+		return []history.Delivery{result}, fmt.Errorf("failed to send telegram message: %w", err)
+	}
+	return []history.Delivery{result}, nil
+}
+
+// buttonsOf returns inline buttons for actions of the notification the bot can handle,
+// the bot finds the notification by its id in data of the button.
+func buttonsOf(content notification.Content) []telegramProvider.Button {
+	notificationID := content.Data[dataNotificationID]
+	if notificationID == "" {
+		return nil
+	}
+	buttons := make([]telegramProvider.Button, 0, len(content.Actions))
+	for _, action := range content.Actions {
+		if action.Action != actionTake && action.Action != actionSnooze {
+			continue
+		}
+		buttons = append(buttons, telegramProvider.Button{
+			Title: action.Title,
+			Data:  action.Action + callbackSeparator + notificationID,
+		})
+	}
+	return buttons
+}
+
+// deviceResultOf converts delivery in history to the result of delivery.
+func deviceResultOf(delivery history.Delivery) DeviceResult {
+	result := DeviceResult{
+		Channel:        delivery.Channel,
+		SubscriptionID: "",
+		Status:         delivery.Status,
+	}
+	if delivery.SubscriptionID != uuid.Nil {
+		result.SubscriptionID = delivery.SubscriptionID.String()
+	}
+	return result
+}
@@ -0,0 +1,17 @@
+// Package contactprovider is a package for interface for contacts of users client.
+package contactprovider
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// ErrNoContact is returned when the user has no email.
+var ErrNoContact = errors.New("no contact")
+
+// ContactProvider provides contacts of user accounts.
+type ContactProvider interface {
+	Email(ctx context.Context, userID uuid.UUID) (string, error)
+}
@@ -0,0 +1,147 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	templateProvider "github.com/FSO-VK/final-project-vk-backend/internal/notifications/application/template_provider"
+	"github.com/FSO-VK/final-project-vk-backend/internal/notifications/domain/deferred"
+	"github.com/FSO-VK/final-project-vk-backend/internal/notifications/domain/history"
+	"github.com/FSO-VK/final-project-vk-backend/internal/notifications/domain/notification"
+	"github.com/FSO-VK/final-project-vk-backend/internal/notifications/domain/preferences"
+	"github.com/google/uuid"
+)
+
+// digestType is a type and template of summary of notifications
+// deferred during quiet hours.
+const digestType = "quiet_hours_digest"
+
+// DeferredNotificationsDeliverer is an interface for delivering notifications
+// held back during quiet hours.
+type DeferredNotificationsDeliverer interface {
+	DeliverDeferred(ctx context.Context) error
+}
+
+// DeliverDeferredService delivers due deferred notifications.
+type DeliverDeferredService struct {
+	deferredRepo    deferred.Repository
+	historyRepo     history.Repository
+	preferencesRepo preferences.Repository
+	notifier        *Notifier
+	templates       templateProvider.TemplateProvider
+}
+
+// NewDeliverDeferredService returns a new DeliverDeferredService.
+func NewDeliverDeferredService(
+	deferredRepo deferred.Repository,
+	historyRepo history.Repository,
+	preferencesRepo preferences.Repository,
+	notifier *Notifier,
+	templates templateProvider.TemplateProvider,
+) *DeliverDeferredService {
+	return &DeliverDeferredService{
+		deferredRepo:    deferredRepo,
+		historyRepo:     historyRepo,
+		preferencesRepo: preferencesRepo,
+		notifier:        notifier,
+		templates:       templates,
+	}
+}
+
+// DeliverDeferred delivers notifications whose quiet hours are over.
+// Digest notifications of a user are delivered as one summary.
+// Notifications are removed before delivery, so failed ones are not repeated every run.
+// Statuses of delivery are recorded to history of every delivered notification.
+func (s *DeliverDeferredService) DeliverDeferred(ctx context.Context) error {
+	due, err := s.deferredRepo.Due(ctx, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to get deferred notifications: %w", err)
+	}
+
+	var errs []error
+	digests := make(map[uuid.UUID][]*deferred.Notification)
+	for _, n := range due {
+		if err := s.deferredRepo.Delete(ctx, n.ID()); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if n.IsDigest() {
+			digests[n.UserID()] = append(digests[n.UserID()], n)
+			continue
+		}
+		deliveries, err := s.deliver(ctx, n.UserID(), n.Category(), n.Content())
+		errs = append(errs, err, s.recordDeliveries(ctx, deliveries, n))
+	}
+	for userID, notifications := range digests {
+		deliveries, err := s.deliver(ctx, userID, "", digestOf(notifications))
+		errs = append(errs, err, s.recordDeliveries(ctx, deliveries, notifications...))
+	}
+	return errors.Join(errs...)
+}
+
+// recordDeliveries saves statuses of delivery to history of the notifications.
+func (s *DeliverDeferredService) recordDeliveries(
+	ctx context.Context,
+	deliveries []history.Delivery,
+	notifications ...*deferred.Notification,
+) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	var errs []error
+	for _, n := range notifications {
+		sentNotification, err := s.historyRepo.GetByID(ctx, n.ID())
+		if errors.Is(err, history.ErrNoNotificationFound) {
+			continue
+		} else if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		sentNotification.SetDeliveries(deliveries)
+		errs = append(errs, s.historyRepo.Save(ctx, sentNotification))
+	}
+	return errors.Join(errs...)
+}
+
+// deliver sends notification unless its category was turned off after it was deferred.
+func (s *DeliverDeferredService) deliver(
+	ctx context.Context,
+	userID uuid.UUID,
+	category string,
+	content notification.Content,
+) ([]history.Delivery, error) {
+	prefs, err := preferencesOf(ctx, s.preferencesRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !prefs.IsEnabled(preferences.Category(category)) {
+		return nil, nil
+	}
+	content, err = localize(s.templates, content, prefs.Locale())
+	if err != nil {
+		return nil, err
+	}
+	return s.notifier.Notify(ctx, prefs, content)
+}
+
+// digestOf makes one summary of notifications in order they were received.
+func digestOf(notifications []*deferred.Notification) notification.Content {
+	if len(notifications) == 1 {
+		return notifications[0].Content()
+	}
+	titles := make([]string, 0, len(notifications))
+	for _, n := range notifications {
+		titles = append(titles, n.Content().Title)
+	}
+	return notification.Content{
+		Type:     digestType,
+		Template: digestType,
+		Params: map[string]any{
+			"count":  len(notifications),
+			"titles": strings.Join(titles, "\n"),
+		},
+	}
+}
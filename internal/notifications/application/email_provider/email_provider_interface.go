@@ -0,0 +1,13 @@
+// Package emailprovider is a package for interface for email client.
+package emailprovider
+
+import (
+	"context"
+
+	"github.com/FSO-VK/final-project-vk-backend/internal/notifications/domain/notification"
+)
+
+// EmailProvider sends notifications by email, the letter is rendered from content.
+type EmailProvider interface {
+	SendEmail(ctx context.Context, to string, content notification.Content) error
+}
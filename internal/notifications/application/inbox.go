@@ -0,0 +1,291 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/FSO-VK/final-project-vk-backend/internal/notifications/domain/history"
+	"github.com/FSO-VK/final-project-vk-backend/internal/utils/validator"
+	"github.com/google/uuid"
+)
+
+// defaultPageSize is a number of notifications in a page if limit is not set.
+const defaultPageSize = 20
+
+var (
+	// ErrInvalidPage is an error when offset or limit of the page are not valid.
+	ErrInvalidPage = errors.New("invalid page")
+	// ErrNoNotification is an error when notification is not found in history of the user.
+	ErrNoNotification = errors.New("notification not found")
+)
+
+// InboxNotification is a notification in history of the user.
+// ReadAt is nil for unread notification.
+type InboxNotification struct {
+	ID         string
+	Category   string
+	Type       string
+	Title      string
+	Body       string
+	URL        string
+	Data       map[string]string
+	Deliveries []DeviceResult
+	CreatedAt  time.Time
+	ReadAt     *time.Time
+}
+
+// ListNotifications is an interface for getting history of notifications.
+type ListNotifications interface {
+	Execute(
+		ctx context.Context,
+		cmd *ListNotificationsCommand,
+	) (*ListNotificationsResponse, error)
+}
+
+// ListNotificationsService is a service for getting history of notifications.
+type ListNotificationsService struct {
+	historyRepo history.Repository
+	validator   validator.Validator
+}
+
+// NewListNotificationsService returns a new ListNotificationsService.
+func NewListNotificationsService(
+	historyRepo history.Repository,
+	valid validator.Validator,
+) *ListNotificationsService {
+	return &ListNotificationsService{
+		historyRepo: historyRepo,
+		validator:   valid,
+	}
+}
+
+// ListNotificationsCommand is a request to get a page of notifications, newest first.
+// 20 notifications are returned if Limit is zero.
+type ListNotificationsCommand struct {
+	UserID string
+	Offset int `validate:"min=0"`
+	Limit  int `validate:"min=0,max=100"`
+}
+
+// ListNotificationsResponse is a page of notifications with total number
+// of notifications and number of unread ones.
+type ListNotificationsResponse struct {
+	Notifications []InboxNotification
+	Total         int
+	Unread        int
+}
+
+// Execute executes the ListNotifications command.
+func (s *ListNotificationsService) Execute(
+	ctx context.Context,
+	req *ListNotificationsCommand,
+) (*ListNotificationsResponse, error) {
+	valErr := s.validator.ValidateStruct(req)
+	if valErr != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPage, valErr)
+	}
+	parsedUUID, err := uuid.Parse(req.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid uuid format: %w", err)
+	}
+
+	limit := req.Limit
+	if limit == 0 {
+		limit = defaultPageSize
+	}
+	notifications, total, err := s.historyRepo.ListByUserID(ctx, parsedUUID, req.Offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notifications: %w", err)
+	}
+	unread, err := s.historyRepo.CountUnread(ctx, parsedUUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count unread notifications: %w", err)
+	}
+
+	response := &ListNotificationsResponse{
+		Notifications: make([]InboxNotification, 0, len(notifications)),
+		Total:         total,
+		Unread:        unread,
+	}
+	for _, n := range notifications {
+		response.Notifications = append(response.Notifications, inboxNotification(n))
+	}
+	return response, nil
+}
+
+// CountUnreadNotifications is an interface for counting unread notifications.
+type CountUnreadNotifications interface {
+	Execute(
+		ctx context.Context,
+		cmd *CountUnreadNotificationsCommand,
+	) (*CountUnreadNotificationsResponse, error)
+}
+
+// CountUnreadNotificationsService is a service for counting unread notifications.
+type CountUnreadNotificationsService struct {
+	historyRepo history.Repository
+	validator   validator.Validator
+}
+
+// NewCountUnreadNotificationsService returns a new CountUnreadNotificationsService.
+func NewCountUnreadNotificationsService(
+	historyRepo history.Repository,
+	valid validator.Validator,
+) *CountUnreadNotificationsService {
+	return &CountUnreadNotificationsService{
+		historyRepo: historyRepo,
+		validator:   valid,
+	}
+}
+
+// CountUnreadNotificationsCommand is a request to count unread notifications.
+type CountUnreadNotificationsCommand struct {
+	UserID string
+}
+
+// CountUnreadNotificationsResponse is a number of unread notifications.
+type CountUnreadNotificationsResponse struct {
+	Unread int
+}
+
+// Execute executes the CountUnreadNotifications command.
+func (s *CountUnreadNotificationsService) Execute(
+	ctx context.Context,
+	req *CountUnreadNotificationsCommand,
+) (*CountUnreadNotificationsResponse, error) {
+	valErr := s.validator.ValidateStruct(req)
+	if valErr != nil {
+		return nil, fmt.Errorf("failed to validate request: %w", valErr)
+	}
+	parsedUUID, err := uuid.Parse(req.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid uuid format: %w", err)
+	}
+
+	unread, err := s.historyRepo.CountUnread(ctx, parsedUUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count unread notifications: %w", err)
+	}
+	return &CountUnreadNotificationsResponse{Unread: unread}, nil
+}
+
+// MarkNotificationsRead is an interface for marking notifications read.
+type MarkNotificationsRead interface {
+	Execute(
+		ctx context.Context,
+		cmd *MarkNotificationsReadCommand,
+	) (*MarkNotificationsReadResponse, error)
+}
+
+// MarkNotificationsReadService is a service for marking notifications read.
+type MarkNotificationsReadService struct {
+	historyRepo history.Repository
+	validator   validator.Validator
+}
+
+// NewMarkNotificationsReadService returns a new MarkNotificationsReadService.
+func NewMarkNotificationsReadService(
+	historyRepo history.Repository,
+	valid validator.Validator,
+) *MarkNotificationsReadService {
+	return &MarkNotificationsReadService{
+		historyRepo: historyRepo,
+		validator:   valid,
+	}
+}
+
+// MarkNotificationsReadCommand is a request to mark notification read,
+// all notifications of the user are marked if NotificationID is empty.
+type MarkNotificationsReadCommand struct {
+	UserID         string
+	NotificationID string `validate:"omitempty,uuid"`
+}
+
+// MarkNotificationsReadResponse is a number of notifications left unread.
+type MarkNotificationsReadResponse struct {
+	Unread int
+}
+
+// Execute executes the MarkNotificationsRead command.
+func (s *MarkNotificationsReadService) Execute(
+	ctx context.Context,
+	req *MarkNotificationsReadCommand,
+) (*MarkNotificationsReadResponse, error) {
+	valErr := s.validator.ValidateStruct(req)
+	if valErr != nil {
+		return nil, fmt.Errorf("%w: %w", ErrNoNotification, valErr)
+	}
+	parsedUUID, err := uuid.Parse(req.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid uuid format: %w", err)
+	}
+
+	now := time.Now()
+	if req.NotificationID == "" {
+		if err := s.historyRepo.MarkAllRead(ctx, parsedUUID, now); err != nil {
+			return nil, fmt.Errorf("failed to mark notifications read: %w", err)
+		}
+	} else if err := s.markRead(ctx, parsedUUID, req.NotificationID, now); err != nil {
+		return nil, err
+	}
+
+	unread, err := s.historyRepo.CountUnread(ctx, parsedUUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count unread notifications: %w", err)
+	}
+	return &MarkNotificationsReadResponse{Unread: unread}, nil
+}
+
+// markRead marks read one notification of the user.
+func (s *MarkNotificationsReadService) markRead(
+	ctx context.Context,
+	userID uuid.UUID,
+	notificationID string,
+	t time.Time,
+) error {
+	parsedID, err := uuid.Parse(notificationID)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrNoNotification, err)
+	}
+	n, err := s.historyRepo.GetByID(ctx, parsedID)
+	if errors.Is(err, history.ErrNoNotificationFound) {
+		return ErrNoNotification
+	} else if err != nil {
+		return fmt.Errorf("failed to get notification: %w", err)
+	}
+	if n.UserID() != userID {
+		return ErrNoNotification
+	}
+	n.MarkRead(t)
+	if err := s.historyRepo.Save(ctx, n); err != nil {
+		return fmt.Errorf("failed to mark notification read: %w", err)
+	}
+	return nil
+}
+
+// inboxNotification converts notification in history to the response.
+func inboxNotification(n *history.Notification) InboxNotification {
+	content := n.Content()
+	info := InboxNotification{
+		ID:         n.ID().String(),
+		Category:   n.Category(),
+		Type:       content.Type,
+		Title:      content.Title,
+		Body:       content.Body,
+		URL:        content.URL,
+		Data:       content.Data,
+		Deliveries: make([]DeviceResult, 0, len(n.Deliveries())),
+		CreatedAt:  n.CreatedAt(),
+		ReadAt:     nil,
+	}
+	for _, delivery := range n.Deliveries() {
+		info.Deliveries = append(info.Deliveries, deviceResultOf(delivery))
+	}
+	if n.IsRead() {
+		readAt := n.ReadAt()
+		info.ReadAt = &readAt
+	}
+	return info
+}
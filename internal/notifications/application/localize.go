@@ -0,0 +1,45 @@
+package application
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	templateProvider "github.com/FSO-VK/final-project-vk-backend/internal/notifications/application/template_provider"
+	"github.com/FSO-VK/final-project-vk-backend/internal/notifications/domain/notification"
+	"github.com/FSO-VK/final-project-vk-backend/internal/notifications/domain/preferences"
+)
+
+// ErrInvalidTemplate is an error when notification can't be rendered from its template.
+var ErrInvalidTemplate = errors.New("invalid notification template")
+
+// localize renders content from its template in the language of the user.
+// Content without template is sent as is, titles of actions missing
+// in the template are kept. Rendered content has no template,
+// so it is not rendered again when deferred notification is delivered.
+func localize(
+	templates templateProvider.TemplateProvider,
+	content notification.Content,
+	locale preferences.Locale,
+) (notification.Content, error) {
+	content.Locale = string(locale)
+	if content.Template == "" {
+		return content, nil
+	}
+
+	message, err := templates.Render(string(locale), content.Template, content.Params)
+	if err != nil {
+		return content, fmt.Errorf("%w: %w", ErrInvalidTemplate, err)
+	}
+	content.Title = message.Title
+	content.Body = message.Body
+	content.Template = ""
+	content.Params = nil
+	content.Actions = slices.Clone(content.Actions)
+	for i, action := range content.Actions {
+		if title, ok := message.Actions[action.Action]; ok {
+			content.Actions[i].Title = title
+		}
+	}
+	return content, nil
+}
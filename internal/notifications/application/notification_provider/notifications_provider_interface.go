@@ -2,13 +2,21 @@
 package notificationprovider
 
 import (
+	"context"
+	"errors"
+
+	"github.com/FSO-VK/final-project-vk-backend/internal/notifications/domain/notification"
 	"github.com/FSO-VK/final-project-vk-backend/internal/notifications/domain/subscriptions"
 	"github.com/google/uuid"
 )
 
+// ErrSubscriptionGone is returned when push service doesn't accept the subscription anymore.
+var ErrSubscriptionGone = errors.New("push subscription is gone")
+
 // PushNotificationClient is an interface for notifications client.
 type NotificationProvider interface {
 	PushNotification(
+		ctx context.Context,
 		pushInfo *Notification,
 		subscriptionInfo *subscriptions.PushSubscription,
 	) error
@@ -19,8 +27,7 @@ type Notification struct {
 	ID             uuid.UUID
 	SubscriptionID uuid.UUID
 	UserID         uuid.UUID
-	Title          string
-	Body           string
+	notification.Content
 }
 
 // NewNotification creates a new notification.
@@ -28,14 +35,12 @@ func NewNotification(
 	id uuid.UUID,
 	subscriptionID uuid.UUID,
 	userID uuid.UUID,
-	title string,
-	body string,
+	content notification.Content,
 ) *Notification {
 	return &Notification{
 		ID:             id,
 		SubscriptionID: subscriptionID,
 		UserID:         userID,
-		Title:          title,
-		Body:           body,
+		Content:        content,
 	}
 }
@@ -0,0 +1,24 @@
+// Package planningprovider is a package for interface for planning service client.
+package planningprovider
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrNoIntake is returned when the intake is not found or doesn't belong to the user.
+	ErrNoIntake = errors.New("intake not found")
+	// ErrIntakeTaken is returned when reminder of taken intake is snoozed.
+	ErrIntakeTaken = errors.New("intake is already taken")
+)
+
+// PlanningProvider makes quick replies to intake reminders on behalf of the user.
+type PlanningProvider interface {
+	TakeIntake(ctx context.Context, userID uuid.UUID, recordID uuid.UUID) error
+	// SnoozeIntake postpones the reminder and returns when it is sent again.
+	SnoozeIntake(ctx context.Context, userID uuid.UUID, recordID uuid.UUID) (time.Time, error)
+}
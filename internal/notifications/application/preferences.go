@@ -0,0 +1,284 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/FSO-VK/final-project-vk-backend/internal/notifications/domain/preferences"
+	"github.com/FSO-VK/final-project-vk-backend/internal/notifications/domain/subscriptions"
+	"github.com/FSO-VK/final-project-vk-backend/internal/utils/validator"
+	"github.com/google/uuid"
+)
+
+// quietHoursLayout is a layout of start and end of quiet hours.
+const quietHoursLayout = "15:04"
+
+var (
+	// ErrInvalidPreferences is an error when preferences in request are not valid.
+	ErrInvalidPreferences = errors.New("invalid preferences")
+	// ErrUnknownDevice is an error when muted device is not a push subscription of the user.
+	ErrUnknownDevice = errors.New("unknown device")
+)
+
+// PreferencesInfo is notification settings of a user.
+// Categories contains all known categories, QuietHours is nil if they are not set.
+// EmailMode is one of off, fallback and always, Locale is one of ru and en.
+type PreferencesInfo struct {
+	Categories   map[string]bool
+	QuietHours   *QuietHoursInfo
+	MutedDevices []string
+	EmailMode    string
+	Locale       string
+}
+
+// QuietHoursInfo is a daily period when notifications are deferred or collected to a digest.
+// Start and End are local times in format 15:04 in TimeZone.
+type QuietHoursInfo struct {
+	Start    string
+	End      string
+	TimeZone string
+	Mode     string
+}
+
+// GetPreferences is an interface for getting notification preferences.
+type GetPreferences interface {
+	Execute(
+		ctx context.Context,
+		cmd *GetPreferencesCommand,
+	) (*GetPreferencesResponse, error)
+}
+
+// GetPreferencesService is a service for getting notification preferences.
+type GetPreferencesService struct {
+	preferencesRepo preferences.Repository
+	validator       validator.Validator
+}
+
+// NewGetPreferencesService returns a new GetPreferencesService.
+func NewGetPreferencesService(
+	preferencesRepo preferences.Repository,
+	valid validator.Validator,
+) *GetPreferencesService {
+	return &GetPreferencesService{
+		preferencesRepo: preferencesRepo,
+		validator:       valid,
+	}
+}
+
+// GetPreferencesCommand is a request to get notification preferences.
+type GetPreferencesCommand struct {
+	UserID string
+}
+
+// GetPreferencesResponse is a response with notification preferences.
+type GetPreferencesResponse struct {
+	PreferencesInfo
+}
+
+// Execute executes the GetPreferences command.
+func (s *GetPreferencesService) Execute(
+	ctx context.Context,
+	req *GetPreferencesCommand,
+) (*GetPreferencesResponse, error) {
+	valErr := s.validator.ValidateStruct(req)
+	if valErr != nil {
+		return nil, fmt.Errorf("failed to validate request: %w", valErr)
+	}
+	parsedUUID, err := uuid.Parse(req.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid uuid format: %w", err)
+	}
+
+	prefs, err := preferencesOf(ctx, s.preferencesRepo, parsedUUID)
+	if err != nil {
+		return nil, err
+	}
+	return &GetPreferencesResponse{PreferencesInfo: preferencesInfo(prefs)}, nil
+}
+
+// UpdatePreferences is an interface for changing notification preferences.
+type UpdatePreferences interface {
+	Execute(
+		ctx context.Context,
+		cmd *UpdatePreferencesCommand,
+	) (*UpdatePreferencesResponse, error)
+}
+
+// UpdatePreferencesService is a service for changing notification preferences.
+type UpdatePreferencesService struct {
+	preferencesRepo   preferences.Repository
+	subscriptionsRepo subscriptions.Repository
+	validator         validator.Validator
+}
+
+// NewUpdatePreferencesService returns a new UpdatePreferencesService.
+func NewUpdatePreferencesService(
+	preferencesRepo preferences.Repository,
+	subscriptionsRepo subscriptions.Repository,
+	valid validator.Validator,
+) *UpdatePreferencesService {
+	return &UpdatePreferencesService{
+		preferencesRepo:   preferencesRepo,
+		subscriptionsRepo: subscriptionsRepo,
+		validator:         valid,
+	}
+}
+
+// UpdatePreferencesCommand is a request to replace notification preferences.
+// Categories missing in the request are enabled, email is used as fallback
+// if EmailMode is empty and notifications are in Russian if Locale is empty.
+type UpdatePreferencesCommand struct {
+	UserID string
+	PreferencesInfo
+}
+
+// UpdatePreferencesResponse is a response with saved notification preferences.
+type UpdatePreferencesResponse struct {
+	PreferencesInfo
+}
+
+// Execute executes the UpdatePreferences command.
+func (s *UpdatePreferencesService) Execute(
+	ctx context.Context,
+	req *UpdatePreferencesCommand,
+) (*UpdatePreferencesResponse, error) {
+	valErr := s.validator.ValidateStruct(req)
+	if valErr != nil {
+		return nil, fmt.Errorf("failed to validate request: %w", valErr)
+	}
+	parsedUUID, err := uuid.Parse(req.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid uuid format: %w", err)
+	}
+
+	prefs := preferences.NewPreferences(parsedUUID)
+	for category, enabled := range req.Categories {
+		if err := prefs.SetEnabled(preferences.Category(category), enabled); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidPreferences, err)
+		}
+	}
+
+	if req.QuietHours != nil {
+		quietHours, err := parseQuietHours(req.QuietHours)
+		if err != nil {
+			return nil, err
+		}
+		prefs.SetQuietHours(quietHours)
+	}
+
+	emailMode, err := preferences.ParseEmailMode(req.EmailMode)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPreferences, err)
+	}
+	if err := prefs.SetEmailMode(emailMode); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPreferences, err)
+	}
+
+	locale, err := preferences.ParseLocale(req.Locale)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPreferences, err)
+	}
+	if err := prefs.SetLocale(locale); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPreferences, err)
+	}
+
+	mutedDevices, err := s.parseDevices(ctx, parsedUUID, req.MutedDevices)
+	if err != nil {
+		return nil, err
+	}
+	prefs.SetMutedDevices(mutedDevices)
+
+	if err := s.preferencesRepo.Save(ctx, prefs); err != nil {
+		return nil, fmt.Errorf("failed to save preferences: %w", err)
+	}
+	return &UpdatePreferencesResponse{PreferencesInfo: preferencesInfo(prefs)}, nil
+}
+
+// parseDevices checks that all devices are push subscriptions of the user.
+func (s *UpdatePreferencesService) parseDevices(
+	ctx context.Context,
+	userID uuid.UUID,
+	devices []string,
+) ([]uuid.UUID, error) {
+	if len(devices) == 0 {
+		return nil, nil
+	}
+	userSubscriptions, err := s.subscriptionsRepo.GetSubscriptionsByUserID(ctx, userID)
+	if err != nil && !errors.Is(err, subscriptions.ErrNoSubscriptionsFound) {
+		return nil, fmt.Errorf("failed to get subscriptions: %w", err)
+	}
+	known := make(map[uuid.UUID]struct{}, len(userSubscriptions))
+	for _, subscription := range userSubscriptions {
+		known[subscription.GetID()] = struct{}{}
+	}
+
+	result := make([]uuid.UUID, 0, len(devices))
+	for _, device := range devices {
+		id, err := uuid.Parse(device)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidPreferences, err)
+		}
+		if _, ok := known[id]; !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownDevice, device)
+		}
+		result = append(result, id)
+	}
+	return result, nil
+}
+
+func parseQuietHours(info *QuietHoursInfo) (*preferences.QuietHours, error) {
+	start, err := time.Parse(quietHoursLayout, info.Start)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPreferences, err)
+	}
+	end, err := time.Parse(quietHoursLayout, info.End)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPreferences, err)
+	}
+	location, err := time.LoadLocation(info.TimeZone)
+	if err != nil || info.TimeZone == "" {
+		return nil, fmt.Errorf("%w: unknown time zone %q", ErrInvalidPreferences, info.TimeZone)
+	}
+	quietHours, err := preferences.NewQuietHours(
+		sinceMidnight(start),
+		sinceMidnight(end),
+		location,
+		preferences.QuietMode(info.Mode),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPreferences, err)
+	}
+	return quietHours, nil
+}
+
+func sinceMidnight(t time.Time) time.Duration {
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+}
+
+func preferencesInfo(prefs *preferences.Preferences) PreferencesInfo {
+	info := PreferencesInfo{
+		Categories:   make(map[string]bool),
+		QuietHours:   nil,
+		MutedDevices: make([]string, 0),
+		EmailMode:    string(prefs.EmailMode()),
+		Locale:       string(prefs.Locale()),
+	}
+	for _, category := range preferences.Categories() {
+		info.Categories[string(category)] = prefs.IsEnabled(category)
+	}
+	if q := prefs.QuietHours(); q != nil {
+		midnight := time.Date(0, 1, 1, 0, 0, 0, 0, time.UTC)
+		info.QuietHours = &QuietHoursInfo{
+			Start:    midnight.Add(q.Start()).Format(quietHoursLayout),
+			End:      midnight.Add(q.End()).Format(quietHoursLayout),
+			TimeZone: q.Location().String(),
+			Mode:     string(q.Mode()),
+		}
+	}
+	for _, id := range prefs.MutedDevices() {
+		info.MutedDevices = append(info.MutedDevices, id.String())
+	}
+	return info
+}
@@ -0,0 +1,25 @@
+// Package profileprovider is a package for interface for dependant profiles client.
+package profileprovider
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// ErrNoProfile is returned when the id is not an id of a dependant profile.
+var ErrNoProfile = errors.New("no profile")
+
+// ProfileProvider provides dependant profiles managed under user accounts.
+type ProfileProvider interface {
+	Profile(ctx context.Context, profileID uuid.UUID) (*Profile, error)
+}
+
+// Profile is a dependant profile, notifications of the profile
+// are delivered to its account.
+type Profile struct {
+	ID        uuid.UUID
+	AccountID uuid.UUID
+	Name      string
+}
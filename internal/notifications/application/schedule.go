@@ -0,0 +1,358 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/FSO-VK/final-project-vk-backend/internal/notifications/domain/preferences"
+	"github.com/FSO-VK/final-project-vk-backend/internal/notifications/domain/scheduled"
+	"github.com/FSO-VK/final-project-vk-backend/internal/utils/validator"
+	"github.com/google/uuid"
+)
+
+const (
+	// maxRescheduleAttempts is how many times rescheduling is attempted
+	// if the notification is changed by the dispatcher at the same time.
+	maxRescheduleAttempts = 3
+	// dueBatchSize is how many due notifications are sent at once,
+	// the rest is sent by the next runs.
+	dueBatchSize = 100
+)
+
+var (
+	// ErrInvalidSchedule is an error when scheduled notification is invalid.
+	ErrInvalidSchedule = errors.New("invalid scheduled notification")
+	// ErrNoScheduledNotification is an error when no notification is scheduled by the key.
+	ErrNoScheduledNotification = errors.New("scheduled notification not found")
+)
+
+// ScheduleNotification is an interface for scheduling a notification.
+type ScheduleNotification interface {
+	Execute(
+		ctx context.Context,
+		cmd *ScheduleNotificationCommand,
+	) (*ScheduleNotificationResponse, error)
+}
+
+// ScheduleNotificationService is a service for scheduling a notification.
+type ScheduleNotificationService struct {
+	scheduledRepo scheduled.Repository
+	validator     validator.Validator
+}
+
+// NewScheduleNotificationService returns a new ScheduleNotificationService.
+func NewScheduleNotificationService(
+	scheduledRepo scheduled.Repository,
+	valid validator.Validator,
+) *ScheduleNotificationService {
+	return &ScheduleNotificationService{
+		scheduledRepo: scheduledRepo,
+		validator:     valid,
+	}
+}
+
+// ScheduleNotificationCommand is a request to send the notification at DeliverAt.
+// Notification with the same Key replaces the scheduled one, so the caller
+// may schedule it again without duplicates, cancel or reschedule it by the key.
+// Notification is sent as it is sent now, quiet hours and preferences
+// are applied at delivery time.
+type ScheduleNotificationCommand struct {
+	// Caller is a service scheduling the notification, keys are unique per caller.
+	Caller       string `validate:"required"`
+	Key          string `validate:"required"`
+	DeliverAt    string `validate:"required,datetime=2006-01-02T15:04:05Z07:00"`
+	Notification SendNotificationCommand
+}
+
+// ScheduleNotificationResponse is a response to schedule a notification,
+// Replaced tells whether a notification with the same key was scheduled before.
+type ScheduleNotificationResponse struct {
+	Key       string
+	DeliverAt time.Time
+	Replaced  bool
+}
+
+// Execute executes the ScheduleNotification command.
+func (s *ScheduleNotificationService) Execute(
+	ctx context.Context,
+	req *ScheduleNotificationCommand,
+) (*ScheduleNotificationResponse, error) {
+	valErr := s.validator.ValidateStruct(req)
+	if valErr != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSchedule, valErr)
+	}
+	deliverAt, err := time.Parse(time.RFC3339, req.DeliverAt)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSchedule, err)
+	}
+	parsedUserID, err := uuid.Parse(req.Notification.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid uuid format: %w", ErrInvalidSchedule, err)
+	}
+	category, err := preferences.ParseCategory(req.Notification.Category)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSchedule, err)
+	}
+
+	n, err := scheduled.NewNotification(
+		req.Caller,
+		req.Key,
+		parsedUserID,
+		string(category),
+		contentOf(&req.Notification),
+		deliverAt,
+		time.Now(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSchedule, err)
+	}
+	replaced, err := s.scheduledRepo.Save(ctx, n)
+	if err != nil {
+		return nil, fmt.Errorf("failed to schedule notification: %w", err)
+	}
+	return &ScheduleNotificationResponse{
+		Key:       n.Key(),
+		DeliverAt: n.DeliverAt(),
+		Replaced:  replaced,
+	}, nil
+}
+
+// RescheduleNotification is an interface for moving scheduled notification to another time.
+type RescheduleNotification interface {
+	Execute(
+		ctx context.Context,
+		cmd *RescheduleNotificationCommand,
+	) (*RescheduleNotificationResponse, error)
+}
+
+// RescheduleNotificationService is a service for moving scheduled notification to another time.
+type RescheduleNotificationService struct {
+	scheduledRepo scheduled.Repository
+	validator     validator.Validator
+}
+
+// NewRescheduleNotificationService returns a new RescheduleNotificationService.
+func NewRescheduleNotificationService(
+	scheduledRepo scheduled.Repository,
+	valid validator.Validator,
+) *RescheduleNotificationService {
+	return &RescheduleNotificationService{
+		scheduledRepo: scheduledRepo,
+		validator:     valid,
+	}
+}
+
+// RescheduleNotificationCommand is a request to move scheduled notification to DeliverAt.
+type RescheduleNotificationCommand struct {
+	Caller    string `validate:"required"`
+	Key       string `validate:"required"`
+	DeliverAt string `validate:"required,datetime=2006-01-02T15:04:05Z07:00"`
+}
+
+// RescheduleNotificationResponse is a response to reschedule a notification.
+type RescheduleNotificationResponse struct {
+	Key       string
+	DeliverAt time.Time
+}
+
+// Execute executes the RescheduleNotification command.
+func (s *RescheduleNotificationService) Execute(
+	ctx context.Context,
+	req *RescheduleNotificationCommand,
+) (*RescheduleNotificationResponse, error) {
+	valErr := s.validator.ValidateStruct(req)
+	if valErr != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSchedule, valErr)
+	}
+	deliverAt, err := time.Parse(time.RFC3339, req.DeliverAt)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSchedule, err)
+	}
+
+	// notification may be retried by the dispatcher at the same time,
+	// then it is got again to reschedule the latest version
+	var errs []error
+	for range maxRescheduleAttempts {
+		n, err := s.scheduledRepo.GetByKey(ctx, req.Caller, req.Key)
+		if errors.Is(err, scheduled.ErrNoNotificationFound) {
+			return nil, ErrNoScheduledNotification
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to get scheduled notification: %w", err)
+		}
+		n.Reschedule(deliverAt)
+		err = s.scheduledRepo.Update(ctx, n)
+		if errors.Is(err, scheduled.ErrNotificationChanged) {
+			errs = append(errs, err)
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to reschedule notification: %w", err)
+		}
+		return &RescheduleNotificationResponse{
+			Key:       n.Key(),
+			DeliverAt: n.DeliverAt(),
+		}, nil
+	}
+	return nil, fmt.Errorf("failed to reschedule notification: %w", errors.Join(errs...))
+}
+
+// CancelScheduledNotification is an interface for canceling scheduled notification.
+type CancelScheduledNotification interface {
+	Execute(
+		ctx context.Context,
+		cmd *CancelScheduledNotificationCommand,
+	) (*CancelScheduledNotificationResponse, error)
+}
+
+// CancelScheduledNotificationService is a service for canceling scheduled notification.
+type CancelScheduledNotificationService struct {
+	scheduledRepo scheduled.Repository
+	validator     validator.Validator
+}
+
+// NewCancelScheduledNotificationService returns a new CancelScheduledNotificationService.
+func NewCancelScheduledNotificationService(
+	scheduledRepo scheduled.Repository,
+	valid validator.Validator,
+) *CancelScheduledNotificationService {
+	return &CancelScheduledNotificationService{
+		scheduledRepo: scheduledRepo,
+		validator:     valid,
+	}
+}
+
+// CancelScheduledNotificationCommand is a request to cancel scheduled notification.
+type CancelScheduledNotificationCommand struct {
+	Caller string `validate:"required"`
+	Key    string `validate:"required"`
+}
+
+// CancelScheduledNotificationResponse is a response to cancel scheduled notification.
+type CancelScheduledNotificationResponse struct{}
+
+// Execute executes the CancelScheduledNotification command.
+func (s *CancelScheduledNotificationService) Execute(
+	ctx context.Context,
+	req *CancelScheduledNotificationCommand,
+) (*CancelScheduledNotificationResponse, error) {
+	valErr := s.validator.ValidateStruct(req)
+	if valErr != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSchedule, valErr)
+	}
+
+	err := s.scheduledRepo.DeleteByKey(ctx, req.Caller, req.Key)
+	if errors.Is(err, scheduled.ErrNoNotificationFound) {
+		return nil, ErrNoScheduledNotification
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to cancel scheduled notification: %w", err)
+	}
+	return &CancelScheduledNotificationResponse{}, nil
+}
+
+// ScheduledNotificationsDeliverer is an interface for delivering scheduled notifications.
+type ScheduledNotificationsDeliverer interface {
+	DeliverScheduled(ctx context.Context) error
+}
+
+// DeliverScheduledService sends due scheduled notifications.
+type DeliverScheduledService struct {
+	scheduledRepo scheduled.Repository
+	sender        SendNotification
+}
+
+// NewDeliverScheduledService returns a new DeliverScheduledService.
+func NewDeliverScheduledService(
+	scheduledRepo scheduled.Repository,
+	sender SendNotification,
+) *DeliverScheduledService {
+	return &DeliverScheduledService{
+		scheduledRepo: scheduledRepo,
+		sender:        sender,
+	}
+}
+
+// DeliverScheduled sends notifications whose time has come.
+// Notification is removed after it is sent, failed delivery is retried
+// later with backoff until attempts of the notification are exhausted.
+// Notification replaced, rescheduled or canceled by the caller while
+// it was being sent is left as the caller changed it.
+func (s *DeliverScheduledService) DeliverScheduled(ctx context.Context) error {
+	now := time.Now()
+	due, err := s.scheduledRepo.Due(ctx, now, dueBatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to get scheduled notifications: %w", err)
+	}
+
+	var errs []error
+	for _, n := range due {
+		// notification may be canceled or rescheduled since it was got
+		current, err := s.scheduledRepo.GetByKey(ctx, n.Caller(), n.Key())
+		if errors.Is(err, scheduled.ErrNoNotificationFound) || err == nil && !current.IsDue(now) {
+			continue
+		} else if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := s.deliver(ctx, current); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// deliver sends the notification and removes it, or keeps it to retry if sending fails.
+func (s *DeliverScheduledService) deliver(ctx context.Context, n *scheduled.Notification) error {
+	_, sendErr := s.sender.Execute(ctx, sendCommandOf(n))
+	if sendErr == nil {
+		return s.remove(ctx, n)
+	}
+
+	sendErr = fmt.Errorf("failed to send notification %s: %w", n.Key(), sendErr)
+	if !n.RetryLater(time.Now()) {
+		return errors.Join(
+			fmt.Errorf("%w, dropped after %d attempts", sendErr, n.Attempts()),
+			s.remove(ctx, n),
+		)
+	}
+	err := s.scheduledRepo.Update(ctx, n)
+	if err != nil && !errors.Is(err, scheduled.ErrNotificationChanged) {
+		return errors.Join(sendErr, fmt.Errorf("failed to save retry: %w", err))
+	}
+	return sendErr
+}
+
+// remove deletes the sent notification unless it was replaced
+// or rescheduled by the caller while it was being sent.
+func (s *DeliverScheduledService) remove(ctx context.Context, n *scheduled.Notification) error {
+	err := s.scheduledRepo.Delete(ctx, n)
+	if err != nil && !errors.Is(err, scheduled.ErrNotificationChanged) {
+		return fmt.Errorf("failed to delete scheduled notification: %w", err)
+	}
+	return nil
+}
+
+// sendCommandOf returns command to send the scheduled notification now.
+func sendCommandOf(n *scheduled.Notification) *SendNotificationCommand {
+	content := n.Content()
+	actions := make([]NotificationAction, 0, len(content.Actions))
+	for _, action := range content.Actions {
+		actions = append(actions, NotificationAction(action))
+	}
+	return &SendNotificationCommand{
+		UserID:   n.UserID().String(),
+		Category: n.Category(),
+		Type:     content.Type,
+		Title:    content.Title,
+		Body:     content.Body,
+		URL:      content.URL,
+		Icon:     content.Icon,
+		Tag:      content.Tag,
+		Renotify: content.Renotify,
+		Urgency:  string(content.Urgency),
+		Actions:  actions,
+		Data:     content.Data,
+		Template: content.Template,
+		Params:   content.Params,
+	}
+}
@@ -2,14 +2,39 @@ package application
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"maps"
+	"time"
 
-	provider "github.com/FSO-VK/final-project-vk-backend/internal/notifications/application/notification_provider"
-	"github.com/FSO-VK/final-project-vk-backend/internal/notifications/domain/subscriptions"
+	profileProvider "github.com/FSO-VK/final-project-vk-backend/internal/notifications/application/profile_provider"
+	templateProvider "github.com/FSO-VK/final-project-vk-backend/internal/notifications/application/template_provider"
+	"github.com/FSO-VK/final-project-vk-backend/internal/notifications/domain/dedupe"
+	"github.com/FSO-VK/final-project-vk-backend/internal/notifications/domain/deferred"
+	"github.com/FSO-VK/final-project-vk-backend/internal/notifications/domain/history"
+	"github.com/FSO-VK/final-project-vk-backend/internal/notifications/domain/notification"
+	"github.com/FSO-VK/final-project-vk-backend/internal/notifications/domain/preferences"
 	"github.com/FSO-VK/final-project-vk-backend/internal/utils/validator"
 	"github.com/google/uuid"
 )
 
+// dataNotificationID is a key of id of notification in history in data of content.
+const dataNotificationID = "notificationId"
+
+// ErrNotDelivered is an error when notification is not delivered by any channel of the user.
+var ErrNotDelivered = errors.New("notification is not delivered")
+
+const (
+	// DeliverySent is a status of device or channel which received notification.
+	DeliverySent = "sent"
+	// DeliveryMuted is a status of device muted in preferences.
+	DeliveryMuted = "muted"
+	// DeliveryRemoved is a status of device whose subscription expired and was deleted.
+	DeliveryRemoved = "removed"
+	// DeliveryFailed is a status of device or channel which didn't receive notification.
+	DeliveryFailed = "failed"
+)
+
 // SendNotification is an interface for adding a notification.
 type SendNotification interface {
 	Execute(
@@ -20,33 +45,92 @@ type SendNotification interface {
 
 // SendNotificationService is a service for sending a notification.
 type SendNotificationService struct {
-	subscriptionsRepo    subscriptions.Repository
-	notificationProvider provider.NotificationProvider
-	validator            validator.Validator
+	preferencesRepo preferences.Repository
+	deferredRepo    deferred.Repository
+	historyRepo     history.Repository
+	dedupeRepo      dedupe.Repository
+	notifier        *Notifier
+	profileProvider profileProvider.ProfileProvider
+	templates       templateProvider.TemplateProvider
+	validator       validator.Validator
 }
 
 // NewSendNotificationService returns a new SendNotificationService.
 func NewSendNotificationService(
-	subscriptionsRepo subscriptions.Repository,
-	notificationProvider provider.NotificationProvider,
+	preferencesRepo preferences.Repository,
+	deferredRepo deferred.Repository,
+	historyRepo history.Repository,
+	dedupeRepo dedupe.Repository,
+	notifier *Notifier,
+	profileProvider profileProvider.ProfileProvider,
+	templates templateProvider.TemplateProvider,
 	valid validator.Validator,
 ) *SendNotificationService {
 	return &SendNotificationService{
-		subscriptionsRepo:    subscriptionsRepo,
-		notificationProvider: notificationProvider,
-		validator:            valid,
+		preferencesRepo: preferencesRepo,
+		deferredRepo:    deferredRepo,
+		historyRepo:     historyRepo,
+		dedupeRepo:      dedupeRepo,
+		notifier:        notifier,
+		profileProvider: profileProvider,
+		templates:       templates,
+		validator:       valid,
 	}
 }
 
 // SendNotificationCommand is a request to send a notification.
+// UserID may be an id of a dependant profile, then notification
+// is sent to the account of the profile titled with the profile name.
+// Category is one of intake, expiration, refill and security, notifications
+// without category can't be turned off by the user.
+// Every notification which is not turned off is kept in history of the user.
+// Other fields are passed to service worker of the user to show the notification
+// and handle clicks, Data contains ids of entities the notification is about.
+// If Template is set, Title, Body and titles of Actions are rendered from
+// the template with Params in the language of the user.
+// Notification with DedupeKey recently sent to the user is dropped,
+// so the caller may safely retry requests. Retries of failed notification
+// replace it in history instead of adding another one.
 type SendNotificationCommand struct {
-	UserID string
+	UserID    string
+	Category  string
+	Type      string
+	Title     string
+	Body      string
+	URL       string
+	Icon      string
+	Tag       string
+	Renotify  bool
+	Urgency   string               `validate:"omitempty,oneof=very-low low normal high"`
+	Actions   []NotificationAction `validate:"max=2,dive"`
+	Data      map[string]string
+	Template  string
+	Params    map[string]any
+	DedupeKey string `validate:"max=128"`
+}
+
+// NotificationAction is a button of notification,
+// Title may be empty if it is rendered from the template.
+type NotificationAction struct {
+	Action string `validate:"required"`
 	Title  string
-	Body   string
 }
 
 // SendNotificationResponse is a response to send a notification.
-type SendNotificationResponse struct{}
+// Devices is empty if notification is turned off or deferred by preferences
+// and if it is a duplicate.
+type SendNotificationResponse struct {
+	Devices   []DeviceResult
+	Duplicate bool
+}
+
+// DeviceResult is a result of delivery by a channel,
+// SubscriptionID is set for deliveries to push subscriptions.
+type DeviceResult struct {
+	Channel        string
+	SubscriptionID string
+	Status         string
+}
 
 // Execute executes the SendNotification command.
 func (s *SendNotificationService) Execute(
@@ -63,24 +147,164 @@ func (s *SendNotificationService) Execute(
 		return nil, fmt.Errorf("invalid uuid format: %w", err)
 	}
 
-	subscriptions, err := s.subscriptionsRepo.GetSubscriptionsByUserID(ctx, parsedUserID)
+	category, err := preferences.ParseCategory(req.Category)
 	if err != nil {
-		return nil, fmt.Errorf("there is no such subscription: %w", err)
+		return nil, fmt.Errorf("failed to validate request: %w", err)
+	}
+
+	if req.DedupeKey == "" {
+		return s.send(ctx, req, uuid.New(), parsedUserID, category)
 	}
-	for _, subscription := range subscriptions {
-		notificationToSend := provider.NewNotification(
-			uuid.New(),
-			subscription.GetID(),
+	key, err := dedupe.NewKey(parsedUserID, req.DedupeKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to validate request: %w", err)
+	}
+	claimed, err := s.dedupeRepo.Claim(ctx, key, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim dedupe key: %w", err)
+	}
+	if !claimed {
+		return &SendNotificationResponse{Duplicate: true}, nil
+	}
+	response, err := s.send(ctx, req, key.NotificationID(), parsedUserID, category)
+	if err != nil {
+		// the caller retries failed notification with the same key
+		return nil, errors.Join(err, s.dedupeRepo.Release(ctx, key))
+	}
+	return response, nil
+}
+
+// send sends notification to the user or to the account of the profile
+// and keeps it in history with notificationID.
+func (s *SendNotificationService) send(
+	ctx context.Context,
+	req *SendNotificationCommand,
+	notificationID uuid.UUID,
+	parsedUserID uuid.UUID,
+	category preferences.Category,
+) (*SendNotificationResponse, error) {
+	parsedUserID, profileName, err := s.recipient(ctx, parsedUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	prefs, err := preferencesOf(ctx, s.preferencesRepo, parsedUserID)
+	if err != nil {
+		return nil, err
+	}
+	if !prefs.IsEnabled(category) {
+		return &SendNotificationResponse{}, nil
+	}
+	content, err := localize(s.templates, contentOf(req), prefs.Locale())
+	if err != nil {
+		return nil, err
+	}
+	if profileName != "" {
+		content.Title = profileName + ": " + content.Title
+	}
+	now := time.Now()
+	content = withNotificationID(content, notificationID)
+	sentNotification := history.NewNotification(
+		notificationID,
+		parsedUserID,
+		string(category),
+		content,
+		now,
+	)
+	if until, quiet := prefs.QuietUntil(now); quiet {
+		if err := s.historyRepo.Save(ctx, sentNotification); err != nil {
+			return nil, fmt.Errorf("failed to save notification to history: %w", err)
+		}
+		notificationToDefer := deferred.NewNotification(
+			sentNotification.ID(),
 			parsedUserID,
-			req.Title,
-			req.Body,
+			string(category),
+			content,
+			until,
+			prefs.QuietHours().Mode() == preferences.QuietModeDigest,
 		)
-		err = s.notificationProvider.PushNotification(notificationToSend, subscription)
-		if err != nil {
-			return nil, fmt.Errorf("failed to send notification: %w", err)
+		if err := s.deferredRepo.Add(ctx, notificationToDefer); err != nil {
+			return nil, fmt.Errorf("failed to defer notification: %w", err)
 		}
+		return &SendNotificationResponse{}, nil
 	}
 
-	response := &SendNotificationResponse{}
+	deliveries, notifyErr := s.notifier.Notify(ctx, prefs, content)
+	sentNotification.SetDeliveries(deliveries)
+	if err := s.historyRepo.Save(ctx, sentNotification); err != nil {
+		return nil, fmt.Errorf("failed to save notification to history: %w", err)
+	}
+	if notifyErr != nil {
+		return nil, notifyErr
+	}
+
+	response := &SendNotificationResponse{
+		Devices: make([]DeviceResult, 0, len(deliveries)),
+	}
+	for _, delivery := range deliveries {
+		response.Devices = append(response.Devices, deviceResultOf(delivery))
+	}
 	return response, nil
 }
+
+// contentOf returns content of notification in the command.
+func contentOf(req *SendNotificationCommand) notification.Content {
+	actions := make([]notification.Action, 0, len(req.Actions))
+	for _, action := range req.Actions {
+		actions = append(actions, notification.Action(action))
+	}
+	return notification.Content{
+		Type:     req.Type,
+		Title:    req.Title,
+		Body:     req.Body,
+		URL:      req.URL,
+		Icon:     req.Icon,
+		Tag:      req.Tag,
+		Renotify: req.Renotify,
+		Urgency:  notification.Urgency(req.Urgency),
+		Actions:  actions,
+		Data:     req.Data,
+		Template: req.Template,
+		Params:   req.Params,
+	}
+}
+
+// withNotificationID adds id of notification in history to data of content,
+// so the user may reply to the notification.
+func withNotificationID(content notification.Content, id uuid.UUID) notification.Content {
+	data := make(map[string]string, len(content.Data)+1)
+	maps.Copy(data, content.Data)
+	data[dataNotificationID] = id.String()
+	content.Data = data
+	return content
+}
+
+// recipient returns the account to notify and the name of the dependant profile
+// the notification is about, the name is empty for the account itself.
+func (s *SendNotificationService) recipient(
+	ctx context.Context,
+	userID uuid.UUID,
+) (uuid.UUID, string, error) {
+	profile, err := s.profileProvider.Profile(ctx, userID)
+	if errors.Is(err, profileProvider.ErrNoProfile) {
+		return userID, "", nil
+	} else if err != nil {
+		return uuid.Nil, "", fmt.Errorf("failed to get profile: %w", err)
+	}
+	return profile.AccountID, profile.Name, nil
+}
+
+// preferencesOf returns preferences of the user, default ones if user has not saved them.
+func preferencesOf(
+	ctx context.Context,
+	preferencesRepo preferences.Repository,
+	userID uuid.UUID,
+) (*preferences.Preferences, error) {
+	prefs, err := preferencesRepo.GetByUserID(ctx, userID)
+	if errors.Is(err, preferences.ErrNoPreferencesFound) {
+		return preferences.NewPreferences(userID), nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get preferences: %w", err)
+	}
+	return prefs, nil
+}
@@ -0,0 +1,124 @@
+package application_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/FSO-VK/final-project-vk-backend/internal/notifications/application"
+	contactProvider "github.com/FSO-VK/final-project-vk-backend/internal/notifications/application/contact_provider"
+	provider "github.com/FSO-VK/final-project-vk-backend/internal/notifications/application/notification_provider"
+	profileProvider "github.com/FSO-VK/final-project-vk-backend/internal/notifications/application/profile_provider"
+	"github.com/FSO-VK/final-project-vk-backend/internal/notifications/domain/notification"
+	"github.com/FSO-VK/final-project-vk-backend/internal/notifications/domain/subscriptions"
+	"github.com/FSO-VK/final-project-vk-backend/internal/notifications/infrastructure/storage/memory"
+	"github.com/FSO-VK/final-project-vk-backend/internal/utils/validator"
+	"github.com/google/uuid"
+)
+
+var errPushFailed = errors.New("push service is unavailable")
+
+// flakyPush fails the first failures pushes and delivers the others.
+type flakyPush struct {
+	failures int
+}
+
+func (p *flakyPush) PushNotification(
+	_ context.Context,
+	_ *provider.Notification,
+	_ *subscriptions.PushSubscription,
+) error {
+	if p.failures > 0 {
+		p.failures--
+		return errPushFailed
+	}
+	return nil
+}
+
+type noContacts struct{}
+
+func (noContacts) Email(_ context.Context, _ uuid.UUID) (string, error) {
+	return "", contactProvider.ErrNoContact
+}
+
+type noEmail struct{}
+
+func (noEmail) SendEmail(_ context.Context, _ string, _ notification.Content) error {
+	return nil
+}
+
+type noProfiles struct{}
+
+func (noProfiles) Profile(_ context.Context, _ uuid.UUID) (*profileProvider.Profile, error) {
+	return nil, profileProvider.ErrNoProfile
+}
+
+func TestSendNotificationService_RetryAfterFailure(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	userID := uuid.New()
+
+	subscriptionsRepo := memory.NewSubscriptionsStorage()
+	err := subscriptionsRepo.CreateSubscription(
+		ctx,
+		subscriptions.NewSubscription(userID, "https://push.example/1", "key", "auth", "test"),
+	)
+	if err != nil {
+		t.Fatalf("arrange failed: %v", err)
+	}
+	historyRepo := memory.NewHistoryStorage()
+	service := application.NewSendNotificationService(
+		memory.NewPreferencesStorage(),
+		memory.NewDeferredStorage(),
+		historyRepo,
+		memory.NewDedupeStorage(),
+		application.NewNotifier(
+			subscriptionsRepo,
+			&flakyPush{failures: 1},
+			memory.NewTelegramStorage(),
+			nil,
+			noContacts{},
+			noEmail{},
+		),
+		noProfiles{},
+		nil,
+		validator.NewValidationProvider(),
+	)
+	cmd := &application.SendNotificationCommand{
+		UserID:    userID.String(),
+		Category:  "intake",
+		Title:     "Пора принять лекарство",
+		DedupeKey: "intake-reminder-" + uuid.NewString(),
+	}
+
+	if _, err := service.Execute(ctx, cmd); !errors.Is(err, application.ErrNotDelivered) {
+		t.Fatalf("first Execute() error = %v, want %v", err, application.ErrNotDelivered)
+	}
+	response, err := service.Execute(ctx, cmd)
+	if err != nil {
+		t.Fatalf("retried Execute() failed: %v", err)
+	}
+	if response.Duplicate || len(response.Devices) != 1 ||
+		response.Devices[0].Status != application.DeliverySent {
+		t.Errorf("retried Execute() = %+v, want delivery to the device", response)
+	}
+
+	notifications, total, err := historyRepo.ListByUserID(ctx, userID, 0, 10)
+	if err != nil {
+		t.Fatalf("ListByUserID() failed: %v", err)
+	}
+	if total != 1 {
+		t.Fatalf("history has %d notifications, want 1", total)
+	}
+	if got := notifications[0].Deliveries(); len(got) != 1 ||
+		got[0].Status != application.DeliverySent {
+		t.Errorf("history deliveries = %+v, want the retried delivery", got)
+	}
+	unread, err := historyRepo.CountUnread(ctx, userID)
+	if err != nil {
+		t.Fatalf("CountUnread() failed: %v", err)
+	}
+	if unread != 1 {
+		t.Errorf("CountUnread() = %d, want 1", unread)
+	}
+}
@@ -8,4 +8,18 @@ type NotificationsApplication struct {
 	CreateSubscription CreateSubscription
 	DeleteSubscription DeleteSubscription
 	SendNotification   SendNotification
+	GetPreferences     GetPreferences
+	UpdatePreferences  UpdatePreferences
+
+	ListNotifications        ListNotifications
+	CountUnreadNotifications CountUnreadNotifications
+	MarkNotificationsRead    MarkNotificationsRead
+
+	CreateTelegramLink CreateTelegramLink
+	GetTelegramLink    GetTelegramLink
+	DeleteTelegramLink DeleteTelegramLink
+
+	ScheduleNotification        ScheduleNotification
+	RescheduleNotification      RescheduleNotification
+	CancelScheduledNotification CancelScheduledNotification
 }
@@ -0,0 +1,400 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"strings"
+	"time"
+
+	planningProvider "github.com/FSO-VK/final-project-vk-backend/internal/notifications/application/planning_provider"
+	telegramProvider "github.com/FSO-VK/final-project-vk-backend/internal/notifications/application/telegram_provider"
+	"github.com/FSO-VK/final-project-vk-backend/internal/notifications/domain/history"
+	"github.com/FSO-VK/final-project-vk-backend/internal/notifications/domain/notification"
+	"github.com/FSO-VK/final-project-vk-backend/internal/notifications/domain/telegram"
+	"github.com/FSO-VK/final-project-vk-backend/internal/utils/validator"
+	"github.com/google/uuid"
+)
+
+const (
+	// callbackSeparator separates action and id of notification in data of inline button.
+	callbackSeparator = ":"
+	// startCommand is a command sent by Telegram when the user opens deep link.
+	startCommand = "/start"
+
+	// actionTake marks the intake of the reminder taken.
+	actionTake = "take"
+	// actionSnooze postpones the reminder.
+	actionSnooze = "snooze"
+)
+
+var (
+	// ErrTelegramDisabled is an error when the bot is not configured.
+	ErrTelegramDisabled = errors.New("telegram bot is disabled")
+	// ErrNoTelegramLink is an error when no chat is linked to the account.
+	ErrNoTelegramLink = errors.New("telegram is not linked")
+)
+
+// BotName is a username of the Telegram bot, empty if the bot is disabled.
+type BotName string
+
+// CreateTelegramLink is an interface for creating deep link to the bot.
+type CreateTelegramLink interface {
+	Execute(
+		ctx context.Context,
+		cmd *CreateTelegramLinkCommand,
+	) (*CreateTelegramLinkResponse, error)
+}
+
+// CreateTelegramLinkService is a service for creating deep link to the bot.
+type CreateTelegramLinkService struct {
+	telegramRepo telegram.Repository
+	botName      BotName
+	validator    validator.Validator
+}
+
+// NewCreateTelegramLinkService returns a new CreateTelegramLinkService.
+func NewCreateTelegramLinkService(
+	telegramRepo telegram.Repository,
+	botName BotName,
+	valid validator.Validator,
+) *CreateTelegramLinkService {
+	return &CreateTelegramLinkService{
+		telegramRepo: telegramRepo,
+		botName:      botName,
+		validator:    valid,
+	}
+}
+
+// CreateTelegramLinkCommand is a request to create deep link to the bot.
+type CreateTelegramLinkCommand struct {
+	UserID string
+}
+
+// CreateTelegramLinkResponse is a one-time deep link, the chat where the user
+// starts the bot by the link is linked to the account.
+type CreateTelegramLinkResponse struct {
+	URL       string
+	ExpiresAt time.Time
+}
+
+// Execute executes the CreateTelegramLink command.
+func (s *CreateTelegramLinkService) Execute(
+	ctx context.Context,
+	req *CreateTelegramLinkCommand,
+) (*CreateTelegramLinkResponse, error) {
+	valErr := s.validator.ValidateStruct(req)
+	if valErr != nil {
+		return nil, fmt.Errorf("failed to validate request: %w", valErr)
+	}
+	parsedUUID, err := uuid.Parse(req.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid uuid format: %w", err)
+	}
+	if s.botName == "" {
+		return nil, ErrTelegramDisabled
+	}
+
+	code := telegram.NewLinkCode(parsedUUID, time.Now())
+	if err := s.telegramRepo.SaveCode(ctx, code); err != nil {
+		return nil, fmt.Errorf("failed to save link code: %w", err)
+	}
+	deepLink := url.URL{
+		Scheme:   "https",
+		Host:     "t.me",
+		Path:     string(s.botName),
+		RawQuery: url.Values{"start": []string{code.Code()}}.Encode(),
+	}
+	return &CreateTelegramLinkResponse{
+		URL:       deepLink.String(),
+		ExpiresAt: code.ExpiresAt(),
+	}, nil
+}
+
+// GetTelegramLink is an interface for getting status of Telegram link.
+type GetTelegramLink interface {
+	Execute(
+		ctx context.Context,
+		cmd *GetTelegramLinkCommand,
+	) (*GetTelegramLinkResponse, error)
+}
+
+// GetTelegramLinkService is a service for getting status of Telegram link.
+type GetTelegramLinkService struct {
+	telegramRepo telegram.Repository
+	validator    validator.Validator
+}
+
+// NewGetTelegramLinkService returns a new GetTelegramLinkService.
+func NewGetTelegramLinkService(
+	telegramRepo telegram.Repository,
+	valid validator.Validator,
+) *GetTelegramLinkService {
+	return &GetTelegramLinkService{
+		telegramRepo: telegramRepo,
+		validator:    valid,
+	}
+}
+
+// GetTelegramLinkCommand is a request to get status of Telegram link.
+type GetTelegramLinkCommand struct {
+	UserID string
+}
+
+// GetTelegramLinkResponse tells whether a chat is linked to the account,
+// LinkedAt is nil if it is not.
+type GetTelegramLinkResponse struct {
+	Linked   bool
+	LinkedAt *time.Time
+}
+
+// Execute executes the GetTelegramLink command.
+func (s *GetTelegramLinkService) Execute(
+	ctx context.Context,
+	req *GetTelegramLinkCommand,
+) (*GetTelegramLinkResponse, error) {
+	valErr := s.validator.ValidateStruct(req)
+	if valErr != nil {
+		return nil, fmt.Errorf("failed to validate request: %w", valErr)
+	}
+	parsedUUID, err := uuid.Parse(req.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid uuid format: %w", err)
+	}
+
+	link, err := s.telegramRepo.GetLinkByUserID(ctx, parsedUUID)
+	if errors.Is(err, telegram.ErrNoLinkFound) {
+		return &GetTelegramLinkResponse{Linked: false, LinkedAt: nil}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get telegram link: %w", err)
+	}
+	linkedAt := link.LinkedAt()
+	return &GetTelegramLinkResponse{Linked: true, LinkedAt: &linkedAt}, nil
+}
+
+// DeleteTelegramLink is an interface for unlinking Telegram chat.
+type DeleteTelegramLink interface {
+	Execute(
+		ctx context.Context,
+		cmd *DeleteTelegramLinkCommand,
+	) (*DeleteTelegramLinkResponse, error)
+}
+
+// DeleteTelegramLinkService is a service for unlinking Telegram chat.
+type DeleteTelegramLinkService struct {
+	telegramRepo telegram.Repository
+	validator    validator.Validator
+}
+
+// NewDeleteTelegramLinkService returns a new DeleteTelegramLinkService.
+func NewDeleteTelegramLinkService(
+	telegramRepo telegram.Repository,
+	valid validator.Validator,
+) *DeleteTelegramLinkService {
+	return &DeleteTelegramLinkService{
+		telegramRepo: telegramRepo,
+		validator:    valid,
+	}
+}
+
+// DeleteTelegramLinkCommand is a request to unlink Telegram chat.
+type DeleteTelegramLinkCommand struct {
+	UserID string
+}
+
+// DeleteTelegramLinkResponse is a response to unlink Telegram chat.
+type DeleteTelegramLinkResponse struct{}
+
+// Execute executes the DeleteTelegramLink command.
+func (s *DeleteTelegramLinkService) Execute(
+	ctx context.Context,
+	req *DeleteTelegramLinkCommand,
+) (*DeleteTelegramLinkResponse, error) {
+	valErr := s.validator.ValidateStruct(req)
+	if valErr != nil {
+		return nil, fmt.Errorf("failed to validate request: %w", valErr)
+	}
+	parsedUUID, err := uuid.Parse(req.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid uuid format: %w", err)
+	}
+
+	err = s.telegramRepo.DeleteLink(ctx, parsedUUID)
+	if errors.Is(err, telegram.ErrNoLinkFound) {
+		return nil, ErrNoTelegramLink
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to delete telegram link: %w", err)
+	}
+	return &DeleteTelegramLinkResponse{}, nil
+}
+
+// TelegramUpdatesHandler is an interface for handling updates of the bot.
+type TelegramUpdatesHandler interface {
+	PollUpdates(ctx context.Context) error
+}
+
+// TelegramBotService links chats by deep link codes and handles
+// presses of inline buttons under intake reminders.
+type TelegramBotService struct {
+	telegramRepo     telegram.Repository
+	historyRepo      history.Repository
+	telegramProvider telegramProvider.TelegramProvider
+	planningProvider planningProvider.PlanningProvider
+	// offset is id of the next update, it is used only by polling goroutine.
+	offset int64
+}
+
+// NewTelegramBotService returns a new TelegramBotService.
+func NewTelegramBotService(
+	telegramRepo telegram.Repository,
+	historyRepo history.Repository,
+	telegramProvider telegramProvider.TelegramProvider,
+	planningProvider planningProvider.PlanningProvider,
+) *TelegramBotService {
+	return &TelegramBotService{
+		telegramRepo:     telegramRepo,
+		historyRepo:      historyRepo,
+		telegramProvider: telegramProvider,
+		planningProvider: planningProvider,
+		offset:           0,
+	}
+}
+
+// PollUpdates waits for updates of the bot and handles them.
+// Failed updates are not repeated, the user may press the button again.
+func (s *TelegramBotService) PollUpdates(ctx context.Context) error {
+	updates, err := s.telegramProvider.Updates(ctx, s.offset)
+	if err != nil {
+		return fmt.Errorf("failed to get telegram updates: %w", err)
+	}
+
+	var errs []error
+	for _, update := range updates {
+		s.offset = max(s.offset, update.ID+1)
+		if update.CallbackID != "" {
+			errs = append(errs, s.handleCallback(ctx, update))
+			continue
+		}
+		if command, code, _ := strings.Cut(update.Text, " "); command == startCommand {
+			errs = append(errs, s.handleStart(ctx, update.ChatID, code))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// handleStart links the chat by the code of deep link.
+func (s *TelegramBotService) handleStart(ctx context.Context, chatID int64, code string) error {
+	if code == "" {
+		return s.reply(ctx, chatID, "Чтобы получать напоминания, "+
+			"откройте ссылку для подключения Telegram в профиле MyHealthBox.")
+	}
+
+	linkCode, err := s.telegramRepo.TakeCode(ctx, code)
+	if errors.Is(err, telegram.ErrNoLinkCodeFound) {
+		return s.reply(ctx, chatID, "Ссылка недействительна, получите новую в профиле MyHealthBox.")
+	} else if err != nil {
+		return fmt.Errorf("failed to get link code: %w", err)
+	}
+	link, err := linkCode.Redeem(chatID, time.Now())
+	if errors.Is(err, telegram.ErrLinkCodeExpired) {
+		return s.reply(ctx, chatID, "Ссылка устарела, получите новую в профиле MyHealthBox.")
+	} else if err != nil {
+		return fmt.Errorf("failed to redeem link code: %w", err)
+	}
+	if err := s.telegramRepo.SaveLink(ctx, link); err != nil {
+		return fmt.Errorf("failed to save telegram link: %w", err)
+	}
+	return s.reply(ctx, chatID, "Готово! Напоминания о приёме лекарств будут приходить сюда.")
+}
+
+// handleCallback makes quick reply to the reminder the button is pressed under.
+func (s *TelegramBotService) handleCallback(
+	ctx context.Context,
+	update telegramProvider.Update,
+) error {
+	answer, err := s.quickReply(ctx, update.ChatID, update.CallbackData)
+	return errors.Join(err, s.telegramProvider.AnswerCallback(ctx, update.CallbackID, answer))
+}
+
+// quickReply performs the action of the button and returns answer to show to the user.
+// Notification of the button must be sent to the account linked to the chat.
+func (s *TelegramBotService) quickReply(
+	ctx context.Context,
+	chatID int64,
+	data string,
+) (string, error) {
+	const failed = "Не получилось, попробуйте в приложении MyHealthBox"
+
+	action, notificationID, _ := strings.Cut(data, callbackSeparator)
+	parsedNotificationID, err := uuid.Parse(notificationID)
+	if err != nil {
+		return failed, nil
+	}
+	link, err := s.telegramRepo.GetLinkByChatID(ctx, chatID)
+	if errors.Is(err, telegram.ErrNoLinkFound) {
+		return "Telegram не подключён к аккаунту MyHealthBox", nil
+	} else if err != nil {
+		return failed, fmt.Errorf("failed to get telegram link: %w", err)
+	}
+	sentNotification, err := s.historyRepo.GetByID(ctx, parsedNotificationID)
+	if errors.Is(err, history.ErrNoNotificationFound) ||
+		err == nil && sentNotification.UserID() != link.UserID() {
+		return failed, nil
+	} else if err != nil {
+		return failed, fmt.Errorf("failed to get notification: %w", err)
+	}
+
+	owner, recordID, err := intakeOf(sentNotification.Content(), link.UserID())
+	if err != nil {
+		return failed, nil
+	}
+
+	var answer string
+	switch action {
+	case actionTake:
+		err = s.planningProvider.TakeIntake(ctx, owner, recordID)
+		answer = "Приём отмечен"
+	case actionSnooze:
+		var until time.Time
+		until, err = s.planningProvider.SnoozeIntake(ctx, owner, recordID)
+		minutes := int(math.Round(time.Until(until).Minutes()))
+		answer = fmt.Sprintf("Напомним через %d мин", minutes)
+	default:
+		return failed, nil
+	}
+	switch {
+	case errors.Is(err, planningProvider.ErrIntakeTaken):
+		answer = "Приём уже отмечен"
+	case errors.Is(err, planningProvider.ErrNoIntake):
+		return "Приём не найден", nil
+	case err != nil:
+		return failed, err
+	}
+
+	sentNotification.MarkRead(time.Now())
+	if err := s.historyRepo.Save(ctx, sentNotification); err != nil {
+		return answer, fmt.Errorf("failed to mark notification read: %w", err)
+	}
+	return answer, nil
+}
+
+// intakeOf returns owner of the plan and the intake the reminder is about,
+// reminders without profileId are about intakes of the account.
+func intakeOf(content notification.Content, accountID uuid.UUID) (uuid.UUID, uuid.UUID, error) {
+	recordID, err := uuid.Parse(content.Data["recordId"])
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	owner, err := uuid.Parse(content.Data["profileId"])
+	if err != nil {
+		owner = accountID
+	}
+	return owner, recordID, nil
+}
+
+// reply sends a text message to the chat.
+func (s *TelegramBotService) reply(ctx context.Context, chatID int64, text string) error {
+	return s.telegramProvider.SendMessage(ctx, chatID, notification.Content{Body: text}, nil)
+}
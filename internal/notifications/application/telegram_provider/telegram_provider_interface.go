@@ -0,0 +1,43 @@
+// Package telegramprovider is a package for interface for Telegram bot client.
+package telegramprovider
+
+import (
+	"context"
+	"errors"
+
+	"github.com/FSO-VK/final-project-vk-backend/internal/notifications/domain/notification"
+)
+
+// ErrChatGone is returned when the bot can't write to the chat anymore,
+// e.g. the user blocked the bot.
+var ErrChatGone = errors.New("telegram chat is gone")
+
+// TelegramProvider sends messages of the bot and receives updates from users.
+type TelegramProvider interface {
+	SendMessage(
+		ctx context.Context,
+		chatID int64,
+		content notification.Content,
+		buttons []Button,
+	) error
+	AnswerCallback(ctx context.Context, callbackID string, text string) error
+	// Updates waits for updates with id not less than offset.
+	Updates(ctx context.Context, offset int64) ([]Update, error)
+}
+
+// Button is an inline button under the message,
+// Data is sent back in the update when the user presses the button.
+type Button struct {
+	Title string
+	Data  string
+}
+
+// Update is a message of the user to the bot or a press of the inline button.
+// CallbackID and CallbackData are set for presses of buttons.
+type Update struct {
+	ID           int64
+	ChatID       int64
+	Text         string
+	CallbackID   string
+	CallbackData string
+}
@@ -0,0 +1,26 @@
+// Package templateprovider is a package for interface for templates of notifications.
+package templateprovider
+
+import "errors"
+
+var (
+	// ErrNoTemplate is returned when template is not found in any locale.
+	ErrNoTemplate = errors.New("template not found")
+	// ErrInvalidParams is returned when params don't fit the template.
+	ErrInvalidParams = errors.New("invalid template params")
+)
+
+// TemplateProvider renders messages of notifications in languages of users.
+type TemplateProvider interface {
+	// Render renders the template with params in the locale,
+	// templates missing in the locale are rendered in the default one.
+	Render(locale string, template string, params map[string]any) (*Message, error)
+}
+
+// Message is a rendered text of notification,
+// Actions are titles of buttons keyed by action ids.
+type Message struct {
+	Title   string
+	Body    string
+	Actions map[string]string
+}
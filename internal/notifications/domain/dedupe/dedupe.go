@@ -0,0 +1,64 @@
+// Package dedupe is a domain layer for dedupe keys of sent notifications,
+// notification with a key recently sent to the user is a duplicate and is dropped.
+package dedupe
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxKeyLength is a maximum length of dedupe key in bytes.
+const MaxKeyLength = 128
+
+// Window is how long the key of sent notification is remembered,
+// callers retry failed requests and restart jobs well within it.
+const Window = 48 * time.Hour
+
+// ErrInvalidKey is an error when dedupe key is empty or too long.
+var ErrInvalidKey = errors.New("invalid dedupe key")
+
+// Key is a dedupe key chosen by the caller, e.g. "intake-reminder-<record id>-<time>".
+// Keys of different users don't clash.
+type Key struct {
+	userID uuid.UUID
+	value  string
+}
+
+// NewKey creates a new dedupe key of notification to the user.
+func NewKey(userID uuid.UUID, value string) (Key, error) {
+	if value == "" || len(value) > MaxKeyLength {
+		return Key{}, ErrInvalidKey
+	}
+	return Key{
+		userID: userID,
+		value:  value,
+	}, nil
+}
+
+// UserID returns id of the user the notification is sent to.
+func (k Key) UserID() uuid.UUID {
+	return k.userID
+}
+
+// Value returns the key chosen by the caller.
+func (k Key) Value() string {
+	return k.value
+}
+
+// String returns the key unique among all users.
+func (k Key) String() string {
+	return k.userID.String() + "/" + k.value
+}
+
+// NotificationID returns id of the notification sent with the key,
+// retries of the notification get the same id, so it is kept in history once.
+func (k Key) NotificationID() uuid.UUID {
+	return uuid.NewSHA1(k.userID, []byte(k.value))
+}
+
+// IsRemembered tells whether the key claimed at claimedAt is still remembered at t.
+func IsRemembered(claimedAt time.Time, t time.Time) bool {
+	return t.Sub(claimedAt) < Window
+}
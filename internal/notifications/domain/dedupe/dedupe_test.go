@@ -0,0 +1,129 @@
+package dedupe_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/FSO-VK/final-project-vk-backend/internal/notifications/domain/dedupe"
+	"github.com/google/uuid"
+)
+
+func TestNewKey(t *testing.T) {
+	t.Parallel()
+	userID := uuid.New()
+	tests := []struct {
+		name    string // description of this test case
+		value   string
+		wantErr error
+	}{
+		{
+			name:    "Should create key",
+			value:   "intake-reminder-" + uuid.NewString(),
+			wantErr: nil,
+		},
+		{
+			name:    "Should fail on empty key",
+			value:   "",
+			wantErr: dedupe.ErrInvalidKey,
+		},
+		{
+			name:    "Should accept key of max length",
+			value:   strings.Repeat("k", dedupe.MaxKeyLength),
+			wantErr: nil,
+		},
+		{
+			name:    "Should fail on too long key",
+			value:   strings.Repeat("k", dedupe.MaxKeyLength+1),
+			wantErr: dedupe.ErrInvalidKey,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			key, err := dedupe.NewKey(userID, tt.value)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewKey() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if key.Value() != tt.value || key.UserID() != userID {
+				t.Errorf("NewKey() = %v, want %v of %v", key, tt.value, userID)
+			}
+		})
+	}
+}
+
+func TestKeysOfUsersDiffer(t *testing.T) {
+	t.Parallel()
+	first, err := dedupe.NewKey(uuid.New(), "key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := dedupe.NewKey(uuid.New(), "key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.String() == second.String() {
+		t.Errorf("String() = %v for keys of different users", first.String())
+	}
+}
+
+func TestKey_NotificationID(t *testing.T) {
+	t.Parallel()
+	userID := uuid.New()
+	first, err := dedupe.NewKey(userID, "key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	retried, err := dedupe.NewKey(userID, "key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := dedupe.NewKey(uuid.New(), "key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.NotificationID() != retried.NotificationID() {
+		t.Errorf("NotificationID() differs for retries of the same key")
+	}
+	if first.NotificationID() == other.NotificationID() {
+		t.Errorf("NotificationID() = %v for keys of different users", first.NotificationID())
+	}
+}
+
+func TestIsRemembered(t *testing.T) {
+	t.Parallel()
+	claimedAt := time.Date(2025, time.March, 10, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string // description of this test case
+		t    time.Time
+		want bool
+	}{
+		{
+			name: "Should remember key right after claim",
+			t:    claimedAt,
+			want: true,
+		},
+		{
+			name: "Should remember key within window",
+			t:    claimedAt.Add(dedupe.Window - time.Second),
+			want: true,
+		},
+		{
+			name: "Should forget key after window",
+			t:    claimedAt.Add(dedupe.Window),
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := dedupe.IsRemembered(claimedAt, tt.t); got != tt.want {
+				t.Errorf("IsRemembered() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,17 @@
+package dedupe
+
+import (
+	"context"
+	"time"
+)
+
+// Repository is a domain repository interface that defines
+// data access contract for dedupe keys of sent notifications.
+type Repository interface {
+	// Claim remembers the key at t, it returns false if the key is already
+	// remembered, i.e. the notification is a duplicate.
+	Claim(ctx context.Context, key Key, t time.Time) (bool, error)
+	// Release forgets the key, so the notification which failed to be sent
+	// may be sent again.
+	Release(ctx context.Context, key Key) error
+}
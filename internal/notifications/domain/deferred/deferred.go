@@ -0,0 +1,70 @@
+// Package deferred is a domain layer for notifications held back until later.
+package deferred
+
+import (
+	"time"
+
+	"github.com/FSO-VK/final-project-vk-backend/internal/notifications/domain/notification"
+	"github.com/google/uuid"
+)
+
+// Notification is a notification to deliver to the user at sendAt.
+// Digest notifications due at the same time are delivered as one summary.
+type Notification struct {
+	id       uuid.UUID
+	userID   uuid.UUID
+	category string
+	content  notification.Content
+	sendAt   time.Time
+	digest   bool
+}
+
+// NewNotification creates a new deferred notification,
+// id is the same as of the notification in history.
+func NewNotification(
+	id uuid.UUID,
+	userID uuid.UUID,
+	category string,
+	content notification.Content,
+	sendAt time.Time,
+	digest bool,
+) *Notification {
+	return &Notification{
+		id:       id,
+		userID:   userID,
+		category: category,
+		content:  content,
+		sendAt:   sendAt,
+		digest:   digest,
+	}
+}
+
+// ID returns id of the notification.
+func (n *Notification) ID() uuid.UUID {
+	return n.id
+}
+
+// UserID returns id of the account to notify.
+func (n *Notification) UserID() uuid.UUID {
+	return n.userID
+}
+
+// Category returns category of the notification.
+func (n *Notification) Category() string {
+	return n.category
+}
+
+// Content returns what is shown to the user.
+func (n *Notification) Content() notification.Content {
+	return n.content
+}
+
+// SendAt returns time to deliver the notification.
+func (n *Notification) SendAt() time.Time {
+	return n.sendAt
+}
+
+// IsDigest tells whether the notification is delivered as a part of summary.
+func (n *Notification) IsDigest() bool {
+	return n.digest
+}
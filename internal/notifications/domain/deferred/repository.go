@@ -0,0 +1,21 @@
+package deferred
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrNoNotificationFound is an error when deferred notification is not found.
+var ErrNoNotificationFound = errors.New("deferred notification not found")
+
+// Repository is a domain repository interface that defines
+// data access contract for deferred notifications.
+type Repository interface {
+	Add(ctx context.Context, n *Notification) error
+	// Due returns notifications to deliver at t or earlier ordered by send time.
+	Due(ctx context.Context, t time.Time) ([]*Notification, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
@@ -0,0 +1,102 @@
+// Package history is a domain layer for notifications sent to users,
+// they are kept to show in-app inbox.
+package history
+
+import (
+	"time"
+
+	"github.com/FSO-VK/final-project-vk-backend/internal/notifications/domain/notification"
+	"github.com/google/uuid"
+)
+
+// Delivery is a status of delivery of the notification by a channel,
+// SubscriptionID is set for deliveries to push subscriptions.
+type Delivery struct {
+	Channel        string
+	SubscriptionID uuid.UUID
+	Status         string
+}
+
+// Notification is a notification sent to the user.
+type Notification struct {
+	id       uuid.UUID
+	userID   uuid.UUID
+	category string
+	content  notification.Content
+	// deliveries is empty while notification is deferred
+	// and for users without available channels.
+	deliveries []Delivery
+	createdAt  time.Time
+	readAt     time.Time
+}
+
+// NewNotification creates a new unread notification.
+func NewNotification(
+	id uuid.UUID,
+	userID uuid.UUID,
+	category string,
+	content notification.Content,
+	createdAt time.Time,
+) *Notification {
+	return &Notification{
+		id:         id,
+		userID:     userID,
+		category:   category,
+		content:    content,
+		deliveries: nil,
+		createdAt:  createdAt,
+		readAt:     time.Time{},
+	}
+}
+
+// ID returns id of the notification.
+func (n *Notification) ID() uuid.UUID {
+	return n.id
+}
+
+// UserID returns id of the account the notification is sent to.
+func (n *Notification) UserID() uuid.UUID {
+	return n.userID
+}
+
+// Category returns category of the notification.
+func (n *Notification) Category() string {
+	return n.category
+}
+
+// Content returns what is shown to the user.
+func (n *Notification) Content() notification.Content {
+	return n.content
+}
+
+// Deliveries returns statuses of delivery by channels available to the user.
+func (n *Notification) Deliveries() []Delivery {
+	return n.deliveries
+}
+
+// SetDeliveries replaces statuses of delivery by channels available to the user.
+func (n *Notification) SetDeliveries(deliveries []Delivery) {
+	n.deliveries = deliveries
+}
+
+// CreatedAt returns time the notification was sent.
+func (n *Notification) CreatedAt() time.Time {
+	return n.createdAt
+}
+
+// MarkRead marks notification read at t, the first read time is kept.
+func (n *Notification) MarkRead(t time.Time) {
+	if n.readAt.IsZero() {
+		n.readAt = t
+	}
+}
+
+// IsRead tells whether the user has read the notification.
+func (n *Notification) IsRead() bool {
+	return !n.readAt.IsZero()
+}
+
+// ReadAt returns time the notification was read, zero if it is unread.
+func (n *Notification) ReadAt() time.Time {
+	return n.readAt
+}
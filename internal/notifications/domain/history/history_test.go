@@ -0,0 +1,58 @@
+package history_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/FSO-VK/final-project-vk-backend/internal/notifications/domain/history"
+	"github.com/FSO-VK/final-project-vk-backend/internal/notifications/domain/notification"
+	"github.com/google/uuid"
+)
+
+func TestNotification_MarkRead(t *testing.T) {
+	t.Parallel()
+	now := time.Now()
+	tests := []struct {
+		name string // description of this test case
+		// Named input parameters for target function.
+		reads      []time.Time
+		wantReadAt time.Time
+	}{
+		{
+			name:       "Should be unread after creation",
+			reads:      nil,
+			wantReadAt: time.Time{},
+		},
+		{
+			name:       "Should remember read time",
+			reads:      []time.Time{now},
+			wantReadAt: now,
+		},
+		{
+			name:       "Should keep the first read time",
+			reads:      []time.Time{now, now.Add(time.Hour)},
+			wantReadAt: now,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			n := history.NewNotification(
+				uuid.New(),
+				uuid.New(),
+				"intake",
+				notification.Content{Title: "title"},
+				now,
+			)
+			for _, read := range tt.reads {
+				n.MarkRead(read)
+			}
+			if !n.ReadAt().Equal(tt.wantReadAt) {
+				t.Errorf("ReadAt() = %v, want %v", n.ReadAt(), tt.wantReadAt)
+			}
+			if n.IsRead() == tt.wantReadAt.IsZero() {
+				t.Errorf("IsRead() = %v, want %v", n.IsRead(), !tt.wantReadAt.IsZero())
+			}
+		})
+	}
+}
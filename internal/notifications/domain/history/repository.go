@@ -0,0 +1,30 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrNoNotificationFound is an error when notification is not found in history.
+var ErrNoNotificationFound = errors.New("notification not found")
+
+// Repository is a domain repository interface that defines
+// data access contract for history of notifications.
+type Repository interface {
+	Save(ctx context.Context, n *Notification) error
+	GetByID(ctx context.Context, id uuid.UUID) (*Notification, error)
+	// ListByUserID returns a page of notifications of the user, newest first,
+	// and total number of them.
+	ListByUserID(
+		ctx context.Context,
+		userID uuid.UUID,
+		offset int,
+		limit int,
+	) ([]*Notification, int, error)
+	CountUnread(ctx context.Context, userID uuid.UUID) (int, error)
+	// MarkAllRead marks all unread notifications of the user read at t.
+	MarkAllRead(ctx context.Context, userID uuid.UUID, t time.Time) error
+}
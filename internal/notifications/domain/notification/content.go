@@ -0,0 +1,45 @@
+// Package notification is a domain layer for content of notifications.
+package notification
+
+// Urgency is a priority of push notification for push service,
+// low urgency notifications may be delayed to save battery.
+type Urgency string
+
+const (
+	// UrgencyVeryLow is for notifications which may wait for power and Wi-Fi.
+	UrgencyVeryLow Urgency = "very-low"
+	// UrgencyLow is for notifications which may wait for power or Wi-Fi.
+	UrgencyLow Urgency = "low"
+	// UrgencyNormal is a default urgency.
+	UrgencyNormal Urgency = "normal"
+	// UrgencyHigh is for time-critical notifications.
+	UrgencyHigh Urgency = "high"
+)
+
+// Action is a button of notification, service worker handles click by Action id.
+type Action struct {
+	Action string
+	Title  string
+}
+
+// Content is what is shown to the user. Type, URL and Data are for service worker
+// to handle clicks. Notification with the same Tag replaces the previous one, the
+// user is alerted again about replacement only if Renotify is set.
+// If Template is set, Title, Body and titles of Actions are rendered from
+// the template with Params in the language of the user, Locale is the language
+// content is shown in.
+type Content struct {
+	Type     string
+	Title    string
+	Body     string
+	URL      string
+	Icon     string
+	Tag      string
+	Renotify bool
+	Urgency  Urgency
+	Actions  []Action
+	Data     map[string]string
+	Template string
+	Params   map[string]any
+	Locale   string
+}
@@ -0,0 +1,322 @@
+// Package preferences is a domain layer for notification preferences of users.
+package preferences
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrInvalidCategory is an error when notification category is unknown.
+	ErrInvalidCategory = errors.New("invalid notification category")
+	// ErrInvalidQuietHours is an error when quiet hours are not valid.
+	ErrInvalidQuietHours = errors.New("invalid quiet hours")
+	// ErrInvalidEmailMode is an error when email mode is unknown.
+	ErrInvalidEmailMode = errors.New("invalid email mode")
+	// ErrInvalidLocale is an error when locale is not supported.
+	ErrInvalidLocale = errors.New("invalid locale")
+)
+
+// Category is a kind of notification the user may turn off.
+type Category string
+
+const (
+	// CategoryIntake is for intake reminders.
+	CategoryIntake Category = "intake"
+	// CategoryExpiration is for expiring medications.
+	CategoryExpiration Category = "expiration"
+	// CategoryRefill is for medications running out.
+	CategoryRefill Category = "refill"
+	// CategorySecurity is for account security events.
+	CategorySecurity Category = "security"
+)
+
+// Categories returns all known categories.
+func Categories() []Category {
+	return []Category{CategoryIntake, CategoryExpiration, CategoryRefill, CategorySecurity}
+}
+
+// ParseCategory parses category, empty string is a notification without category.
+func ParseCategory(s string) (Category, error) {
+	c := Category(s)
+	if c == "" {
+		return c, nil
+	}
+	for _, known := range Categories() {
+		if c == known {
+			return c, nil
+		}
+	}
+	return "", ErrInvalidCategory
+}
+
+// QuietMode is what to do with notifications during quiet hours.
+type QuietMode string
+
+const (
+	// QuietModeDefer delivers every notification after quiet hours.
+	QuietModeDefer QuietMode = "defer"
+	// QuietModeDigest delivers one summary notification after quiet hours.
+	QuietModeDigest QuietMode = "digest"
+)
+
+// EmailMode is when notifications are sent to email of the user.
+type EmailMode string
+
+const (
+	// EmailOff never sends notifications to email.
+	EmailOff EmailMode = "off"
+	// EmailFallback sends notification to email if it is not delivered to any device.
+	EmailFallback EmailMode = "fallback"
+	// EmailAlways sends every notification to email along with push.
+	EmailAlways EmailMode = "always"
+)
+
+// ParseEmailMode parses email mode, empty string is the default fallback mode.
+func ParseEmailMode(s string) (EmailMode, error) {
+	switch m := EmailMode(s); m {
+	case "":
+		return EmailFallback, nil
+	case EmailOff, EmailFallback, EmailAlways:
+		return m, nil
+	}
+	return "", ErrInvalidEmailMode
+}
+
+// Locale is a language notifications are shown to the user in.
+type Locale string
+
+const (
+	// LocaleRU is Russian, it is the default locale.
+	LocaleRU Locale = "ru"
+	// LocaleEN is English.
+	LocaleEN Locale = "en"
+)
+
+// ParseLocale parses locale, empty string is the default Russian locale.
+func ParseLocale(s string) (Locale, error) {
+	switch l := Locale(s); l {
+	case "":
+		return LocaleRU, nil
+	case LocaleRU, LocaleEN:
+		return l, nil
+	}
+	return "", ErrInvalidLocale
+}
+
+// QuietHours is a daily period in the time zone of the user when
+// notifications are held back. Period may span midnight.
+type QuietHours struct {
+	start    time.Duration
+	end      time.Duration
+	location *time.Location
+	mode     QuietMode
+}
+
+// NewQuietHours creates quiet hours from start and end offsets since local midnight.
+func NewQuietHours(
+	start, end time.Duration,
+	location *time.Location,
+	mode QuietMode,
+) (*QuietHours, error) {
+	const day = 24 * time.Hour
+	if start < 0 || start >= day || end < 0 || end >= day || start == end {
+		return nil, ErrInvalidQuietHours
+	}
+	if location == nil {
+		return nil, ErrInvalidQuietHours
+	}
+	if mode != QuietModeDefer && mode != QuietModeDigest {
+		return nil, ErrInvalidQuietHours
+	}
+	return &QuietHours{
+		start:    start,
+		end:      end,
+		location: location,
+		mode:     mode,
+	}, nil
+}
+
+// Start returns the offset of the beginning of quiet hours since local midnight.
+func (q *QuietHours) Start() time.Duration {
+	return q.start
+}
+
+// End returns the offset of the end of quiet hours since local midnight.
+func (q *QuietHours) End() time.Duration {
+	return q.end
+}
+
+// Location returns time zone of quiet hours.
+func (q *QuietHours) Location() *time.Location {
+	return q.location
+}
+
+// Mode returns what to do with notifications during quiet hours.
+func (q *QuietHours) Mode() QuietMode {
+	return q.mode
+}
+
+// Until returns the end of quiet hours if t is within them.
+func (q *QuietHours) Until(t time.Time) (time.Time, bool) {
+	local := t.In(q.location)
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, q.location)
+	offset := local.Sub(midnight)
+
+	switch {
+	case q.start < q.end && offset >= q.start && offset < q.end:
+		return atOffset(midnight, q.end), true
+	case q.start > q.end && offset >= q.start:
+		return atOffset(midnight.AddDate(0, 0, 1), q.end), true
+	case q.start > q.end && offset < q.end:
+		return atOffset(midnight, q.end), true
+	}
+	return time.Time{}, false
+}
+
+// atOffset returns wall clock time of the day, so DST changes don't shift it.
+func atOffset(midnight time.Time, offset time.Duration) time.Time {
+	return time.Date(
+		midnight.Year(), midnight.Month(), midnight.Day(),
+		int(offset/time.Hour), int(offset%time.Hour/time.Minute), 0, 0,
+		midnight.Location(),
+	)
+}
+
+// Preferences is an aggregate of notification settings of a user.
+// By default all categories are enabled, there are no quiet hours,
+// no devices are muted, email is used when push is not delivered
+// and notifications are in Russian.
+type Preferences struct {
+	userID       uuid.UUID
+	disabled     map[Category]struct{}
+	quietHours   *QuietHours
+	mutedDevices map[uuid.UUID]struct{}
+	emailMode    EmailMode
+	locale       Locale
+	updatedAt    time.Time
+}
+
+// NewPreferences creates default preferences of the user.
+func NewPreferences(userID uuid.UUID) *Preferences {
+	return &Preferences{
+		userID:       userID,
+		disabled:     make(map[Category]struct{}),
+		quietHours:   nil,
+		mutedDevices: make(map[uuid.UUID]struct{}),
+		emailMode:    EmailFallback,
+		locale:       LocaleRU,
+		updatedAt:    time.Now(),
+	}
+}
+
+// UserID returns id of the user.
+func (p *Preferences) UserID() uuid.UUID {
+	return p.userID
+}
+
+// IsEnabled tells whether notifications of the category are sent.
+// Notifications without category are always sent.
+func (p *Preferences) IsEnabled(c Category) bool {
+	_, disabled := p.disabled[c]
+	return !disabled
+}
+
+// SetEnabled turns notifications of the category on or off.
+func (p *Preferences) SetEnabled(c Category, enabled bool) error {
+	if c == "" {
+		return ErrInvalidCategory
+	}
+	if _, err := ParseCategory(string(c)); err != nil {
+		return err
+	}
+	if enabled {
+		delete(p.disabled, c)
+	} else {
+		p.disabled[c] = struct{}{}
+	}
+	p.updatedAt = time.Now()
+	return nil
+}
+
+// QuietHours returns quiet hours, nil if they are not set.
+func (p *Preferences) QuietHours() *QuietHours {
+	return p.quietHours
+}
+
+// SetQuietHours replaces quiet hours, nil turns them off.
+func (p *Preferences) SetQuietHours(q *QuietHours) {
+	p.quietHours = q
+	p.updatedAt = time.Now()
+}
+
+// QuietUntil returns the end of quiet hours if t is within them.
+func (p *Preferences) QuietUntil(t time.Time) (time.Time, bool) {
+	if p.quietHours == nil {
+		return time.Time{}, false
+	}
+	return p.quietHours.Until(t)
+}
+
+// IsDeviceMuted tells whether notifications are not sent to the push subscription.
+func (p *Preferences) IsDeviceMuted(subscriptionID uuid.UUID) bool {
+	_, muted := p.mutedDevices[subscriptionID]
+	return muted
+}
+
+// MutedDevices returns ids of muted push subscriptions.
+func (p *Preferences) MutedDevices() []uuid.UUID {
+	result := make([]uuid.UUID, 0, len(p.mutedDevices))
+	for id := range p.mutedDevices {
+		result = append(result, id)
+	}
+	return result
+}
+
+// SetMutedDevices replaces muted push subscriptions.
+func (p *Preferences) SetMutedDevices(subscriptionIDs []uuid.UUID) {
+	p.mutedDevices = make(map[uuid.UUID]struct{}, len(subscriptionIDs))
+	for _, id := range subscriptionIDs {
+		p.mutedDevices[id] = struct{}{}
+	}
+	p.updatedAt = time.Now()
+}
+
+// EmailMode returns when notifications are sent to email.
+func (p *Preferences) EmailMode() EmailMode {
+	return p.emailMode
+}
+
+// SetEmailMode changes when notifications are sent to email.
+func (p *Preferences) SetEmailMode(m EmailMode) error {
+	switch m {
+	case EmailOff, EmailFallback, EmailAlways:
+	default:
+		return ErrInvalidEmailMode
+	}
+	p.emailMode = m
+	p.updatedAt = time.Now()
+	return nil
+}
+
+// Locale returns language of notifications.
+func (p *Preferences) Locale() Locale {
+	return p.locale
+}
+
+// SetLocale changes language of notifications.
+func (p *Preferences) SetLocale(l Locale) error {
+	if _, err := ParseLocale(string(l)); err != nil || l == "" {
+		return ErrInvalidLocale
+	}
+	p.locale = l
+	p.updatedAt = time.Now()
+	return nil
+}
+
+// UpdatedAt returns time of the last change.
+func (p *Preferences) UpdatedAt() time.Time {
+	return p.updatedAt
+}
@@ -0,0 +1,211 @@
+package preferences_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/FSO-VK/final-project-vk-backend/internal/notifications/domain/preferences"
+)
+
+func TestQuietHours_Until(t *testing.T) {
+	t.Parallel()
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string // description of this test case
+		// Named input parameters for target function.
+		start time.Duration
+		end   time.Duration
+		now   time.Time
+		// want is the end of quiet hours, zero if now is not quiet.
+		want time.Time
+	}{
+		{
+			name:  "Should be quiet after start of night period",
+			start: 22 * time.Hour,
+			end:   7 * time.Hour,
+			now:   time.Date(2024, 1, 1, 23, 30, 0, 0, moscow),
+			want:  time.Date(2024, 1, 2, 7, 0, 0, 0, moscow),
+		},
+		{
+			name:  "Should be quiet after midnight of night period",
+			start: 22 * time.Hour,
+			end:   7 * time.Hour,
+			now:   time.Date(2024, 1, 2, 6, 59, 0, 0, moscow),
+			want:  time.Date(2024, 1, 2, 7, 0, 0, 0, moscow),
+		},
+		{
+			name:  "Should not be quiet at the end of night period",
+			start: 22 * time.Hour,
+			end:   7 * time.Hour,
+			now:   time.Date(2024, 1, 2, 7, 0, 0, 0, moscow),
+			want:  time.Time{},
+		},
+		{
+			name:  "Should be quiet within day period",
+			start: 13 * time.Hour,
+			end:   14*time.Hour + 30*time.Minute,
+			now:   time.Date(2024, 1, 1, 13, 0, 0, 0, moscow),
+			want:  time.Date(2024, 1, 1, 14, 30, 0, 0, moscow),
+		},
+		{
+			name:  "Should not be quiet before day period",
+			start: 13 * time.Hour,
+			end:   14 * time.Hour,
+			now:   time.Date(2024, 1, 1, 12, 59, 0, 0, moscow),
+			want:  time.Time{},
+		},
+		{
+			name:  "Should use time zone of quiet hours",
+			start: 22 * time.Hour,
+			end:   7 * time.Hour,
+			now:   time.Date(2024, 1, 1, 20, 0, 0, 0, time.UTC),
+			want:  time.Date(2024, 1, 2, 7, 0, 0, 0, moscow),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			q, err := preferences.NewQuietHours(
+				tt.start,
+				tt.end,
+				moscow,
+				preferences.QuietModeDefer,
+			)
+			if err != nil {
+				t.Fatalf("NewQuietHours() failed: %v", err)
+			}
+			got, quiet := q.Until(tt.now)
+			if quiet != !tt.want.IsZero() || !got.Equal(tt.want) {
+				t.Errorf("Until() = %v, %v, want %v", got, quiet, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewQuietHours(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string // description of this test case
+		start   time.Duration
+		end     time.Duration
+		mode    preferences.QuietMode
+		wantErr bool
+	}{
+		{
+			name:  "Should accept night period",
+			start: 22 * time.Hour,
+			end:   7 * time.Hour,
+			mode:  preferences.QuietModeDigest,
+		},
+		{
+			name:    "Should reject empty period",
+			start:   time.Hour,
+			end:     time.Hour,
+			mode:    preferences.QuietModeDefer,
+			wantErr: true,
+		},
+		{
+			name:    "Should reject end after day",
+			start:   time.Hour,
+			end:     24 * time.Hour,
+			mode:    preferences.QuietModeDefer,
+			wantErr: true,
+		},
+		{
+			name:    "Should reject unknown mode",
+			start:   time.Hour,
+			end:     2 * time.Hour,
+			mode:    "drop",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := preferences.NewQuietHours(tt.start, tt.end, time.UTC, tt.mode)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewQuietHours() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseEmailMode(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string // description of this test case
+		mode    string
+		want    preferences.EmailMode
+		wantErr error
+	}{
+		{
+			name: "Should use fallback by default",
+			mode: "",
+			want: preferences.EmailFallback,
+		},
+		{
+			name: "Should parse known mode",
+			mode: "always",
+			want: preferences.EmailAlways,
+		},
+		{
+			name:    "Should reject unknown mode",
+			mode:    "sometimes",
+			wantErr: preferences.ErrInvalidEmailMode,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := preferences.ParseEmailMode(tt.mode)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseEmailMode() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseEmailMode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseLocale(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string // description of this test case
+		locale  string
+		want    preferences.Locale
+		wantErr error
+	}{
+		{
+			name:   "Should use Russian by default",
+			locale: "",
+			want:   preferences.LocaleRU,
+		},
+		{
+			name:   "Should parse supported locale",
+			locale: "en",
+			want:   preferences.LocaleEN,
+		},
+		{
+			name:    "Should reject unsupported locale",
+			locale:  "de",
+			wantErr: preferences.ErrInvalidLocale,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := preferences.ParseLocale(tt.locale)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseLocale() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseLocale() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package preferences
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// ErrNoPreferencesFound is an error when user has not saved preferences.
+var ErrNoPreferencesFound = errors.New("preferences not found")
+
+// Repository is a domain repository interface that defines
+// data access contract for preferences aggregate.
+type Repository interface {
+	GetByUserID(ctx context.Context, userID uuid.UUID) (*Preferences, error)
+	Save(ctx context.Context, p *Preferences) error
+}
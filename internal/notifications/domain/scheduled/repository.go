@@ -0,0 +1,38 @@
+package scheduled
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	// ErrNoNotificationFound is an error when scheduled notification is not found.
+	ErrNoNotificationFound = errors.New("scheduled notification not found")
+	// ErrNotificationChanged is an error when scheduled notification was replaced,
+	// changed or removed since it was got.
+	ErrNotificationChanged = errors.New("scheduled notification changed")
+)
+
+// Repository is a domain repository interface that defines
+// data access contract for scheduled notifications.
+// Notifications returned by the repository are copies, they are changed
+// in the repository only by its methods.
+type Repository interface {
+	// Save saves the notification replacing one with the same caller and key,
+	// it tells whether a notification was replaced.
+	Save(ctx context.Context, n *Notification) (bool, error)
+	GetByKey(ctx context.Context, caller string, key string) (*Notification, error)
+	// Due returns at most limit notifications to send at t or earlier
+	// ordered by delivery time.
+	Due(ctx context.Context, t time.Time, limit int) ([]*Notification, error)
+	// Update saves changes of the notification got from the repository,
+	// it returns ErrNotificationChanged if the notification was replaced,
+	// changed or removed since it was got.
+	Update(ctx context.Context, n *Notification) error
+	// Delete removes the notification got from the repository,
+	// it returns ErrNotificationChanged if the notification was replaced,
+	// changed or removed since it was got.
+	Delete(ctx context.Context, n *Notification) error
+	DeleteByKey(ctx context.Context, caller string, key string) error
+}
@@ -0,0 +1,173 @@
+// Package scheduled is a domain layer for notifications scheduled by other services.
+package scheduled
+
+import (
+	"errors"
+	"time"
+
+	"github.com/FSO-VK/final-project-vk-backend/internal/notifications/domain/notification"
+	"github.com/google/uuid"
+)
+
+// MaxKeyLength is a maximum length of dedupe key in bytes.
+const MaxKeyLength = 128
+
+const (
+	// MaxAttempts is how many times delivery of the notification is attempted.
+	MaxAttempts = 5
+	// retryDelay is a delay before the first retry of failed delivery,
+	// it doubles every retry.
+	retryDelay = time.Minute
+)
+
+var (
+	// ErrInvalidKey is an error when dedupe key is empty or too long.
+	ErrInvalidKey = errors.New("invalid dedupe key")
+	// ErrNoCaller is an error when the service scheduling the notification is unknown.
+	ErrNoCaller = errors.New("scheduled notification has no caller")
+)
+
+// Notification is a notification to send at deliverAt.
+// Key is chosen by the caller, e.g. "intake-<record id>", scheduling
+// another notification with the same key replaces the previous one,
+// so the caller may cancel or reschedule it by the key.
+// Keys are namespaced by the caller, services can't touch notifications
+// scheduled by each other even if their keys are the same.
+// UserID may be an id of a dependant profile as in notifications sent now.
+type Notification struct {
+	caller    string
+	key       string
+	userID    uuid.UUID
+	category  string
+	content   notification.Content
+	deliverAt time.Time
+	// attempts is a number of failed deliveries.
+	attempts  int
+	createdAt time.Time
+	// version is changed by the repository on every save, so the repository
+	// rejects changes of the notification replaced or changed since it was got.
+	version uuid.UUID
+}
+
+// NewNotification creates a new scheduled notification.
+func NewNotification(
+	caller string,
+	key string,
+	userID uuid.UUID,
+	category string,
+	content notification.Content,
+	deliverAt time.Time,
+	createdAt time.Time,
+) (*Notification, error) {
+	if caller == "" {
+		return nil, ErrNoCaller
+	}
+	if key == "" || len(key) > MaxKeyLength {
+		return nil, ErrInvalidKey
+	}
+	return &Notification{
+		caller:    caller,
+		key:       key,
+		userID:    userID,
+		category:  category,
+		content:   content,
+		deliverAt: deliverAt,
+		createdAt: createdAt,
+		version:   uuid.Nil,
+	}, nil
+}
+
+// Restore returns the notification saved by the repository with its
+// attempts and version.
+func Restore(
+	caller string,
+	key string,
+	userID uuid.UUID,
+	category string,
+	content notification.Content,
+	deliverAt time.Time,
+	attempts int,
+	createdAt time.Time,
+	version uuid.UUID,
+) *Notification {
+	return &Notification{
+		caller:    caller,
+		key:       key,
+		userID:    userID,
+		category:  category,
+		content:   content,
+		deliverAt: deliverAt,
+		attempts:  attempts,
+		createdAt: createdAt,
+		version:   version,
+	}
+}
+
+// Caller returns name of the service which scheduled the notification.
+func (n *Notification) Caller() string {
+	return n.caller
+}
+
+// Key returns dedupe key of the notification in namespace of the caller.
+func (n *Notification) Key() string {
+	return n.key
+}
+
+// UserID returns id of the user to notify.
+func (n *Notification) UserID() uuid.UUID {
+	return n.userID
+}
+
+// Category returns category of the notification.
+func (n *Notification) Category() string {
+	return n.category
+}
+
+// Content returns what is shown to the user.
+func (n *Notification) Content() notification.Content {
+	return n.content
+}
+
+// DeliverAt returns time to send the notification.
+func (n *Notification) DeliverAt() time.Time {
+	return n.deliverAt
+}
+
+// CreatedAt returns time the notification was scheduled.
+func (n *Notification) CreatedAt() time.Time {
+	return n.createdAt
+}
+
+// Attempts returns a number of failed deliveries.
+func (n *Notification) Attempts() int {
+	return n.attempts
+}
+
+// Version returns version of the notification saved by the repository.
+func (n *Notification) Version() uuid.UUID {
+	return n.version
+}
+
+// Reschedule moves the notification to another time,
+// delivery is attempted again from scratch.
+func (n *Notification) Reschedule(deliverAt time.Time) {
+	n.deliverAt = deliverAt
+	n.attempts = 0
+}
+
+// RetryLater records failed delivery at t and moves the notification
+// to the next attempt with exponential backoff. It tells false
+// if attempts are exhausted and the notification must be dropped.
+func (n *Notification) RetryLater(t time.Time) bool {
+	n.attempts++
+	if n.attempts >= MaxAttempts {
+		return false
+	}
+	n.deliverAt = t.Add(retryDelay << (n.attempts - 1))
+	return true
+}
+
+// IsDue tells whether the notification must be sent at t.
+func (n *Notification) IsDue(t time.Time) bool {
+	return !n.deliverAt.After(t)
+}
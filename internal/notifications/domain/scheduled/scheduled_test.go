@@ -0,0 +1,198 @@
+package scheduled_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/FSO-VK/final-project-vk-backend/internal/notifications/domain/notification"
+	"github.com/FSO-VK/final-project-vk-backend/internal/notifications/domain/scheduled"
+	"github.com/google/uuid"
+)
+
+func TestNewNotification(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string // description of this test case
+		caller  string
+		key     string
+		wantErr error
+	}{
+		{
+			name:    "Should create notification with key",
+			caller:  "planning",
+			key:     "intake-" + uuid.NewString(),
+			wantErr: nil,
+		},
+		{
+			name:    "Should fail on empty key",
+			caller:  "planning",
+			key:     "",
+			wantErr: scheduled.ErrInvalidKey,
+		},
+		{
+			name:    "Should accept key of max length",
+			caller:  "planning",
+			key:     strings.Repeat("k", scheduled.MaxKeyLength),
+			wantErr: nil,
+		},
+		{
+			name:    "Should fail on too long key",
+			caller:  "planning",
+			key:     strings.Repeat("k", scheduled.MaxKeyLength+1),
+			wantErr: scheduled.ErrInvalidKey,
+		},
+		{
+			name:    "Should fail without caller",
+			caller:  "",
+			key:     "intake-" + uuid.NewString(),
+			wantErr: scheduled.ErrNoCaller,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			now := time.Now()
+			n, err := scheduled.NewNotification(
+				tt.caller,
+				tt.key,
+				uuid.New(),
+				"intake",
+				notification.Content{Title: "title"},
+				now.Add(time.Hour),
+				now,
+			)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewNotification() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && n.Key() != tt.key {
+				t.Errorf("Key() = %v, want %v", n.Key(), tt.key)
+			}
+		})
+	}
+}
+
+func TestNotification_IsDue(t *testing.T) {
+	t.Parallel()
+	now := time.Now()
+	tests := []struct {
+		name         string // description of this test case
+		deliverAt    time.Time
+		rescheduleTo time.Time
+		want         bool
+	}{
+		{
+			name:         "Should be due at delivery time",
+			deliverAt:    now,
+			rescheduleTo: time.Time{},
+			want:         true,
+		},
+		{
+			name:         "Should be due after delivery time",
+			deliverAt:    now.Add(-time.Minute),
+			rescheduleTo: time.Time{},
+			want:         true,
+		},
+		{
+			name:         "Should not be due before delivery time",
+			deliverAt:    now.Add(time.Minute),
+			rescheduleTo: time.Time{},
+			want:         false,
+		},
+		{
+			name:         "Should not be due after postponing",
+			deliverAt:    now.Add(-time.Minute),
+			rescheduleTo: now.Add(time.Hour),
+			want:         false,
+		},
+		{
+			name:         "Should be due after moving earlier",
+			deliverAt:    now.Add(time.Hour),
+			rescheduleTo: now.Add(-time.Second),
+			want:         true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			n, err := scheduled.NewNotification(
+				"planning",
+				"key",
+				uuid.New(),
+				"",
+				notification.Content{Title: "title"},
+				tt.deliverAt,
+				now,
+			)
+			if err != nil {
+				t.Fatalf("NewNotification() error = %v", err)
+			}
+			if !tt.rescheduleTo.IsZero() {
+				n.Reschedule(tt.rescheduleTo)
+			}
+			if got := n.IsDue(now); got != tt.want {
+				t.Errorf("IsDue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNotification_RetryLater(t *testing.T) {
+	t.Parallel()
+	now := time.Now()
+	n, err := scheduled.NewNotification(
+		"planning",
+		"key",
+		uuid.New(),
+		"",
+		notification.Content{Title: "title"},
+		now,
+		now,
+	)
+	if err != nil {
+		t.Fatalf("NewNotification() error = %v", err)
+	}
+
+	prevDelay := time.Duration(0)
+	for attempt := 1; attempt < scheduled.MaxAttempts; attempt++ {
+		if !n.RetryLater(now) {
+			t.Fatalf("RetryLater() = false on attempt %d", attempt)
+		}
+		delay := n.DeliverAt().Sub(now)
+		if delay <= prevDelay {
+			t.Errorf("attempt %d: delay %v is not longer than %v", attempt, delay, prevDelay)
+		}
+		if n.IsDue(now) {
+			t.Errorf("attempt %d: IsDue() = true right after failure", attempt)
+		}
+		prevDelay = delay
+	}
+	if n.RetryLater(now) {
+		t.Errorf("RetryLater() = true after %d attempts", scheduled.MaxAttempts)
+	}
+}
+
+func TestRestore(t *testing.T) {
+	t.Parallel()
+	now := time.Now()
+	version := uuid.New()
+	n := scheduled.Restore(
+		"planning",
+		"key",
+		uuid.New(),
+		"",
+		notification.Content{Title: "title"},
+		now,
+		scheduled.MaxAttempts-1,
+		now,
+		version,
+	)
+
+	if n.Version() != version {
+		t.Errorf("Version() = %v, want %v", n.Version(), version)
+	}
+	if n.RetryLater(now) {
+		t.Errorf("RetryLater() = true, want attempts restored to be exhausted")
+	}
+}
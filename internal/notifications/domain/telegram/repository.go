@@ -0,0 +1,29 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrNoLinkFound is an error when the user or the chat is not linked.
+	ErrNoLinkFound = errors.New("telegram link not found")
+	// ErrNoLinkCodeFound is an error when the code of deep link is unknown or used.
+	ErrNoLinkCodeFound = errors.New("telegram link code not found")
+)
+
+// Repository is a domain repository interface that defines
+// data access contract for Telegram chats linked to accounts.
+// User has at most one linked chat, new link replaces the previous one.
+type Repository interface {
+	SaveLink(ctx context.Context, l *Link) error
+	GetLinkByUserID(ctx context.Context, userID uuid.UUID) (*Link, error)
+	GetLinkByChatID(ctx context.Context, chatID int64) (*Link, error)
+	DeleteLink(ctx context.Context, userID uuid.UUID) error
+
+	SaveCode(ctx context.Context, c *LinkCode) error
+	// TakeCode returns the code and removes it, so it can be used only once.
+	TakeCode(ctx context.Context, code string) (*LinkCode, error)
+}
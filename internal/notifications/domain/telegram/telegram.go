@@ -0,0 +1,91 @@
+// Package telegram is a domain layer for Telegram chats linked to user accounts.
+package telegram
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// linkCodeTTL is how long the code of deep link may be used.
+const linkCodeTTL = 15 * time.Minute
+
+// ErrLinkCodeExpired is an error when the code of deep link is used too late.
+var ErrLinkCodeExpired = errors.New("link code is expired")
+
+// Link is a Telegram chat where notifications of the user are delivered.
+type Link struct {
+	userID   uuid.UUID
+	chatID   int64
+	linkedAt time.Time
+}
+
+// NewLink links the chat to the account of the user.
+func NewLink(userID uuid.UUID, chatID int64, linkedAt time.Time) *Link {
+	return &Link{
+		userID:   userID,
+		chatID:   chatID,
+		linkedAt: linkedAt,
+	}
+}
+
+// UserID returns id of the account.
+func (l *Link) UserID() uuid.UUID {
+	return l.userID
+}
+
+// ChatID returns id of the chat with the bot.
+func (l *Link) ChatID() int64 {
+	return l.chatID
+}
+
+// LinkedAt returns time the chat was linked.
+func (l *Link) LinkedAt() time.Time {
+	return l.linkedAt
+}
+
+// LinkCode is a one-time code of deep link to the bot, the chat where
+// the user starts the bot by the link is linked to the account.
+type LinkCode struct {
+	code      string
+	userID    uuid.UUID
+	expiresAt time.Time
+}
+
+// NewLinkCode creates a random code for the user valid for 15 minutes since now.
+func NewLinkCode(userID uuid.UUID, now time.Time) *LinkCode {
+	// 24 bytes are encoded to 32 characters allowed in start parameter of deep link
+	b := make([]byte, 24)
+	_, _ = rand.Read(b)
+	return &LinkCode{
+		code:      base64.RawURLEncoding.EncodeToString(b),
+		userID:    userID,
+		expiresAt: now.Add(linkCodeTTL),
+	}
+}
+
+// Code returns the code.
+func (c *LinkCode) Code() string {
+	return c.code
+}
+
+// UserID returns id of the account to link.
+func (c *LinkCode) UserID() uuid.UUID {
+	return c.userID
+}
+
+// ExpiresAt returns time after which the code can't be used.
+func (c *LinkCode) ExpiresAt() time.Time {
+	return c.expiresAt
+}
+
+// Redeem links the chat to the account if the code is not expired at t.
+func (c *LinkCode) Redeem(chatID int64, t time.Time) (*Link, error) {
+	if t.After(c.expiresAt) {
+		return nil, ErrLinkCodeExpired
+	}
+	return NewLink(c.userID, chatID, t), nil
+}
@@ -0,0 +1,50 @@
+package telegram_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/FSO-VK/final-project-vk-backend/internal/notifications/domain/telegram"
+	"github.com/google/uuid"
+)
+
+func TestLinkCode_Redeem(t *testing.T) {
+	t.Parallel()
+	now := time.Now()
+	tests := []struct {
+		name string // description of this test case
+		// Named input parameters for target function.
+		at      time.Time
+		wantErr error
+	}{
+		{
+			name:    "Should link chat by fresh code",
+			at:      now.Add(time.Minute),
+			wantErr: nil,
+		},
+		{
+			name:    "Should reject expired code",
+			at:      now.Add(time.Hour),
+			wantErr: telegram.ErrLinkCodeExpired,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			userID := uuid.New()
+			code := telegram.NewLinkCode(userID, now)
+			link, err := code.Redeem(42, tt.at)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Redeem() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if link.UserID() != userID || link.ChatID() != 42 {
+				t.Errorf("Redeem() = (%v, %v), want (%v, %v)",
+					link.UserID(), link.ChatID(), userID, 42)
+			}
+		})
+	}
+}
@@ -2,8 +2,15 @@
 package config
 
 import (
+	contact "github.com/FSO-VK/final-project-vk-backend/internal/notifications/infrastructure/contact_client"
+	email "github.com/FSO-VK/final-project-vk-backend/internal/notifications/infrastructure/email_client"
+	planning "github.com/FSO-VK/final-project-vk-backend/internal/notifications/infrastructure/planning_client"
+	profile "github.com/FSO-VK/final-project-vk-backend/internal/notifications/infrastructure/profile_client"
 	client "github.com/FSO-VK/final-project-vk-backend/internal/notifications/infrastructure/push_provider"
+	"github.com/FSO-VK/final-project-vk-backend/internal/notifications/infrastructure/storage/mongo"
+	telegram "github.com/FSO-VK/final-project-vk-backend/internal/notifications/infrastructure/telegram_client"
 	"github.com/FSO-VK/final-project-vk-backend/internal/notifications/presentation/http"
+	"github.com/FSO-VK/final-project-vk-backend/internal/utils/httputil"
 	auth "github.com/FSO-VK/final-project-vk-backend/pkg/auth/client"
 )
 
@@ -12,4 +19,13 @@ type Config struct {
 	Server     http.ServerConfig
 	PushClient client.PushClient
 	Auth       auth.ClientConfig
+	Profile    profile.ClientConfig
+	Contact    contact.ClientConfig
+	Email      email.EmailClient
+	Telegram   telegram.TelegramClient
+	Planning   planning.ClientConfig
+	// Storage keeps notifications scheduled by other services.
+	Storage mongo.Config
+	// ServiceAuth authenticates services sending notifications.
+	ServiceAuth httputil.ServiceAuthConfig
 }
@@ -0,0 +1,83 @@
+// Package contactclient implements ContactProvider interface for getting emails from auth service.
+package contactclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	contactProvider "github.com/FSO-VK/final-project-vk-backend/internal/notifications/application/contact_provider"
+	"github.com/FSO-VK/final-project-vk-backend/pkg/api"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// ContactClient implements ContactProvider.
+type ContactClient struct {
+	client *http.Client
+	cfg    ClientConfig
+	logger *logrus.Entry
+}
+
+// NewContactClient creates a new ContactClient.
+func NewContactClient(cfg ClientConfig, logger *logrus.Entry) *ContactClient {
+	client := &http.Client{
+		Timeout:       cfg.Timeout,
+		Transport:     nil,
+		CheckRedirect: nil,
+		Jar:           nil,
+	}
+	return &ContactClient{client: client, cfg: cfg, logger: logger}
+}
+
+type contactExpectedResponse struct {
+	UserID string `json:"userId"`
+	Email  string `json:"email"`
+}
+
+// Email implements ContactProvider interface.
+func (h *ContactClient) Email(ctx context.Context, userID uuid.UUID) (string, error) {
+	if h.cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, h.cfg.Timeout)
+		defer cancel()
+	}
+
+	query := url.Values{"userId": []string{userID.String()}}
+	httpReq, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodGet,
+		h.cfg.Endpoint+"?"+query.Encode(),
+		nil,
+	)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := h.client.Do(httpReq)
+	if err != nil {
+		h.logger.WithError(err).Warn("contact API request failed")
+		return "", ErrAuthServiceUnavailable
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode == http.StatusNotFound {
+		return "", contactProvider.ErrNoContact
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", ErrBadResponse
+	}
+
+	var parsedResponse api.Response[contactExpectedResponse]
+	if err := json.NewDecoder(resp.Body).Decode(&parsedResponse); err != nil {
+		h.logger.WithError(err).Error("failed to decode contact API response")
+		return "", fmt.Errorf("%w: %w", ErrBadResponse, err)
+	}
+	if parsedResponse.Body.Email == "" {
+		return "", contactProvider.ErrNoContact
+	}
+	return parsedResponse.Body.Email, nil
+}
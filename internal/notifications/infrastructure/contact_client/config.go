@@ -0,0 +1,9 @@
+package contactclient
+
+import "time"
+
+// ClientConfig is configuration for contact client.
+type ClientConfig struct {
+	Endpoint string        // http://auth:8001/internal/contact
+	Timeout  time.Duration // общий timeout запроса (30c)
+}
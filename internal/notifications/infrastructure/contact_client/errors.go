@@ -0,0 +1,10 @@
+package contactclient
+
+import "errors"
+
+var (
+	// ErrAuthServiceUnavailable is returned when auth service is unavailable.
+	ErrAuthServiceUnavailable = errors.New("contact api: service unavailable")
+	// ErrBadResponse is returned when the response status code is unexpected or body is not like expected.
+	ErrBadResponse = errors.New("contact api: unexpected response")
+)
@@ -0,0 +1,146 @@
+// Package emailclient implements EmailProvider interface for sending notifications by email.
+package emailclient
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/FSO-VK/final-project-vk-backend/internal/notifications/domain/notification"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// SMTPEmailProvider implements EmailProvider by SMTP server.
+type SMTPEmailProvider struct {
+	cfg    EmailClient
+	logger *logrus.Entry
+}
+
+// NewSMTPEmailProvider creates a new SMTPEmailProvider.
+func NewSMTPEmailProvider(cfg EmailClient, logger *logrus.Entry) *SMTPEmailProvider {
+	return &SMTPEmailProvider{cfg: cfg, logger: logger}
+}
+
+// SendEmail implements EmailProvider interface.
+// Connection is upgraded to TLS if server supports it.
+func (h *SMTPEmailProvider) SendEmail(
+	ctx context.Context,
+	to string,
+	content notification.Content,
+) error {
+	from, err := mail.ParseAddress(h.cfg.From)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrBadRequest, err)
+	}
+	letter, err := buildLetter(h.cfg, to, content, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if h.cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, h.cfg.Timeout)
+		defer cancel()
+	}
+	address := net.JoinHostPort(h.cfg.Host, strconv.Itoa(h.cfg.Port))
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", address)
+	if err != nil {
+		h.logger.WithError(err).Warn("smtp server is unavailable")
+		return fmt.Errorf("%w: %w", ErrSMTPUnavailable, err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, h.cfg.Host)
+	if err != nil {
+		_ = conn.Close()
+		return fmt.Errorf("%w: %w", ErrSMTPUnavailable, err)
+	}
+	defer func() {
+		_ = client.Close()
+	}()
+
+	if err := h.send(client, from.Address, to, letter); err != nil {
+		h.logger.WithError(err).Error("smtp server rejected the letter")
+		return fmt.Errorf("%w: %w", ErrSMTPUnavailable, err)
+	}
+	return client.Quit()
+}
+
+func (h *SMTPEmailProvider) send(client *smtp.Client, from, to string, letter []byte) error {
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		tlsConfig := &tls.Config{
+			ServerName: h.cfg.Host,
+			MinVersion: tls.VersionTLS12,
+		}
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return err
+		}
+	}
+	if h.cfg.Username != "" {
+		auth := smtp.PlainAuth("", h.cfg.Username, h.cfg.Password, h.cfg.Host)
+		if err := client.Auth(auth); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(from); err != nil {
+		return err
+	}
+	if err := client.Rcpt(to); err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(letter); err != nil {
+		return err
+	}
+	return w.Close()
+}
+
+// FileEmailProvider implements EmailProvider by writing letters to files,
+// it is a stand-in for SMTP server in local development.
+type FileEmailProvider struct {
+	cfg    EmailClient
+	logger *logrus.Entry
+}
+
+// NewFileEmailProvider creates a new FileEmailProvider.
+func NewFileEmailProvider(cfg EmailClient, logger *logrus.Entry) *FileEmailProvider {
+	return &FileEmailProvider{cfg: cfg, logger: logger}
+}
+
+// SendEmail implements EmailProvider interface, every letter is written to .eml file.
+func (h *FileEmailProvider) SendEmail(
+	_ context.Context,
+	to string,
+	content notification.Content,
+) error {
+	now := time.Now()
+	letter, err := buildLetter(h.cfg, to, content, now)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(h.cfg.SinkDir, 0o750); err != nil {
+		return err
+	}
+	name := filepath.Join(
+		h.cfg.SinkDir,
+		now.Format("20060102T150405")+"-"+uuid.NewString()+".eml",
+	)
+	if err := os.WriteFile(name, letter, 0o600); err != nil {
+		return err
+	}
+	h.logger.WithField("file", name).Debug("letter is written to sink")
+	return nil
+}
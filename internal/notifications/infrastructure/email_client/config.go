@@ -0,0 +1,19 @@
+package emailclient
+
+import "time"
+
+// EmailClient is configuration for email client.
+type EmailClient struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	// From is an address letters are sent from.
+	From string
+	// BaseURL is prepended to relative links of notifications.
+	BaseURL string
+	Timeout time.Duration // общий timeout отправки письма (30c)
+	// SinkDir is a directory to write letters to instead of sending them,
+	// it is used for local development.
+	SinkDir string
+}
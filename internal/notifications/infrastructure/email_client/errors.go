@@ -0,0 +1,10 @@
+package emailclient
+
+import "errors"
+
+var (
+	// ErrSMTPUnavailable is returned when SMTP server is unavailable or rejects the letter.
+	ErrSMTPUnavailable = errors.New("email: smtp server unavailable")
+	// ErrBadRequest is returned when the letter can't be built.
+	ErrBadRequest = errors.New("email: bad request")
+)
@@ -0,0 +1,144 @@
+package emailclient
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmlTemplate "html/template"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/textproto"
+	"strings"
+	textTemplate "text/template"
+	"time"
+
+	"github.com/FSO-VK/final-project-vk-backend/internal/notifications/domain/notification"
+	"github.com/google/uuid"
+)
+
+//go:embed templates
+var templatesFS embed.FS
+
+var (
+	textTemplates = textTemplate.Must(
+		textTemplate.ParseFS(templatesFS, "templates/*.txt.tmpl"),
+	)
+	htmlTemplates = htmlTemplate.Must(
+		htmlTemplate.ParseFS(templatesFS, "templates/*.html.tmpl"),
+	)
+)
+
+// letterLabels are texts of the letter which do not come from notification.
+type letterLabels struct {
+	Lang   string
+	Open   string
+	Footer string
+}
+
+// labels are texts of the letter by locale of notification, russian is default.
+var labels = map[string]letterLabels{
+	"ru": {
+		Lang:   "ru",
+		Open:   "Открыть",
+		Footer: "MyHealthBox. Настроить уведомления можно в профиле приложения.",
+	},
+	"en": {
+		Lang:   "en",
+		Open:   "Open",
+		Footer: "MyHealthBox. Notifications can be set up in the app profile.",
+	},
+}
+
+// letterData is data for templates of the letter.
+type letterData struct {
+	letterLabels
+
+	Title string
+	Lines []string
+	Link  string
+}
+
+// buildLetter renders notification to a MIME letter with text and HTML alternatives.
+func buildLetter(
+	cfg EmailClient,
+	to string,
+	content notification.Content,
+	now time.Time,
+) ([]byte, error) {
+	letterLabels, ok := labels[content.Locale]
+	if !ok {
+		letterLabels = labels["ru"]
+	}
+	data := letterData{
+		letterLabels: letterLabels,
+		Title:        content.Title,
+		Lines:        strings.Split(content.Body, "\n"),
+		Link:         linkOf(cfg.BaseURL, content.URL),
+	}
+
+	var text, html bytes.Buffer
+	if err := textTemplates.ExecuteTemplate(&text, "notification.txt.tmpl", data); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrBadRequest, err)
+	}
+	if err := htmlTemplates.ExecuteTemplate(&html, "notification.html.tmpl", data); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrBadRequest, err)
+	}
+
+	var letter bytes.Buffer
+	body := multipart.NewWriter(&letter)
+	headers := []string{
+		"From: " + cfg.From,
+		"To: " + to,
+		"Subject: " + mime.QEncoding.Encode("utf-8", content.Title),
+		"Date: " + now.Format(time.RFC1123Z),
+		"Message-ID: <" + uuid.NewString() + "@" + domainOf(cfg.From) + ">",
+		"MIME-Version: 1.0",
+		"Content-Type: multipart/alternative; boundary=" + body.Boundary(),
+	}
+	letter.WriteString(strings.Join(headers, "\r\n") + "\r\n\r\n")
+
+	for _, part := range []struct {
+		contentType string
+		content     []byte
+	}{
+		{contentType: "text/plain; charset=utf-8", content: text.Bytes()},
+		{contentType: "text/html; charset=utf-8", content: html.Bytes()},
+	} {
+		w, err := body.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(w)
+		if _, err := qp.Write(part.content); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := body.Close(); err != nil {
+		return nil, err
+	}
+	return letter.Bytes(), nil
+}
+
+// linkOf makes relative link of notification absolute.
+func linkOf(baseURL, link string) string {
+	if link == "" || !strings.HasPrefix(link, "/") {
+		return link
+	}
+	return strings.TrimSuffix(baseURL, "/") + link
+}
+
+// domainOf returns domain of the address.
+func domainOf(address string) string {
+	_, domain, found := strings.Cut(address, "@")
+	if !found {
+		return "localhost"
+	}
+	return strings.TrimSuffix(domain, ">")
+}
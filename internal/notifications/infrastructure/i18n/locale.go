@@ -0,0 +1,112 @@
+package i18n
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"text/template"
+	"time"
+)
+
+// locale is a language with its rules of plural forms and formats of date and time.
+type locale struct {
+	// pluralForm returns index of the plural form of the number.
+	pluralForm func(n int64) int
+	dateLayout string
+	timeLayout string
+}
+
+// locales are supported languages keyed by codes.
+var locales = map[string]locale{
+	"ru": {
+		pluralForm: pluralFormRU,
+		dateLayout: "02.01.2006",
+		timeLayout: "15:04",
+	},
+	"en": {
+		pluralForm: pluralFormEN,
+		dateLayout: "Jan 2, 2006",
+		timeLayout: "3:04 PM",
+	},
+}
+
+// pluralFormRU chooses one of forms like "препарат", "препарата", "препаратов".
+func pluralFormRU(n int64) int {
+	n = abs(n)
+	switch {
+	case n%10 == 1 && n%100 != 11:
+		return 0
+	case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+		return 1
+	default:
+		return 2
+	}
+}
+
+// pluralFormEN chooses one of forms like "medication", "medications".
+func pluralFormEN(n int64) int {
+	if n == 1 {
+		return 0
+	}
+	return 1
+}
+
+func abs(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// funcs returns functions available in templates of the locale:
+// plural chooses form of the word for the number, date and time format
+// RFC 3339 timestamps in the time zone of the timestamp.
+func (l locale) funcs() template.FuncMap {
+	return template.FuncMap{
+		"plural": func(n any, forms ...string) (string, error) {
+			count, err := toInt(n)
+			if err != nil {
+				return "", err
+			}
+			if len(forms) == 0 {
+				return "", nil
+			}
+			return forms[min(l.pluralForm(count), len(forms)-1)], nil
+		},
+		"date": func(t any) (string, error) {
+			return formatTime(t, l.dateLayout)
+		},
+		"time": func(t any) (string, error) {
+			return formatTime(t, l.timeLayout)
+		},
+	}
+}
+
+// toInt converts number of params decoded from JSON to integer.
+func toInt(n any) (int64, error) {
+	switch v := n.(type) {
+	case int:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	case float64:
+		return int64(math.Trunc(v)), nil
+	case string:
+		return strconv.ParseInt(v, 10, 64)
+	}
+	return 0, fmt.Errorf("%v is not a number", n)
+}
+
+func formatTime(t any, layout string) (string, error) {
+	switch v := t.(type) {
+	case time.Time:
+		return v.Format(layout), nil
+	case string:
+		parsed, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return "", err
+		}
+		return parsed.Format(layout), nil
+	}
+	return "", fmt.Errorf("%v is not a time", t)
+}
@@ -0,0 +1,138 @@
+// Package i18n implements TemplateProvider interface by bundles of templates
+// shipped with the service, one bundle for every supported language.
+package i18n
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+	"text/template"
+
+	templateProvider "github.com/FSO-VK/final-project-vk-backend/internal/notifications/application/template_provider"
+)
+
+// defaultLocale is a locale of templates missing in other locales.
+const defaultLocale = "ru"
+
+//go:embed bundles
+var bundlesFS embed.FS
+
+// messageTemplate is a template of notification in a bundle,
+// texts are Go templates with params of notification as data.
+type messageTemplate struct {
+	Title   string            `json:"title"`
+	Body    string            `json:"body"`
+	Actions map[string]string `json:"actions"`
+}
+
+type compiledTemplate struct {
+	title   *template.Template
+	body    *template.Template
+	actions map[string]*template.Template
+}
+
+// Renderer implements TemplateProvider.
+type Renderer struct {
+	bundles map[string]map[string]*compiledTemplate
+}
+
+// NewRenderer parses bundles of all supported locales.
+func NewRenderer() (*Renderer, error) {
+	r := &Renderer{bundles: make(map[string]map[string]*compiledTemplate, len(locales))}
+	for code, l := range locales {
+		bundle, err := parseBundle(code, l)
+		if err != nil {
+			return nil, err
+		}
+		r.bundles[code] = bundle
+	}
+	return r, nil
+}
+
+// Render implements TemplateProvider interface.
+func (r *Renderer) Render(
+	locale string,
+	templateID string,
+	params map[string]any,
+) (*templateProvider.Message, error) {
+	t, ok := r.bundles[locale][templateID]
+	if !ok {
+		t, ok = r.bundles[defaultLocale][templateID]
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", templateProvider.ErrNoTemplate, templateID)
+	}
+
+	message := &templateProvider.Message{
+		Title:   "",
+		Body:    "",
+		Actions: make(map[string]string, len(t.actions)),
+	}
+	var err error
+	if message.Title, err = execute(t.title, params); err != nil {
+		return nil, err
+	}
+	if message.Body, err = execute(t.body, params); err != nil {
+		return nil, err
+	}
+	for action, actionTemplate := range t.actions {
+		if message.Actions[action], err = execute(actionTemplate, params); err != nil {
+			return nil, err
+		}
+	}
+	return message, nil
+}
+
+func parseBundle(code string, l locale) (map[string]*compiledTemplate, error) {
+	raw, err := bundlesFS.ReadFile(path.Join("bundles", code+".json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bundle %s: %w", code, err)
+	}
+	var templates map[string]messageTemplate
+	if err := json.Unmarshal(raw, &templates); err != nil {
+		return nil, fmt.Errorf("failed to decode bundle %s: %w", code, err)
+	}
+
+	bundle := make(map[string]*compiledTemplate, len(templates))
+	for id, t := range templates {
+		compiled := &compiledTemplate{
+			title:   nil,
+			body:    nil,
+			actions: make(map[string]*template.Template, len(t.Actions)),
+		}
+		if compiled.title, err = parse(l, code+"/"+id+"/title", t.Title); err != nil {
+			return nil, err
+		}
+		if compiled.body, err = parse(l, code+"/"+id+"/body", t.Body); err != nil {
+			return nil, err
+		}
+		for action, title := range t.Actions {
+			name := code + "/" + id + "/actions/" + action
+			if compiled.actions[action], err = parse(l, name, title); err != nil {
+				return nil, err
+			}
+		}
+		bundle[id] = compiled
+	}
+	return bundle, nil
+}
+
+func parse(l locale, name string, text string) (*template.Template, error) {
+	t, err := template.New(name).Funcs(l.funcs()).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template %s: %w", name, err)
+	}
+	return t, nil
+}
+
+// execute renders the template, missing params are errors.
+func execute(t *template.Template, params map[string]any) (string, error) {
+	var b bytes.Buffer
+	if err := t.Execute(&b, params); err != nil {
+		return "", fmt.Errorf("%w: %w", templateProvider.ErrInvalidParams, err)
+	}
+	return strings.TrimSpace(b.String()), nil
+}
@@ -0,0 +1,117 @@
+// Package planningclient implements PlanningProvider interface for quick replies to reminders.
+package planningclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	planningProvider "github.com/FSO-VK/final-project-vk-backend/internal/notifications/application/planning_provider"
+	"github.com/FSO-VK/final-project-vk-backend/pkg/api"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	takePath   = "take"
+	snoozePath = "snooze"
+)
+
+// PlanningClient implements PlanningProvider.
+type PlanningClient struct {
+	client *http.Client
+	cfg    ClientConfig
+	logger *logrus.Entry
+}
+
+// NewPlanningClient creates a new PlanningClient.
+func NewPlanningClient(cfg ClientConfig, logger *logrus.Entry) *PlanningClient {
+	client := &http.Client{
+		Timeout:       cfg.Timeout,
+		Transport:     nil,
+		CheckRedirect: nil,
+		Jar:           nil,
+	}
+	return &PlanningClient{client: client, cfg: cfg, logger: logger}
+}
+
+type snoozeExpectedResponse struct {
+	SnoozedUntil time.Time `json:"snoozedUntil"`
+}
+
+// TakeIntake implements PlanningProvider interface.
+func (h *PlanningClient) TakeIntake(ctx context.Context, userID, recordID uuid.UUID) error {
+	resp, err := h.post(ctx, userID, recordID, takePath)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode == http.StatusNotFound {
+		return planningProvider.ErrNoIntake
+	}
+	if resp.StatusCode != http.StatusOK {
+		return ErrBadResponse
+	}
+	return nil
+}
+
+// SnoozeIntake implements PlanningProvider interface.
+func (h *PlanningClient) SnoozeIntake(
+	ctx context.Context,
+	userID uuid.UUID,
+	recordID uuid.UUID,
+) (time.Time, error) {
+	resp, err := h.post(ctx, userID, recordID, snoozePath)
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return time.Time{}, planningProvider.ErrNoIntake
+	case http.StatusConflict:
+		return time.Time{}, planningProvider.ErrIntakeTaken
+	default:
+		return time.Time{}, ErrBadResponse
+	}
+
+	var parsedResponse api.Response[snoozeExpectedResponse]
+	if err := json.NewDecoder(resp.Body).Decode(&parsedResponse); err != nil {
+		h.logger.WithError(err).Error("failed to decode planning API response")
+		return time.Time{}, fmt.Errorf("%w: %w", ErrBadResponse, err)
+	}
+	return parsedResponse.Body.SnoozedUntil, nil
+}
+
+// post makes the action with the intake on behalf of the owner of the plan,
+// the caller must close body of the response.
+func (h *PlanningClient) post(
+	ctx context.Context,
+	userID uuid.UUID,
+	recordID uuid.UUID,
+	action string,
+) (*http.Response, error) {
+	endpoint, err := url.JoinPath(h.cfg.Endpoint, recordID.String(), action, userID.String())
+	if err != nil {
+		return nil, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.client.Do(httpReq)
+	if err != nil {
+		h.logger.WithError(err).Warn("planning API request failed")
+		return nil, ErrPlanningServiceUnavailable
+	}
+	return resp, nil
+}
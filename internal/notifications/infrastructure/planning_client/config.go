@@ -0,0 +1,9 @@
+package planningclient
+
+import "time"
+
+// ClientConfig is configuration for planning client.
+type ClientConfig struct {
+	Endpoint string        // http://planning:8001/internal/intake/
+	Timeout  time.Duration // общий timeout запроса (30c)
+}
@@ -0,0 +1,10 @@
+package planningclient
+
+import "errors"
+
+var (
+	// ErrPlanningServiceUnavailable is returned when planning service is unavailable.
+	ErrPlanningServiceUnavailable = errors.New("planning api: service unavailable")
+	// ErrBadResponse is returned when the response status code is unexpected or body is not like expected.
+	ErrBadResponse = errors.New("planning api: unexpected response")
+)
@@ -0,0 +1,92 @@
+// Package profileclient implements ProfileProvider interface for getting profiles from auth service.
+package profileclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	profileProvider "github.com/FSO-VK/final-project-vk-backend/internal/notifications/application/profile_provider"
+	"github.com/FSO-VK/final-project-vk-backend/pkg/api"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// ProfileClient implements ProfileProvider.
+type ProfileClient struct {
+	client *http.Client
+	cfg    ClientConfig
+	logger *logrus.Entry
+}
+
+// NewProfileClient creates a new ProfileClient.
+func NewProfileClient(cfg ClientConfig, logger *logrus.Entry) *ProfileClient {
+	client := &http.Client{
+		Timeout:       cfg.Timeout,
+		Transport:     nil,
+		CheckRedirect: nil,
+		Jar:           nil,
+	}
+	return &ProfileClient{client: client, cfg: cfg, logger: logger}
+}
+
+type profileExpectedResponse struct {
+	ProfileID string `json:"profileId"`
+	AccountID string `json:"accountId"`
+	Name      string `json:"name"`
+}
+
+// Profile implements ProfileProvider interface.
+func (h *ProfileClient) Profile(
+	ctx context.Context,
+	profileID uuid.UUID,
+) (*profileProvider.Profile, error) {
+	if h.cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, h.cfg.Timeout)
+		defer cancel()
+	}
+
+	query := url.Values{"profileId": []string{profileID.String()}}
+	httpReq, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodGet,
+		h.cfg.Endpoint+"?"+query.Encode(),
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.client.Do(httpReq)
+	if err != nil {
+		h.logger.WithError(err).Warn("profile API request failed")
+		return nil, ErrAuthServiceUnavailable
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, profileProvider.ErrNoProfile
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, ErrBadResponse
+	}
+
+	var parsedResponse api.Response[profileExpectedResponse]
+	if err := json.NewDecoder(resp.Body).Decode(&parsedResponse); err != nil {
+		h.logger.WithError(err).Error("failed to decode profile API response")
+		return nil, fmt.Errorf("%w: %w", ErrBadResponse, err)
+	}
+	accountID, err := uuid.Parse(parsedResponse.Body.AccountID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrBadResponse, err)
+	}
+	return &profileProvider.Profile{
+		ID:        profileID,
+		AccountID: accountID,
+		Name:      parsedResponse.Body.Name,
+	}, nil
+}
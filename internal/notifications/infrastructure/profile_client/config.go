@@ -0,0 +1,9 @@
+package profileclient
+
+import "time"
+
+// ClientConfig is configuration for profile client.
+type ClientConfig struct {
+	Endpoint string        // http://auth:8001/internal/profile
+	Timeout  time.Duration // общий timeout запроса (30c)
+}
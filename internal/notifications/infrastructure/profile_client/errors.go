@@ -0,0 +1,10 @@
+package profileclient
+
+import "errors"
+
+var (
+	// ErrAuthServiceUnavailable is returned when auth service is unavailable.
+	ErrAuthServiceUnavailable = errors.New("profile api: service unavailable")
+	// ErrBadResponse is returned when the response status code is unexpected or body is not like expected.
+	ErrBadResponse = errors.New("profile api: unexpected response")
+)
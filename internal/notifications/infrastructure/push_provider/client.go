@@ -4,7 +4,11 @@ package psuhprovider
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"maps"
 	"net/http"
+	"strconv"
+	"time"
 
 	notificationProvider "github.com/FSO-VK/final-project-vk-backend/internal/notifications/application/notification_provider"
 	"github.com/FSO-VK/final-project-vk-backend/internal/notifications/domain/subscriptions"
@@ -12,6 +16,9 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+// maxRetryAfter limits how long push service may ask to wait before retry.
+const maxRetryAfter = time.Minute
+
 // PushNotificationProvider implements NotificationProvider.
 type PushNotificationProvider struct {
 	client *http.Client
@@ -31,20 +38,17 @@ func NewPushNotificationProvider(cfg PushClient, logger *logrus.Entry) *PushNoti
 }
 
 // PushNotification implements NotificationProvider interface.
+// Requests rejected with 429 or 5xx are retried with exponential backoff,
+// Retry-After of the push service is honoured. Retries stop when the next
+// attempt wouldn't fit in the retry budget, so the caller isn't timed out.
 func (h *PushNotificationProvider) PushNotification(
+	ctx context.Context,
 	pushInfo *notificationProvider.Notification,
 	subscriptionInfo *subscriptions.PushSubscription,
 ) error {
 	if pushInfo == nil || subscriptionInfo == nil {
 		return ErrBadRequest
 	}
-	ctx := context.Background()
-
-	if h.cfg.Timeout > 0 {
-		var cancel context.CancelFunc
-		_, cancel = context.WithTimeout(ctx, h.cfg.Timeout)
-		defer cancel()
-	}
 
 	webpushSubscription := &webpush.Subscription{
 		Endpoint: subscriptionInfo.GetEndpoint(),
@@ -54,29 +58,69 @@ func (h *PushNotificationProvider) PushNotification(
 		},
 	}
 
-	notificationPayload := map[string]interface{}{
-		"title": pushInfo.Title,
-		"body":  pushInfo.Body,
-		"data": map[string]interface{}{
-			"userID": pushInfo.UserID.String(),
-		},
-	}
-
-	payload, err := json.Marshal(notificationPayload)
+	payload, err := json.Marshal(payloadOf(pushInfo))
 	if err != nil {
 		h.logger.WithError(err).Error("failed to marshal notification payload")
 		return err
 	}
 
-	resp, err := webpush.SendNotification(payload, webpushSubscription, &webpush.Options{
+	if h.cfg.RetryBudget > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, h.cfg.RetryBudget)
+		defer cancel()
+	}
+
+	backoff := h.cfg.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		retryAfter, err := h.send(ctx, payload, webpushSubscription, pushInfo)
+		if err == nil || retryAfter < 0 || attempt >= h.cfg.MaxRetries {
+			return err
+		}
+
+		wait := max(backoff, retryAfter)
+		if deadline, ok := ctx.Deadline(); ok && time.Now().Add(wait).After(deadline) {
+			return err
+		}
+		h.logger.WithFields(logrus.Fields{
+			"subscription_id": pushInfo.SubscriptionID,
+			"attempt":         attempt + 1,
+			"wait":            wait,
+		}).Info("retry push notification")
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w: %w", ErrPushServiceUnavailable, ctx.Err())
+		case <-time.After(wait):
+		}
+		backoff *= 2
+	}
+}
+
+// send makes one attempt to push the notification. If the attempt may be
+// retried it returns time push service asked to wait, otherwise it returns -1.
+func (h *PushNotificationProvider) send(
+	ctx context.Context,
+	payload []byte,
+	webpushSubscription *webpush.Subscription,
+	pushInfo *notificationProvider.Notification,
+) (time.Duration, error) {
+	if h.cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, h.cfg.Timeout)
+		defer cancel()
+	}
+
+	options := &webpush.Options{
+		HTTPClient:      h.client,
 		Subscriber:      h.cfg.Subscriber,
 		VAPIDPublicKey:  h.cfg.VapidPublicKey,
 		VAPIDPrivateKey: h.cfg.VapidPrivateKey,
 		TTL:             int(h.cfg.Timeout),
-	})
+		Urgency:         webpush.Urgency(pushInfo.Urgency),
+	}
+	resp, err := webpush.SendNotificationWithContext(ctx, payload, webpushSubscription, options)
 	if err != nil {
 		h.logger.WithError(err).Error("failed to send push notification")
-		return err
+		return 0, fmt.Errorf("%w: %w", ErrPushServiceUnavailable, err)
 	}
 	defer func() {
 		if err := resp.Body.Close(); err != nil {
@@ -84,19 +128,81 @@ func (h *PushNotificationProvider) PushNotification(
 		}
 	}()
 
-	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-		h.logger.WithFields(logrus.Fields{
-			"subscription_id": pushInfo.ID,
-			"user_id":         pushInfo.UserID,
-			"status":          resp.Status,
-		}).Info("push notification sent successfully")
-		return nil
-	}
-	h.logger.WithFields(logrus.Fields{
-		"subscription_id": pushInfo.ID,
+	logger := h.logger.WithFields(logrus.Fields{
+		"subscription_id": pushInfo.SubscriptionID,
+		"user_id":         pushInfo.UserID,
 		"status_code":     resp.StatusCode,
 		"status":          resp.Status,
-	}).Warn("push notification failed to sent with non-200 status")
+	})
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		logger.Info("push notification sent successfully")
+		return 0, nil
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		logger.Info("push subscription is gone")
+		return -1, notificationProvider.ErrSubscriptionGone
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		logger.Warn("push notification failed to sent, push service is unavailable")
+		return parseRetryAfter(resp.Header.Get("Retry-After")), ErrPushServiceUnavailable
+	default:
+		logger.Warn("push notification failed to sent with non-200 status")
+		return -1, ErrBadRequest
+	}
+}
 
-	return ErrPushServiceUnavailable
+// pushPayload is a message for service worker, it is shown with showNotification.
+type pushPayload struct {
+	Title    string       `json:"title"`
+	Body     string       `json:"body"`
+	Icon     string       `json:"icon,omitempty"`
+	Tag      string       `json:"tag,omitempty"`
+	Renotify bool         `json:"renotify,omitempty"`
+	Actions  []pushAction `json:"actions,omitempty"`
+	// Data is for service worker to handle clicks on notification and its actions,
+	// it contains userID, type, url and data fields of the notification.
+	Data map[string]string `json:"data"`
+}
+
+type pushAction struct {
+	Action string `json:"action"`
+	Title  string `json:"title"`
+}
+
+func payloadOf(pushInfo *notificationProvider.Notification) *pushPayload {
+	actions := make([]pushAction, 0, len(pushInfo.Actions))
+	for _, action := range pushInfo.Actions {
+		actions = append(actions, pushAction(action))
+	}
+	data := make(map[string]string, len(pushInfo.Data)+3)
+	maps.Copy(data, pushInfo.Data)
+	data["userID"] = pushInfo.UserID.String()
+	if pushInfo.Type != "" {
+		data["type"] = pushInfo.Type
+	}
+	if pushInfo.URL != "" {
+		data["url"] = pushInfo.URL
+	}
+	return &pushPayload{
+		Title:    pushInfo.Title,
+		Body:     pushInfo.Body,
+		Icon:     pushInfo.Icon,
+		Tag:      pushInfo.Tag,
+		Renotify: pushInfo.Renotify && pushInfo.Tag != "",
+		Actions:  actions,
+		Data:     data,
+	}
+}
+
+// parseRetryAfter parses Retry-After header in seconds or HTTP date format.
+func parseRetryAfter(header string) time.Duration {
+	if header == "" {
+		return 0
+	}
+	var wait time.Duration
+	if seconds, err := strconv.Atoi(header); err == nil {
+		wait = time.Duration(seconds) * time.Second
+	} else if at, err := http.ParseTime(header); err == nil {
+		wait = time.Until(at)
+	}
+	return min(max(wait, 0), maxRetryAfter)
 }
@@ -8,4 +8,11 @@ type PushClient struct {
 	VapidPrivateKey string
 	Subscriber      string
 	Timeout         time.Duration // общий timeout запроса (30c)
+	// MaxRetries is how many times push is retried when push service is unavailable.
+	MaxRetries int
+	// RetryBackoff is a delay before the first retry, it doubles every retry.
+	RetryBackoff time.Duration
+	// RetryBudget limits time of all attempts and delays between them,
+	// it must be less than timeouts of services sending notifications.
+	RetryBudget time.Duration
 }
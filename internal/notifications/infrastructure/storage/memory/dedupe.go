@@ -0,0 +1,71 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/FSO-VK/final-project-vk-backend/internal/notifications/domain/dedupe"
+)
+
+// claim is a key claimed at the time.
+type claim struct {
+	key       string
+	claimedAt time.Time
+}
+
+// DedupeStorage is a storage for dedupe keys of sent notifications.
+type DedupeStorage struct {
+	// claimedAt is when keys were claimed by their string form.
+	claimedAt map[string]time.Time
+	// expiry is claims in order they were made, forgotten claims are
+	// removed from its head, so claim costs no more than a map lookup.
+	expiry []claim
+	mu     sync.Mutex
+}
+
+// NewDedupeStorage returns a new DedupeStorage.
+func NewDedupeStorage() *DedupeStorage {
+	return &DedupeStorage{
+		claimedAt: make(map[string]time.Time),
+		expiry:    nil,
+		mu:        sync.Mutex{},
+	}
+}
+
+// Claim remembers the key at t unless it is already remembered.
+// Keys forgotten by t are removed on the way.
+func (s *DedupeStorage) Claim(_ context.Context, key dedupe.Key, t time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.expire(t)
+	claimedAt, ok := s.claimedAt[key.String()]
+	if ok && dedupe.IsRemembered(claimedAt, t) {
+		return false, nil
+	}
+	s.claimedAt[key.String()] = t
+	s.expiry = append(s.expiry, claim{key: key.String(), claimedAt: t})
+	return true, nil
+}
+
+// Release forgets the key.
+func (s *DedupeStorage) Release(_ context.Context, key dedupe.Key) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.claimedAt, key.String())
+	return nil
+}
+
+// expire removes claims forgotten by t from the head of the queue.
+// Claims of released or reclaimed keys only leave the queue.
+func (s *DedupeStorage) expire(t time.Time) {
+	for len(s.expiry) > 0 && !dedupe.IsRemembered(s.expiry[0].claimedAt, t) {
+		c := s.expiry[0]
+		if claimedAt, ok := s.claimedAt[c.key]; ok && claimedAt.Equal(c.claimedAt) {
+			delete(s.claimedAt, c.key)
+		}
+		s.expiry = s.expiry[1:]
+	}
+}
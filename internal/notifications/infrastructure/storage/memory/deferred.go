@@ -0,0 +1,52 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/FSO-VK/final-project-vk-backend/internal/notifications/domain/deferred"
+	"github.com/FSO-VK/final-project-vk-backend/internal/utils/cache"
+	"github.com/google/uuid"
+)
+
+// DeferredStorage is a storage for deferred notifications.
+type DeferredStorage struct {
+	data *cache.Cache[*deferred.Notification]
+}
+
+// NewDeferredStorage returns a new DeferredStorage.
+func NewDeferredStorage() *DeferredStorage {
+	return &DeferredStorage{
+		data: cache.NewCache[*deferred.Notification](),
+	}
+}
+
+// Add saves a deferred notification.
+func (s *DeferredStorage) Add(_ context.Context, n *deferred.Notification) error {
+	s.data.Set(n.ID().String(), n)
+	return nil
+}
+
+// Due returns notifications to deliver at t or earlier ordered by send time.
+func (s *DeferredStorage) Due(_ context.Context, t time.Time) ([]*deferred.Notification, error) {
+	var result []*deferred.Notification
+	for _, n := range s.data.GetAll() {
+		if !n.SendAt().After(t) {
+			result = append(result, n)
+		}
+	}
+	slices.SortFunc(result, func(a, b *deferred.Notification) int {
+		return a.SendAt().Compare(b.SendAt())
+	})
+	return result, nil
+}
+
+// Delete removes a deferred notification.
+func (s *DeferredStorage) Delete(_ context.Context, id uuid.UUID) error {
+	if _, ok := s.data.Get(id.String()); !ok {
+		return deferred.ErrNoNotificationFound
+	}
+	s.data.Delete(id.String())
+	return nil
+}
@@ -0,0 +1,89 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/FSO-VK/final-project-vk-backend/internal/notifications/domain/history"
+	"github.com/FSO-VK/final-project-vk-backend/internal/utils/cache"
+	"github.com/google/uuid"
+)
+
+// HistoryStorage is a storage for history of notifications.
+type HistoryStorage struct {
+	data *cache.Cache[*history.Notification]
+}
+
+// NewHistoryStorage returns a new HistoryStorage.
+func NewHistoryStorage() *HistoryStorage {
+	return &HistoryStorage{
+		data: cache.NewCache[*history.Notification](),
+	}
+}
+
+// Save creates or replaces a notification.
+func (s *HistoryStorage) Save(_ context.Context, n *history.Notification) error {
+	s.data.Set(n.ID().String(), n)
+	return nil
+}
+
+// GetByID returns a notification by id.
+func (s *HistoryStorage) GetByID(
+	_ context.Context,
+	id uuid.UUID,
+) (*history.Notification, error) {
+	n, ok := s.data.Get(id.String())
+	if !ok {
+		return nil, history.ErrNoNotificationFound
+	}
+	return n, nil
+}
+
+// ListByUserID returns a page of notifications of the user, newest first,
+// and total number of them.
+func (s *HistoryStorage) ListByUserID(
+	_ context.Context,
+	userID uuid.UUID,
+	offset int,
+	limit int,
+) ([]*history.Notification, int, error) {
+	notifications := s.byUserID(userID)
+	slices.SortFunc(notifications, func(a, b *history.Notification) int {
+		return b.CreatedAt().Compare(a.CreatedAt())
+	})
+
+	total := len(notifications)
+	start := min(offset, total)
+	end := min(start+limit, total)
+	return notifications[start:end], total, nil
+}
+
+// CountUnread returns number of unread notifications of the user.
+func (s *HistoryStorage) CountUnread(_ context.Context, userID uuid.UUID) (int, error) {
+	unread := 0
+	for _, n := range s.byUserID(userID) {
+		if !n.IsRead() {
+			unread++
+		}
+	}
+	return unread, nil
+}
+
+// MarkAllRead marks all unread notifications of the user read at t.
+func (s *HistoryStorage) MarkAllRead(_ context.Context, userID uuid.UUID, t time.Time) error {
+	for _, n := range s.byUserID(userID) {
+		n.MarkRead(t)
+	}
+	return nil
+}
+
+func (s *HistoryStorage) byUserID(userID uuid.UUID) []*history.Notification {
+	var result []*history.Notification
+	for _, n := range s.data.GetAll() {
+		if n.UserID() == userID {
+			result = append(result, n)
+		}
+	}
+	return result
+}
@@ -0,0 +1,39 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/FSO-VK/final-project-vk-backend/internal/notifications/domain/preferences"
+	"github.com/FSO-VK/final-project-vk-backend/internal/utils/cache"
+	"github.com/google/uuid"
+)
+
+// PreferencesStorage is a storage for notification preferences.
+type PreferencesStorage struct {
+	data *cache.Cache[*preferences.Preferences]
+}
+
+// NewPreferencesStorage returns a new PreferencesStorage.
+func NewPreferencesStorage() *PreferencesStorage {
+	return &PreferencesStorage{
+		data: cache.NewCache[*preferences.Preferences](),
+	}
+}
+
+// GetByUserID returns preferences of the user.
+func (s *PreferencesStorage) GetByUserID(
+	_ context.Context,
+	userID uuid.UUID,
+) (*preferences.Preferences, error) {
+	p, ok := s.data.Get(userID.String())
+	if !ok {
+		return nil, preferences.ErrNoPreferencesFound
+	}
+	return p, nil
+}
+
+// Save creates or replaces preferences of the user.
+func (s *PreferencesStorage) Save(_ context.Context, p *preferences.Preferences) error {
+	s.data.Set(p.UserID().String(), p)
+	return nil
+}
@@ -0,0 +1,93 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/FSO-VK/final-project-vk-backend/internal/notifications/domain/telegram"
+	"github.com/FSO-VK/final-project-vk-backend/internal/utils/cache"
+	"github.com/google/uuid"
+)
+
+// TelegramStorage is a storage for Telegram chats linked to accounts.
+type TelegramStorage struct {
+	links *cache.Cache[*telegram.Link]
+	codes *cache.Cache[*telegram.LinkCode]
+	mu    sync.Mutex
+}
+
+// NewTelegramStorage returns a new TelegramStorage.
+func NewTelegramStorage() *TelegramStorage {
+	return &TelegramStorage{
+		links: cache.NewCache[*telegram.Link](),
+		codes: cache.NewCache[*telegram.LinkCode](),
+		mu:    sync.Mutex{},
+	}
+}
+
+// SaveLink links the chat to the user, the chat is unlinked from other users.
+func (s *TelegramStorage) SaveLink(_ context.Context, l *telegram.Link) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, linked := range s.links.GetAll() {
+		if linked.ChatID() == l.ChatID() && linked.UserID() != l.UserID() {
+			s.links.Delete(linked.UserID().String())
+		}
+	}
+	s.links.Set(l.UserID().String(), l)
+	return nil
+}
+
+// GetLinkByUserID returns the chat linked to the user.
+func (s *TelegramStorage) GetLinkByUserID(
+	_ context.Context,
+	userID uuid.UUID,
+) (*telegram.Link, error) {
+	l, ok := s.links.Get(userID.String())
+	if !ok {
+		return nil, telegram.ErrNoLinkFound
+	}
+	return l, nil
+}
+
+// GetLinkByChatID returns the user linked to the chat.
+func (s *TelegramStorage) GetLinkByChatID(_ context.Context, chatID int64) (*telegram.Link, error) {
+	for _, l := range s.links.GetAll() {
+		if l.ChatID() == chatID {
+			return l, nil
+		}
+	}
+	return nil, telegram.ErrNoLinkFound
+}
+
+// DeleteLink unlinks the chat of the user.
+func (s *TelegramStorage) DeleteLink(_ context.Context, userID uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.links.Get(userID.String()); !ok {
+		return telegram.ErrNoLinkFound
+	}
+	s.links.Delete(userID.String())
+	return nil
+}
+
+// SaveCode saves the code of deep link.
+func (s *TelegramStorage) SaveCode(_ context.Context, c *telegram.LinkCode) error {
+	s.codes.Set(c.Code(), c)
+	return nil
+}
+
+// TakeCode returns the code and removes it, so it can be used only once.
+func (s *TelegramStorage) TakeCode(_ context.Context, code string) (*telegram.LinkCode, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.codes.Get(code)
+	if !ok {
+		return nil, telegram.ErrNoLinkCodeFound
+	}
+	s.codes.Delete(code)
+	return c, nil
+}
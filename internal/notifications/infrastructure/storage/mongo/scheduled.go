@@ -0,0 +1,367 @@
+// Package mongo is an implementation of notifications storages for MongoDB.
+package mongo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/FSO-VK/final-project-vk-backend/internal/notifications/domain/notification"
+	"github.com/FSO-VK/final-project-vk-backend/internal/notifications/domain/scheduled"
+	"github.com/FSO-VK/final-project-vk-backend/internal/utils/logcon"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const scheduledCollection = "scheduled"
+
+// ScheduledStorage implements scheduled.Repository for a MongoDB.
+// Notifications survive restarts of the service, due notifications
+// are found by the index on delivery time without scanning the others.
+// Every change is made by one atomic operation, changes of the notification
+// which version differs from the saved one are rejected.
+type ScheduledStorage struct {
+	client     *mongo.Client
+	collection *mongo.Collection
+	config     *Config
+	log        *logrus.Entry
+}
+
+// NewScheduledStorage creates a new MongoDB storage for scheduled notifications.
+func NewScheduledStorage(config *Config, log *logrus.Entry) (*ScheduledStorage, error) {
+	uri := fmt.Sprintf(
+		"mongodb://%s:%s@%s/%s",
+		config.User,
+		config.Password,
+		net.JoinHostPort(config.Host, config.Port), // required if using IPv6
+		config.Database,
+	)
+
+	client, err := mongo.Connect(options.Client().ApplyURI(uri))
+	if err != nil {
+		return nil, fmt.Errorf("MongoDB connect: %w", err)
+	}
+
+	s := &ScheduledStorage{
+		client:     client,
+		collection: client.Database(config.Database).Collection(scheduledCollection),
+		config:     config,
+		log:        log,
+	}
+
+	err = client.Ping(context.Background(), nil)
+	if err != nil {
+		_ = client.Disconnect(context.Background())
+		return nil, fmt.Errorf("MongoDB ping: %w", err)
+	}
+	s.debugLog(context.Background(), "MongoDB storage connected")
+
+	err = s.initIndexes()
+	if err != nil {
+		_ = client.Disconnect(context.Background())
+		return nil, fmt.Errorf("init indexes: %w", err)
+	}
+	s.debugLog(context.Background(), "MongoDB storage initialized")
+
+	return s, nil
+}
+
+// Save saves a scheduled notification replacing one with the same caller and key.
+func (s *ScheduledStorage) Save(ctx context.Context, n *scheduled.Notification) (bool, error) {
+	s.debugLog(ctx, "save scheduled notification %s/%s", n.Caller(), n.Key())
+
+	doc, err := toDocument(n, uuid.New())
+	if err != nil {
+		return false, err
+	}
+	result, err := s.collection.ReplaceOne(
+		ctx,
+		byKey(n.Caller(), n.Key()),
+		doc,
+		options.Replace().SetUpsert(true),
+	)
+	if err != nil {
+		return false, fmt.Errorf("replace scheduled notification: %w", err)
+	}
+	return result.MatchedCount > 0, nil
+}
+
+// GetByKey returns a scheduled notification by dedupe key of the caller.
+func (s *ScheduledStorage) GetByKey(
+	ctx context.Context,
+	caller string,
+	key string,
+) (*scheduled.Notification, error) {
+	var doc scheduledDocument
+	err := s.collection.FindOne(ctx, byKey(caller, key)).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, scheduled.ErrNoNotificationFound
+	} else if err != nil {
+		return nil, fmt.Errorf("find scheduled notification: %w", err)
+	}
+	return doc.toNotification()
+}
+
+// Due returns at most limit notifications to send at t or earlier
+// ordered by delivery time.
+func (s *ScheduledStorage) Due(
+	ctx context.Context,
+	t time.Time,
+	limit int,
+) ([]*scheduled.Notification, error) {
+	cursor, err := s.collection.Find(
+		ctx,
+		bson.D{{Key: "deliverAt", Value: bson.D{{Key: "$lte", Value: t}}}},
+		options.Find().
+			SetSort(bson.D{{Key: "deliverAt", Value: 1}}).
+			SetLimit(int64(limit)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("find due notifications: %w", err)
+	}
+	var docs []scheduledDocument
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("find due notifications decode: %w", err)
+	}
+
+	result := make([]*scheduled.Notification, 0, len(docs))
+	for _, doc := range docs {
+		n, err := doc.toNotification()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, n)
+	}
+	s.debugLog(ctx, "due notifications: %d", len(result))
+	return result, nil
+}
+
+// Update saves changes of the notification if it has not changed since it was got.
+func (s *ScheduledStorage) Update(ctx context.Context, n *scheduled.Notification) error {
+	s.debugLog(ctx, "update scheduled notification %s/%s", n.Caller(), n.Key())
+
+	doc, err := toDocument(n, uuid.New())
+	if err != nil {
+		return err
+	}
+	result, err := s.collection.ReplaceOne(ctx, byVersion(n), doc)
+	if err != nil {
+		return fmt.Errorf("replace scheduled notification: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return scheduled.ErrNotificationChanged
+	}
+	return nil
+}
+
+// Delete removes the notification if it has not changed since it was got.
+func (s *ScheduledStorage) Delete(ctx context.Context, n *scheduled.Notification) error {
+	s.debugLog(ctx, "delete scheduled notification %s/%s", n.Caller(), n.Key())
+
+	result, err := s.collection.DeleteOne(ctx, byVersion(n))
+	if err != nil {
+		return fmt.Errorf("delete scheduled notification: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return scheduled.ErrNotificationChanged
+	}
+	return nil
+}
+
+// DeleteByKey removes a scheduled notification.
+func (s *ScheduledStorage) DeleteByKey(ctx context.Context, caller string, key string) error {
+	s.debugLog(ctx, "delete scheduled notification %s/%s", caller, key)
+
+	result, err := s.collection.DeleteOne(ctx, byKey(caller, key))
+	if err != nil {
+		return fmt.Errorf("delete scheduled notification: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return scheduled.ErrNoNotificationFound
+	}
+	return nil
+}
+
+// Close gracefully closes MongoDB connection.
+func (s *ScheduledStorage) Close(ctx context.Context) error {
+	if s.client != nil {
+		return s.client.Disconnect(ctx)
+	}
+	return nil
+}
+
+// initIndexes creates unique index of keys of callers and index of delivery time.
+func (s *ScheduledStorage) initIndexes() error {
+	indexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "caller", Value: 1}, {Key: "key", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "deliverAt", Value: 1}},
+			Options: nil,
+		},
+	}
+	names, err := s.collection.Indexes().CreateMany(context.Background(), indexes)
+	if err != nil {
+		return fmt.Errorf("create indexes %v: %w", names, err)
+	}
+	return nil
+}
+
+func (s *ScheduledStorage) debugLog(ctx context.Context, format string, args ...any) {
+	if !s.config.Log {
+		return
+	}
+	log, ok := logcon.FromContext(ctx)
+	if !ok {
+		log = s.log
+	}
+	log.Debugf(format, args...)
+}
+
+// byKey returns filter of the notification by key of the caller.
+func byKey(caller string, key string) bson.D {
+	return bson.D{{Key: "caller", Value: caller}, {Key: "key", Value: key}}
+}
+
+// byVersion returns filter of the notification in the version it was got in.
+func byVersion(n *scheduled.Notification) bson.D {
+	return append(byKey(n.Caller(), n.Key()), bson.E{Key: "version", Value: n.Version().String()})
+}
+
+// scheduledDocument is a scheduled notification stored in MongoDB.
+type scheduledDocument struct {
+	Caller    string          `bson:"caller"`
+	Key       string          `bson:"key"`
+	UserID    string          `bson:"userId"`
+	Category  string          `bson:"category"`
+	Content   contentDocument `bson:"content"`
+	DeliverAt time.Time       `bson:"deliverAt"`
+	Attempts  int             `bson:"attempts"`
+	CreatedAt time.Time       `bson:"createdAt"`
+	Version   string          `bson:"version"`
+}
+
+// contentDocument is content of the scheduled notification.
+// Params are kept in JSON, so they are restored with the same types
+// as they were received in and rendered by templates the same way.
+type contentDocument struct {
+	Type     string            `bson:"type"`
+	Title    string            `bson:"title"`
+	Body     string            `bson:"body"`
+	URL      string            `bson:"url"`
+	Icon     string            `bson:"icon"`
+	Tag      string            `bson:"tag"`
+	Renotify bool              `bson:"renotify"`
+	Urgency  string            `bson:"urgency"`
+	Actions  []actionDocument  `bson:"actions"`
+	Data     map[string]string `bson:"data"`
+	Template string            `bson:"template"`
+	Params   string            `bson:"params"`
+	Locale   string            `bson:"locale"`
+}
+
+type actionDocument struct {
+	Action string `bson:"action"`
+	Title  string `bson:"title"`
+}
+
+// toDocument returns document of the notification saved in the version.
+func toDocument(n *scheduled.Notification, version uuid.UUID) (scheduledDocument, error) {
+	content := n.Content()
+	actions := make([]actionDocument, 0, len(content.Actions))
+	for _, action := range content.Actions {
+		actions = append(actions, actionDocument(action))
+	}
+	var params string
+	if content.Params != nil {
+		encoded, err := json.Marshal(content.Params)
+		if err != nil {
+			return scheduledDocument{}, fmt.Errorf(
+				"scheduled notification %s/%s params: %w", n.Caller(), n.Key(), err)
+		}
+		params = string(encoded)
+	}
+	return scheduledDocument{
+		Caller:   n.Caller(),
+		Key:      n.Key(),
+		UserID:   n.UserID().String(),
+		Category: n.Category(),
+		Content: contentDocument{
+			Type:     content.Type,
+			Title:    content.Title,
+			Body:     content.Body,
+			URL:      content.URL,
+			Icon:     content.Icon,
+			Tag:      content.Tag,
+			Renotify: content.Renotify,
+			Urgency:  string(content.Urgency),
+			Actions:  actions,
+			Data:     content.Data,
+			Template: content.Template,
+			Params:   params,
+			Locale:   content.Locale,
+		},
+		DeliverAt: n.DeliverAt(),
+		Attempts:  n.Attempts(),
+		CreatedAt: n.CreatedAt(),
+		Version:   version.String(),
+	}, nil
+}
+
+// toNotification restores the notification from the document.
+func (d *scheduledDocument) toNotification() (*scheduled.Notification, error) {
+	userID, err := uuid.Parse(d.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("scheduled notification %s/%s user id: %w", d.Caller, d.Key, err)
+	}
+	version, err := uuid.Parse(d.Version)
+	if err != nil {
+		return nil, fmt.Errorf("scheduled notification %s/%s version: %w", d.Caller, d.Key, err)
+	}
+	var params map[string]any
+	if d.Content.Params != "" {
+		err := json.Unmarshal([]byte(d.Content.Params), &params)
+		if err != nil {
+			return nil, fmt.Errorf("scheduled notification %s/%s params: %w", d.Caller, d.Key, err)
+		}
+	}
+	actions := make([]notification.Action, 0, len(d.Content.Actions))
+	for _, action := range d.Content.Actions {
+		actions = append(actions, notification.Action(action))
+	}
+
+	content := notification.Content{
+		Type:     d.Content.Type,
+		Title:    d.Content.Title,
+		Body:     d.Content.Body,
+		URL:      d.Content.URL,
+		Icon:     d.Content.Icon,
+		Tag:      d.Content.Tag,
+		Renotify: d.Content.Renotify,
+		Urgency:  notification.Urgency(d.Content.Urgency),
+		Actions:  actions,
+		Data:     d.Content.Data,
+		Template: d.Content.Template,
+		Params:   params,
+		Locale:   d.Content.Locale,
+	}
+	return scheduled.Restore(
+		d.Caller,
+		d.Key,
+		userID,
+		d.Category,
+		content,
+		d.DeliverAt,
+		d.Attempts,
+		d.CreatedAt,
+		version,
+	), nil
+}
@@ -0,0 +1,224 @@
+// Package telegramclient implements TelegramProvider interface by Telegram Bot API.
+package telegramclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"strings"
+
+	telegramProvider "github.com/FSO-VK/final-project-vk-backend/internal/notifications/application/telegram_provider"
+	"github.com/FSO-VK/final-project-vk-backend/internal/notifications/domain/notification"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	parseModeHTML = "HTML"
+	// chatNotFound is a description of error for chat which was deleted or never started the bot.
+	chatNotFound = "chat not found"
+)
+
+// Client implements TelegramProvider.
+type Client struct {
+	client *http.Client
+	cfg    TelegramClient
+	logger *logrus.Entry
+}
+
+// NewClient creates a new Client.
+func NewClient(cfg TelegramClient, logger *logrus.Entry) *Client {
+	client := &http.Client{
+		// long polling request lasts up to PollTimeout
+		Timeout:       cfg.Timeout + cfg.PollTimeout,
+		Transport:     nil,
+		CheckRedirect: nil,
+		Jar:           nil,
+	}
+	return &Client{client: client, cfg: cfg, logger: logger}
+}
+
+type inlineKeyboardButton struct {
+	Text         string `json:"text"`
+	CallbackData string `json:"callback_data"`
+}
+
+type inlineKeyboardMarkup struct {
+	InlineKeyboard [][]inlineKeyboardButton `json:"inline_keyboard"`
+}
+
+type sendMessageRequest struct {
+	ChatID      int64                 `json:"chat_id"`
+	Text        string                `json:"text"`
+	ParseMode   string                `json:"parse_mode"`
+	ReplyMarkup *inlineKeyboardMarkup `json:"reply_markup,omitempty"`
+}
+
+type answerCallbackQueryRequest struct {
+	CallbackQueryID string `json:"callback_query_id"`
+	Text            string `json:"text,omitempty"`
+}
+
+type getUpdatesRequest struct {
+	Offset         int64    `json:"offset"`
+	Timeout        int      `json:"timeout"`
+	AllowedUpdates []string `json:"allowed_updates"`
+}
+
+type chat struct {
+	ID int64 `json:"id"`
+}
+
+type message struct {
+	Chat chat   `json:"chat"`
+	Text string `json:"text"`
+}
+
+type callbackQuery struct {
+	ID      string   `json:"id"`
+	Message *message `json:"message"`
+	Data    string   `json:"data"`
+}
+
+type update struct {
+	UpdateID      int64          `json:"update_id"`
+	Message       *message       `json:"message"`
+	CallbackQuery *callbackQuery `json:"callback_query"`
+}
+
+// botResponse is a response of Bot API, Result is set if OK is true.
+type botResponse struct {
+	OK          bool            `json:"ok"`
+	ErrorCode   int             `json:"error_code"`
+	Description string          `json:"description"`
+	Result      json.RawMessage `json:"result"`
+}
+
+// SendMessage implements TelegramProvider interface.
+// Buttons are placed in one row under the message.
+func (h *Client) SendMessage(
+	ctx context.Context,
+	chatID int64,
+	content notification.Content,
+	buttons []telegramProvider.Button,
+) error {
+	req := sendMessageRequest{
+		ChatID:      chatID,
+		Text:        messageText(content),
+		ParseMode:   parseModeHTML,
+		ReplyMarkup: nil,
+	}
+	if len(buttons) > 0 {
+		row := make([]inlineKeyboardButton, 0, len(buttons))
+		for _, button := range buttons {
+			row = append(row, inlineKeyboardButton{Text: button.Title, CallbackData: button.Data})
+		}
+		req.ReplyMarkup = &inlineKeyboardMarkup{InlineKeyboard: [][]inlineKeyboardButton{row}}
+	}
+	return h.call(ctx, "sendMessage", req, nil)
+}
+
+// AnswerCallback implements TelegramProvider interface.
+func (h *Client) AnswerCallback(ctx context.Context, callbackID string, text string) error {
+	req := answerCallbackQueryRequest{CallbackQueryID: callbackID, Text: text}
+	return h.call(ctx, "answerCallbackQuery", req, nil)
+}
+
+// Updates implements TelegramProvider interface by long polling.
+func (h *Client) Updates(ctx context.Context, offset int64) ([]telegramProvider.Update, error) {
+	req := getUpdatesRequest{
+		Offset:         offset,
+		Timeout:        int(h.cfg.PollTimeout.Seconds()),
+		AllowedUpdates: []string{"message", "callback_query"},
+	}
+	var received []update
+	if err := h.call(ctx, "getUpdates", req, &received); err != nil {
+		return nil, err
+	}
+
+	updates := make([]telegramProvider.Update, 0, len(received))
+	for _, u := range received {
+		converted := telegramProvider.Update{ID: u.UpdateID}
+		switch {
+		case u.CallbackQuery != nil:
+			converted.CallbackID = u.CallbackQuery.ID
+			converted.CallbackData = u.CallbackQuery.Data
+			if u.CallbackQuery.Message != nil {
+				converted.ChatID = u.CallbackQuery.Message.Chat.ID
+			}
+		case u.Message != nil:
+			converted.ChatID = u.Message.Chat.ID
+			converted.Text = u.Message.Text
+		}
+		updates = append(updates, converted)
+	}
+	return updates, nil
+}
+
+// call calls the method of Bot API and decodes result of the method into result if it is not nil.
+// The bot can't write to chats of users who blocked it or deleted the chat.
+func (h *Client) call(ctx context.Context, method string, req any, result any) error {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrBadResponse, err)
+	}
+	endpoint, err := url.JoinPath(h.cfg.BaseURL, "bot"+h.cfg.Token, method)
+	if err != nil {
+		return err
+	}
+	httpReq, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPost,
+		endpoint,
+		bytes.NewReader(body),
+	)
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := h.client.Do(httpReq)
+	if err != nil {
+		// token is a part of URL, so the error is not logged
+		h.logger.WithField("method", method).Warn("telegram API request failed")
+		return ErrTelegramUnavailable
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	var parsedResponse botResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsedResponse); err != nil {
+		h.logger.WithError(err).Error("failed to decode telegram API response")
+		return fmt.Errorf("%w: %w", ErrBadResponse, err)
+	}
+	if !parsedResponse.OK {
+		if parsedResponse.ErrorCode == http.StatusForbidden ||
+			strings.Contains(parsedResponse.Description, chatNotFound) {
+			return telegramProvider.ErrChatGone
+		}
+		return fmt.Errorf("%w: %s", ErrBadResponse, parsedResponse.Description)
+	}
+	if result == nil {
+		return nil
+	}
+	if err := json.Unmarshal(parsedResponse.Result, result); err != nil {
+		return fmt.Errorf("%w: %w", ErrBadResponse, err)
+	}
+	return nil
+}
+
+// messageText returns text of the message with bold title.
+func messageText(content notification.Content) string {
+	text := "<b>" + html.EscapeString(content.Title) + "</b>"
+	if content.Title == "" {
+		text = ""
+	}
+	if content.Body != "" {
+		text = strings.TrimPrefix(text+"\n"+html.EscapeString(content.Body), "\n")
+	}
+	return text
+}
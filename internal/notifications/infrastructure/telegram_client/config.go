@@ -0,0 +1,15 @@
+package telegramclient
+
+import "time"
+
+// TelegramClient is configuration for Telegram Bot API client.
+type TelegramClient struct {
+	BaseURL string // https://api.telegram.org
+	// Token is a token of the bot, the bot is disabled if it is empty.
+	Token string
+	// BotName is a username of the bot used in deep links.
+	BotName string
+	Timeout time.Duration // общий timeout запроса (30c)
+	// PollTimeout is how long the server holds request for updates if there are none.
+	PollTimeout time.Duration
+}
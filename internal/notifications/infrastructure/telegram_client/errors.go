@@ -0,0 +1,10 @@
+package telegramclient
+
+import "errors"
+
+var (
+	// ErrTelegramUnavailable is returned when Bot API is unavailable.
+	ErrTelegramUnavailable = errors.New("telegram api: service unavailable")
+	// ErrBadResponse is returned when Bot API rejects the request or body is not like expected.
+	ErrBadResponse = errors.New("telegram api: unexpected response")
+)
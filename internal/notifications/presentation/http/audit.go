@@ -0,0 +1,43 @@
+package http
+
+import (
+	"github.com/FSO-VK/final-project-vk-backend/internal/utils/httputil"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// audit records which service asked to notify whom and what,
+// so unexpected notifications can be traced to the caller.
+func (h *NotificationsHandlers) audit(
+	c *gin.Context,
+	action string,
+	fields logrus.Fields,
+	err error,
+) {
+	caller, callerErr := httputil.GetCallerFromCtx(c.Request)
+	if callerErr != nil {
+		caller = "unknown"
+	}
+	log := h.logger.WithFields(fields).WithFields(logrus.Fields{
+		"audit":  true,
+		"caller": caller,
+		"action": action,
+	})
+	if err != nil {
+		log.WithError(err).Warn("Service request failed")
+		return
+	}
+	log.Info("Service request")
+}
+
+// auditFieldsOf returns what is audited of the notification.
+func auditFieldsOf(reqJSON SendNotificationJSONRequest) logrus.Fields {
+	return logrus.Fields{
+		"user_id":    reqJSON.UserID,
+		"category":   reqJSON.Category,
+		"type":       reqJSON.Type,
+		"title":      reqJSON.Title,
+		"template":   reqJSON.Template,
+		"dedupe_key": reqJSON.DedupeKey,
+	}
+}
@@ -1,5 +1,7 @@
 package http
 
+import "time"
+
 // SubscriptionObject is info about subscription .
 type SubscriptionObject struct {
 	SendInfo  SendInfo `json:"subscription"`
@@ -32,3 +34,53 @@ type PushNotificationObject struct {
 	Title          string `json:"title"`
 	Body           string `json:"body"`
 }
+
+// NotificationActionObject is a button of notification,
+// service worker handles click by action id.
+type NotificationActionObject struct {
+	Action string `json:"action"`
+	Title  string `json:"title"`
+}
+
+// DeviceResultObject is a result of delivery by "push" or "email" channel,
+// status is one of "sent", "muted", "removed" and "failed".
+type DeviceResultObject struct {
+	Channel        string `json:"channel"`
+	SubscriptionID string `json:"subscriptionId,omitempty"`
+	Status         string `json:"status"`
+}
+
+// PreferencesObject is notification settings of a user,
+// email is "off", "fallback" to use it when push is not delivered or "always",
+// locale is a language of notifications, "ru" or "en".
+type PreferencesObject struct {
+	Categories   map[string]bool   `json:"categories"`
+	QuietHours   *QuietHoursObject `json:"quietHours"`
+	MutedDevices []string          `json:"mutedDevices"`
+	Email        string            `json:"email"`
+	Locale       string            `json:"locale"`
+}
+
+// QuietHoursObject is a daily period when notifications are held back,
+// mode is "defer" to deliver them after or "digest" to deliver one summary.
+type QuietHoursObject struct {
+	Start    string `json:"start"`
+	End      string `json:"end"`
+	TimeZone string `json:"timeZone"`
+	Mode     string `json:"mode"`
+}
+
+// InboxNotificationObject is a notification in history of the user,
+// readAt is null for unread notification.
+type InboxNotificationObject struct {
+	ID         string               `json:"id"`
+	Category   string               `json:"category"`
+	Type       string               `json:"type"`
+	Title      string               `json:"title"`
+	Body       string               `json:"body"`
+	URL        string               `json:"url"`
+	Data       map[string]string    `json:"data"`
+	Deliveries []DeviceResultObject `json:"deliveries"`
+	CreatedAt  time.Time            `json:"createdAt"`
+	ReadAt     *time.Time           `json:"readAt"`
+}
@@ -11,6 +11,52 @@ const (
 	MsgFailedToDeleteSubscription api.ErrorType = "Failed to delete subscription"
 	// MsgFailedToSendNotification is a message for failed to send notification.
 	MsgFailedToSendNotification api.ErrorType = "Failed to send notification"
+	// MsgInvalidTemplate is a message for unknown template or missing params of notification.
+	MsgInvalidTemplate api.ErrorType = "Invalid notification template"
 	// MsgMissingSlug is a message for missing slug.
 	MsgMissingSlug api.ErrorType = "Missing slug"
 )
+
+const (
+	// MsgFailedToGetPreferences is a message for failed to get preferences.
+	MsgFailedToGetPreferences api.ErrorType = "Failed to get notification preferences"
+	// MsgFailedToUpdatePreferences is a message for failed to update preferences.
+	MsgFailedToUpdatePreferences api.ErrorType = "Failed to update notification preferences"
+	// MsgInvalidPreferences is a message for invalid preferences.
+	MsgInvalidPreferences api.ErrorType = "Invalid notification preferences"
+	// MsgUnknownDevice is a message for muted device which is not a push subscription of the user.
+	MsgUnknownDevice api.ErrorType = "Unknown device"
+)
+
+const (
+	// MsgFailedToGetNotifications is a message for failed to get history of notifications.
+	MsgFailedToGetNotifications api.ErrorType = "Failed to get notifications"
+	// MsgInvalidPage is a message for invalid offset or limit of the page.
+	MsgInvalidPage api.ErrorType = "Invalid page"
+	// MsgFailedToMarkRead is a message for failed to mark notifications read.
+	MsgFailedToMarkRead api.ErrorType = "Failed to mark notifications read"
+	// MsgNotificationNotFound is a message for notification not found in history of the user.
+	MsgNotificationNotFound api.ErrorType = "Notification not found"
+)
+
+const (
+	// MsgFailedToLinkTelegram is a message for failed to create link to the bot.
+	MsgFailedToLinkTelegram api.ErrorType = "Failed to link telegram"
+	// MsgTelegramDisabled is a message for the bot which is not configured.
+	MsgTelegramDisabled api.ErrorType = "Telegram is not available"
+	// MsgFailedToGetTelegramLink is a message for failed to get status of telegram link.
+	MsgFailedToGetTelegramLink api.ErrorType = "Failed to get telegram link"
+	// MsgFailedToUnlinkTelegram is a message for failed to unlink telegram chat.
+	MsgFailedToUnlinkTelegram api.ErrorType = "Failed to unlink telegram"
+	// MsgTelegramNotLinked is a message for account without linked telegram chat.
+	MsgTelegramNotLinked api.ErrorType = "Telegram is not linked"
+)
+
+const (
+	// MsgFailedToScheduleNotification is a message for failed to schedule notification.
+	MsgFailedToScheduleNotification api.ErrorType = "Failed to schedule notification"
+	// MsgInvalidSchedule is a message for invalid scheduled notification.
+	MsgInvalidSchedule api.ErrorType = "Invalid scheduled notification"
+	// MsgScheduledNotificationNotFound is a message for no notification scheduled by the key.
+	MsgScheduledNotificationNotFound api.ErrorType = "Scheduled notification not found"
+)
@@ -3,7 +3,9 @@ package http
 
 import (
 	"context"
+	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/FSO-VK/final-project-vk-backend/internal/notifications/application"
 	"github.com/FSO-VK/final-project-vk-backend/internal/utils/httputil"
@@ -15,6 +17,8 @@ import (
 const (
 	// SlugID is a slug for id.
 	SlugID = "id"
+	// SlugKey is a slug for dedupe key of scheduled notification.
+	SlugKey = "key"
 )
 
 // NotificationsHandlers is a handler for Notifications.
@@ -150,6 +154,7 @@ func (h *NotificationsHandlers) CreateSubscriptionGin(c *gin.Context) {
 	go func() {
 		command := &application.SendNotificationCommand{
 			UserID: auth.UserID,
+			Type:   "welcome",
 			Title:  "Добро пожаловать в MyHealthBox!",
 			Body:   "Спасибо, что включили уведомления, рады видеть вас в нашем сервисе!",
 		}
@@ -203,16 +208,33 @@ func (h *NotificationsHandlers) DeleteSubscriptionGin(c *gin.Context) {
 	})
 }
 
-// SendNotificationJSONRequest is a request for SendNotification.
+// SendNotificationJSONRequest is a request for SendNotification,
+// notification recently sent with the same dedupe key is dropped.
 type SendNotificationJSONRequest struct {
 	// embedded struct
 	PushNotificationObject `json:",inline"`
 
-	UserID string `json:"userId"`
+	UserID    string                     `json:"userId"`
+	Category  string                     `json:"category"`
+	Type      string                     `json:"type"`
+	URL       string                     `json:"url"`
+	Icon      string                     `json:"icon"`
+	Tag       string                     `json:"tag"`
+	Renotify  bool                       `json:"renotify"`
+	Urgency   string                     `json:"urgency"`
+	Actions   []NotificationActionObject `json:"actions"`
+	Data      map[string]string          `json:"data"`
+	Template  string                     `json:"template"`
+	Params    map[string]any             `json:"params"`
+	DedupeKey string                     `json:"dedupeKey"`
 }
 
-// SendNotificationJSONResponse is a response for SendNotification.
-type SendNotificationJSONResponse struct{}
+// SendNotificationJSONResponse is a response for SendNotification,
+// duplicate notification is not sent.
+type SendNotificationJSONResponse struct {
+	Devices   []DeviceResultObject `json:"devices"`
+	Duplicate bool                 `json:"duplicate"`
+}
 
 // SendNotification delete a subscription one time for every device.
 func (h *NotificationsHandlers) SendNotificationGin(c *gin.Context) {
@@ -227,26 +249,414 @@ func (h *NotificationsHandlers) SendNotificationGin(c *gin.Context) {
 		return
 	}
 
+	command := sendNotificationFromHTTP(reqJSON)
+	serviceResponse, err := h.app.SendNotification.Execute(c.Request.Context(), command)
+	h.audit(c, "send", auditFieldsOf(reqJSON), err)
+	if errors.Is(err, application.ErrInvalidTemplate) {
+		h.logger.WithError(err).Error("Failed to render notification")
+		c.JSON(http.StatusBadRequest, api.Response[any]{
+			StatusCode: http.StatusBadRequest,
+			Body:       struct{}{},
+			Error:      MsgInvalidTemplate,
+		})
+		return
+	} else if err != nil {
+		h.logger.WithError(err).Error("Failed to send notification")
+		c.JSON(http.StatusInternalServerError, api.Response[any]{
+			StatusCode: http.StatusInternalServerError,
+			Body:       struct{}{},
+			Error:      MsgFailedToSendNotification,
+		})
+		return
+	}
+
+	response := &SendNotificationJSONResponse{
+		Devices:   make([]DeviceResultObject, 0, len(serviceResponse.Devices)),
+		Duplicate: serviceResponse.Duplicate,
+	}
+	for _, device := range serviceResponse.Devices {
+		response.Devices = append(response.Devices, DeviceResultObject(device))
+	}
+
+	c.JSON(http.StatusOK, api.Response[any]{
+		StatusCode: http.StatusOK,
+		Body:       response,
+		Error:      "",
+	})
+}
+
+func sendNotificationFromHTTP(
+	reqJSON SendNotificationJSONRequest,
+) *application.SendNotificationCommand {
 	command := &application.SendNotificationCommand{
-		UserID: reqJSON.UserID,
-		Title:  reqJSON.Title,
-		Body:   reqJSON.Body,
+		UserID:    reqJSON.UserID,
+		Category:  reqJSON.Category,
+		Type:      reqJSON.Type,
+		Title:     reqJSON.Title,
+		Body:      reqJSON.Body,
+		URL:       reqJSON.URL,
+		Icon:      reqJSON.Icon,
+		Tag:       reqJSON.Tag,
+		Renotify:  reqJSON.Renotify,
+		Urgency:   reqJSON.Urgency,
+		Actions:   make([]application.NotificationAction, 0, len(reqJSON.Actions)),
+		Data:      reqJSON.Data,
+		Template:  reqJSON.Template,
+		Params:    reqJSON.Params,
+		DedupeKey: reqJSON.DedupeKey,
+	}
+	for _, action := range reqJSON.Actions {
+		command.Actions = append(command.Actions, application.NotificationAction(action))
 	}
+	return command
+}
 
-	_, err := h.app.SendNotification.Execute(c.Request.Context(), command)
+// GetPreferencesJSONResponse is a response for GetPreferences.
+type GetPreferencesJSONResponse struct {
+	// embedded struct
+	PreferencesObject `json:",inline"`
+}
+
+// GetPreferencesGin returns notification preferences of the user.
+func (h *NotificationsHandlers) GetPreferencesGin(c *gin.Context) {
+	auth, err := httputil.GetAuthFromCtx(c.Request)
 	if err != nil {
-		h.logger.WithError(err).Error("Failed to send notification")
+		c.JSON(http.StatusUnauthorized, api.Response[any]{
+			StatusCode: http.StatusUnauthorized,
+			Error:      api.MsgUnauthorized,
+			Body:       struct{}{},
+		})
+		return
+	}
+
+	serviceRequest := &application.GetPreferencesCommand{
+		UserID: auth.UserID,
+	}
+	serviceResponse, err := h.app.GetPreferences.Execute(c.Request.Context(), serviceRequest)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get preferences")
 		c.JSON(http.StatusInternalServerError, api.Response[any]{
 			StatusCode: http.StatusInternalServerError,
 			Body:       struct{}{},
-			Error:      MsgFailedToSendNotification,
+			Error:      MsgFailedToGetPreferences,
 		})
 		return
 	}
 
 	c.JSON(http.StatusOK, api.Response[any]{
 		StatusCode: http.StatusOK,
-		Body:       struct{}{},
+		Body: &GetPreferencesJSONResponse{
+			PreferencesObject: preferencesToHTTP(serviceResponse.PreferencesInfo),
+		},
+		Error: "",
+	})
+}
+
+// UpdatePreferencesJSONRequest is a request for UpdatePreferences.
+type UpdatePreferencesJSONRequest struct {
+	// embedded struct
+	PreferencesObject `json:",inline"`
+}
+
+// UpdatePreferencesJSONResponse is a response for UpdatePreferences.
+type UpdatePreferencesJSONResponse struct {
+	// embedded struct
+	PreferencesObject `json:",inline"`
+}
+
+// UpdatePreferencesGin replaces notification preferences of the user.
+func (h *NotificationsHandlers) UpdatePreferencesGin(c *gin.Context) {
+	auth, err := httputil.GetAuthFromCtx(c.Request)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, api.Response[any]{
+			StatusCode: http.StatusUnauthorized,
+			Error:      api.MsgUnauthorized,
+			Body:       struct{}{},
+		})
+		return
+	}
+
+	var reqJSON UpdatePreferencesJSONRequest
+	if err := c.ShouldBindJSON(&reqJSON); err != nil {
+		h.logger.WithError(err).Error("Failed to bind request body")
+		c.JSON(http.StatusBadRequest, api.Response[any]{
+			StatusCode: http.StatusBadRequest,
+			Body:       struct{}{},
+			Error:      api.MsgBadBody,
+		})
+		return
+	}
+
+	command := &application.UpdatePreferencesCommand{
+		UserID: auth.UserID,
+		PreferencesInfo: application.PreferencesInfo{
+			Categories:   reqJSON.Categories,
+			QuietHours:   nil,
+			MutedDevices: reqJSON.MutedDevices,
+			EmailMode:    reqJSON.Email,
+			Locale:       reqJSON.Locale,
+		},
+	}
+	if reqJSON.QuietHours != nil {
+		command.QuietHours = &application.QuietHoursInfo{
+			Start:    reqJSON.QuietHours.Start,
+			End:      reqJSON.QuietHours.End,
+			TimeZone: reqJSON.QuietHours.TimeZone,
+			Mode:     reqJSON.QuietHours.Mode,
+		}
+	}
+
+	serviceResponse, err := h.app.UpdatePreferences.Execute(c.Request.Context(), command)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to update preferences")
+		status, body := h.handleUpdatePreferencesError(err)
+		c.JSON(status, body)
+		return
+	}
+
+	c.JSON(http.StatusOK, api.Response[any]{
+		StatusCode: http.StatusOK,
+		Body: &UpdatePreferencesJSONResponse{
+			PreferencesObject: preferencesToHTTP(serviceResponse.PreferencesInfo),
+		},
+		Error: "",
+	})
+}
+
+// handleUpdatePreferencesError maps service errors to HTTP status and API responses.
+func (h *NotificationsHandlers) handleUpdatePreferencesError(err error) (int, *api.Response[any]) {
+	switch {
+	case errors.Is(err, application.ErrInvalidPreferences):
+		return http.StatusBadRequest, &api.Response[any]{
+			StatusCode: http.StatusBadRequest,
+			Body:       struct{}{},
+			Error:      MsgInvalidPreferences,
+		}
+	case errors.Is(err, application.ErrUnknownDevice):
+		return http.StatusUnprocessableEntity, &api.Response[any]{
+			StatusCode: http.StatusUnprocessableEntity,
+			Body:       struct{}{},
+			Error:      MsgUnknownDevice,
+		}
+	default:
+		return http.StatusInternalServerError, &api.Response[any]{
+			StatusCode: http.StatusInternalServerError,
+			Body:       struct{}{},
+			Error:      MsgFailedToUpdatePreferences,
+		}
+	}
+}
+
+func preferencesToHTTP(info application.PreferencesInfo) PreferencesObject {
+	obj := PreferencesObject{
+		Categories:   info.Categories,
+		QuietHours:   nil,
+		MutedDevices: info.MutedDevices,
+		Email:        info.EmailMode,
+		Locale:       info.Locale,
+	}
+	if info.QuietHours != nil {
+		obj.QuietHours = &QuietHoursObject{
+			Start:    info.QuietHours.Start,
+			End:      info.QuietHours.End,
+			TimeZone: info.QuietHours.TimeZone,
+			Mode:     info.QuietHours.Mode,
+		}
+	}
+	return obj
+}
+
+// ListNotificationsJSONResponse is a response for ListNotifications.
+type ListNotificationsJSONResponse struct {
+	Notifications []InboxNotificationObject `json:"notifications"`
+	Total         int                       `json:"total"`
+	Unread        int                       `json:"unread"`
+}
+
+// ListNotificationsGin returns a page of notifications of the user, newest first.
+// Page is set by offset and limit query params.
+func (h *NotificationsHandlers) ListNotificationsGin(c *gin.Context) {
+	auth, err := httputil.GetAuthFromCtx(c.Request)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, api.Response[any]{
+			StatusCode: http.StatusUnauthorized,
+			Error:      api.MsgUnauthorized,
+			Body:       struct{}{},
+		})
+		return
+	}
+
+	offset, offsetErr := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	limit, limitErr := strconv.Atoi(c.DefaultQuery("limit", "0"))
+	if offsetErr != nil || limitErr != nil {
+		c.JSON(http.StatusBadRequest, api.Response[any]{
+			StatusCode: http.StatusBadRequest,
+			Body:       struct{}{},
+			Error:      MsgInvalidPage,
+		})
+		return
+	}
+
+	serviceRequest := &application.ListNotificationsCommand{
+		UserID: auth.UserID,
+		Offset: offset,
+		Limit:  limit,
+	}
+	serviceResponse, err := h.app.ListNotifications.Execute(c.Request.Context(), serviceRequest)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get notifications")
+		if errors.Is(err, application.ErrInvalidPage) {
+			c.JSON(http.StatusBadRequest, api.Response[any]{
+				StatusCode: http.StatusBadRequest,
+				Body:       struct{}{},
+				Error:      MsgInvalidPage,
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, api.Response[any]{
+			StatusCode: http.StatusInternalServerError,
+			Body:       struct{}{},
+			Error:      MsgFailedToGetNotifications,
+		})
+		return
+	}
+
+	response := &ListNotificationsJSONResponse{
+		Notifications: make([]InboxNotificationObject, 0, len(serviceResponse.Notifications)),
+		Total:         serviceResponse.Total,
+		Unread:        serviceResponse.Unread,
+	}
+	for _, n := range serviceResponse.Notifications {
+		response.Notifications = append(response.Notifications, inboxNotificationToHTTP(n))
+	}
+
+	c.JSON(http.StatusOK, api.Response[any]{
+		StatusCode: http.StatusOK,
+		Body:       response,
 		Error:      "",
 	})
 }
+
+// UnreadNotificationsJSONResponse is a number of unread notifications of the user.
+type UnreadNotificationsJSONResponse struct {
+	Unread int `json:"unread"`
+}
+
+// CountUnreadNotificationsGin returns number of unread notifications for the badge.
+func (h *NotificationsHandlers) CountUnreadNotificationsGin(c *gin.Context) {
+	auth, err := httputil.GetAuthFromCtx(c.Request)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, api.Response[any]{
+			StatusCode: http.StatusUnauthorized,
+			Error:      api.MsgUnauthorized,
+			Body:       struct{}{},
+		})
+		return
+	}
+
+	serviceRequest := &application.CountUnreadNotificationsCommand{
+		UserID: auth.UserID,
+	}
+	serviceResponse, err := h.app.CountUnreadNotifications.Execute(
+		c.Request.Context(),
+		serviceRequest,
+	)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to count unread notifications")
+		c.JSON(http.StatusInternalServerError, api.Response[any]{
+			StatusCode: http.StatusInternalServerError,
+			Body:       struct{}{},
+			Error:      MsgFailedToGetNotifications,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, api.Response[any]{
+		StatusCode: http.StatusOK,
+		Body:       &UnreadNotificationsJSONResponse{Unread: serviceResponse.Unread},
+		Error:      "",
+	})
+}
+
+// MarkNotificationReadGin marks read the notification of the user.
+func (h *NotificationsHandlers) MarkNotificationReadGin(c *gin.Context) {
+	slugNotificationID := c.Param(SlugID)
+	if slugNotificationID == "" {
+		h.logger.Error("Notification ID not found in path params")
+		c.JSON(http.StatusBadRequest, api.Response[any]{
+			StatusCode: http.StatusBadRequest,
+			Error:      MsgMissingSlug,
+			Body:       struct{}{},
+		})
+		return
+	}
+	h.markNotificationsRead(c, slugNotificationID)
+}
+
+// MarkAllNotificationsReadGin marks read all notifications of the user.
+func (h *NotificationsHandlers) MarkAllNotificationsReadGin(c *gin.Context) {
+	h.markNotificationsRead(c, "")
+}
+
+func (h *NotificationsHandlers) markNotificationsRead(c *gin.Context, notificationID string) {
+	auth, err := httputil.GetAuthFromCtx(c.Request)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, api.Response[any]{
+			StatusCode: http.StatusUnauthorized,
+			Error:      api.MsgUnauthorized,
+			Body:       struct{}{},
+		})
+		return
+	}
+
+	serviceRequest := &application.MarkNotificationsReadCommand{
+		UserID:         auth.UserID,
+		NotificationID: notificationID,
+	}
+	serviceResponse, err := h.app.MarkNotificationsRead.Execute(
+		c.Request.Context(),
+		serviceRequest,
+	)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to mark notifications read")
+		if errors.Is(err, application.ErrNoNotification) {
+			c.JSON(http.StatusNotFound, api.Response[any]{
+				StatusCode: http.StatusNotFound,
+				Body:       struct{}{},
+				Error:      MsgNotificationNotFound,
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, api.Response[any]{
+			StatusCode: http.StatusInternalServerError,
+			Body:       struct{}{},
+			Error:      MsgFailedToMarkRead,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, api.Response[any]{
+		StatusCode: http.StatusOK,
+		Body:       &UnreadNotificationsJSONResponse{Unread: serviceResponse.Unread},
+		Error:      "",
+	})
+}
+
+func inboxNotificationToHTTP(n application.InboxNotification) InboxNotificationObject {
+	obj := InboxNotificationObject{
+		ID:         n.ID,
+		Category:   n.Category,
+		Type:       n.Type,
+		Title:      n.Title,
+		Body:       n.Body,
+		URL:        n.URL,
+		Data:       n.Data,
+		Deliveries: make([]DeviceResultObject, 0, len(n.Deliveries)),
+		CreatedAt:  n.CreatedAt,
+		ReadAt:     n.ReadAt,
+	}
+	for _, delivery := range n.Deliveries {
+		obj.Deliveries = append(obj.Deliveries, DeviceResultObject(delivery))
+	}
+	return obj
+}
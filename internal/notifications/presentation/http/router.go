@@ -9,6 +9,7 @@ import (
 func Router(
 	notificationHandlers *NotificationsHandlers,
 	authMw *httputil.AuthMiddleware,
+	serviceAuthMw *httputil.ServiceAuthMiddleware,
 ) *gin.Engine {
 	r := gin.New()
 
@@ -20,8 +21,25 @@ func Router(
 		authGroup.GET("/vapidPublicKey", notificationHandlers.GetVapidPublicKeyGin)
 		authGroup.POST("/pushSubscription", notificationHandlers.CreateSubscriptionGin)
 		authGroup.DELETE("/pushSubscription", notificationHandlers.DeleteSubscriptionGin)
+		authGroup.GET("/preferences", notificationHandlers.GetPreferencesGin)
+		authGroup.PUT("/preferences", notificationHandlers.UpdatePreferencesGin)
+		authGroup.GET("/notifications", notificationHandlers.ListNotificationsGin)
+		authGroup.GET("/notifications/unread", notificationHandlers.CountUnreadNotificationsGin)
+		authGroup.POST("/notifications/read", notificationHandlers.MarkAllNotificationsReadGin)
+		authGroup.POST("/notifications/:id/read", notificationHandlers.MarkNotificationReadGin)
+		authGroup.GET("/telegram/link", notificationHandlers.GetTelegramLinkGin)
+		authGroup.POST("/telegram/link", notificationHandlers.CreateTelegramLinkGin)
+		authGroup.DELETE("/telegram/link", notificationHandlers.DeleteTelegramLinkGin)
+	}
+	// other services send notifications by signed requests
+	serviceGroup := r.Group("/")
+	serviceGroup.Use(serviceAuthMw.Middleware())
+	{
+		serviceGroup.POST("/send", notificationHandlers.SendNotificationGin)
+		serviceGroup.POST("/schedule", notificationHandlers.ScheduleNotificationGin)
+		serviceGroup.PUT("/schedule/:key", notificationHandlers.RescheduleNotificationGin)
+		serviceGroup.DELETE("/schedule/:key", notificationHandlers.CancelScheduledNotificationGin)
 	}
-	r.POST("/send", notificationHandlers.SendNotificationGin)
 
 	return r
 }
@@ -0,0 +1,100 @@
+package http_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	notificationsHTTP "github.com/FSO-VK/final-project-vk-backend/internal/notifications/presentation/http"
+	"github.com/FSO-VK/final-project-vk-backend/internal/utils/httputil"
+	auth "github.com/FSO-VK/final-project-vk-backend/pkg/auth/client"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	caregiverID = "6f1c2a1e-8b0e-4a43-9d42-1f0b7c1f6a01"
+	ownerID     = "0d8e5f8c-3b7a-4f57-a0d4-5a2b9c8e7d02"
+	profileID   = "9a4b3c2d-1e0f-4a5b-8c7d-6e5f4a3b2c03"
+)
+
+// managingCaregiver is an auth checker of the session of the caregiver
+// who manages data of the owner and its profiles.
+type managingCaregiver struct{}
+
+func (managingCaregiver) CheckAuth(_ *auth.Request) (*auth.Response, error) {
+	return &auth.Response{UserID: caregiverID, IsAuthorized: true}, nil
+}
+
+func (managingCaregiver) CheckAccess(_ *auth.AccessRequest) (*auth.AccessResponse, error) {
+	return &auth.AccessResponse{
+		UserID:  ownerID,
+		ActorID: caregiverID,
+		Role:    httputil.CareRoleManage,
+	}, nil
+}
+
+func (managingCaregiver) CheckProfile(_ *auth.ProfileRequest) (*auth.ProfileResponse, error) {
+	return &auth.ProfileResponse{
+		ProfileID: profileID,
+		AccountID: ownerID,
+		ActorID:   caregiverID,
+		Role:      httputil.CareRoleManage,
+		IsAllowed: true,
+	}, nil
+}
+
+func TestRouter_RejectsDelegation(t *testing.T) {
+	t.Parallel()
+	gin.SetMode(gin.TestMode)
+	router := notificationsHTTP.Router(
+		notificationsHTTP.NewHandlers(nil, nil),
+		httputil.NewAuthMiddleware(managingCaregiver{}),
+		httputil.NewServiceAuthMiddleware(nil, nil, nil),
+	)
+
+	tests := []struct {
+		name string // description of this test case
+		// Named input parameters for target function.
+		session    bool
+		headers    map[string]string
+		wantStatus int
+	}{
+		{
+			name:       "Should forbid linking telegram on behalf of the owner",
+			session:    true,
+			headers:    map[string]string{httputil.OnBehalfOfHeader: ownerID},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "Should forbid linking telegram for the profile of the owner",
+			session:    true,
+			headers:    map[string]string{httputil.ProfileHeader: profileID},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "Should require session",
+			session:    false,
+			headers:    map[string]string{httputil.OnBehalfOfHeader: ownerID},
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			req := httptest.NewRequest(http.MethodPost, "/telegram/link", nil)
+			if tt.session {
+				req.AddCookie(&http.Cookie{Name: httputil.SessionCookieKey, Value: "session"})
+			}
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("POST /telegram/link status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+}
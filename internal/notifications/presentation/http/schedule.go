@@ -0,0 +1,197 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/FSO-VK/final-project-vk-backend/internal/notifications/application"
+	"github.com/FSO-VK/final-project-vk-backend/internal/utils/httputil"
+	"github.com/FSO-VK/final-project-vk-backend/pkg/api"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// ScheduleNotificationJSONRequest is a request for ScheduleNotification,
+// deliverAt is RFC 3339 time to send the notification.
+type ScheduleNotificationJSONRequest struct {
+	// embedded struct
+	SendNotificationJSONRequest `json:",inline"`
+
+	Key       string `json:"key"`
+	DeliverAt string `json:"deliverAt"`
+}
+
+// ScheduleNotificationJSONResponse is a response for ScheduleNotification.
+type ScheduleNotificationJSONResponse struct {
+	Key       string    `json:"key"`
+	DeliverAt time.Time `json:"deliverAt"`
+	Replaced  bool      `json:"replaced"`
+}
+
+// ScheduleNotificationGin schedules notification, notification with the same key is replaced.
+func (h *NotificationsHandlers) ScheduleNotificationGin(c *gin.Context) {
+	var reqJSON ScheduleNotificationJSONRequest
+	if err := c.ShouldBindJSON(&reqJSON); err != nil {
+		h.logger.WithError(err).Error("Failed to bind request body")
+		c.JSON(http.StatusBadRequest, api.Response[any]{
+			StatusCode: http.StatusBadRequest,
+			Body:       struct{}{},
+			Error:      api.MsgBadBody,
+		})
+		return
+	}
+
+	caller, ok := h.caller(c)
+	if !ok {
+		return
+	}
+
+	command := &application.ScheduleNotificationCommand{
+		Caller:       caller,
+		Key:          reqJSON.Key,
+		DeliverAt:    reqJSON.DeliverAt,
+		Notification: *sendNotificationFromHTTP(reqJSON.SendNotificationJSONRequest),
+	}
+	serviceResponse, err := h.app.ScheduleNotification.Execute(c.Request.Context(), command)
+	fields := auditFieldsOf(reqJSON.SendNotificationJSONRequest)
+	fields["key"] = reqJSON.Key
+	fields["deliver_at"] = reqJSON.DeliverAt
+	h.audit(c, "schedule", fields, err)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to schedule notification")
+		status, body := h.handleScheduleServiceError(err)
+		c.JSON(status, body)
+		return
+	}
+
+	c.JSON(http.StatusOK, api.Response[any]{
+		StatusCode: http.StatusOK,
+		Body: &ScheduleNotificationJSONResponse{
+			Key:       serviceResponse.Key,
+			DeliverAt: serviceResponse.DeliverAt,
+			Replaced:  serviceResponse.Replaced,
+		},
+		Error: "",
+	})
+}
+
+// RescheduleNotificationJSONRequest is a request for RescheduleNotification.
+type RescheduleNotificationJSONRequest struct {
+	DeliverAt string `json:"deliverAt"`
+}
+
+// RescheduleNotificationJSONResponse is a response for RescheduleNotification.
+type RescheduleNotificationJSONResponse struct {
+	Key       string    `json:"key"`
+	DeliverAt time.Time `json:"deliverAt"`
+}
+
+// RescheduleNotificationGin moves notification scheduled by the key to another time.
+func (h *NotificationsHandlers) RescheduleNotificationGin(c *gin.Context) {
+	var reqJSON RescheduleNotificationJSONRequest
+	if err := c.ShouldBindJSON(&reqJSON); err != nil {
+		h.logger.WithError(err).Error("Failed to bind request body")
+		c.JSON(http.StatusBadRequest, api.Response[any]{
+			StatusCode: http.StatusBadRequest,
+			Body:       struct{}{},
+			Error:      api.MsgBadBody,
+		})
+		return
+	}
+
+	caller, ok := h.caller(c)
+	if !ok {
+		return
+	}
+
+	command := &application.RescheduleNotificationCommand{
+		Caller:    caller,
+		Key:       c.Param(SlugKey),
+		DeliverAt: reqJSON.DeliverAt,
+	}
+	serviceResponse, err := h.app.RescheduleNotification.Execute(c.Request.Context(), command)
+	fields := logrus.Fields{"key": command.Key, "deliver_at": command.DeliverAt}
+	h.audit(c, "reschedule", fields, err)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to reschedule notification")
+		status, body := h.handleScheduleServiceError(err)
+		c.JSON(status, body)
+		return
+	}
+
+	c.JSON(http.StatusOK, api.Response[any]{
+		StatusCode: http.StatusOK,
+		Body: &RescheduleNotificationJSONResponse{
+			Key:       serviceResponse.Key,
+			DeliverAt: serviceResponse.DeliverAt,
+		},
+		Error: "",
+	})
+}
+
+// CancelScheduledNotificationGin cancels notification scheduled by the key.
+func (h *NotificationsHandlers) CancelScheduledNotificationGin(c *gin.Context) {
+	caller, ok := h.caller(c)
+	if !ok {
+		return
+	}
+
+	command := &application.CancelScheduledNotificationCommand{
+		Caller: caller,
+		Key:    c.Param(SlugKey),
+	}
+	_, err := h.app.CancelScheduledNotification.Execute(c.Request.Context(), command)
+	h.audit(c, "cancel", logrus.Fields{"key": command.Key}, err)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to cancel scheduled notification")
+		status, body := h.handleScheduleServiceError(err)
+		c.JSON(status, body)
+		return
+	}
+
+	c.JSON(http.StatusOK, api.Response[any]{
+		StatusCode: http.StatusOK,
+		Body:       struct{}{},
+		Error:      "",
+	})
+}
+
+// caller returns the service of the signed request, keys of scheduled
+// notifications are namespaced by it. Response is written if it is unknown.
+func (h *NotificationsHandlers) caller(c *gin.Context) (string, bool) {
+	caller, err := httputil.GetCallerFromCtx(c.Request)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get caller of the request")
+		c.JSON(http.StatusUnauthorized, api.Response[any]{
+			StatusCode: http.StatusUnauthorized,
+			Body:       struct{}{},
+			Error:      api.MsgUnauthorized,
+		})
+		return "", false
+	}
+	return caller, true
+}
+
+func (h *NotificationsHandlers) handleScheduleServiceError(err error) (int, *api.Response[any]) {
+	switch {
+	case errors.Is(err, application.ErrInvalidSchedule):
+		return http.StatusBadRequest, &api.Response[any]{
+			StatusCode: http.StatusBadRequest,
+			Body:       struct{}{},
+			Error:      MsgInvalidSchedule,
+		}
+	case errors.Is(err, application.ErrNoScheduledNotification):
+		return http.StatusNotFound, &api.Response[any]{
+			StatusCode: http.StatusNotFound,
+			Body:       struct{}{},
+			Error:      MsgScheduledNotificationNotFound,
+		}
+	default:
+		return http.StatusInternalServerError, &api.Response[any]{
+			StatusCode: http.StatusInternalServerError,
+			Body:       struct{}{},
+			Error:      MsgFailedToScheduleNotification,
+		}
+	}
+}
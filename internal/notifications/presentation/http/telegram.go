@@ -0,0 +1,147 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/FSO-VK/final-project-vk-backend/internal/notifications/application"
+	"github.com/FSO-VK/final-project-vk-backend/internal/utils/httputil"
+	"github.com/FSO-VK/final-project-vk-backend/pkg/api"
+	"github.com/gin-gonic/gin"
+)
+
+// CreateTelegramLinkJSONResponse is a one-time deep link to the bot.
+type CreateTelegramLinkJSONResponse struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// CreateTelegramLinkGin creates deep link, the chat which starts the bot
+// by the link receives notifications of the user.
+func (h *NotificationsHandlers) CreateTelegramLinkGin(c *gin.Context) {
+	auth, err := httputil.GetAuthFromCtx(c.Request)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, api.Response[any]{
+			StatusCode: http.StatusUnauthorized,
+			Error:      api.MsgUnauthorized,
+			Body:       struct{}{},
+		})
+		return
+	}
+
+	serviceRequest := &application.CreateTelegramLinkCommand{
+		UserID: auth.UserID,
+	}
+	serviceResponse, err := h.app.CreateTelegramLink.Execute(c.Request.Context(), serviceRequest)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to create telegram link")
+		if errors.Is(err, application.ErrTelegramDisabled) {
+			c.JSON(http.StatusServiceUnavailable, api.Response[any]{
+				StatusCode: http.StatusServiceUnavailable,
+				Body:       struct{}{},
+				Error:      MsgTelegramDisabled,
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, api.Response[any]{
+			StatusCode: http.StatusInternalServerError,
+			Body:       struct{}{},
+			Error:      MsgFailedToLinkTelegram,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, api.Response[any]{
+		StatusCode: http.StatusOK,
+		Body: &CreateTelegramLinkJSONResponse{
+			URL:       serviceResponse.URL,
+			ExpiresAt: serviceResponse.ExpiresAt,
+		},
+		Error: "",
+	})
+}
+
+// GetTelegramLinkJSONResponse is a status of telegram link,
+// linkedAt is null if no chat is linked.
+type GetTelegramLinkJSONResponse struct {
+	Linked   bool       `json:"linked"`
+	LinkedAt *time.Time `json:"linkedAt"`
+}
+
+// GetTelegramLinkGin returns whether a telegram chat is linked to the user.
+func (h *NotificationsHandlers) GetTelegramLinkGin(c *gin.Context) {
+	auth, err := httputil.GetAuthFromCtx(c.Request)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, api.Response[any]{
+			StatusCode: http.StatusUnauthorized,
+			Error:      api.MsgUnauthorized,
+			Body:       struct{}{},
+		})
+		return
+	}
+
+	serviceRequest := &application.GetTelegramLinkCommand{
+		UserID: auth.UserID,
+	}
+	serviceResponse, err := h.app.GetTelegramLink.Execute(c.Request.Context(), serviceRequest)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get telegram link")
+		c.JSON(http.StatusInternalServerError, api.Response[any]{
+			StatusCode: http.StatusInternalServerError,
+			Body:       struct{}{},
+			Error:      MsgFailedToGetTelegramLink,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, api.Response[any]{
+		StatusCode: http.StatusOK,
+		Body: &GetTelegramLinkJSONResponse{
+			Linked:   serviceResponse.Linked,
+			LinkedAt: serviceResponse.LinkedAt,
+		},
+		Error: "",
+	})
+}
+
+// DeleteTelegramLinkGin unlinks telegram chat of the user.
+func (h *NotificationsHandlers) DeleteTelegramLinkGin(c *gin.Context) {
+	auth, err := httputil.GetAuthFromCtx(c.Request)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, api.Response[any]{
+			StatusCode: http.StatusUnauthorized,
+			Error:      api.MsgUnauthorized,
+			Body:       struct{}{},
+		})
+		return
+	}
+
+	serviceRequest := &application.DeleteTelegramLinkCommand{
+		UserID: auth.UserID,
+	}
+	_, err = h.app.DeleteTelegramLink.Execute(c.Request.Context(), serviceRequest)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to delete telegram link")
+		if errors.Is(err, application.ErrNoTelegramLink) {
+			c.JSON(http.StatusNotFound, api.Response[any]{
+				StatusCode: http.StatusNotFound,
+				Body:       struct{}{},
+				Error:      MsgTelegramNotLinked,
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, api.Response[any]{
+			StatusCode: http.StatusInternalServerError,
+			Body:       struct{}{},
+			Error:      MsgFailedToUnlinkTelegram,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, api.Response[any]{
+		StatusCode: http.StatusOK,
+		Body:       struct{}{},
+		Error:      "",
+	})
+}
@@ -32,6 +32,8 @@ type AddPlanService struct {
 	validator          validator.Validator
 	medicationProvider medication.MedicationService
 	creationShift      time.Duration
+	// defaultLocation is a time zone of plans created without one.
+	defaultLocation *time.Location
 }
 
 // NewAddPlanService returns a new AddPlanService.
@@ -41,6 +43,7 @@ func NewAddPlanService(
 	valid validator.Validator,
 	medicationProvider medication.MedicationService,
 	creationShift time.Duration,
+	defaultLocation *time.Location,
 ) *AddPlanService {
 	return &AddPlanService{
 		planningRepo:       planningRepo,
@@ -48,21 +51,50 @@ func NewAddPlanService(
 		validator:          valid,
 		medicationProvider: medicationProvider,
 		creationShift:      creationShift,
+		defaultLocation:    defaultLocation,
 	}
 }
 
-// AddPlanCommand is a request to add a plan.
-type AddPlanCommand struct {
-	MedicationID   string   `validate:"required,uuid"`
-	UserID         string   `validate:"required,uuid"`
-	AmountValue    float64  `validate:"required,gte=0"`
+// AsNeededLimits are limits of intakes of as-needed plan.
+type AsNeededLimits struct {
+	MinIntervalMinutes int `validate:"gte=0"`
+	MaxDoses           int `validate:"gte=0"`
+	WindowHours        int `validate:"gte=0"`
+}
+
+// PlanPhase is a period of the course with its own dosage and schedule.
+type PlanPhase struct {
+	AmountValue    float64  `validate:"required,gt=0"`
 	AmountUnit     string   `validate:"required"`
-	Condition      string   `validate:"omitempty,max=300"`
 	StartDate      string   `validate:"required,datetime=2006-01-02T15:04:05Z07:00"`
 	EndDate        string   `validate:"required,datetime=2006-01-02T15:04:05Z07:00"`
 	RecurrenceRule []string `validate:"required"`
 }
 
+// AddPlanCommand is a request to add a plan.
+// As-needed plan has no recurrence rules, its intakes are restricted by AsNeeded limits.
+// Phased plan (taper, titration) takes dosage, range and rules from its phases.
+// TimeZone is an IANA time zone of the user, recurrence rules are evaluated in it.
+// Schedule is a human-friendly alternative to RecurrenceRule of scheduled plan.
+// ExDates are skipped intakes and RDates are one-off intakes of scheduled plan.
+type AddPlanCommand struct {
+	MedicationID   string          `validate:"required,uuid"`
+	UserID         string          `validate:"required,uuid"`
+	AmountValue    float64         `validate:"required_without=Phases,gte=0"`
+	AmountUnit     string          `validate:"required_without=Phases"`
+	Condition      string          `validate:"omitempty,max=300"`
+	StartDate      string          `validate:"required_without=Phases,omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	EndDate        string          `validate:"required_without=Phases,omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	Kind           string          `validate:"omitempty,oneof=scheduled as_needed"`
+	RecurrenceRule []string        `validate:"omitempty"`
+	AsNeeded       *AsNeededLimits `validate:"required_if=Kind as_needed"`
+	Phases         []PlanPhase     `validate:"omitempty,max=20,dive"`
+	TimeZone       string          `validate:"omitempty,timezone"`
+	Schedule       *SchedulePreset `validate:"omitempty"`
+	ExDates        []string        `validate:"omitempty,max=100,dive,datetime=2006-01-02T15:04:05Z07:00"`
+	RDates         []string        `validate:"omitempty,max=100,dive,datetime=2006-01-02T15:04:05Z07:00"`
+}
+
 // AddPlanResponse is a response to add a plan.
 type AddPlanResponse struct {
 	ID             string
@@ -74,7 +106,15 @@ type AddPlanResponse struct {
 	Status         string
 	StartDate      string
 	EndDate        string
+	Kind           string
+	TimeZone       string
 	RecurrenceRule []string
+	// Schedule is nil if rules of the plan are not produced by a preset.
+	Schedule *SchedulePreset
+	// AsNeeded is nil for scheduled plan.
+	AsNeeded *AsNeededLimits
+	// Phases are empty for plan with a single dosage.
+	Phases []PlanPhase
 }
 
 // Execute executes the AddPlan command.
@@ -98,7 +138,14 @@ func (s *AddPlanService) Execute(
 	if err != nil {
 		return nil, fmt.Errorf("failed to get medication - plan need to have medication: %w", err)
 	}
-	newPlan, err := createPlan(req, parsedUser, parsedMedicationID)
+	loc := s.defaultLocation
+	if req.TimeZone != "" {
+		loc, err = time.LoadLocation(req.TimeZone)
+		if err != nil {
+			return nil, ErrValidationFail
+		}
+	}
+	newPlan, err := createPlan(req, parsedUser, parsedMedicationID, loc)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create plan: %w", err)
 	}
@@ -118,7 +165,12 @@ func (s *AddPlanService) Execute(
 		Status:         newPlan.Status().String(),
 		StartDate:      newPlan.CourseStart().Format(time.RFC3339),
 		EndDate:        newPlan.CourseEnd().Format(time.RFC3339),
+		Kind:           newPlan.Kind().String(),
+		TimeZone:       newPlan.Location().String(),
 		RecurrenceRule: newPlan.ScheduleIcal(),
+		Schedule:       schedulePresetOf(newPlan),
+		AsNeeded:       asNeededLimitsOf(newPlan),
+		Phases:         phasesOf(newPlan),
 	}
 
 	err = s.generatorProvider.GenerateRecordForPlan(
@@ -135,7 +187,52 @@ func (s *AddPlanService) Execute(
 func createPlan(req *AddPlanCommand,
 	userID uuid.UUID,
 	medicationID uuid.UUID,
+	loc *time.Location,
+) (*plan.Plan, error) {
+	newPlan, err := buildPlan(req, userID, medicationID, loc)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.ExDates) == 0 && len(req.RDates) == 0 {
+		return newPlan, nil
+	}
+	exDates, err := parseDates(req.ExDates)
+	if err != nil {
+		return nil, err
+	}
+	rDates, err := parseDates(req.RDates)
+	if err != nil {
+		return nil, err
+	}
+	err = newPlan.AddExceptions(exDates, rDates)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidException, err)
+	}
+	return newPlan, nil
+}
+
+func buildPlan(req *AddPlanCommand,
+	userID uuid.UUID,
+	medicationID uuid.UUID,
+	loc *time.Location,
 ) (*plan.Plan, error) {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate uuid: %w", err)
+	}
+	if req.Schedule != nil && (len(req.RecurrenceRule) > 0 || len(req.Phases) > 0 ||
+		req.Kind == plan.KindAsNeeded.String()) {
+		return nil, &FieldsError{Fields: map[string]string{
+			presetField: "schedule can't be used with recurrence rules, phases or as-needed plan",
+		}}
+	}
+	if len(req.Phases) > 0 {
+		if req.Kind == plan.KindAsNeeded.String() {
+			return nil, fmt.Errorf("%w: as-needed plan can't have phases", ErrValidationFail)
+		}
+		return createPhasedPlan(req, id, userID, medicationID, loc)
+	}
+
 	dosage, err := plan.NewDosage(
 		req.AmountValue,
 		req.AmountUnit,
@@ -144,33 +241,45 @@ func createPlan(req *AddPlanCommand,
 		return nil, fmt.Errorf("invalid dosage: %w", err)
 	}
 
-	parsedStart, err := time.Parse(time.RFC3339, req.StartDate)
+	parsedStart, parsedEnd, err := parseCourseRange(req.StartDate, req.EndDate)
 	if err != nil {
-		return nil, fmt.Errorf("invalid course start: %w", err)
+		return nil, err
 	}
-	parsedEnd, err := time.Parse(time.RFC3339, req.EndDate)
-	if err != nil {
-		return nil, fmt.Errorf("invalid course end: %w", err)
-	}
-	if len(req.RecurrenceRule) == 0 {
-		return nil, ErrUnsupportedRrule
-	}
-	rules := make([]*rrule.RRule, 0, len(req.RecurrenceRule))
-
-	for _, ruleStr := range req.RecurrenceRule {
-		rule, err := rrule.StrToRRule(ruleStr)
+	if req.Kind == plan.KindAsNeeded.String() {
+		limits, err := plan.NewAsNeededLimits(
+			time.Duration(req.AsNeeded.MinIntervalMinutes)*time.Minute,
+			req.AsNeeded.MaxDoses,
+			time.Duration(req.AsNeeded.WindowHours)*time.Hour,
+		)
 		if err != nil {
-			return nil, ErrUnsupportedRrule
+			return nil, fmt.Errorf("invalid as-needed limits: %w", err)
 		}
-		rules = append(rules, rule)
+		return plan.NewAsNeededPlan(
+			id,
+			medicationID,
+			userID,
+			dosage,
+			parsedStart,
+			parsedEnd,
+			limits,
+			loc,
+			req.Condition,
+			time.Now(),
+			time.Now(),
+		)
+	}
+	var rules []*rrule.RRule
+	if req.Schedule != nil {
+		rules, err = compilePreset(req.Schedule, parsedStart, loc)
+	} else {
+		rules, err = parseRules(req.RecurrenceRule, parsedStart, loc)
 	}
-	schedule, err := plan.NewSchedule(parsedStart, parsedEnd, rules)
 	if err != nil {
-		return nil, fmt.Errorf("invalid schedule: %w", err)
+		return nil, err
 	}
-	id, err := uuid.NewV7()
+	schedule, err := plan.NewSchedule(parsedStart, parsedEnd, rules)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate uuid: %w", err)
+		return nil, fmt.Errorf("invalid schedule: %w", err)
 	}
 	newPlan, err := plan.NewPlan(
 		id,
@@ -178,9 +287,162 @@ func createPlan(req *AddPlanCommand,
 		userID,
 		dosage,
 		schedule,
+		loc,
 		req.Condition,
 		time.Now(),
 		time.Now(),
 	)
 	return newPlan, err
 }
+
+func createPhasedPlan(
+	req *AddPlanCommand,
+	id uuid.UUID,
+	userID uuid.UUID,
+	medicationID uuid.UUID,
+	loc *time.Location,
+) (*plan.Plan, error) {
+	phases := make([]plan.Phase, 0, len(req.Phases))
+	for i, ph := range req.Phases {
+		dosage, err := plan.NewDosage(ph.AmountValue, ph.AmountUnit)
+		if err != nil {
+			return nil, fmt.Errorf("invalid dosage of phase %d: %w", i, err)
+		}
+		start, end, err := parseCourseRange(ph.StartDate, ph.EndDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid range of phase %d: %w", i, err)
+		}
+		rules, err := parseRules(ph.RecurrenceRule, start, loc)
+		var fieldsErr *FieldsError
+		if errors.As(err, &fieldsErr) {
+			phaseErr := fieldsErr.withPrefix(fmt.Sprintf("phases[%d]", i))
+			return nil, fmt.Errorf("%w: %w", ErrUnsupportedRrule, phaseErr)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid rules of phase %d: %w", i, err)
+		}
+		schedule, err := plan.NewSchedule(start, end, rules)
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule of phase %d: %w", i, err)
+		}
+		phases = append(phases, plan.NewPhase(dosage, schedule))
+	}
+	return plan.NewPhasedPlan(
+		id,
+		medicationID,
+		userID,
+		phases,
+		loc,
+		req.Condition,
+		time.Now(),
+		time.Now(),
+	)
+}
+
+func parseCourseRange(start, end string) (time.Time, time.Time, error) {
+	parsedStart, err := time.Parse(time.RFC3339, start)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid course start: %w", err)
+	}
+	parsedEnd, err := time.Parse(time.RFC3339, end)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid course end: %w", err)
+	}
+	return parsedStart, parsedEnd, nil
+}
+
+func parseDates(dates []string) ([]time.Time, error) {
+	parsed := make([]time.Time, 0, len(dates))
+	for _, d := range dates {
+		t, err := time.Parse(time.RFC3339, d)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrValidationFail, err)
+		}
+		parsed = append(parsed, t)
+	}
+	return parsed, nil
+}
+
+// parseRules parses recurrence rules anchored to the time zone,
+// so wall-clock time of intakes is kept across DST changes.
+// Invalid rules are reported by *FieldsError wrapped into ErrUnsupportedRrule.
+func parseRules(
+	recurrenceRule []string,
+	start time.Time,
+	loc *time.Location,
+) ([]*rrule.RRule, error) {
+	if len(recurrenceRule) == 0 {
+		return nil, fmt.Errorf(
+			"%w: %w",
+			ErrUnsupportedRrule,
+			&FieldsError{Fields: map[string]string{
+				"recurrenceRule": "recurrence rule or schedule is required",
+			}},
+		)
+	}
+	rules := make([]*rrule.RRule, 0, len(recurrenceRule))
+	for i, ruleStr := range recurrenceRule {
+		opts, err := rrule.StrToROptionInLocation(ruleStr, loc)
+		if err == nil {
+			opts.Dtstart = ruleStart(opts.Dtstart, start, loc)
+		}
+		var rule *rrule.RRule
+		if err == nil {
+			rule, err = rrule.NewRRule(*opts)
+		}
+		if err != nil {
+			return nil, fmt.Errorf(
+				"%w: %w",
+				ErrUnsupportedRrule,
+				&FieldsError{Fields: map[string]string{
+					fmt.Sprintf("recurrenceRule[%d]", i): err.Error(),
+				}},
+			)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// ruleStart returns DTSTART of the rule in the plan time zone. Rule without
+// DTSTART starts at midnight of the course start, otherwise rrule would
+// start it now in UTC and BYHOUR would be in UTC with seconds of now.
+func ruleStart(dtstart time.Time, courseStart time.Time, loc *time.Location) time.Time {
+	if !dtstart.IsZero() {
+		return dtstart.In(loc)
+	}
+	y, m, d := courseStart.In(loc).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, loc)
+}
+
+// asNeededLimitsOf returns limits of as-needed plan or nil for scheduled plan.
+func asNeededLimitsOf(p *plan.Plan) *AsNeededLimits {
+	if !p.IsAsNeeded() {
+		return nil
+	}
+	minInterval, maxDoses, window := p.AsNeededLimits()
+	return &AsNeededLimits{
+		MinIntervalMinutes: int(minInterval / time.Minute),
+		MaxDoses:           maxDoses,
+		WindowHours:        int(window / time.Hour),
+	}
+}
+
+// phasesOf returns phases of the plan in command format.
+func phasesOf(p *plan.Plan) []PlanPhase {
+	phases := make([]PlanPhase, 0)
+	for _, ph := range p.Phases() {
+		phases = append(phases, phaseFromInfo(ph))
+	}
+	return phases
+}
+
+func phaseFromInfo(ph plan.PhaseInfo) PlanPhase {
+	return PlanPhase{
+		AmountValue:    ph.DosageValue,
+		AmountUnit:     ph.DosageUnit,
+		StartDate:      ph.Start.Format(time.RFC3339),
+		EndDate:        ph.End.Format(time.RFC3339),
+		RecurrenceRule: ph.RecurrenceRule,
+	}
+}
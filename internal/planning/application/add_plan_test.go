@@ -0,0 +1,52 @@
+package application
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRules_DefaultStart(t *testing.T) {
+	t.Parallel()
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	if err != nil {
+		t.Fatalf("arrange failed: %v", err)
+	}
+	courseStart := time.Date(2025, time.March, 10, 15, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string // description of this test case
+		// Named input parameters for target function.
+		rule      string
+		loc       *time.Location
+		wantFirst time.Time
+	}{
+		{
+			name:      "Should evaluate BYHOUR in plan time zone from course start date",
+			rule:      "RRULE:FREQ=DAILY;BYHOUR=8;BYMINUTE=0",
+			loc:       moscow,
+			wantFirst: time.Date(2025, time.March, 11, 8, 0, 0, 0, moscow),
+		},
+		{
+			name:      "Should keep DTSTART of the rule",
+			rule:      "DTSTART;TZID=Europe/Moscow:20250312T090000\nRRULE:FREQ=DAILY",
+			loc:       moscow,
+			wantFirst: time.Date(2025, time.March, 12, 9, 0, 0, 0, moscow),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			rules, err := parseRules([]string{tt.rule}, courseStart, tt.loc)
+			if err != nil {
+				t.Fatalf("parseRules() error = %v", err)
+			}
+			got := rules[0].After(courseStart, true)
+			if !got.Equal(tt.wantFirst) {
+				t.Errorf("first occurrence = %v, want %v", got, tt.wantFirst)
+			}
+			if got.Second() != 0 || got.Location().String() != tt.loc.String() {
+				t.Errorf("first occurrence = %v, want whole minutes in %v", got, tt.loc)
+			}
+		})
+	}
+}
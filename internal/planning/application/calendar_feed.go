@@ -0,0 +1,100 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/FSO-VK/final-project-vk-backend/internal/planning/application/medication"
+	"github.com/FSO-VK/final-project-vk-backend/internal/planning/domain/calendar"
+	"github.com/FSO-VK/final-project-vk-backend/internal/planning/domain/plan"
+	"github.com/FSO-VK/final-project-vk-backend/internal/utils/validator"
+	"github.com/FSO-VK/final-project-vk-backend/pkg/ical"
+)
+
+// ErrNoCalendarFeed is an error when calendar feed token is unknown or revoked.
+var ErrNoCalendarFeed = errors.New("no calendar feed")
+
+// CalendarFeed is an interface for getting a subscribable calendar of user intakes.
+type CalendarFeed interface {
+	Execute(
+		ctx context.Context,
+		cmd *CalendarFeedCommand,
+	) (*CalendarFeedResponse, error)
+}
+
+// CalendarFeedService is a service for getting a subscribable calendar of user intakes.
+type CalendarFeedService struct {
+	feedRepo           calendar.Repository
+	planningRepo       plan.Repository
+	validator          validator.Validator
+	medicationProvider medication.MedicationService
+}
+
+// NewCalendarFeedService returns a new CalendarFeedService.
+func NewCalendarFeedService(
+	feedRepo calendar.Repository,
+	planningRepo plan.Repository,
+	valid validator.Validator,
+	medicationProvider medication.MedicationService,
+) *CalendarFeedService {
+	return &CalendarFeedService{
+		feedRepo:           feedRepo,
+		planningRepo:       planningRepo,
+		validator:          valid,
+		medicationProvider: medicationProvider,
+	}
+}
+
+// CalendarFeedCommand is a request to get a calendar by secret feed token.
+type CalendarFeedCommand struct {
+	Token string `validate:"required,max=64"`
+}
+
+// CalendarFeedResponse is a response to get a calendar by secret feed token.
+type CalendarFeedResponse struct {
+	// Calendar is an iCalendar RFC 5545 object.
+	Calendar string
+}
+
+// Execute renders intakes of all active plans of the feed owner.
+func (s *CalendarFeedService) Execute(
+	ctx context.Context,
+	req *CalendarFeedCommand,
+) (*CalendarFeedResponse, error) {
+	valErr := s.validator.ValidateStruct(req)
+	if valErr != nil {
+		return nil, ErrValidationFail
+	}
+
+	feed, err := s.feedRepo.GetByToken(ctx, req.Token)
+	if err != nil {
+		return nil, ErrNoCalendarFeed
+	}
+	userPlans, err := s.planningRepo.UserPlans(ctx, feed.UserID())
+	if err != nil {
+		return nil, ErrNoPlan
+	}
+
+	now := time.Now()
+	events := make([]ical.Event, 0, len(userPlans))
+	for _, p := range userPlans {
+		if !p.IsActive() {
+			continue
+		}
+		planEvents, err := intakeEvents(s.medicationProvider, p, now)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, planEvents...)
+	}
+	cal := &ical.Calendar{
+		ProdID:   calendarProdID,
+		Name:     calendarName,
+		TimeZone: "",
+		Events:   events,
+	}
+	return &CalendarFeedResponse{
+		Calendar: cal.String(),
+	}, nil
+}
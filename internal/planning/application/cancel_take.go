@@ -4,6 +4,7 @@ package application
 import (
 	"context"
 
+	"github.com/FSO-VK/final-project-vk-backend/internal/planning/application/medication"
 	"github.com/FSO-VK/final-project-vk-backend/internal/planning/domain/plan"
 	"github.com/FSO-VK/final-project-vk-backend/internal/planning/domain/record"
 	"github.com/FSO-VK/final-project-vk-backend/internal/utils/validator"
@@ -20,9 +21,10 @@ type CancelMedicationTake interface {
 
 // CancelMedicationTakeService is a service for making a medication not taken.
 type CancelMedicationTakeService struct {
-	recordRepo   record.Repository
-	planningRepo plan.Repository
-	validator    validator.Validator
+	recordRepo         record.Repository
+	planningRepo       plan.Repository
+	validator          validator.Validator
+	medicationProvider medication.MedicationService
 }
 
 // NewCancelMedicationTakeService returns a new CancelMedicationTakeService.
@@ -30,11 +32,13 @@ func NewCancelMedicationTakeService(
 	recordRepo record.Repository,
 	planningRepo plan.Repository,
 	valid validator.Validator,
+	medicationProvider medication.MedicationService,
 ) *CancelMedicationTakeService {
 	return &CancelMedicationTakeService{
-		recordRepo:   recordRepo,
-		planningRepo: planningRepo,
-		validator:    valid,
+		recordRepo:         recordRepo,
+		planningRepo:       planningRepo,
+		validator:          valid,
+		medicationProvider: medicationProvider,
 	}
 }
 
@@ -42,6 +46,8 @@ func NewCancelMedicationTakeService(
 type CancelMedicationTakeCommand struct {
 	RecordID string `validate:"required,uuid"`
 	UserID   string `validate:"required,uuid"`
+	// ActorID is an optional user who cancels the take, owner of the plan if empty.
+	ActorID string `validate:"omitempty,uuid"`
 }
 
 // CancelMedicationTakeResponse is a response to cancel medication take.
@@ -67,6 +73,11 @@ func (s *CancelMedicationTakeService) Execute(
 		return nil, ErrValidationFail
 	}
 
+	canceledBy, err := parseActor(req.ActorID, parsedUser)
+	if err != nil {
+		return nil, ErrValidationFail
+	}
+
 	requestedRecord, err := s.recordRepo.GetByID(ctx, parsedRecordID)
 	if err != nil {
 		return nil, ErrNoIntakeRecord
@@ -81,6 +92,12 @@ func (s *CancelMedicationTakeService) Execute(
 		return nil, ErrPlanNotBelongToUser
 	}
 
+	// stock is restored before the record loses its take identifier
+	err = restoreDose(ctx, s.medicationProvider, requestedPlan, requestedRecord, canceledBy)
+	if err != nil {
+		return nil, err
+	}
+
 	requestedRecord.Cancel()
 
 	err = s.recordRepo.UpdateByID(ctx, requestedRecord)
@@ -0,0 +1,21 @@
+// Package care is a package for interface of caregivers provider.
+package care
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// CareService provides caregivers linked to users.
+type CareService interface {
+	// Caregivers returns caregivers of the user or of the account of the profile.
+	Caregivers(ctx context.Context, userID uuid.UUID) (*Caregivers, error)
+}
+
+// Caregivers contains name of the user to show to caregivers and their ids,
+// name is empty if the user is an account without a profile.
+type Caregivers struct {
+	Name string
+	IDs  []uuid.UUID
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/FSO-VK/final-project-vk-backend/internal/planning/application/medication"
 	"github.com/FSO-VK/final-project-vk-backend/internal/planning/domain/plan"
 	"github.com/FSO-VK/final-project-vk-backend/internal/planning/domain/record"
 	"github.com/FSO-VK/final-project-vk-backend/internal/utils/validator"
@@ -22,9 +23,10 @@ type ChangeTakeMedication interface {
 
 // ChangeTakeMedicationService is a service for making a medication taken in any time of the day.
 type ChangeTakeMedicationService struct {
-	recordRepo   record.Repository
-	planningRepo plan.Repository
-	validator    validator.Validator
+	recordRepo         record.Repository
+	planningRepo       plan.Repository
+	validator          validator.Validator
+	medicationProvider medication.MedicationService
 }
 
 // NewChangeTakeMedicationService returns a new ChangeTakeMedicationService.
@@ -32,11 +34,13 @@ func NewChangeTakeMedicationService(
 	recordRepo record.Repository,
 	planningRepo plan.Repository,
 	valid validator.Validator,
+	medicationProvider medication.MedicationService,
 ) *ChangeTakeMedicationService {
 	return &ChangeTakeMedicationService{
-		recordRepo:   recordRepo,
-		planningRepo: planningRepo,
-		validator:    valid,
+		recordRepo:         recordRepo,
+		planningRepo:       planningRepo,
+		validator:          valid,
+		medicationProvider: medicationProvider,
 	}
 }
 
@@ -45,10 +49,15 @@ type ChangeTakeMedicationCommand struct {
 	RecordID string `validate:"required,uuid"`
 	UserID   string `validate:"required,uuid"`
 	TakenAt  string `validate:"required,datetime=2006-01-02T15:04:05Z07:00"`
+	// ActorID is an optional user who changes the take, owner of the plan if empty.
+	ActorID string `validate:"omitempty,uuid"`
 }
 
 // ChangeTakeMedicationResponse is a response to change medication take time.
-type ChangeTakeMedicationResponse struct{}
+type ChangeTakeMedicationResponse struct {
+	// Warnings tell that the take is logged but stock is not changed.
+	Warnings []string
+}
 
 // Execute executes the ChangeTakeMedication command.
 func (s *ChangeTakeMedicationService) Execute(
@@ -70,6 +79,11 @@ func (s *ChangeTakeMedicationService) Execute(
 		return nil, ErrValidationFail
 	}
 
+	takenBy, err := parseActor(req.ActorID, parsedUser)
+	if err != nil {
+		return nil, ErrValidationFail
+	}
+
 	parsedTakenAt, err := time.Parse(time.RFC3339, req.TakenAt)
 	if err != nil {
 		return nil, fmt.Errorf("invalid taking time: %w", err)
@@ -88,12 +102,26 @@ func (s *ChangeTakeMedicationService) Execute(
 		return nil, ErrPlanNotBelongToUser
 	}
 
-	requestedRecord.MarkTaken(parsedTakenAt)
+	amount := requestedRecord.TakenAmount()
+	if amount == 0 {
+		amount = plannedAmount(requestedPlan, requestedRecord)
+	}
 
-	err = s.recordRepo.UpdateByID(ctx, requestedRecord)
+	warnings, err := takeRecord(
+		ctx,
+		s.recordRepo,
+		s.medicationProvider,
+		requestedPlan,
+		requestedRecord,
+		parsedTakenAt,
+		amount,
+		takenBy,
+	)
 	if err != nil {
 		return nil, err
 	}
 
-	return &ChangeTakeMedicationResponse{}, nil
+	return &ChangeTakeMedicationResponse{
+		Warnings: warnings,
+	}, nil
 }
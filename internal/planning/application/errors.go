@@ -1,6 +1,10 @@
 package application
 
-import "errors"
+import (
+	"errors"
+	"slices"
+	"strings"
+)
 
 // Common errors for application layer.
 var (
@@ -14,4 +18,36 @@ var (
 	ErrPlanNotBelongToUser = errors.New("plan does not belong to user")
 	// ErrNoMedicationForPlan is an error when there is no medication for plan.
 	ErrNoMedicationForPlan = errors.New("no medication for plan")
+	// ErrStockNotUpdated is an error when medication stock can't be changed by intake.
+	ErrStockNotUpdated = errors.New("medication stock is not updated")
+	// ErrTakeConflict is an error when the take is applied to stock with another dose.
+	ErrTakeConflict = errors.New("take is applied to stock with another dose")
 )
+
+// FieldsError is a validation error with a reason for every invalid field of the command.
+type FieldsError struct {
+	Fields map[string]string
+}
+
+func (e *FieldsError) Error() string {
+	fields := make([]string, 0, len(e.Fields))
+	for name, reason := range e.Fields {
+		fields = append(fields, name+": "+reason)
+	}
+	slices.Sort(fields)
+	return "invalid fields: " + strings.Join(fields, "; ")
+}
+
+// Unwrap returns ErrValidationFail.
+func (e *FieldsError) Unwrap() error {
+	return ErrValidationFail
+}
+
+// withPrefix returns the error with fields nested into the prefix field.
+func (e *FieldsError) withPrefix(prefix string) *FieldsError {
+	fields := make(map[string]string, len(e.Fields))
+	for name, reason := range e.Fields {
+		fields[prefix+"."+name] = reason
+	}
+	return &FieldsError{Fields: fields}
+}
@@ -0,0 +1,164 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/FSO-VK/final-project-vk-backend/internal/planning/application/care"
+	"github.com/FSO-VK/final-project-vk-backend/internal/planning/application/medication"
+	"github.com/FSO-VK/final-project-vk-backend/internal/planning/application/notification"
+	"github.com/FSO-VK/final-project-vk-backend/internal/planning/domain/plan"
+	"github.com/FSO-VK/final-project-vk-backend/internal/planning/domain/record"
+	"github.com/FSO-VK/final-project-vk-backend/pkg/workerpool"
+)
+
+// escalationWindow is how long after the reminder missed intake is escalated.
+const escalationWindow = 25 * time.Hour
+
+// IntakeEscalationGenerator is an interface for escalating missed intakes.
+type IntakeEscalationGenerator interface {
+	EscalateMissedIntakes(ctx context.Context) error
+}
+
+// IntakeEscalationService escalates intakes which are not taken after reminder
+// according to escalation policies of plans.
+type IntakeEscalationService struct {
+	recordsRepo          record.Repository
+	planRepo             plan.Repository
+	notificationProvider notification.NotificationService
+	medicationProvider   medication.MedicationService
+	careProvider         care.CareService
+	pool                 *workerpool.Pool
+}
+
+// NewIntakeEscalationService creates a new IntakeEscalationService.
+func NewIntakeEscalationService(
+	recordsRepo record.Repository,
+	planRepo plan.Repository,
+	notificationProvider notification.NotificationService,
+	medicationProvider medication.MedicationService,
+	careProvider care.CareService,
+	pool *workerpool.Pool,
+) *IntakeEscalationService {
+	return &IntakeEscalationService{
+		recordsRepo:          recordsRepo,
+		planRepo:             planRepo,
+		notificationProvider: notificationProvider,
+		medicationProvider:   medicationProvider,
+		careProvider:         careProvider,
+		pool:                 pool,
+	}
+}
+
+// escalation is a fired escalation step of the missed intake.
+type escalation struct {
+	plan      *plan.Plan
+	record    *record.IntakeRecord
+	target    plan.EscalationTarget
+	dedupeKey string
+}
+
+// EscalateMissedIntakes fires due escalation steps of reminded intakes.
+// Intake taken late has no pending escalations.
+// Names of medications are got by one request, notifications are sent
+// in parallel and failed notifications don't stop others.
+func (g *IntakeEscalationService) EscalateMissedIntakes(ctx context.Context) error {
+	now := time.Now()
+	records, err := g.recordsRepo.RecordsToEscalate(ctx, now.Add(-escalationWindow))
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	var escalations []escalation
+	plans := make([]*plan.Plan, 0, len(records))
+	for _, r := range records {
+		p, err := g.planRepo.GetByID(ctx, r.PlanID())
+		if err != nil || !p.IsActive() {
+			continue
+		}
+		steps := p.Escalation().Due(r.RemindedAt(), r.Escalations(), now)
+		if len(steps) == 0 {
+			continue
+		}
+		// steps are marked fired first, so failed notification is not repeated every run
+		if err := r.Escalate(len(steps)); err != nil {
+			continue
+		}
+		if err := g.recordsRepo.UpdateByID(ctx, r); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		escalations = append(escalations, escalation{
+			plan:      p,
+			record:    r,
+			target:    steps[len(steps)-1].Target,
+			dedupeKey: fmt.Sprintf("intake-missed-%s-%d", r.ID(), r.Escalations()),
+		})
+		plans = append(plans, p)
+	}
+
+	names, err := medicationNames(ctx, g.medicationProvider, plans)
+	if err != nil {
+		return errors.Join(append(errs, err)...)
+	}
+	err = workerpool.Run(ctx, g.pool, escalations, func(ctx context.Context, e escalation) error {
+		medicationName, ok := names[e.plan.MedicationID()]
+		if !ok {
+			return nil
+		}
+		return g.escalate(ctx, e, medicationName)
+	})
+	return errors.Join(append(errs, err)...)
+}
+
+// escalate notifies the target of the last due step about the missed intake.
+// Notifications of every step have their own dedupe key.
+func (g *IntakeEscalationService) escalate(
+	ctx context.Context,
+	e escalation,
+	medicationName string,
+) error {
+	p, r, dedupeKey := e.plan, e.record, e.dedupeKey
+	plannedAt := r.PlannedTime().In(p.Location()).Format(time.RFC3339)
+
+	switch e.target {
+	case plan.EscalateToUser:
+		info := intakeReminder(p, r, "intake_missed", map[string]any{
+			"medication": medicationName,
+			"plannedAt":  plannedAt,
+		})
+		info.DedupeKey = dedupeKey
+		return g.notificationProvider.SendNotification(ctx, info)
+	case plan.EscalateToCaregivers:
+		caregivers, err := g.careProvider.Caregivers(ctx, p.UserID())
+		if err != nil {
+			return fmt.Errorf("failed to get caregivers: %w", err)
+		}
+		var errs []error
+		for _, caregiverID := range caregivers.IDs {
+			err := g.notificationProvider.SendNotification(ctx, notification.NotificationInfo{
+				UserID:   caregiverID,
+				Category: notification.CategoryIntake,
+				Type:     "intake_missed_caregiver",
+				Template: "intake_missed_caregiver",
+				Params: map[string]any{
+					"name":       caregivers.Name,
+					"medication": medicationName,
+					"plannedAt":  plannedAt,
+				},
+				Urgency:   "high",
+				DedupeKey: dedupeKey,
+				Data: map[string]string{
+					"recordId": r.ID().String(),
+					"planId":   p.ID().String(),
+				},
+			})
+			errs = append(errs, err)
+		}
+		return errors.Join(errs...)
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/FSO-VK/final-project-vk-backend/internal/planning/application/medication"
+	"github.com/FSO-VK/final-project-vk-backend/internal/planning/domain/plan"
+	"github.com/FSO-VK/final-project-vk-backend/internal/utils/validator"
+	"github.com/FSO-VK/final-project-vk-backend/pkg/ical"
+	"github.com/google/uuid"
+)
+
+const (
+	calendarProdID = "-//FSO-VK//Planning//RU"
+	calendarName   = "Приём лекарств"
+	// intakeEventDuration is a duration of intake event in calendar.
+	intakeEventDuration = 15 * time.Minute
+)
+
+// ExportPlanCalendar is an interface for exporting a plan to iCalendar.
+type ExportPlanCalendar interface {
+	Execute(
+		ctx context.Context,
+		cmd *ExportPlanCalendarCommand,
+	) (*ExportPlanCalendarResponse, error)
+}
+
+// ExportPlanCalendarService is a service for exporting a plan to iCalendar.
+type ExportPlanCalendarService struct {
+	planningRepo       plan.Repository
+	validator          validator.Validator
+	medicationProvider medication.MedicationService
+}
+
+// NewExportPlanCalendarService returns a new ExportPlanCalendarService.
+func NewExportPlanCalendarService(
+	planningRepo plan.Repository,
+	valid validator.Validator,
+	medicationProvider medication.MedicationService,
+) *ExportPlanCalendarService {
+	return &ExportPlanCalendarService{
+		planningRepo:       planningRepo,
+		validator:          valid,
+		medicationProvider: medicationProvider,
+	}
+}
+
+// ExportPlanCalendarCommand is a request to export a plan to iCalendar.
+type ExportPlanCalendarCommand struct {
+	PlanID string `validate:"required,uuid"`
+	UserID string `validate:"required,uuid"`
+}
+
+// ExportPlanCalendarResponse is a response to export a plan to iCalendar.
+type ExportPlanCalendarResponse struct {
+	// Calendar is an iCalendar RFC 5545 object.
+	Calendar string
+}
+
+// Execute renders intakes of the plan as recurring calendar events.
+func (s *ExportPlanCalendarService) Execute(
+	ctx context.Context,
+	req *ExportPlanCalendarCommand,
+) (*ExportPlanCalendarResponse, error) {
+	valErr := s.validator.ValidateStruct(req)
+	if valErr != nil {
+		return nil, ErrValidationFail
+	}
+	parsedPlanID, err := uuid.Parse(req.PlanID)
+	if err != nil {
+		return nil, ErrValidationFail
+	}
+	parsedUser, err := uuid.Parse(req.UserID)
+	if err != nil {
+		return nil, ErrValidationFail
+	}
+
+	requestedPlan, err := s.planningRepo.GetByID(ctx, parsedPlanID)
+	if err != nil {
+		return nil, ErrNoPlan
+	}
+	if requestedPlan.UserID() != parsedUser {
+		return nil, ErrPlanNotBelongToUser
+	}
+
+	events, err := intakeEvents(s.medicationProvider, requestedPlan, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	cal := &ical.Calendar{
+		ProdID:   calendarProdID,
+		Name:     calendarName,
+		TimeZone: requestedPlan.Location().String(),
+		Events:   events,
+	}
+	return &ExportPlanCalendarResponse{
+		Calendar: cal.String(),
+	}, nil
+}
+
+// intakeEvents returns a recurring calendar event for every recurrence rule of the plan.
+func intakeEvents(
+	medicationProvider medication.MedicationService,
+	p *plan.Plan,
+	stamp time.Time,
+) ([]ical.Event, error) {
+	recurrences := p.Recurrences()
+	if len(recurrences) == 0 {
+		return nil, nil
+	}
+	medicationName, err := medicationProvider.MedicationName(p.MedicationID(), p.UserID())
+	if err != nil {
+		return nil, ErrNoMedicationForPlan
+	}
+
+	events := make([]ical.Event, 0, len(recurrences))
+	for i, r := range recurrences {
+		events = append(events, ical.Event{
+			UID:   fmt.Sprintf("%s-%d@planning", p.ID(), i),
+			Stamp: stamp,
+			Summary: fmt.Sprintf(
+				"%s, %s %s",
+				medicationName,
+				strconv.FormatFloat(r.DosageValue, 'f', -1, 64),
+				r.DosageUnit,
+			),
+			Description: p.Condition(),
+			Start:       r.Start,
+			Duration:    intakeEventDuration,
+			RRule:       r.Rule,
+			ExDates:     r.ExDates,
+			RDates:      r.RDates,
+			Alarms: []ical.Alarm{{
+				Before:      0,
+				Description: "Пора принять " + medicationName,
+			}},
+		})
+	}
+	return events, nil
+}
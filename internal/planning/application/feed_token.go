@@ -0,0 +1,132 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/FSO-VK/final-project-vk-backend/internal/planning/domain/calendar"
+	"github.com/FSO-VK/final-project-vk-backend/internal/utils/validator"
+	"github.com/google/uuid"
+)
+
+// RotateFeedToken is an interface for issuing a new secret token of calendar feed.
+type RotateFeedToken interface {
+	Execute(
+		ctx context.Context,
+		cmd *RotateFeedTokenCommand,
+	) (*RotateFeedTokenResponse, error)
+}
+
+// RotateFeedTokenService is a service for issuing a new secret token of calendar feed.
+type RotateFeedTokenService struct {
+	feedRepo  calendar.Repository
+	validator validator.Validator
+}
+
+// NewRotateFeedTokenService returns a new RotateFeedTokenService.
+func NewRotateFeedTokenService(
+	feedRepo calendar.Repository,
+	valid validator.Validator,
+) *RotateFeedTokenService {
+	return &RotateFeedTokenService{
+		feedRepo:  feedRepo,
+		validator: valid,
+	}
+}
+
+// RotateFeedTokenCommand is a request to issue a new feed token.
+type RotateFeedTokenCommand struct {
+	UserID string `validate:"required,uuid"`
+}
+
+// RotateFeedTokenResponse is a response to issue a new feed token.
+type RotateFeedTokenResponse struct {
+	Token     string
+	CreatedAt time.Time
+}
+
+// Execute issues a new token, the previous token of the user stops working.
+func (s *RotateFeedTokenService) Execute(
+	ctx context.Context,
+	req *RotateFeedTokenCommand,
+) (*RotateFeedTokenResponse, error) {
+	valErr := s.validator.ValidateStruct(req)
+	if valErr != nil {
+		return nil, ErrValidationFail
+	}
+	parsedUser, err := uuid.Parse(req.UserID)
+	if err != nil {
+		return nil, ErrValidationFail
+	}
+
+	feed, err := calendar.NewFeed(parsedUser, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create feed: %w", err)
+	}
+	if err := s.feedRepo.Save(ctx, feed); err != nil {
+		return nil, fmt.Errorf("failed to save feed: %w", err)
+	}
+	return &RotateFeedTokenResponse{
+		Token:     feed.Token(),
+		CreatedAt: feed.CreatedAt(),
+	}, nil
+}
+
+// RevokeFeedToken is an interface for revoking a secret token of calendar feed.
+type RevokeFeedToken interface {
+	Execute(
+		ctx context.Context,
+		cmd *RevokeFeedTokenCommand,
+	) (*RevokeFeedTokenResponse, error)
+}
+
+// RevokeFeedTokenService is a service for revoking a secret token of calendar feed.
+type RevokeFeedTokenService struct {
+	feedRepo  calendar.Repository
+	validator validator.Validator
+}
+
+// NewRevokeFeedTokenService returns a new RevokeFeedTokenService.
+func NewRevokeFeedTokenService(
+	feedRepo calendar.Repository,
+	valid validator.Validator,
+) *RevokeFeedTokenService {
+	return &RevokeFeedTokenService{
+		feedRepo:  feedRepo,
+		validator: valid,
+	}
+}
+
+// RevokeFeedTokenCommand is a request to revoke a feed token.
+type RevokeFeedTokenCommand struct {
+	UserID string `validate:"required,uuid"`
+}
+
+// RevokeFeedTokenResponse is a response to revoke a feed token.
+type RevokeFeedTokenResponse struct{}
+
+// Execute revokes the token of the user, calendar subscriptions stop updating.
+func (s *RevokeFeedTokenService) Execute(
+	ctx context.Context,
+	req *RevokeFeedTokenCommand,
+) (*RevokeFeedTokenResponse, error) {
+	valErr := s.validator.ValidateStruct(req)
+	if valErr != nil {
+		return nil, ErrValidationFail
+	}
+	parsedUser, err := uuid.Parse(req.UserID)
+	if err != nil {
+		return nil, ErrValidationFail
+	}
+
+	err = s.feedRepo.DeleteByUserID(ctx, parsedUser)
+	if errors.Is(err, calendar.ErrNoFeedFound) {
+		return nil, ErrNoCalendarFeed
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete feed: %w", err)
+	}
+	return &RevokeFeedTokenResponse{}, nil
+}
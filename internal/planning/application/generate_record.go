@@ -40,7 +40,8 @@ func NewGenerateRecordService(
 	}
 }
 
-// GenerateRecordForPlan generates records for a specific plan.
+// GenerateRecordForPlan generates records for a specific plan
+// up to the local midnight of the plan time zone shifted by creationShift.
 func (g *GenerateRecordService) GenerateRecordForPlan(
 	ctx context.Context,
 	planID uuid.UUID,
@@ -50,25 +51,12 @@ func (g *GenerateRecordService) GenerateRecordForPlan(
 	if err != nil {
 		return err
 	}
-	now := time.Now()
-	records, err := p.GenerateIntakeRecords(
-		now,
-		time.Date(
-			now.Year(), now.Month(), now.Day(),
-			0, 0, 0, 0, now.Location(),
-		).Add(creationShift),
-	)
-	if err != nil {
-		return err
-	}
-	if err := g.recordsRepo.SaveBulk(ctx, records); err != nil {
-		return err
-	}
-
-	return nil
+	return g.generateDueRecords(ctx, p, time.Now(), creationShift)
 }
 
-// GenerateRecordsForDay generates records for all active plans.
+// GenerateRecordsForDay generates records for active plans which local day has begun.
+// It is safe to call it more often than once a day: every plan gets records
+// for the next day only once, at its own local midnight.
 func (g *GenerateRecordService) GenerateRecordsForDay(
 	ctx context.Context,
 	batchSize int,
@@ -79,22 +67,37 @@ func (g *GenerateRecordService) GenerateRecordsForDay(
 		return err
 	}
 	now := time.Now()
-	creationTime := time.Date(
-		now.Year(), now.Month(), now.Day(),
-		0, 0, 0, 0, now.Location(),
-	).Add(creationShift)
+	// plans are updated after iteration, repository may hold a lock while iterating
+	due := make([]*plan.Plan, 0)
 	for p := range seq {
-		records, err := p.GenerateIntakeRecords(now, creationTime)
-		if err != nil {
-			return err
-		}
-		if len(records) == 0 {
+		if p.RecordsUntil().After(now) {
 			continue
 		}
-		if err := g.recordsRepo.SaveBulk(ctx, records); err != nil {
+		due = append(due, p)
+	}
+	for _, p := range due {
+		if err := g.generateDueRecords(ctx, p, now, creationShift); err != nil {
 			return err
 		}
 	}
 
 	return nil
 }
+
+func (g *GenerateRecordService) generateDueRecords(
+	ctx context.Context,
+	p *plan.Plan,
+	now time.Time,
+	creationShift time.Duration,
+) error {
+	records, err := p.GenerateDueRecords(now, creationShift)
+	if err != nil {
+		return err
+	}
+	if len(records) > 0 {
+		if err := g.recordsRepo.SaveBulk(ctx, records); err != nil {
+			return err
+		}
+	}
+	return g.planRepo.UpdatePlan(ctx, p)
+}
@@ -50,7 +50,15 @@ type PlanItem struct {
 	Status         string
 	StartDate      string
 	EndDate        string
+	Kind           string
+	TimeZone       string
 	RecurrenceRule []string
+	// Schedule is nil if rules of the plan are not produced by a preset.
+	Schedule *SchedulePreset
+	// AsNeeded is nil for scheduled plan.
+	AsNeeded *AsNeededLimits
+	// Phases are empty for plan with a single dosage.
+	Phases []PlanPhase
 }
 
 // GetAllPlansResponse is a response to get a plan.
@@ -92,7 +100,12 @@ func (s *GetAllPlansService) Execute(
 			Status:         onePlan.Status().String(),
 			StartDate:      onePlan.CourseStart().Format(time.DateOnly),
 			EndDate:        onePlan.CourseEnd().Format(time.DateOnly),
+			Kind:           onePlan.Kind().String(),
+			TimeZone:       onePlan.Location().String(),
 			RecurrenceRule: onePlan.ScheduleIcal(),
+			Schedule:       schedulePresetOf(onePlan),
+			AsNeeded:       asNeededLimitsOf(onePlan),
+			Phases:         phasesOf(onePlan),
 		})
 	}
 
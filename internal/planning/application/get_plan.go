@@ -52,7 +52,30 @@ type GetPlanResponse struct {
 	Status         string
 	StartDate      string
 	EndDate        string
+	Kind           string
+	TimeZone       string
 	RecurrenceRule []string
+	// Schedule is nil if rules of the plan are not produced by a preset.
+	Schedule *SchedulePreset
+	// AsNeeded is nil for scheduled plan.
+	AsNeeded *AsNeededLimits
+	// Phases are empty for plan with a single dosage.
+	Phases []PlanPhase
+	// CurrentPhase is nil if plan has no phases or no phase is active now.
+	CurrentPhase *CurrentPhase
+	// ExDates are skipped intakes, RDates are one-off intakes.
+	ExDates []string
+	RDates  []string
+	// Escalation is empty if missed intakes are not escalated.
+	Escalation []EscalationStep
+}
+
+// CurrentPhase is a phase of the plan active now.
+type CurrentPhase struct {
+	PlanPhase
+
+	// Index is a position of the phase in plan phases.
+	Index int
 }
 
 // Execute executes the GetPlan command.
@@ -95,7 +118,25 @@ func (s *GetPlanService) Execute(
 		Status:         requestedPlan.Status().String(),
 		StartDate:      requestedPlan.CourseStart().Format(time.DateOnly),
 		EndDate:        requestedPlan.CourseEnd().Format(time.DateOnly),
+		Kind:           requestedPlan.Kind().String(),
+		TimeZone:       requestedPlan.Location().String(),
 		RecurrenceRule: requestedPlan.ScheduleIcal(),
+		Schedule:       schedulePresetOf(requestedPlan),
+		AsNeeded:       asNeededLimitsOf(requestedPlan),
+		Phases:         phasesOf(requestedPlan),
+		CurrentPhase:   nil,
+		ExDates:        nil,
+		RDates:         nil,
+		Escalation:     escalationOf(requestedPlan),
+	}
+	exDates, rDates := requestedPlan.Exceptions()
+	response.ExDates = formatDates(exDates)
+	response.RDates = formatDates(rDates)
+	if i, ok := requestedPlan.CurrentPhase(time.Now()); ok {
+		response.CurrentPhase = &CurrentPhase{
+			PlanPhase: response.Phases[i],
+			Index:     i,
+		}
 	}
 	return response, nil
 }
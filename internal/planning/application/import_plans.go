@@ -0,0 +1,307 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/FSO-VK/final-project-vk-backend/internal/planning/application/medication"
+	"github.com/FSO-VK/final-project-vk-backend/internal/planning/domain/plan"
+	"github.com/FSO-VK/final-project-vk-backend/internal/utils/validator"
+	"github.com/FSO-VK/final-project-vk-backend/pkg/ical"
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrInvalidCalendar is an error when uploaded calendar can't be parsed.
+	ErrInvalidCalendar = errors.New("invalid calendar")
+	// errNoCourseEnd is an error when imported event repeats forever and no end date is given.
+	errNoCourseEnd = errors.New("event repeats forever, course end date is required")
+)
+
+// ImportPlans is an interface for importing plans from iCalendar.
+type ImportPlans interface {
+	Execute(
+		ctx context.Context,
+		cmd *ImportPlansCommand,
+	) (*ImportPlansResponse, error)
+}
+
+// ImportPlansService is a service for importing plans from iCalendar.
+type ImportPlansService struct {
+	addPlan AddPlan
+	// finishPlan rolls back plans of the import if one of them is not saved.
+	finishPlan         FinishPlan
+	validator          validator.Validator
+	medicationProvider medication.MedicationService
+	// defaultLocation is a time zone of floating times if the command has no time zone.
+	defaultLocation *time.Location
+}
+
+// NewImportPlansService returns a new ImportPlansService.
+func NewImportPlansService(
+	addPlan AddPlan,
+	finishPlan FinishPlan,
+	valid validator.Validator,
+	medicationProvider medication.MedicationService,
+	defaultLocation *time.Location,
+) *ImportPlansService {
+	return &ImportPlansService{
+		addPlan:            addPlan,
+		finishPlan:         finishPlan,
+		validator:          valid,
+		medicationProvider: medicationProvider,
+		defaultLocation:    defaultLocation,
+	}
+}
+
+// ImportPlansCommand is a request to import plans of the medication from iCalendar.
+// Calendar has no dosage, so every event gets the same dosage and condition.
+type ImportPlansCommand struct {
+	UserID       string  `validate:"required,uuid"`
+	MedicationID string  `validate:"required,uuid"`
+	AmountValue  float64 `validate:"required,gt=0"`
+	AmountUnit   string  `validate:"required"`
+	Condition    string  `validate:"omitempty,max=300"`
+	// TimeZone is used for floating times and events in UTC.
+	TimeZone string `validate:"omitempty,timezone"`
+	// EndDate is a course end of events repeating forever.
+	EndDate  string `validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	Calendar string `validate:"required,max=1048576"`
+	// DryRun returns a preview of plans without creating them.
+	DryRun bool
+}
+
+// ImportedPlan is a plan mapped from a calendar event.
+type ImportedPlan struct {
+	UID            string
+	Summary        string
+	StartDate      string
+	EndDate        string
+	TimeZone       string
+	RecurrenceRule []string
+	ExDates        []string
+	RDates         []string
+	// Warnings describe constructs of the event that are ignored or unsupported.
+	Warnings []string
+	// Skipped tells that the event can't be imported as a plan.
+	Skipped bool
+	// PlanID is an ID of created plan, it is empty in dry run.
+	PlanID string
+}
+
+// ImportPlansResponse is a response to import plans from iCalendar.
+type ImportPlansResponse struct {
+	DryRun bool
+	Plans  []ImportedPlan
+}
+
+// Execute maps calendar events to plans and creates them unless it is a dry run.
+// Every event is validated before any plan is created, and plans created
+// before a failed save are finished, so a calendar is not imported partially.
+func (s *ImportPlansService) Execute(
+	ctx context.Context,
+	req *ImportPlansCommand,
+) (*ImportPlansResponse, error) {
+	valErr := s.validator.ValidateStruct(req)
+	if valErr != nil {
+		return nil, ErrValidationFail
+	}
+	parsedUser, err := uuid.Parse(req.UserID)
+	if err != nil {
+		return nil, ErrValidationFail
+	}
+	parsedMedicationID, err := uuid.Parse(req.MedicationID)
+	if err != nil {
+		return nil, ErrValidationFail
+	}
+	loc := s.defaultLocation
+	if req.TimeZone != "" {
+		loc, err = time.LoadLocation(req.TimeZone)
+		if err != nil {
+			return nil, ErrValidationFail
+		}
+	}
+	_, err = s.medicationProvider.MedicationName(parsedMedicationID, parsedUser)
+	if err != nil {
+		return nil, ErrNoMedicationForPlan
+	}
+
+	cal, err := ical.Decode(strings.NewReader(req.Calendar), loc)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCalendar, err)
+	}
+
+	imported := make([]ImportedPlan, 0, len(cal.Events))
+	cmds := make([]*AddPlanCommand, 0, len(cal.Events))
+	for _, ev := range cal.Events {
+		item, cmd, cmdLoc := importEvent(req, ev, loc)
+		if !item.Skipped {
+			// the plan is built but not saved to find problems before committing
+			if err := s.validator.ValidateStruct(cmd); err != nil {
+				item.Skipped = true
+				item.Warnings = append(item.Warnings, err.Error())
+			} else if _, err := createPlan(
+				cmd,
+				parsedUser,
+				parsedMedicationID,
+				cmdLoc,
+			); err != nil {
+				item.Skipped = true
+				item.Warnings = append(item.Warnings, err.Error())
+			}
+		}
+		imported = append(imported, item)
+		cmds = append(cmds, cmd)
+	}
+
+	if !req.DryRun {
+		if err := s.save(ctx, req.UserID, imported, cmds); err != nil {
+			return nil, err
+		}
+	}
+
+	return &ImportPlansResponse{
+		DryRun: req.DryRun,
+		Plans:  imported,
+	}, nil
+}
+
+// save creates plans of the imported events which are not skipped.
+// If a plan is not saved, already created plans are finished.
+func (s *ImportPlansService) save(
+	ctx context.Context,
+	userID string,
+	imported []ImportedPlan,
+	cmds []*AddPlanCommand,
+) error {
+	for i := range imported {
+		if imported[i].Skipped {
+			continue
+		}
+		created, err := s.addPlan.Execute(ctx, cmds[i])
+		if err != nil {
+			errs := []error{fmt.Errorf("failed to import event %q: %w", imported[i].UID, err)}
+			for _, item := range imported[:i] {
+				if item.PlanID == "" {
+					continue
+				}
+				_, err := s.finishPlan.Execute(ctx, &FinishPlanCommand{
+					ID:     item.PlanID,
+					UserID: userID,
+				})
+				errs = append(errs, err)
+			}
+			return errors.Join(errs...)
+		}
+		imported[i].PlanID = created.ID
+	}
+	return nil
+}
+
+// importEvent maps the calendar event to the command of adding a plan
+// and returns the time zone of the plan.
+func importEvent(
+	req *ImportPlansCommand,
+	ev ical.Event,
+	loc *time.Location,
+) (ImportedPlan, *AddPlanCommand, *time.Location) {
+	item := ImportedPlan{
+		UID:     ev.UID,
+		Summary: ev.Summary,
+	}
+	for _, prop := range ev.Unsupported {
+		switch {
+		case prop == "DTSTART;VALUE=DATE":
+			item.Skipped = true
+			item.Warnings = append(item.Warnings, "all-day event has no intake time")
+		case prop == "RRULE":
+			item.Warnings = append(item.Warnings, "only the first RRULE is imported")
+		case strings.HasPrefix(prop, "TZID="):
+			item.Warnings = append(item.Warnings,
+				fmt.Sprintf("unknown time zone %s, %s is used", prop, loc))
+		case prop == "TRIGGER":
+			// reminders of the plan are sent by the service, alarms are not imported
+		default:
+			item.Warnings = append(item.Warnings, prop+" is not supported and ignored")
+		}
+	}
+	if ev.RRule == "" {
+		item.Skipped = true
+		item.Warnings = append(item.Warnings, "event without RRULE is not a regimen")
+	}
+	if item.Skipped {
+		return item, nil, nil
+	}
+
+	// events in UTC have no user time zone, floating times are already in loc
+	if ev.Start.Location() != time.UTC {
+		loc = ev.Start.Location()
+	}
+	rule := "DTSTART" + ical.FormatDateTime(ev.Start) + "\nRRULE:" + ev.RRule
+	end, err := importCourseEnd(rule, req.EndDate, loc)
+	if err != nil {
+		item.Skipped = true
+		item.Warnings = append(item.Warnings, err.Error())
+		return item, nil, nil
+	}
+
+	item.TimeZone = loc.String()
+	item.StartDate = ev.Start.Format(time.RFC3339)
+	item.EndDate = end.Format(time.RFC3339)
+	item.RecurrenceRule = []string{rule}
+	for _, t := range ev.ExDates {
+		item.ExDates = append(item.ExDates, t.In(loc).Format(time.RFC3339))
+	}
+	for _, t := range ev.RDates {
+		item.RDates = append(item.RDates, t.In(loc).Format(time.RFC3339))
+	}
+	return item, &AddPlanCommand{
+		MedicationID:   req.MedicationID,
+		UserID:         req.UserID,
+		AmountValue:    req.AmountValue,
+		AmountUnit:     req.AmountUnit,
+		Condition:      req.Condition,
+		StartDate:      item.StartDate,
+		EndDate:        item.EndDate,
+		Kind:           plan.KindScheduled.String(),
+		RecurrenceRule: item.RecurrenceRule,
+		AsNeeded:       nil,
+		Phases:         nil,
+		TimeZone:       item.TimeZone,
+		Schedule:       nil,
+		ExDates:        item.ExDates,
+		RDates:         item.RDates,
+	}, loc
+}
+
+// importCourseEnd returns the course end for rule with UNTIL or COUNT,
+// rule repeating forever ends at fallback.
+func importCourseEnd(rule string, fallback string, loc *time.Location) (time.Time, error) {
+	// the rule of the event always has DTSTART
+	rules, err := parseRules([]string{rule}, time.Time{}, loc)
+	if err != nil {
+		return time.Time{}, err
+	}
+	r := rules[0]
+
+	var last time.Time
+	switch {
+	case !r.OrigOptions.Until.IsZero():
+		last = r.OrigOptions.Until
+	case r.OrigOptions.Count > 0:
+		all := r.All()
+		if len(all) == 0 {
+			return time.Time{}, ErrUnsupportedRrule
+		}
+		last = all[len(all)-1]
+	case fallback != "":
+		return time.Parse(time.RFC3339, fallback)
+	default:
+		return time.Time{}, errNoCourseEnd
+	}
+	// course end is exclusive while UNTIL and the last occurrence are included
+	return last.In(loc).Add(time.Second), nil
+}
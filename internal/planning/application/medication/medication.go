@@ -1,8 +1,57 @@
 package medication
 
-import "github.com/google/uuid"
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrNotEnoughMedication is an error when stock is less than the dose.
+	ErrNotEnoughMedication = errors.New("not enough medication in stock")
+	// ErrIncompatibleUnit is an error when dose unit can't be converted to medication unit.
+	ErrIncompatibleUnit = errors.New("dose unit is incompatible with medication unit")
+	// ErrIdempotencyKeyReused is an error when the key is already used for another change.
+	ErrIdempotencyKeyReused = errors.New("idempotency key is used for another stock change")
+)
 
 // MedicationService provides access to medication data.
 type MedicationService interface {
 	MedicationName(id uuid.UUID, userID uuid.UUID) (string, error)
+	// MedicationNames returns names of many medications by their ids at once,
+	// medications missing in boxes of users are omitted.
+	MedicationNames(ctx context.Context, refs []MedicationRef) (map[uuid.UUID]string, error)
+	// TakeDose decreases medication stock by the dose taken by the actor.
+	// Calls with the same idempotency key are applied once.
+	TakeDose(
+		ctx context.Context,
+		id uuid.UUID,
+		userID uuid.UUID,
+		actorID uuid.UUID,
+		dose Dose,
+		idempotencyKey string,
+	) error
+	// RestoreDose increases medication stock by the dose to compensate a take
+	// canceled by the actor. Calls with the same idempotency key are applied once.
+	RestoreDose(
+		ctx context.Context,
+		id uuid.UUID,
+		userID uuid.UUID,
+		actorID uuid.UUID,
+		dose Dose,
+		idempotencyKey string,
+	) error
+}
+
+// MedicationRef is a medication in the box of the user.
+type MedicationRef struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+// Dose is an amount of medication in one of plan units.
+type Dose struct {
+	Value float64
+	Unit  string
 }
@@ -6,14 +6,40 @@ import (
 	"github.com/google/uuid"
 )
 
+// CategoryIntake is a category of intake reminders.
+const CategoryIntake = "intake"
+
 // NotificationService is service for sending notifications.
 type NotificationService interface {
 	SendNotification(ctx context.Context, notificationInfo NotificationInfo) error
 }
 
 // NotificationInfo contains information of notification.
+// Category lets the user turn notifications off in preferences.
+// Type, URL, Actions and Data let service worker of the user handle clicks,
+// notification with the same Tag replaces the previous one.
+// Title, Body and titles of Actions are rendered by notifications service
+// from Template with Params in the language of the user.
+// Notifications service drops notification recently sent with the same DedupeKey.
 type NotificationInfo struct {
-	UserID uuid.UUID
+	UserID    uuid.UUID
+	Category  string
+	Type      string
+	Title     string
+	Body      string
+	URL       string
+	Tag       string
+	Renotify  bool
+	Urgency   string
+	Actions   []Action
+	Data      map[string]string
+	Template  string
+	Params    map[string]any
+	DedupeKey string
+}
+
+// Action is a button of notification.
+type Action struct {
+	Action string
 	Title  string
-	Body   string
 }
@@ -2,12 +2,17 @@ package application
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sync"
 	"time"
 
 	"github.com/FSO-VK/final-project-vk-backend/internal/planning/application/medication"
 	"github.com/FSO-VK/final-project-vk-backend/internal/planning/application/notification"
 	"github.com/FSO-VK/final-project-vk-backend/internal/planning/domain/plan"
 	"github.com/FSO-VK/final-project-vk-backend/internal/planning/domain/record"
+	"github.com/FSO-VK/final-project-vk-backend/pkg/workerpool"
+	"github.com/google/uuid"
 )
 
 // IntakeNotificationGenerator is an interface for generating notifications for intake.
@@ -15,55 +20,221 @@ type IntakeNotificationGenerator interface {
 	GenerateIntakeNotifications(ctx context.Context) error
 }
 
+// reminderCatchUp is how late the reminder about the intake may be sent,
+// e.g. after the service was down. Later intakes are left to escalations.
+const reminderCatchUp = time.Hour
+
 // IntakeNotificationService implements IntakeNotification.
 type IntakeNotificationService struct {
 	recordsRepo          record.Repository
 	planRepo             plan.Repository
 	notificationProvider notification.NotificationService
 	medicationProvider   medication.MedicationService
+	pool                 *workerpool.Pool
+
+	// lastRun is when all due reminders were sent last time.
+	lastRun time.Time
+	mu      sync.Mutex
 }
 
 // NewIntakeNotificationService creates a new IntakeNotificationService.
+// Reminders are sent by workers of the pool.
 func NewIntakeNotificationService(
 	recordsRepo record.Repository,
 	planRepo plan.Repository,
 	notificationProvider notification.NotificationService,
 	medicationProvider medication.MedicationService,
+	pool *workerpool.Pool,
 ) *IntakeNotificationService {
 	return &IntakeNotificationService{
 		recordsRepo:          recordsRepo,
 		planRepo:             planRepo,
 		notificationProvider: notificationProvider,
 		medicationProvider:   medicationProvider,
+		pool:                 pool,
+		lastRun:              time.Time{},
+		mu:                   sync.Mutex{},
 	}
 }
 
-// GenerateIntakeNotifications generates notifications for intake.
+// reminder is a reminder about the intake with its dedupe key.
+type reminder struct {
+	plan      *plan.Plan
+	record    *record.IntakeRecord
+	dedupeKey string
+}
+
+// GenerateIntakeNotifications generates notifications for intake planned
+// since the last successful run and for intake which reminder was snoozed till now.
+// Reminded records are marked, so every intake is reminded once however runs
+// are aligned, and failed run is repeated from the same time.
+// Notifications have dedupe keys, so the reminder sent before the record
+// was marked is not sent twice.
+// Names of medications are got by one request, reminders are sent in parallel
+// and failed reminders don't stop others.
 func (g *IntakeNotificationService) GenerateIntakeNotifications(
 	ctx context.Context,
 ) error {
-	records, err := g.recordsRepo.RecordsByTime(ctx, time.Now())
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := time.Now()
+	since := now.Add(-reminderCatchUp)
+	if g.lastRun.After(since) {
+		since = g.lastRun
+	}
+	planned, err := g.recordsRepo.RecordsToRemind(ctx, since, now)
+	if err != nil {
+		return err
+	}
+	snoozed, err := g.recordsRepo.RecordsSnoozedUntil(ctx, now)
 	if err != nil {
 		return err
 	}
 
-	for r := range records {
-		p, err := g.planRepo.GetByID(ctx, r.PlanID())
-		if err != nil {
+	reminders := make([]reminder, 0, len(planned)+len(snoozed))
+	for _, r := range planned {
+		key := fmt.Sprintf("intake-reminder-%s-%d", r.ID(), r.PlannedTime().Unix())
+		reminders = append(reminders, reminder{record: r, dedupeKey: key})
+	}
+	for _, r := range snoozed {
+		if r.IsTaken() {
 			continue
 		}
-		medicationName, err := g.medicationProvider.MedicationName(p.MedicationID(), p.UserID())
-		if err != nil {
+		key := fmt.Sprintf("intake-snooze-%s-%d", r.ID(), r.SnoozedUntil().Unix())
+		reminders = append(reminders, reminder{record: r, dedupeKey: key})
+	}
+	// reminders with failed plan lookup are retried by the next run
+	reminders, plansErr := g.withPlans(ctx, reminders)
+
+	plans := make([]*plan.Plan, 0, len(reminders))
+	for _, rem := range reminders {
+		plans = append(plans, rem.plan)
+	}
+	names, err := medicationNames(ctx, g.medicationProvider, plans)
+	if err != nil {
+		return err
+	}
+
+	err = workerpool.Run(ctx, g.pool, reminders, func(ctx context.Context, rem reminder) error {
+		return g.remind(ctx, rem, names, now)
+	})
+	if err = errors.Join(plansErr, err); err != nil {
+		return err
+	}
+	g.lastRun = now
+	return nil
+}
+
+// withPlans returns reminders with plans of their records.
+// Reminders about records of deleted plans are skipped, reminders whose
+// plan is not got are left out and reported by the error, so one failed
+// lookup doesn't stop other reminders.
+func (g *IntakeNotificationService) withPlans(
+	ctx context.Context,
+	reminders []reminder,
+) ([]reminder, error) {
+	result := make([]reminder, 0, len(reminders))
+	var errs []error
+	for _, rem := range reminders {
+		p, err := g.planRepo.GetByID(ctx, rem.record.PlanID())
+		if errors.Is(err, plan.ErrNoPlanFound) {
+			continue
+		} else if err != nil {
+			errs = append(errs, fmt.Errorf(
+				"failed to get plan of intake %s: %w",
+				rem.record.ID(),
+				err,
+			))
 			continue
 		}
-		info := notification.NotificationInfo{
-			UserID: p.UserID(),
-			Title:  "Время принять " + medicationName,
-			Body:   p.Condition(),
-		}
-		if err := g.notificationProvider.SendNotification(ctx, info); err != nil {
-			return err
+		rem.plan = p
+		result = append(result, rem)
+	}
+	return result, errors.Join(errs...)
+}
+
+// remind sends reminder about the intake and marks the record reminded.
+// Reminders about deleted medications are skipped.
+func (g *IntakeNotificationService) remind(
+	ctx context.Context,
+	rem reminder,
+	names map[uuid.UUID]string,
+	now time.Time,
+) error {
+	p, r := rem.plan, rem.record
+	medicationName, ok := names[p.MedicationID()]
+	if !ok {
+		return nil
+	}
+	info := intakeReminder(p, r, "intake_reminder", map[string]any{
+		"medication": medicationName,
+		"condition":  p.Condition(),
+	})
+	info.DedupeKey = rem.dedupeKey
+	if err := g.notificationProvider.SendNotification(ctx, info); err != nil {
+		return fmt.Errorf("failed to remind about intake %s: %w", r.ID(), err)
+	}
+	// escalation delays are counted from the first reminder
+	r.MarkReminded(now)
+	r.Unsnooze()
+	return g.recordsRepo.UpdateByID(ctx, r)
+}
+
+// medicationNames returns names of medications of the plans by one request.
+func medicationNames(
+	ctx context.Context,
+	medicationProvider medication.MedicationService,
+	plans []*plan.Plan,
+) (map[uuid.UUID]string, error) {
+	if len(plans) == 0 {
+		return map[uuid.UUID]string{}, nil
+	}
+	refs := make([]medication.MedicationRef, 0, len(plans))
+	seen := make(map[uuid.UUID]bool, len(plans))
+	for _, p := range plans {
+		if seen[p.MedicationID()] {
+			continue
 		}
+		seen[p.MedicationID()] = true
+		refs = append(refs, medication.MedicationRef{ID: p.MedicationID(), UserID: p.UserID()})
+	}
+	names, err := medicationProvider.MedicationNames(ctx, refs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get medication names: %w", err)
+	}
+	return names, nil
+}
+
+// intakeReminder returns notification about the intake which the user
+// can take or snooze right from the notification.
+// Notifications about the same intake replace each other.
+// Type of notification is also the template it is rendered from.
+func intakeReminder(
+	p *plan.Plan,
+	r *record.IntakeRecord,
+	notificationType string,
+	params map[string]any,
+) notification.NotificationInfo {
+	return notification.NotificationInfo{
+		UserID:   p.UserID(),
+		Category: notification.CategoryIntake,
+		Type:     notificationType,
+		Template: notificationType,
+		Params:   params,
+		URL:      "/schedule",
+		Tag:      "intake-" + r.ID().String(),
+		Renotify: true,
+		Urgency:  "high",
+		Actions: []notification.Action{
+			{Action: "take"},
+			{Action: "snooze"},
+		},
+		Data: map[string]string{
+			"profileId":    p.UserID().String(),
+			"recordId":     r.ID().String(),
+			"planId":       p.ID().String(),
+			"medicationId": p.MedicationID().String(),
+		},
 	}
-	return nil
 }
@@ -0,0 +1,125 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/FSO-VK/final-project-vk-backend/internal/planning/domain/plan"
+	"github.com/FSO-VK/final-project-vk-backend/internal/utils/validator"
+	"github.com/google/uuid"
+)
+
+// ErrInvalidEscalation is an error when escalation policy doesn't fit the plan.
+var ErrInvalidEscalation = errors.New("invalid escalation policy")
+
+// SetPlanEscalation is an interface for configuring escalation of missed intakes of a plan.
+type SetPlanEscalation interface {
+	Execute(
+		ctx context.Context,
+		cmd *SetPlanEscalationCommand,
+	) (*SetPlanEscalationResponse, error)
+}
+
+// SetPlanEscalationService is a service for configuring escalation of missed intakes of a plan.
+type SetPlanEscalationService struct {
+	planningRepo plan.Repository
+	validator    validator.Validator
+}
+
+// NewSetPlanEscalationService returns a new SetPlanEscalationService.
+func NewSetPlanEscalationService(
+	planningRepo plan.Repository,
+	valid validator.Validator,
+) *SetPlanEscalationService {
+	return &SetPlanEscalationService{
+		planningRepo: planningRepo,
+		validator:    valid,
+	}
+}
+
+// EscalationStep notifies the target when intake is not taken AfterMinutes after reminder.
+// Target is "user" to re-notify the user or "caregivers" to notify linked caregivers.
+type EscalationStep struct {
+	AfterMinutes int    `validate:"required,gt=0,lte=1440"`
+	Target       string `validate:"required,oneof=user caregivers"`
+}
+
+// SetPlanEscalationCommand is a request to replace escalation policy of a plan,
+// empty steps disable escalation.
+type SetPlanEscalationCommand struct {
+	PlanID string           `validate:"required,uuid"`
+	UserID string           `validate:"required,uuid"`
+	Steps  []EscalationStep `validate:"max=5,dive"`
+}
+
+// SetPlanEscalationResponse is a response to replace escalation policy of a plan.
+type SetPlanEscalationResponse struct {
+	Steps []EscalationStep
+}
+
+// Execute executes the SetPlanEscalation command.
+func (s *SetPlanEscalationService) Execute(
+	ctx context.Context,
+	req *SetPlanEscalationCommand,
+) (*SetPlanEscalationResponse, error) {
+	valErr := s.validator.ValidateStruct(req)
+	if valErr != nil {
+		return nil, ErrValidationFail
+	}
+	parsedPlanID, err := uuid.Parse(req.PlanID)
+	if err != nil {
+		return nil, ErrValidationFail
+	}
+	parsedUser, err := uuid.Parse(req.UserID)
+	if err != nil {
+		return nil, ErrValidationFail
+	}
+
+	steps := make([]plan.EscalationStep, 0, len(req.Steps))
+	for _, step := range req.Steps {
+		steps = append(steps, plan.EscalationStep{
+			After:  time.Duration(step.AfterMinutes) * time.Minute,
+			Target: plan.EscalationTarget(step.Target),
+		})
+	}
+	policy, err := plan.NewEscalationPolicy(steps)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidEscalation, err)
+	}
+
+	p, err := s.planningRepo.GetByID(ctx, parsedPlanID)
+	if err != nil {
+		return nil, ErrNoPlan
+	}
+	if p.UserID() != parsedUser {
+		return nil, ErrPlanNotBelongToUser
+	}
+	err = p.SetEscalation(policy)
+	if errors.Is(err, plan.ErrInvalidEscalation) {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidEscalation, err)
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to set escalation: %w", err)
+	}
+	err = s.planningRepo.UpdatePlan(ctx, p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update plan: %w", err)
+	}
+
+	return &SetPlanEscalationResponse{
+		Steps: escalationOf(p),
+	}, nil
+}
+
+func escalationOf(p *plan.Plan) []EscalationStep {
+	steps := p.Escalation().Steps()
+	result := make([]EscalationStep, 0, len(steps))
+	for _, step := range steps {
+		result = append(result, EscalationStep{
+			AfterMinutes: int(step.After / time.Minute),
+			Target:       string(step.Target),
+		})
+	}
+	return result
+}
@@ -0,0 +1,203 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/FSO-VK/final-project-vk-backend/internal/planning/domain/plan"
+	"github.com/FSO-VK/final-project-vk-backend/internal/planning/domain/record"
+	"github.com/FSO-VK/final-project-vk-backend/internal/utils/validator"
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrInvalidException is an error when exception doesn't fit the plan schedule.
+	ErrInvalidException = errors.New("invalid schedule exception")
+	// ErrExceptionInPast is an error when exception changes an intake in the past.
+	ErrExceptionInPast = errors.New("schedule exception is in the past")
+	// ErrIntakeAlreadyTaken is an error when excluded or snoozed intake is already taken.
+	ErrIntakeAlreadyTaken = errors.New("intake is already taken")
+)
+
+// AddPlanExceptions is an interface for skipping and adding single intakes of a plan.
+type AddPlanExceptions interface {
+	Execute(
+		ctx context.Context,
+		cmd *AddPlanExceptionsCommand,
+	) (*AddPlanExceptionsResponse, error)
+}
+
+// AddPlanExceptionsService is a service for skipping and adding single intakes of a plan.
+type AddPlanExceptionsService struct {
+	planningRepo plan.Repository
+	recordsRepo  record.Repository
+	validator    validator.Validator
+}
+
+// NewAddPlanExceptionsService returns a new AddPlanExceptionsService.
+func NewAddPlanExceptionsService(
+	planningRepo plan.Repository,
+	recordsRepo record.Repository,
+	valid validator.Validator,
+) *AddPlanExceptionsService {
+	return &AddPlanExceptionsService{
+		planningRepo: planningRepo,
+		recordsRepo:  recordsRepo,
+		validator:    valid,
+	}
+}
+
+// AddPlanExceptionsCommand is a request to change single intakes of a plan.
+// Exclude are times of intakes to skip, Add are times of one-off intakes.
+// Moving an intake is excluding the old time and adding the new one.
+type AddPlanExceptionsCommand struct {
+	PlanID  string   `validate:"required,uuid"`
+	UserID  string   `validate:"required,uuid"`
+	Exclude []string `validate:"required_without=Add,max=100,dive,datetime=2006-01-02T15:04:05Z07:00"`
+	Add     []string `validate:"required_without=Exclude,max=100,dive,datetime=2006-01-02T15:04:05Z07:00"`
+}
+
+// AddPlanExceptionsResponse is a response to change single intakes of a plan.
+type AddPlanExceptionsResponse struct {
+	ExDates []string
+	RDates  []string
+}
+
+// Execute applies exceptions to the plan schedule and to already generated records.
+func (s *AddPlanExceptionsService) Execute(
+	ctx context.Context,
+	req *AddPlanExceptionsCommand,
+) (*AddPlanExceptionsResponse, error) {
+	valErr := s.validator.ValidateStruct(req)
+	if valErr != nil {
+		return nil, ErrValidationFail
+	}
+	parsedPlanID, err := uuid.Parse(req.PlanID)
+	if err != nil {
+		return nil, ErrValidationFail
+	}
+	parsedUser, err := uuid.Parse(req.UserID)
+	if err != nil {
+		return nil, ErrValidationFail
+	}
+	now := time.Now()
+	exDates, err := parseExceptionDates(req.Exclude, now)
+	if err != nil {
+		return nil, err
+	}
+	rDates, err := parseExceptionDates(req.Add, now)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := s.planningRepo.GetByID(ctx, parsedPlanID)
+	if err != nil {
+		return nil, ErrNoPlan
+	}
+	if p.UserID() != parsedUser {
+		return nil, ErrPlanNotBelongToUser
+	}
+
+	// records up to the horizon are generated already and must follow the schedule
+	staleRecords, err := s.generatedRecords(ctx, p, exDates)
+	if err != nil {
+		return nil, err
+	}
+	err = p.AddExceptions(exDates, rDates)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidException, err)
+	}
+	var newRecords []*record.IntakeRecord
+	for _, r := range rDates {
+		if !r.Before(p.RecordsUntil()) {
+			continue
+		}
+		// Schedule is exclusive, so the range holds only the one-off intake
+		records, err := p.GenerateIntakeRecords(r.Add(-time.Nanosecond), r.Add(time.Nanosecond))
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate records: %w", err)
+		}
+		newRecords = append(newRecords, records...)
+	}
+
+	err = s.planningRepo.UpdatePlan(ctx, p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update plan: %w", err)
+	}
+	for _, r := range staleRecords {
+		err = s.recordsRepo.DeleteByID(ctx, r.ID())
+		if err != nil && !errors.Is(err, record.ErrNoRecordFound) {
+			return nil, fmt.Errorf("failed to delete record: %w", err)
+		}
+	}
+	if len(newRecords) > 0 {
+		err = s.recordsRepo.SaveBulk(ctx, newRecords)
+		if err != nil {
+			return nil, fmt.Errorf("failed to save records: %w", err)
+		}
+	}
+
+	planExDates, planRDates := p.Exceptions()
+	return &AddPlanExceptionsResponse{
+		ExDates: formatDates(planExDates),
+		RDates:  formatDates(planRDates),
+	}, nil
+}
+
+// generatedRecords returns records of the plan at excluded times,
+// taken intake can't be excluded.
+func (s *AddPlanExceptionsService) generatedRecords(
+	ctx context.Context,
+	p *plan.Plan,
+	exDates []time.Time,
+) ([]*record.IntakeRecord, error) {
+	var generated []time.Time
+	for _, ex := range exDates {
+		if ex.Before(p.RecordsUntil()) {
+			generated = append(generated, ex)
+		}
+	}
+	if len(generated) == 0 {
+		return nil, nil
+	}
+	records, err := s.recordsRepo.GetByPlanID(ctx, p.ID())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get records: %w", err)
+	}
+	var stale []*record.IntakeRecord
+	for _, r := range records {
+		for _, ex := range generated {
+			if !r.PlannedTime().Equal(ex) {
+				continue
+			}
+			if r.IsTaken() {
+				return nil, ErrIntakeAlreadyTaken
+			}
+			stale = append(stale, r)
+		}
+	}
+	return stale, nil
+}
+
+func parseExceptionDates(dates []string, now time.Time) ([]time.Time, error) {
+	parsed, err := parseDates(dates)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range parsed {
+		if !t.After(now) {
+			return nil, ErrExceptionInPast
+		}
+	}
+	return parsed, nil
+}
+
+func formatDates(dates []time.Time) []string {
+	formatted := make([]string, 0, len(dates))
+	for _, d := range dates {
+		formatted = append(formatted, d.Format(time.RFC3339))
+	}
+	return formatted
+}
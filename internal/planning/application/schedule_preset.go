@@ -0,0 +1,136 @@
+package application
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/FSO-VK/final-project-vk-backend/internal/planning/domain/plan"
+	"github.com/teambition/rrule-go"
+)
+
+// presetField is a name of the schedule preset field in the command.
+const presetField = "schedule"
+
+// SchedulePreset is a human-friendly schedule, it is an alternative to recurrence rules.
+// Kind is one of "times_a_day", "every_n_hours", "weekdays", "every_n_days", "cycle".
+// Times are local times of intakes "15:04", Weekdays are "MO".."SU".
+type SchedulePreset struct {
+	Kind          string
+	Times         []string
+	IntervalHours int
+	IntervalDays  int
+	Weekdays      []string
+	DaysOn        int
+	DaysOff       int
+}
+
+// compilePreset compiles the preset to recurrence rules starting from the course start.
+// Invalid fields are reported by *FieldsError.
+func compilePreset(
+	preset *SchedulePreset,
+	start time.Time,
+	loc *time.Location,
+) ([]*rrule.RRule, error) {
+	fields := make(map[string]string)
+
+	times := make([]plan.TimeOfDay, 0, len(preset.Times))
+	for i, t := range preset.Times {
+		parsed, err := time.Parse("15:04", t)
+		if err != nil {
+			fields[fmt.Sprintf("%s.times[%d]", presetField, i)] = "time must be in format HH:MM"
+			continue
+		}
+		times = append(times, plan.TimeOfDay{Hour: parsed.Hour(), Minute: parsed.Minute()})
+	}
+	weekdays := make([]time.Weekday, 0, len(preset.Weekdays))
+	for i, d := range preset.Weekdays {
+		wd, ok := weekdayFromCode(d)
+		if !ok {
+			key := fmt.Sprintf("%s.weekdays[%d]", presetField, i)
+			fields[key] = "weekday must be one of MO, TU, WE, TH, FR, SA, SU"
+			continue
+		}
+		weekdays = append(weekdays, wd)
+	}
+	if len(fields) > 0 {
+		return nil, &FieldsError{Fields: fields}
+	}
+
+	rules, err := plan.SchedulePreset{
+		Kind:          plan.PresetKind(preset.Kind),
+		Times:         times,
+		IntervalHours: preset.IntervalHours,
+		IntervalDays:  preset.IntervalDays,
+		Weekdays:      weekdays,
+		DaysOn:        preset.DaysOn,
+		DaysOff:       preset.DaysOff,
+	}.Compile(start, loc)
+	if err != nil {
+		return nil, presetFieldsError(err)
+	}
+	return rules, nil
+}
+
+// presetFieldsError collects field errors of the domain preset.
+func presetFieldsError(err error) error {
+	fields := make(map[string]string)
+	var walk func(err error)
+	walk = func(err error) {
+		var fieldErr *plan.PresetFieldError
+		if joined, ok := err.(interface{ Unwrap() []error }); ok {
+			for _, e := range joined.Unwrap() {
+				walk(e)
+			}
+			return
+		}
+		if errors.As(err, &fieldErr) {
+			fields[presetField+"."+fieldErr.Field] = fieldErr.Reason
+		}
+	}
+	walk(err)
+	if len(fields) == 0 {
+		return fmt.Errorf("%w: %w", ErrValidationFail, err)
+	}
+	return &FieldsError{Fields: fields}
+}
+
+// schedulePresetOf returns the schedule of the plan as the preset
+// or nil if the plan rules are not produced by a preset.
+func schedulePresetOf(p *plan.Plan) *SchedulePreset {
+	preset, ok := p.Preset()
+	if !ok {
+		return nil
+	}
+	times := make([]string, 0, len(preset.Times))
+	for _, t := range preset.Times {
+		times = append(times, t.String())
+	}
+	var weekdays []string
+	for _, d := range preset.Weekdays {
+		weekdays = append(weekdays, weekdayCodes[d])
+	}
+	return &SchedulePreset{
+		Kind:          string(preset.Kind),
+		Times:         times,
+		IntervalHours: preset.IntervalHours,
+		IntervalDays:  preset.IntervalDays,
+		Weekdays:      weekdays,
+		DaysOn:        preset.DaysOn,
+		DaysOff:       preset.DaysOff,
+	}
+}
+
+// weekdayCodes are iCalendar codes of weekdays indexed by time.Weekday.
+//
+//nolint:gochecknoglobals
+var weekdayCodes = []string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+func weekdayFromCode(code string) (time.Weekday, bool) {
+	for i, c := range weekdayCodes {
+		if c == code {
+			return time.Weekday(i), true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,108 @@
+package application
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/FSO-VK/final-project-vk-backend/internal/planning/domain/plan"
+	"github.com/FSO-VK/final-project-vk-backend/internal/utils/validator"
+	"github.com/google/uuid"
+)
+
+// ScheduledDoses is an interface for getting planned intakes of a medication.
+type ScheduledDoses interface {
+	Execute(
+		ctx context.Context,
+		cmd *ScheduledDosesCommand,
+	) (*ScheduledDosesResponse, error)
+}
+
+// ScheduledDosesService is a service for getting planned intakes of a medication.
+type ScheduledDosesService struct {
+	planningRepo plan.Repository
+	validator    validator.Validator
+}
+
+// NewScheduledDosesService returns a new ScheduledDosesService.
+func NewScheduledDosesService(
+	planningRepo plan.Repository,
+	valid validator.Validator,
+) *ScheduledDosesService {
+	return &ScheduledDosesService{
+		planningRepo: planningRepo,
+		validator:    valid,
+	}
+}
+
+// ScheduledDosesCommand is a request to get planned intakes of a medication
+// in range [now, To].
+type ScheduledDosesCommand struct {
+	UserID       string `validate:"required,uuid"`
+	MedicationID string `validate:"required,uuid"`
+	To           string `validate:"required,datetime=2006-01-02T15:04:05Z07:00"`
+}
+
+// ScheduledDose is a planned intake of a medication.
+type ScheduledDose struct {
+	TakeAt time.Time
+	Value  float64
+	Unit   string
+}
+
+// ScheduledDosesResponse is a response to get planned intakes of a medication.
+type ScheduledDosesResponse struct {
+	// Doses are sorted by intake time.
+	Doses []ScheduledDose
+}
+
+// Execute collects intakes of all active plans of the medication.
+func (s *ScheduledDosesService) Execute(
+	ctx context.Context,
+	req *ScheduledDosesCommand,
+) (*ScheduledDosesResponse, error) {
+	valErr := s.validator.ValidateStruct(req)
+	if valErr != nil {
+		return nil, ErrValidationFail
+	}
+
+	userID, err := uuid.Parse(req.UserID)
+	if err != nil {
+		return nil, ErrValidationFail
+	}
+	medicationID, err := uuid.Parse(req.MedicationID)
+	if err != nil {
+		return nil, ErrValidationFail
+	}
+	to, err := time.Parse(time.RFC3339, req.To)
+	if err != nil {
+		return nil, ErrValidationFail
+	}
+
+	userPlans, err := s.planningRepo.UserPlans(ctx, userID)
+	if err != nil {
+		return nil, ErrNoPlan
+	}
+
+	now := time.Now()
+	doses := make([]ScheduledDose, 0)
+	for _, p := range userPlans {
+		if !p.IsActive() || p.MedicationID() != medicationID {
+			continue
+		}
+		for _, o := range p.Schedule(now, to) {
+			doses = append(doses, ScheduledDose{
+				TakeAt: o.At,
+				Value:  o.DosageValue,
+				Unit:   o.DosageUnit,
+			})
+		}
+	}
+	slices.SortFunc(doses, func(a, b ScheduledDose) int {
+		return a.TakeAt.Compare(b.TakeAt)
+	})
+
+	return &ScheduledDosesResponse{
+		Doses: doses,
+	}, nil
+}
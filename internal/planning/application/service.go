@@ -12,4 +12,14 @@ type PlanningApplication struct {
 	TakeMedication       TakeMedication
 	CancelMedicationTake CancelMedicationTake
 	ChangeTakeMedication ChangeTakeMedication
+	ScheduledDoses       ScheduledDoses
+	TakeAsNeeded         TakeAsNeeded
+	ExportPlanCalendar   ExportPlanCalendar
+	CalendarFeed         CalendarFeed
+	RotateFeedToken      RotateFeedToken
+	RevokeFeedToken      RevokeFeedToken
+	ImportPlans          ImportPlans
+	AddPlanExceptions    AddPlanExceptions
+	SetPlanEscalation    SetPlanEscalation
+	SnoozeIntake         SnoozeIntake
 }
@@ -13,7 +13,8 @@ import (
 )
 
 const (
-	StatusIntakePlanned = "Запланировано"
+	// StatusIntakePlanned is a status of intake which has no record yet.
+	StatusIntakePlanned = "planned"
 )
 
 // ShowSchedule is an interface for getting a notification.
@@ -30,9 +31,6 @@ type ShowScheduleService struct {
 	recordsRepo        record.Repository
 	validator          validator.Validator
 	medicationProvider medication.MedicationService
-	// createdShift is the offset from 00:00 when records are generated.
-	// At 00:00 + createdShift, all records for that day are created. (basically 24h - today creating for the next day)
-	createdShift time.Duration
 }
 
 // NewShowScheduleService returns a new ShowScheduleService.
@@ -41,14 +39,12 @@ func NewShowScheduleService(
 	recordsRepo record.Repository,
 	valid validator.Validator,
 	medicationProvider medication.MedicationService,
-	createdShift time.Duration,
 ) *ShowScheduleService {
 	return &ShowScheduleService{
 		planningRepo:       planningRepo,
 		recordsRepo:        recordsRepo,
 		validator:          valid,
 		medicationProvider: medicationProvider,
-		createdShift:       createdShift,
 	}
 }
 
@@ -69,6 +65,10 @@ type ScheduleTime struct {
 	Status         string // is taken
 	PlannedAt      time.Time
 	TakenAt        time.Time
+	// TakenAmount is an actually taken amount, zero if not taken.
+	TakenAmount float64
+	// TakenBy is the user who took the intake, uuid.Nil if not taken.
+	TakenBy uuid.UUID
 }
 
 // ShowScheduleResponse is a response to get a plan.
@@ -137,7 +137,7 @@ func (s *ShowScheduleService) scheduleList(
 	pastScheduleList := make([]*ScheduleTime, 0, len(userPlans))
 	futureScheduleList := make([]*ScheduleTime, 0, len(userPlans))
 	for _, p := range userPlans {
-		amountValue, amountUnit := p.Dosage()
+		_, amountUnit := p.Dosage()
 		medicationName, nameErr := s.medicationProvider.MedicationName(p.MedicationID(), p.UserID())
 		if nameErr != nil {
 			return nil, ErrNoMedicationForPlan
@@ -147,49 +147,55 @@ func (s *ShowScheduleService) scheduleList(
 		// because all of them are in the future and we will calculate them after a while
 		if err == nil {
 			for _, record := range records {
+				// ad-hoc intakes of as-needed plan exist only while taken
+				if p.IsAsNeeded() && !record.IsTaken() {
+					continue
+				}
 				if !record.PlannedTime().After(parsedEnd) &&
 					!record.PlannedTime().Before(parsedStart) {
 					pastScheduleList = append(pastScheduleList, &ScheduleTime{
 						IntakeRecordID: record.ID(),
 						MedicationID:   p.MedicationID(),
 						MedicationName: medicationName,
-						AmountValue:    amountValue,
+						AmountValue:    plannedAmount(p, record),
 						AmountUnit:     amountUnit,
 						Status:         record.Status().String(),
 						PlannedAt:      record.PlannedTime().UTC(),
-						TakenAt:        record.TakenAt(),
+						TakenAt:        record.TakenAt().UTC(),
+						TakenAmount:    record.TakenAmount(),
+						TakenBy:        record.TakenBy(),
 					})
 				}
 			}
 		}
-		// we are calculating all future records that are not created in db
-		now := time.Now().UTC()
-		createdAlready := time.Date(
-			now.Year(), now.Month(), now.Day(),
-			0, 0, 0, 0, time.UTC,
-		).Add(s.createdShift)
-		var futureTimes []time.Time
+		// we are calculating all future records that are not created in db,
+		// records are generated by local days of the plan time zone
+		createdAlready := p.RecordsUntil()
+		var futureTimes []plan.Occurrence
 		if parsedStart.After(createdAlready) {
 			futureTimes = p.Schedule(
 				parsedStart,
 				parsedEnd,
 			)
 		} else {
+			// Schedule is exclusive, so the occurrence at the records horizon is included
 			futureTimes = p.Schedule(
-				createdAlready,
+				createdAlready.Add(-time.Nanosecond),
 				parsedEnd,
 			)
 		}
-		for _, t := range futureTimes {
+		for _, o := range futureTimes {
 			futureScheduleList = append(futureScheduleList, &ScheduleTime{
 				IntakeRecordID: uuid.Nil,
 				MedicationID:   p.MedicationID(),
 				MedicationName: medicationName,
-				AmountValue:    amountValue,
-				AmountUnit:     amountUnit,
+				AmountValue:    o.DosageValue,
+				AmountUnit:     o.DosageUnit,
 				Status:         StatusIntakePlanned,
-				PlannedAt:      t.UTC(),
+				PlannedAt:      o.At.UTC(),
 				TakenAt:        time.Time{},
+				TakenAmount:    0,
+				TakenBy:        uuid.Nil,
 			})
 		}
 	}
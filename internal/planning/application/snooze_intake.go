@@ -0,0 +1,118 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/FSO-VK/final-project-vk-backend/internal/planning/domain/plan"
+	"github.com/FSO-VK/final-project-vk-backend/internal/planning/domain/record"
+	"github.com/FSO-VK/final-project-vk-backend/internal/utils/validator"
+	"github.com/google/uuid"
+)
+
+// defaultSnooze is how long the reminder is postponed if duration is not set.
+const defaultSnooze = 10 * time.Minute
+
+// ErrInvalidSnooze is an error when the reminder can't be postponed for such duration.
+var ErrInvalidSnooze = errors.New("invalid snooze duration")
+
+// SnoozeIntake is an interface for postponing the reminder about intake.
+type SnoozeIntake interface {
+	Execute(
+		ctx context.Context,
+		cmd *SnoozeIntakeCommand,
+	) (*SnoozeIntakeResponse, error)
+}
+
+// SnoozeIntakeService is a service for postponing the reminder about intake.
+type SnoozeIntakeService struct {
+	recordRepo   record.Repository
+	planningRepo plan.Repository
+	validator    validator.Validator
+}
+
+// NewSnoozeIntakeService returns a new SnoozeIntakeService.
+func NewSnoozeIntakeService(
+	recordRepo record.Repository,
+	planningRepo plan.Repository,
+	valid validator.Validator,
+) *SnoozeIntakeService {
+	return &SnoozeIntakeService{
+		recordRepo:   recordRepo,
+		planningRepo: planningRepo,
+		validator:    valid,
+	}
+}
+
+// SnoozeIntakeCommand is a request to remind about the intake again after Minutes,
+// 10 minutes are used if Minutes is zero.
+type SnoozeIntakeCommand struct {
+	RecordID string `validate:"required,uuid"`
+	UserID   string `validate:"required,uuid"`
+	Minutes  int    `validate:"omitempty,gt=0"`
+}
+
+// SnoozeIntakeResponse is a response to postpone the reminder about intake.
+type SnoozeIntakeResponse struct {
+	SnoozedUntil time.Time
+}
+
+// Execute executes the SnoozeIntake command.
+func (s *SnoozeIntakeService) Execute(
+	ctx context.Context,
+	req *SnoozeIntakeCommand,
+) (*SnoozeIntakeResponse, error) {
+	valErr := s.validator.ValidateStruct(req)
+	if valErr != nil {
+		return nil, ErrValidationFail
+	}
+
+	parsedRecordID, err := uuid.Parse(req.RecordID)
+	if err != nil {
+		return nil, ErrValidationFail
+	}
+
+	parsedUser, err := uuid.Parse(req.UserID)
+	if err != nil {
+		return nil, ErrValidationFail
+	}
+
+	requestedRecord, err := s.recordRepo.GetByID(ctx, parsedRecordID)
+	if err != nil {
+		return nil, ErrNoIntakeRecord
+	}
+
+	requestedPlan, err := s.planningRepo.GetByID(ctx, requestedRecord.PlanID())
+	if err != nil {
+		return nil, ErrNoPlan
+	}
+
+	if requestedPlan.UserID() != parsedUser {
+		return nil, ErrPlanNotBelongToUser
+	}
+
+	snooze := defaultSnooze
+	if req.Minutes != 0 {
+		snooze = time.Duration(req.Minutes) * time.Minute
+	}
+	err = requestedRecord.Snooze(time.Now(), snooze)
+	switch {
+	case errors.Is(err, record.ErrRecordTaken):
+		return nil, ErrIntakeAlreadyTaken
+	case errors.Is(err, record.ErrInvalidSnooze):
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSnooze, err)
+	case err != nil:
+		return nil, err
+	}
+
+	err = s.recordRepo.UpdateByID(ctx, requestedRecord)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SnoozeIntakeResponse{
+		SnoozedUntil: requestedRecord.SnoozedUntil(),
+	}, nil
+}
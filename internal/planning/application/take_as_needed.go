@@ -0,0 +1,192 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/FSO-VK/final-project-vk-backend/internal/planning/application/medication"
+	"github.com/FSO-VK/final-project-vk-backend/internal/planning/domain/plan"
+	"github.com/FSO-VK/final-project-vk-backend/internal/planning/domain/record"
+	"github.com/FSO-VK/final-project-vk-backend/internal/utils/validator"
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrPlanNotAsNeeded is an error when ad-hoc intake is requested for scheduled plan.
+	ErrPlanNotAsNeeded = errors.New("plan is not as-needed")
+	// ErrDoseLimitExceeded is an error when intake exceeds limits of as-needed plan.
+	ErrDoseLimitExceeded = errors.New("dose limit exceeded")
+)
+
+// TakeAsNeeded is an interface for taking medication of as-needed plan.
+type TakeAsNeeded interface {
+	Execute(
+		ctx context.Context,
+		cmd *TakeAsNeededCommand,
+	) (*TakeAsNeededResponse, error)
+}
+
+// TakeAsNeededService is a service for taking medication of as-needed plan.
+type TakeAsNeededService struct {
+	recordRepo         record.Repository
+	planningRepo       plan.Repository
+	validator          validator.Validator
+	medicationProvider medication.MedicationService
+
+	// planLocks serialize takes of the same plan, so limits of the plan
+	// are checked against records saved by concurrent takes.
+	planLocks *sync.Map
+}
+
+// NewTakeAsNeededService returns a new TakeAsNeededService.
+func NewTakeAsNeededService(
+	recordRepo record.Repository,
+	planningRepo plan.Repository,
+	valid validator.Validator,
+	medicationProvider medication.MedicationService,
+) *TakeAsNeededService {
+	return &TakeAsNeededService{
+		recordRepo:         recordRepo,
+		planningRepo:       planningRepo,
+		validator:          valid,
+		medicationProvider: medicationProvider,
+		planLocks:          &sync.Map{},
+	}
+}
+
+// TakeAsNeededCommand is a request to take medication of as-needed plan.
+// Force takes medication even if limits of the plan are exceeded.
+type TakeAsNeededCommand struct {
+	PlanID      string  `validate:"required,uuid"`
+	UserID      string  `validate:"required,uuid"`
+	TakenAt     string  `validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	AmountValue float64 `validate:"omitempty,gt=0"`
+	Force       bool
+	// ActorID is an optional user who takes medication, owner of the plan if empty.
+	ActorID string `validate:"omitempty,uuid"`
+}
+
+// TakeAsNeededResponse is a response to take medication of as-needed plan.
+type TakeAsNeededResponse struct {
+	IntakeRecordID uuid.UUID
+	// Warnings describe exceeded limits of forced intake.
+	Warnings []string
+}
+
+// Execute creates ad-hoc intake record taken by user.
+func (s *TakeAsNeededService) Execute(
+	ctx context.Context,
+	req *TakeAsNeededCommand,
+) (*TakeAsNeededResponse, error) {
+	valErr := s.validator.ValidateStruct(req)
+	if valErr != nil {
+		return nil, ErrValidationFail
+	}
+
+	parsedPlanID, err := uuid.Parse(req.PlanID)
+	if err != nil {
+		return nil, ErrValidationFail
+	}
+	parsedUser, err := uuid.Parse(req.UserID)
+	if err != nil {
+		return nil, ErrValidationFail
+	}
+	takenBy, err := parseActor(req.ActorID, parsedUser)
+	if err != nil {
+		return nil, ErrValidationFail
+	}
+
+	takenAt := time.Now()
+	if req.TakenAt != "" {
+		takenAt, err = time.Parse(time.RFC3339, req.TakenAt)
+		if err != nil {
+			return nil, ErrValidationFail
+		}
+	}
+
+	requestedPlan, err := s.planningRepo.GetByID(ctx, parsedPlanID)
+	if err != nil {
+		return nil, ErrNoPlan
+	}
+	if requestedPlan.UserID() != parsedUser {
+		return nil, ErrPlanNotBelongToUser
+	}
+	if !requestedPlan.IsAsNeeded() {
+		return nil, ErrPlanNotAsNeeded
+	}
+
+	amount := req.AmountValue
+	if amount == 0 {
+		amount, _ = requestedPlan.Dosage()
+	}
+	if err := record.ValidateTake(takenAt, amount); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrValidationFail, err)
+	}
+
+	unlock := s.lockPlan(requestedPlan.ID())
+	defer unlock()
+
+	// plan without records has no taken intakes yet
+	records, _ := s.recordRepo.GetByPlanID(ctx, requestedPlan.ID())
+	taken := make([]time.Time, 0, len(records))
+	for _, r := range records {
+		if r.IsTaken() {
+			taken = append(taken, r.TakenAt())
+		}
+	}
+
+	var warnings []string
+	err = requestedPlan.CheckAsNeededTake(takenAt, taken)
+	switch {
+	case errors.Is(err, plan.ErrMinIntervalViolated), errors.Is(err, plan.ErrMaxDosesExceeded):
+		if !req.Force {
+			return nil, fmt.Errorf("%w: %w", ErrDoseLimitExceeded, err)
+		}
+		warnings = append(warnings, err.Error())
+	case err != nil:
+		return nil, fmt.Errorf("%w: %w", ErrValidationFail, err)
+	}
+
+	adHocRecord, err := requestedPlan.NewAsNeededRecord(takenAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create intake record: %w", err)
+	}
+
+	takeID, stockWarnings, err := takeDose(
+		ctx,
+		s.medicationProvider,
+		requestedPlan,
+		adHocRecord.NextTakeID(amount),
+		amount,
+		takenBy,
+	)
+	if err != nil {
+		return nil, err
+	}
+	warnings = append(warnings, stockWarnings...)
+
+	if _, err := adHocRecord.MarkTaken(takenAt, amount, takeID, takenBy); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrValidationFail, err)
+	}
+	if err := s.recordRepo.Save(ctx, adHocRecord); err != nil {
+		// compensate the stock change, the take is not saved
+		_ = restoreDose(ctx, s.medicationProvider, requestedPlan, adHocRecord, takenBy)
+		return nil, err
+	}
+
+	return &TakeAsNeededResponse{
+		IntakeRecordID: adHocRecord.ID(),
+		Warnings:       warnings,
+	}, nil
+}
+
+// lockPlan locks takes of the plan and returns the function to unlock them.
+func (s *TakeAsNeededService) lockPlan(planID uuid.UUID) func() {
+	lock, _ := s.planLocks.LoadOrStore(planID, &sync.Mutex{})
+	mu, _ := lock.(*sync.Mutex)
+	mu.Lock()
+	return mu.Unlock
+}
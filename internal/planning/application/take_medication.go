@@ -3,7 +3,11 @@ package application
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"time"
 
+	"github.com/FSO-VK/final-project-vk-backend/internal/planning/application/medication"
 	"github.com/FSO-VK/final-project-vk-backend/internal/planning/domain/plan"
 	"github.com/FSO-VK/final-project-vk-backend/internal/planning/domain/record"
 	"github.com/FSO-VK/final-project-vk-backend/internal/utils/validator"
@@ -20,9 +24,10 @@ type TakeMedication interface {
 
 // TakeMedicationService is a service for making a medication taken.
 type TakeMedicationService struct {
-	recordRepo   record.Repository
-	planningRepo plan.Repository
-	validator    validator.Validator
+	recordRepo         record.Repository
+	planningRepo       plan.Repository
+	validator          validator.Validator
+	medicationProvider medication.MedicationService
 }
 
 // NewTakeMedicationService returns a new TakeMedicationService.
@@ -30,11 +35,13 @@ func NewTakeMedicationService(
 	recordRepo record.Repository,
 	planningRepo plan.Repository,
 	valid validator.Validator,
+	medicationProvider medication.MedicationService,
 ) *TakeMedicationService {
 	return &TakeMedicationService{
-		recordRepo:   recordRepo,
-		planningRepo: planningRepo,
-		validator:    valid,
+		recordRepo:         recordRepo,
+		planningRepo:       planningRepo,
+		validator:          valid,
+		medicationProvider: medicationProvider,
 	}
 }
 
@@ -42,10 +49,20 @@ func NewTakeMedicationService(
 type TakeMedicationCommand struct {
 	RecordID string `validate:"required,uuid"`
 	UserID   string `validate:"required,uuid"`
+	// TakenAt is an optional actual intake time, current time is used if empty.
+	TakenAt string `validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	// AmountValue is an optional actually taken dose, plan dosage is used if zero.
+	AmountValue float64 `validate:"omitempty,gt=0"`
+	// ActorID is an optional user who takes medication, owner of the plan if empty.
+	// It differs from UserID when caregiver takes for the user.
+	ActorID string `validate:"omitempty,uuid"`
 }
 
 // TakeMedicationResponse is a response to make medication taken.
-type TakeMedicationResponse struct{}
+type TakeMedicationResponse struct {
+	// Warnings tell that the take is logged but stock is not changed.
+	Warnings []string
+}
 
 // Execute executes the TakeMedication command.
 func (s *TakeMedicationService) Execute(
@@ -67,6 +84,19 @@ func (s *TakeMedicationService) Execute(
 		return nil, ErrValidationFail
 	}
 
+	takenBy, err := parseActor(req.ActorID, parsedUser)
+	if err != nil {
+		return nil, ErrValidationFail
+	}
+
+	takenAt := time.Now()
+	if req.TakenAt != "" {
+		takenAt, err = time.Parse(time.RFC3339, req.TakenAt)
+		if err != nil {
+			return nil, ErrValidationFail
+		}
+	}
+
 	requestedRecord, err := s.recordRepo.GetByID(ctx, parsedRecordID)
 	if err != nil {
 		return nil, ErrNoIntakeRecord
@@ -81,12 +111,167 @@ func (s *TakeMedicationService) Execute(
 		return nil, ErrPlanNotBelongToUser
 	}
 
-	requestedRecord.MarkTaken(requestedRecord.PlannedTime())
+	amount := req.AmountValue
+	if amount == 0 {
+		amount = plannedAmount(requestedPlan, requestedRecord)
+	}
 
-	err = s.recordRepo.UpdateByID(ctx, requestedRecord)
+	warnings, err := takeRecord(
+		ctx,
+		s.recordRepo,
+		s.medicationProvider,
+		requestedPlan,
+		requestedRecord,
+		takenAt,
+		amount,
+		takenBy,
+	)
 	if err != nil {
 		return nil, err
 	}
 
-	return &TakeMedicationResponse{}, nil
+	return &TakeMedicationResponse{
+		Warnings: warnings,
+	}, nil
+}
+
+// takeRecord marks the record taken and keeps medication stock consistent with it.
+// Stock is decreased when the record becomes taken, retiming of already taken
+// record leaves stock as is, and changing the taken amount re-takes the dose.
+// Stock changes are keyed by the take id derived from the record,
+// so retries and double submits of the same take change stock once.
+// The take is logged even if stock is too low, warnings tell that stock is not changed.
+func takeRecord(
+	ctx context.Context,
+	recordRepo record.Repository,
+	medicationProvider medication.MedicationService,
+	p *plan.Plan,
+	r *record.IntakeRecord,
+	takenAt time.Time,
+	amount float64,
+	takenBy uuid.UUID,
+) ([]string, error) {
+	if err := record.ValidateTake(takenAt, amount); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrValidationFail, err)
+	}
+
+	var warnings []string
+	takeID := r.TakeID()
+	if !r.IsTaken() || r.TakenAmount() != amount {
+		// the new dose is taken before the previous one is restored,
+		// so a failure of the first call leaves stock as it was
+		var err error
+		takeID, warnings, err = takeDose(
+			ctx,
+			medicationProvider,
+			p,
+			r.NextTakeID(amount),
+			amount,
+			takenBy,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if err := restoreDose(ctx, medicationProvider, p, r, takenBy); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := r.MarkTaken(takenAt, amount, takeID, takenBy); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrValidationFail, err)
+	}
+
+	// the stock change is not compensated if the take is not saved,
+	// the retried take gets the same id and completes it
+	if err := recordRepo.UpdateByID(ctx, r); err != nil {
+		return nil, err
+	}
+	return warnings, nil
+}
+
+// takeDose decreases medication stock by the take of the actor. If stock is too low,
+// the take is logged anyway without stock, it gets nil id and a warning.
+func takeDose(
+	ctx context.Context,
+	medicationProvider medication.MedicationService,
+	p *plan.Plan,
+	takeID uuid.UUID,
+	amount float64,
+	actorID uuid.UUID,
+) (uuid.UUID, []string, error) {
+	_, unit := p.Dosage()
+	err := medicationProvider.TakeDose(
+		ctx,
+		p.MedicationID(),
+		p.UserID(),
+		actorID,
+		medication.Dose{Value: amount, Unit: unit},
+		takeIdempotencyKey(takeID),
+	)
+	switch {
+	case errors.Is(err, medication.ErrNotEnoughMedication):
+		return uuid.Nil, []string{err.Error()}, nil
+	case errors.Is(err, medication.ErrIdempotencyKeyReused):
+		return uuid.Nil, nil, fmt.Errorf("%w: %w", ErrTakeConflict, err)
+	case err != nil:
+		return uuid.Nil, nil, fmt.Errorf("%w: %w", ErrStockNotUpdated, err)
+	}
+	return takeID, nil, nil
+}
+
+// restoreDose compensates the stock decrease made by the current take of the record,
+// the actor is the user who cancels or changes the take.
+func restoreDose(
+	ctx context.Context,
+	medicationProvider medication.MedicationService,
+	p *plan.Plan,
+	r *record.IntakeRecord,
+	actorID uuid.UUID,
+) error {
+	if !r.IsTaken() || r.TakeID() == uuid.Nil {
+		return nil
+	}
+	_, unit := p.Dosage()
+	err := medicationProvider.RestoreDose(
+		ctx,
+		p.MedicationID(),
+		p.UserID(),
+		actorID,
+		medication.Dose{Value: r.TakenAmount(), Unit: unit},
+		restoreIdempotencyKey(r.TakeID()),
+	)
+	switch {
+	case errors.Is(err, medication.ErrIdempotencyKeyReused):
+		return fmt.Errorf("%w: %w", ErrTakeConflict, err)
+	case err != nil:
+		return fmt.Errorf("%w: %w", ErrStockNotUpdated, err)
+	}
+	return nil
+}
+
+// plannedAmount returns dosage of the record by plan,
+// records created before phased plans have no own dosage.
+func plannedAmount(p *plan.Plan, r *record.IntakeRecord) float64 {
+	if r.PlannedAmount() > 0 {
+		return r.PlannedAmount()
+	}
+	value, _ := p.DosageAt(r.PlannedTime())
+	return value
+}
+
+// parseActor returns the user who performs the action,
+// the owner acts on their own behalf if actor is empty.
+func parseActor(actorID string, owner uuid.UUID) (uuid.UUID, error) {
+	if actorID == "" {
+		return owner, nil
+	}
+	return uuid.Parse(actorID)
+}
+
+func takeIdempotencyKey(takeID uuid.UUID) string {
+	return "take-" + takeID.String()
+}
+
+func restoreIdempotencyKey(takeID uuid.UUID) string {
+	return "restore-" + takeID.String()
 }
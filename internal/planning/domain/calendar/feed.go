@@ -0,0 +1,55 @@
+// Package calendar is subdomain for calendar feeds of planned intakes.
+package calendar
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// tokenBytes is a length of random part of the feed token.
+const tokenBytes = 32
+
+// ErrGenToken tells that secret token of the feed can't be generated.
+var ErrGenToken = errors.New("cannot generate feed token")
+
+// Feed is an aggregate that represents a secret subscription of the user
+// to the calendar of intakes. Anyone with the token can read the calendar,
+// so the token is rotated or revoked by the user.
+type Feed struct {
+	userID    uuid.UUID
+	token     string
+	createdAt time.Time
+}
+
+// NewFeed creates a feed of the user with a new random token.
+func NewFeed(userID uuid.UUID, createdAt time.Time) (*Feed, error) {
+	b := make([]byte, tokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrGenToken, err)
+	}
+	return &Feed{
+		userID:    userID,
+		token:     base64.RawURLEncoding.EncodeToString(b),
+		createdAt: createdAt,
+	}, nil
+}
+
+// UserID returns the owner of the feed.
+func (f *Feed) UserID() uuid.UUID {
+	return f.userID
+}
+
+// Token returns the secret token of the feed.
+func (f *Feed) Token() string {
+	return f.token
+}
+
+// CreatedAt returns the time the token was issued.
+func (f *Feed) CreatedAt() time.Time {
+	return f.createdAt
+}
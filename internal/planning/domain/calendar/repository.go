@@ -0,0 +1,20 @@
+package calendar
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// ErrNoFeedFound is an error when a feed is not found.
+var ErrNoFeedFound = errors.New("calendar feed not found")
+
+// Repository is a domain service interface for repository.
+type Repository interface {
+	GetByToken(ctx context.Context, token string) (*Feed, error)
+	// Save stores the feed replacing the previous feed of the user,
+	// so the previous token stops working.
+	Save(ctx context.Context, feed *Feed) error
+	DeleteByUserID(ctx context.Context, userID uuid.UUID) error
+}
@@ -0,0 +1,108 @@
+package plan
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+const (
+	maxEscalationSteps = 5
+	maxEscalationDelay = 24 * time.Hour
+)
+
+// ErrInvalidEscalation tells that escalation policy is invalid.
+var ErrInvalidEscalation = errors.New("invalid escalation policy")
+
+// EscalationTarget is who is notified when the intake is still not taken.
+type EscalationTarget string
+
+const (
+	// EscalateToUser re-notifies the user of the plan.
+	EscalateToUser EscalationTarget = "user"
+	// EscalateToCaregivers notifies caregivers linked to the user.
+	EscalateToCaregivers EscalationTarget = "caregivers"
+)
+
+// EscalationStep notifies the target when the intake is not taken
+// After the reminder is sent.
+type EscalationStep struct {
+	After  time.Duration
+	Target EscalationTarget
+}
+
+// EscalationPolicy is a VO with steps ordered by delay,
+// empty policy means missed intakes are not escalated.
+type EscalationPolicy struct {
+	steps []EscalationStep
+}
+
+// NewEscalationPolicy creates validated escalation policy.
+// Delays must be positive, not longer than a day and strictly increasing.
+func NewEscalationPolicy(steps []EscalationStep) (EscalationPolicy, error) {
+	if len(steps) > maxEscalationSteps {
+		return EscalationPolicy{}, fmt.Errorf(
+			"%w: more than %d steps", ErrInvalidEscalation, maxEscalationSteps,
+		)
+	}
+	for i, step := range steps {
+		if step.Target != EscalateToUser && step.Target != EscalateToCaregivers {
+			return EscalationPolicy{}, fmt.Errorf(
+				"%w: unknown target %q", ErrInvalidEscalation, step.Target,
+			)
+		}
+		if step.After <= 0 || step.After > maxEscalationDelay {
+			return EscalationPolicy{}, fmt.Errorf(
+				"%w: delay must be in (0, %s]", ErrInvalidEscalation, maxEscalationDelay,
+			)
+		}
+		if i > 0 && step.After <= steps[i-1].After {
+			return EscalationPolicy{}, fmt.Errorf(
+				"%w: delays must increase", ErrInvalidEscalation,
+			)
+		}
+	}
+	return EscalationPolicy{steps: slices.Clone(steps)}, nil
+}
+
+// Steps returns steps of the policy.
+func (e EscalationPolicy) Steps() []EscalationStep {
+	return slices.Clone(e.steps)
+}
+
+// IsEmpty tells that the policy has no steps.
+func (e EscalationPolicy) IsEmpty() bool {
+	return len(e.steps) == 0
+}
+
+// Due returns steps to fire at now for the intake reminded at remindedAt
+// which has fired steps already. Steps overdue together are returned at once.
+func (e EscalationPolicy) Due(remindedAt time.Time, fired int, now time.Time) []EscalationStep {
+	if remindedAt.IsZero() || fired >= len(e.steps) {
+		return nil
+	}
+	due := fired
+	for due < len(e.steps) && !remindedAt.Add(e.steps[due].After).After(now) {
+		due++
+	}
+	return slices.Clone(e.steps[fired:due])
+}
+
+// Escalation returns escalation policy of the plan.
+func (p *Plan) Escalation() EscalationPolicy {
+	return p.escalation
+}
+
+// SetEscalation replaces escalation policy of the plan.
+// As-needed plan has no reminders, so it can't be escalated.
+func (p *Plan) SetEscalation(e EscalationPolicy) error {
+	if p.status != StatusActive {
+		return ErrFinishedPlan
+	}
+	if p.kind == KindAsNeeded && !e.IsEmpty() {
+		return fmt.Errorf("%w: as-needed plan has no reminders", ErrInvalidEscalation)
+	}
+	p.escalation = e
+	return nil
+}
@@ -0,0 +1,96 @@
+package plan_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/FSO-VK/final-project-vk-backend/internal/planning/domain/plan"
+)
+
+func TestEscalationPolicy_Due(t *testing.T) {
+	t.Parallel()
+	remindedAt := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	steps := []plan.EscalationStep{
+		{After: 30 * time.Minute, Target: plan.EscalateToUser},
+		{After: 60 * time.Minute, Target: plan.EscalateToCaregivers},
+	}
+	tests := []struct {
+		name string // description of this test case
+		// Named input parameters for target function.
+		steps []plan.EscalationStep
+		fired int
+		now   time.Time
+		// want is targets of due steps.
+		want    []plan.EscalationTarget
+		wantErr error
+	}{
+		{
+			name:  "Should not escalate before delay",
+			steps: steps,
+			now:   remindedAt.Add(29 * time.Minute),
+			want:  nil,
+		},
+		{
+			name:  "Should re-notify user after first delay",
+			steps: steps,
+			now:   remindedAt.Add(30 * time.Minute),
+			want:  []plan.EscalationTarget{plan.EscalateToUser},
+		},
+		{
+			name:  "Should notify caregivers after fired re-notification",
+			steps: steps,
+			fired: 1,
+			now:   remindedAt.Add(61 * time.Minute),
+			want:  []plan.EscalationTarget{plan.EscalateToCaregivers},
+		},
+		{
+			name:  "Should fire overdue steps at once",
+			steps: steps,
+			now:   remindedAt.Add(2 * time.Hour),
+			want:  []plan.EscalationTarget{plan.EscalateToUser, plan.EscalateToCaregivers},
+		},
+		{
+			name:  "Should not fire steps twice",
+			steps: steps,
+			fired: 2,
+			now:   remindedAt.Add(2 * time.Hour),
+			want:  nil,
+		},
+		{
+			name: "Should reject decreasing delays",
+			steps: []plan.EscalationStep{
+				{After: time.Hour, Target: plan.EscalateToUser},
+				{After: 30 * time.Minute, Target: plan.EscalateToCaregivers},
+			},
+			wantErr: plan.ErrInvalidEscalation,
+		},
+		{
+			name:    "Should reject unknown target",
+			steps:   []plan.EscalationStep{{After: time.Hour, Target: "neighbours"}},
+			wantErr: plan.ErrInvalidEscalation,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			policy, err := plan.NewEscalationPolicy(tt.steps)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewEscalationPolicy() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			got := policy.Due(remindedAt, tt.fired, tt.now)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Due() returned %d steps, want %d", len(got), len(tt.want))
+			}
+			for i, step := range got {
+				if step.Target != tt.want[i] {
+					t.Errorf("Due()[%d].Target = %v, want %v", i, step.Target, tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package plan
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+var (
+	// ErrNoOccurrence tells that excluded time is not an intake of the plan.
+	ErrNoOccurrence = errors.New("no intake at excluded time")
+	// ErrDuplicateOccurrence tells that added one-off intake is already scheduled.
+	ErrDuplicateOccurrence = errors.New("intake is already scheduled")
+)
+
+// AddExceptions excludes intakes at exDates and adds one-off intakes at rDates.
+// Excluding a one-off intake removes it. Every date must be in the course
+// (in a phase for phased plan), one-off intake takes dosage of its phase.
+// On error the plan is not changed.
+func (p *Plan) AddExceptions(exDates, rDates []time.Time) error {
+	if p.status != StatusActive {
+		return ErrFinishedPlan
+	}
+	if p.kind == KindAsNeeded {
+		return fmt.Errorf("%w: as-needed plan has no schedule", ErrOutOfCourse)
+	}
+
+	// changes are applied to copies and committed when all dates are valid
+	schedules := make([]schedule, 0, len(p.phases)+1)
+	if len(p.phases) == 0 {
+		schedules = append(schedules, p.schedule.clone())
+	}
+	for _, ph := range p.phases {
+		schedules = append(schedules, ph.schedule.clone())
+	}
+	scheduleAt := func(t time.Time) (*schedule, error) {
+		for i := range schedules {
+			if schedules[i].covers(t) {
+				return &schedules[i], nil
+			}
+		}
+		return nil, fmt.Errorf("%w: %s", ErrOutOfCourse, t.Format(time.RFC3339))
+	}
+
+	for _, ex := range exDates {
+		s, err := scheduleAt(ex)
+		if err != nil {
+			return err
+		}
+		if !s.has(ex) {
+			return fmt.Errorf("%w: %s", ErrNoOccurrence, ex.Format(time.RFC3339))
+		}
+		s.exclude(ex.In(p.location))
+	}
+	for _, r := range rDates {
+		s, err := scheduleAt(r)
+		if err != nil {
+			return err
+		}
+		if s.has(r) {
+			return fmt.Errorf("%w: %s", ErrDuplicateOccurrence, r.Format(time.RFC3339))
+		}
+		s.include(r.In(p.location))
+	}
+
+	if len(p.phases) == 0 {
+		p.schedule = schedules[0]
+		return nil
+	}
+	for i := range p.phases {
+		p.phases[i].schedule = schedules[i]
+	}
+	return nil
+}
+
+// Exceptions returns excluded intakes and one-off intakes of the plan.
+func (p *Plan) Exceptions() ([]time.Time, []time.Time) {
+	exDates := slices.Clone(p.schedule.exDates)
+	rDates := slices.Clone(p.schedule.rDates)
+	for _, ph := range p.phases {
+		exDates = append(exDates, ph.schedule.exDates...)
+		rDates = append(rDates, ph.schedule.rDates...)
+	}
+	slices.SortFunc(exDates, time.Time.Compare)
+	return exDates, rDates
+}
+
+func (s schedule) clone() schedule {
+	s.exDates = slices.Clone(s.exDates)
+	s.rDates = slices.Clone(s.rDates)
+	return s
+}
+
+// exclude skips the occurrence at t, one-off occurrence is removed.
+func (s *schedule) exclude(t time.Time) {
+	if i := slices.IndexFunc(s.rDates, t.Equal); i >= 0 {
+		s.rDates = slices.Delete(s.rDates, i, i+1)
+		return
+	}
+	s.exDates = append(s.exDates, t)
+}
+
+// include adds one-off occurrence at t keeping one-off dates sorted,
+// previously excluded occurrence at t is restored.
+func (s *schedule) include(t time.Time) {
+	if i := slices.IndexFunc(s.exDates, t.Equal); i >= 0 {
+		s.exDates = slices.Delete(s.exDates, i, i+1)
+		return
+	}
+	i, _ := slices.BinarySearchFunc(s.rDates, t, time.Time.Compare)
+	s.rDates = slices.Insert(s.rDates, i, t)
+}
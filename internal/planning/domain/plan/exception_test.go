@@ -0,0 +1,114 @@
+package plan_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/FSO-VK/final-project-vk-backend/internal/planning/domain/plan"
+	"github.com/google/uuid"
+	"github.com/teambition/rrule-go"
+)
+
+func TestPlan_AddExceptions(t *testing.T) {
+	t.Parallel()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	day := func(n, hour int) time.Time {
+		return start.AddDate(0, 0, n).Add(time.Duration(hour) * time.Hour)
+	}
+	tests := []struct {
+		name string // description of this test case
+		// Named input parameters for target function.
+		exDates []time.Time
+		rDates  []time.Time
+		want    []time.Time
+		wantErr error
+	}{
+		{
+			name:    "Should skip excluded intake",
+			exDates: []time.Time{day(1, 9)},
+			want:    []time.Time{day(0, 9), day(2, 9), day(3, 9)},
+			wantErr: nil,
+		},
+		{
+			name:    "Should move intake to another time",
+			exDates: []time.Time{day(1, 9)},
+			rDates:  []time.Time{day(1, 14)},
+			want:    []time.Time{day(0, 9), day(1, 14), day(2, 9), day(3, 9)},
+			wantErr: nil,
+		},
+		{
+			name:    "Should restore excluded intake",
+			exDates: []time.Time{day(2, 9)},
+			rDates:  []time.Time{day(2, 9)},
+			want:    []time.Time{day(0, 9), day(1, 9), day(2, 9), day(3, 9)},
+			wantErr: nil,
+		},
+		{
+			name:    "Should reject excluding time without intake",
+			exDates: []time.Time{day(1, 10)},
+			wantErr: plan.ErrNoOccurrence,
+		},
+		{
+			name:    "Should reject one-off intake at scheduled time",
+			rDates:  []time.Time{day(1, 9)},
+			wantErr: plan.ErrDuplicateOccurrence,
+		},
+		{
+			name:    "Should reject one-off intake out of course",
+			rDates:  []time.Time{day(5, 9)},
+			wantErr: plan.ErrOutOfCourse,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			dosage, err := plan.NewDosage(1, "шт.")
+			if err != nil {
+				t.Fatalf("arrange failed: %v", err)
+			}
+			rule, err := rrule.NewRRule(rrule.ROption{Dtstart: day(0, 9), Freq: rrule.DAILY})
+			if err != nil {
+				t.Fatalf("arrange failed: %v", err)
+			}
+			s, err := plan.NewSchedule(start, day(4, 0), []*rrule.RRule{rule})
+			if err != nil {
+				t.Fatalf("arrange failed: %v", err)
+			}
+			p, err := plan.NewPlan(
+				uuid.New(),
+				uuid.New(),
+				uuid.New(),
+				dosage,
+				s,
+				nil,
+				"",
+				start,
+				start,
+			)
+			if err != nil {
+				t.Fatalf("arrange failed: %v", err)
+			}
+
+			err = p.AddExceptions(tt.exDates, tt.rDates)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("AddExceptions() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if exDates, rDates := p.Exceptions(); len(exDates) != 0 || len(rDates) != 0 {
+					t.Errorf("AddExceptions() changed plan on error: %v, %v", exDates, rDates)
+				}
+				return
+			}
+			got := p.Schedule(start, day(4, 0))
+			if len(got) != len(tt.want) {
+				t.Fatalf("Schedule() returned %d occurrences, want %d", len(got), len(tt.want))
+			}
+			for i, o := range got {
+				if !o.At.Equal(tt.want[i]) {
+					t.Errorf("Schedule()[%d] = %v, want %v", i, o.At, tt.want[i])
+				}
+			}
+		})
+	}
+}
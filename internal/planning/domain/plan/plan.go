@@ -17,6 +17,14 @@ var (
 	ErrCourseRange = errors.New("course ends before it starts")
 	// ErrFinishedPlan tells that plan is already finished and can;t be mutated.
 	ErrFinishedPlan = errors.New("can't modify finished plan")
+	// ErrNotAsNeeded tells that plan has a schedule and ad-hoc intakes are not allowed.
+	ErrNotAsNeeded = errors.New("plan is not as-needed")
+	// ErrOutOfCourse tells that intake time is out of the course range.
+	ErrOutOfCourse = errors.New("intake is out of course range")
+	// ErrMinIntervalViolated tells that intake is too close to the previous one.
+	ErrMinIntervalViolated = errors.New("intake is too close to the previous one")
+	// ErrMaxDosesExceeded tells that intake exceeds max doses per window.
+	ErrMaxDosesExceeded = errors.New("max doses per window exceeded")
 )
 
 // Plan is an aggregate that represents a plan for medication intake.
@@ -27,22 +35,37 @@ type Plan struct {
 	// dosage is an amount of medication intake per one take.
 	dosage dosage
 	status Status
-	// schedule contains the schedule of the plan.
+	kind   Kind
+	// schedule contains the schedule of the plan,
+	// as-needed plan has only course range without rules.
 	schedule schedule
+	// limits restrict intakes of as-needed plan.
+	limits asNeededLimits
+	// phases split the course into periods with different dosages,
+	// plan with a single dosage has no phases.
+	phases []Phase
+	// location is a time zone of the user, it defines local days of the plan.
+	location *time.Location
+	// recordsUntil is an end of the range intake records are generated for.
+	recordsUntil time.Time
 	// condition is a description of the condition
 	// under which the medication should be taken.
 	condition string
-	createdAt time.Time
-	updatedAt time.Time
+	// escalation defines notifications when the intake is not taken after reminder.
+	escalation EscalationPolicy
+	createdAt  time.Time
+	updatedAt  time.Time
 }
 
 // NewPlan creates validated plan.
+// Location is a time zone of the user, nil means UTC.
 func NewPlan(
 	id uuid.UUID,
 	medicationID uuid.UUID,
 	userID uuid.UUID,
 	dosage dosage,
 	schedule schedule,
+	location *time.Location,
 	condition string,
 	createdAt time.Time,
 	updatedAt time.Time,
@@ -54,6 +77,84 @@ func NewPlan(
 		dosage:       dosage,
 		schedule:     schedule,
 		status:       StatusActive,
+		kind:         KindScheduled,
+		limits:       asNeededLimits{},
+		phases:       nil,
+		location:     locationOrUTC(location),
+		condition:    condition,
+		createdAt:    createdAt,
+		updatedAt:    updatedAt,
+	}, nil
+}
+
+// NewPhasedPlan creates validated plan which dosage changes over time (taper, titration).
+// Phases must be ordered by time, must not overlap and must share the dosage unit.
+func NewPhasedPlan(
+	id uuid.UUID,
+	medicationID uuid.UUID,
+	userID uuid.UUID,
+	phases []Phase,
+	location *time.Location,
+	condition string,
+	createdAt time.Time,
+	updatedAt time.Time,
+) (*Plan, error) {
+	validPhases, err := newPhases(phases)
+	if err != nil {
+		return nil, err
+	}
+	first, last := validPhases[0], validPhases[len(validPhases)-1]
+	courseRange, err := NewSchedule(first.schedule.start, last.schedule.end, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrCourseRange, err)
+	}
+	return &Plan{
+		id:           id,
+		medicationID: medicationID,
+		userID:       userID,
+		dosage:       first.dosage,
+		schedule:     courseRange,
+		status:       StatusActive,
+		kind:         KindScheduled,
+		limits:       asNeededLimits{},
+		phases:       validPhases,
+		location:     locationOrUTC(location),
+		condition:    condition,
+		createdAt:    createdAt,
+		updatedAt:    updatedAt,
+	}, nil
+}
+
+// NewAsNeededPlan creates validated plan without schedule,
+// medication is taken when needed within the limits.
+func NewAsNeededPlan(
+	id uuid.UUID,
+	medicationID uuid.UUID,
+	userID uuid.UUID,
+	dosage dosage,
+	start time.Time,
+	end time.Time,
+	limits asNeededLimits,
+	location *time.Location,
+	condition string,
+	createdAt time.Time,
+	updatedAt time.Time,
+) (*Plan, error) {
+	courseRange, err := NewSchedule(start, end, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrCourseRange, err)
+	}
+	return &Plan{
+		id:           id,
+		medicationID: medicationID,
+		userID:       userID,
+		dosage:       dosage,
+		schedule:     courseRange,
+		status:       StatusActive,
+		kind:         KindAsNeeded,
+		limits:       limits,
+		phases:       nil,
+		location:     locationOrUTC(location),
 		condition:    condition,
 		createdAt:    createdAt,
 		updatedAt:    updatedAt,
@@ -88,28 +189,129 @@ func (p *Plan) Deactivate() (*Plan, error) {
 	return p, nil
 }
 
-// Schedule returns the schedule of the plan in range [from, to].
+// Occurrence is a planned intake of the plan with its dosage.
+type Occurrence struct {
+	At          time.Time
+	DosageValue float64
+	DosageUnit  string
+}
+
+// Schedule returns the schedule of the plan in range [from, to]
+// with dosage of each occurrence.
 // If there is no records in the range, it returns nil.
-func (p *Plan) Schedule(from, to time.Time) []time.Time {
+func (p *Plan) Schedule(from, to time.Time) []Occurrence {
 	if from.After(to) {
 		return nil
 	}
-	var schedule []time.Time
-	for t := p.schedule.Next(from); t.Before(to) && !t.IsZero(); t = p.schedule.Next(t) {
-		schedule = append(schedule, t)
+	if len(p.phases) == 0 {
+		return occurrences(&p.schedule, p.dosage, from, to)
+	}
+
+	var schedule []Occurrence
+	for _, ph := range p.phases {
+		if !ph.schedule.start.Before(to) || !ph.schedule.end.After(from) {
+			continue
+		}
+		phaseFrom := from
+		if ph.schedule.start.After(phaseFrom) {
+			// Next is exclusive, so the phase start itself is included
+			phaseFrom = ph.schedule.start.Add(-time.Nanosecond)
+		}
+		phaseTo := to
+		if ph.schedule.end.Before(phaseTo) {
+			phaseTo = ph.schedule.end
+		}
+		schedule = append(schedule, occurrences(&ph.schedule, ph.dosage, phaseFrom, phaseTo)...)
+	}
+	return schedule
+}
+
+func occurrences(s *schedule, d dosage, from, to time.Time) []Occurrence {
+	var schedule []Occurrence
+	for t := s.Next(from); t.Before(to) && !t.IsZero(); t = s.Next(t) {
+		schedule = append(schedule, Occurrence{
+			At:          t,
+			DosageValue: d.value,
+			DosageUnit:  d.unit,
+		})
 	}
 	return schedule
 }
 
+// DosageAt returns the dosage of the phase active at time t.
+// Plan without phases has the same dosage for the whole course.
+func (p *Plan) DosageAt(t time.Time) (float64, string) {
+	if ph, ok := p.phaseAt(t); ok {
+		return ph.dosage.value, ph.dosage.unit
+	}
+	return p.dosage.value, p.dosage.unit
+}
+
+func (p *Plan) phaseAt(t time.Time) (Phase, bool) {
+	for _, ph := range p.phases {
+		if !t.Before(ph.schedule.start) && t.Before(ph.schedule.end) {
+			return ph, true
+		}
+	}
+	return Phase{}, false
+}
+
+// Phases returns the phases of the plan, it is empty for plan with a single dosage.
+func (p *Plan) Phases() []PhaseInfo {
+	phases := make([]PhaseInfo, 0, len(p.phases))
+	for _, ph := range p.phases {
+		phases = append(phases, ph.info())
+	}
+	return phases
+}
+
+// CurrentPhase returns index of the phase active at time t.
+// It returns false if the plan has no phases or t is between or out of them.
+func (p *Plan) CurrentPhase(t time.Time) (int, bool) {
+	for i, ph := range p.phases {
+		if !t.Before(ph.schedule.start) && t.Before(ph.schedule.end) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// Recurrence is a read-only view of a recurrence rule of the plan with its dosage.
+type Recurrence struct {
+	// Start is the first intake of the rule in the plan time zone.
+	Start time.Time
+	// Rule is a value of RRULE property in iCalendar RFC 5545 format.
+	Rule string
+	// ExDates are skipped occurrences of the rule.
+	ExDates []time.Time
+	// RDates are one-off intakes of the same dosage.
+	RDates      []time.Time
+	DosageValue float64
+	DosageUnit  string
+}
+
+// Recurrences returns recurrence rules of all phases of the plan,
+// as-needed plan has no recurrences.
+func (p *Plan) Recurrences() []Recurrence {
+	if len(p.phases) == 0 {
+		return p.schedule.recurrences(p.dosage)
+	}
+	var recurrences []Recurrence
+	for _, ph := range p.phases {
+		recurrences = append(recurrences, ph.schedule.recurrences(ph.dosage)...)
+	}
+	return recurrences
+}
+
 // GenerateIntakeRecords is a factory for intake records related to the plan.
 func (p *Plan) GenerateIntakeRecords(from, to time.Time) ([]*intake.IntakeRecord, error) {
 	records := make([]*intake.IntakeRecord, 0)
-	times := p.Schedule(from, to)
-	for _, t := range times {
+	for _, o := range p.Schedule(from, to) {
 		record, err := intake.NewIntakeRecord(
 			uuid.New(),
 			p.id,
-			t,
+			o.At,
+			o.DosageValue,
 			time.Now(),
 			time.Now(),
 		)
@@ -122,6 +324,83 @@ func (p *Plan) GenerateIntakeRecords(from, to time.Time) ([]*intake.IntakeRecord
 	return records, nil
 }
 
+// GenerateDueRecords generates intake records of the plan up to the records horizon,
+// which is a local midnight of now shifted by shift. Records that are already
+// generated are skipped, so it returns nil until the next local day begins.
+func (p *Plan) GenerateDueRecords(
+	now time.Time,
+	shift time.Duration,
+) ([]*intake.IntakeRecord, error) {
+	to := p.RecordsHorizon(now, shift)
+	if !p.recordsUntil.Before(to) {
+		return nil, nil
+	}
+	from := now
+	if !p.recordsUntil.IsZero() {
+		// continue from the previous horizon, Schedule is exclusive,
+		// so the occurrence exactly at the horizon is included
+		from = p.recordsUntil.Add(-time.Nanosecond)
+	}
+	records, err := p.GenerateIntakeRecords(from, to)
+	if err != nil {
+		return nil, err
+	}
+	p.recordsUntil = to
+	return records, nil
+}
+
+// RecordsHorizon returns a local midnight of the day containing t
+// shifted by shift in wall-clock time, so the day length changed by DST is respected.
+func (p *Plan) RecordsHorizon(t time.Time, shift time.Duration) time.Time {
+	local := t.In(p.location)
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, p.location)
+	day := 24 * time.Hour
+	return midnight.AddDate(0, 0, int(shift/day)).Add(shift % day)
+}
+
+// RecordsUntil returns an end of the range intake records are generated for,
+// zero time means that there are no generated records yet.
+func (p *Plan) RecordsUntil() time.Time {
+	return p.recordsUntil
+}
+
+// CheckAsNeededTake checks that intake at time t fits the limits of as-needed plan.
+// Taken contains times of intakes that are already taken.
+func (p *Plan) CheckAsNeededTake(t time.Time, taken []time.Time) error {
+	if p.kind != KindAsNeeded {
+		return ErrNotAsNeeded
+	}
+	if p.status != StatusActive {
+		return ErrFinishedPlan
+	}
+	if t.Before(p.schedule.start) || t.After(p.schedule.end) {
+		return ErrOutOfCourse
+	}
+
+	inWindow := 0
+	for _, prev := range taken {
+		diff := t.Sub(prev).Abs()
+		if p.limits.minInterval > 0 && diff < p.limits.minInterval {
+			return ErrMinIntervalViolated
+		}
+		if !prev.After(t) && diff < p.limits.window {
+			inWindow++
+		}
+	}
+	if p.limits.maxDoses > 0 && inWindow >= p.limits.maxDoses {
+		return ErrMaxDosesExceeded
+	}
+	return nil
+}
+
+// NewAsNeededRecord is a factory for ad-hoc intake record of as-needed plan.
+func (p *Plan) NewAsNeededRecord(t time.Time) (*intake.IntakeRecord, error) {
+	if p.kind != KindAsNeeded {
+		return nil, ErrNotAsNeeded
+	}
+	return intake.NewIntakeRecord(uuid.New(), p.id, t, p.dosage.value, time.Now(), time.Now())
+}
+
 // ID returns the ID of the plan.
 func (p *Plan) ID() uuid.UUID {
 	return p.id
@@ -143,11 +422,32 @@ func (p *Plan) Dosage() (float64, string) {
 	return p.dosage.value, p.dosage.unit
 }
 
+// Kind returns the kind of the plan.
+func (p *Plan) Kind() Kind {
+	return p.kind
+}
+
+// IsAsNeeded tells whether the plan has no schedule and is taken when needed.
+func (p *Plan) IsAsNeeded() bool {
+	return p.kind == KindAsNeeded
+}
+
+// AsNeededLimits returns limits of as-needed plan:
+// minimal interval between intakes, max doses per window and the window.
+func (p *Plan) AsNeededLimits() (time.Duration, int, time.Duration) {
+	return p.limits.minInterval, p.limits.maxDoses, p.limits.window
+}
+
 // IsActive tells whether the plan is active.
 func (p *Plan) IsActive() bool {
 	return p.status == StatusActive
 }
 
+// Location returns the time zone of the plan.
+func (p *Plan) Location() *time.Location {
+	return p.location
+}
+
 // Condition returns the intake condition of the plan.
 func (p *Plan) Condition() string {
 	return p.condition
@@ -167,11 +467,15 @@ func (p *Plan) CourseEnd() time.Time {
 // Each rule string defines a recurrence pattern for the schedule using the RRULE property.
 // The format specifies how the event repeats over time (frequency, interval, by day, etc.).
 // Multiple rules can be returned for complex schedules with different patterns.
+// Rules of phased plan are returned phase by phase.
 // RFC 5545 Specification: https://tools.ietf.org/html/rfc5545#section-3.3.10
 func (p *Plan) ScheduleIcal() []string {
-	rules := make([]string, 0, len(p.schedule.rules))
-	for _, rule := range p.schedule.rules {
-		rules = append(rules, rule.String())
+	if len(p.phases) == 0 {
+		return p.schedule.ical()
+	}
+	rules := make([]string, 0, len(p.phases))
+	for _, ph := range p.phases {
+		rules = append(rules, ph.schedule.ical()...)
 	}
 	return rules
 }
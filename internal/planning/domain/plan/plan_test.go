@@ -0,0 +1,266 @@
+package plan_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/FSO-VK/final-project-vk-backend/internal/planning/domain/plan"
+	"github.com/google/uuid"
+	"github.com/teambition/rrule-go"
+)
+
+func TestPlan_CheckAsNeededTake(t *testing.T) {
+	t.Parallel()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+	at := time.Date(2024, 1, 10, 20, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string // description of this test case
+		// Named input parameters for target function.
+		t       time.Time
+		taken   []time.Time
+		wantErr error
+	}{
+		{
+			name:    "Should allow first intake",
+			t:       at,
+			taken:   nil,
+			wantErr: nil,
+		},
+		{
+			name:    "Should reject intake closer than min interval",
+			t:       at,
+			taken:   []time.Time{at.Add(-5 * time.Hour)},
+			wantErr: plan.ErrMinIntervalViolated,
+		},
+		{
+			name: "Should reject intake over max doses in rolling window",
+			t:    at,
+			taken: []time.Time{
+				at.Add(-6 * time.Hour),
+				at.Add(-12 * time.Hour),
+				at.Add(-18 * time.Hour),
+				at.Add(-23 * time.Hour),
+			},
+			wantErr: plan.ErrMaxDosesExceeded,
+		},
+		{
+			name: "Should not count intakes out of rolling window",
+			t:    at,
+			taken: []time.Time{
+				at.Add(-6 * time.Hour),
+				at.Add(-12 * time.Hour),
+				at.Add(-18 * time.Hour),
+				at.Add(-25 * time.Hour),
+			},
+			wantErr: nil,
+		},
+		{
+			name:    "Should reject intake out of course",
+			t:       end.Add(time.Hour),
+			taken:   nil,
+			wantErr: plan.ErrOutOfCourse,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			dosage, err := plan.NewDosage(1, "шт.")
+			if err != nil {
+				t.Fatalf("arrange failed: %v", err)
+			}
+			limits, err := plan.NewAsNeededLimits(6*time.Hour, 4, 0)
+			if err != nil {
+				t.Fatalf("arrange failed: %v", err)
+			}
+			p, err := plan.NewAsNeededPlan(
+				uuid.New(), uuid.New(), uuid.New(),
+				dosage, start, end, limits, nil, "", start, start,
+			)
+			if err != nil {
+				t.Fatalf("could not construct receiver type: %v", err)
+			}
+			gotErr := p.CheckAsNeededTake(tt.t, tt.taken)
+			if !errors.Is(gotErr, tt.wantErr) {
+				t.Errorf("CheckAsNeededTake() error = %v, want %v", gotErr, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPlan_Schedule_Phases(t *testing.T) {
+	t.Parallel()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	newPhase := func(value float64, from, days int) plan.Phase {
+		d, err := plan.NewDosage(value, "мг.")
+		if err != nil {
+			t.Fatalf("arrange failed: %v", err)
+		}
+		phaseStart := start.AddDate(0, 0, from)
+		rule, err := rrule.NewRRule(rrule.ROption{
+			Dtstart: phaseStart.Add(9 * time.Hour),
+			Freq:    rrule.DAILY,
+		})
+		if err != nil {
+			t.Fatalf("arrange failed: %v", err)
+		}
+		s, err := plan.NewSchedule(phaseStart, phaseStart.AddDate(0, 0, days), []*rrule.RRule{rule})
+		if err != nil {
+			t.Fatalf("arrange failed: %v", err)
+		}
+		return plan.NewPhase(d, s)
+	}
+	tests := []struct {
+		name string // description of this test case
+		// Named input parameters for receiver constructor.
+		phases []plan.Phase
+		// Named input parameters for target function.
+		from    time.Time
+		to      time.Time
+		want    []float64
+		wantErr error
+	}{
+		{
+			name:    "Should produce dosage of each phase",
+			phases:  []plan.Phase{newPhase(40, 0, 2), newPhase(30, 2, 2), newPhase(20, 4, 1)},
+			from:    start,
+			to:      start.AddDate(0, 0, 10),
+			want:    []float64{40, 40, 30, 30, 20},
+			wantErr: nil,
+		},
+		{
+			name:    "Should produce occurrences in range only",
+			phases:  []plan.Phase{newPhase(40, 0, 2), newPhase(30, 2, 2), newPhase(20, 4, 1)},
+			from:    start.AddDate(0, 0, 1).Add(10 * time.Hour),
+			to:      start.AddDate(0, 0, 3).Add(10 * time.Hour),
+			want:    []float64{30, 30},
+			wantErr: nil,
+		},
+		{
+			name:    "Should reject overlapping phases",
+			phases:  []plan.Phase{newPhase(40, 0, 3), newPhase(30, 2, 2)},
+			wantErr: plan.ErrInvalidPhases,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			p, err := plan.NewPhasedPlan(
+				uuid.New(), uuid.New(), uuid.New(),
+				tt.phases, nil, "", start, start,
+			)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewPhasedPlan() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			got := p.Schedule(tt.from, tt.to)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Schedule() returned %d occurrences, want %d", len(got), len(tt.want))
+			}
+			for i, o := range got {
+				if o.DosageValue != tt.want[i] {
+					t.Errorf("Schedule()[%d] dosage = %v, want %v", i, o.DosageValue, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestPlan_GenerateDueRecords(t *testing.T) {
+	t.Parallel()
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatalf("arrange failed: %v", err)
+	}
+	start := time.Date(2024, 3, 28, 0, 0, 0, 0, loc)
+	tests := []struct {
+		name string // description of this test case
+		// intakeAt is a daily intake time from local midnight.
+		intakeAt time.Duration
+		// Named input parameters for target function.
+		now []time.Time
+		// want is a wall-clock time of generated records in plan time zone.
+		want []time.Time
+	}{
+		{
+			name:     "Should keep wall-clock time across DST change",
+			intakeAt: 8 * time.Hour,
+			now: []time.Time{
+				time.Date(2024, 3, 30, 0, 5, 0, 0, loc),
+				time.Date(2024, 3, 31, 0, 5, 0, 0, loc),
+			},
+			want: []time.Time{
+				time.Date(2024, 3, 30, 8, 0, 0, 0, loc),
+				time.Date(2024, 3, 31, 8, 0, 0, 0, loc),
+			},
+		},
+		{
+			name:     "Should generate records once per local day",
+			intakeAt: 8 * time.Hour,
+			now: []time.Time{
+				time.Date(2024, 3, 30, 0, 5, 0, 0, loc),
+				time.Date(2024, 3, 30, 12, 0, 0, 0, loc),
+				time.Date(2024, 3, 30, 23, 50, 0, 0, loc),
+			},
+			want: []time.Time{
+				time.Date(2024, 3, 30, 8, 0, 0, 0, loc),
+			},
+		},
+		{
+			name:     "Should not skip intake at local midnight",
+			intakeAt: 0,
+			now: []time.Time{
+				time.Date(2024, 3, 29, 23, 0, 0, 0, loc),
+				time.Date(2024, 3, 30, 0, 5, 0, 0, loc),
+			},
+			want: []time.Time{
+				time.Date(2024, 3, 30, 0, 0, 0, 0, loc),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			d, err := plan.NewDosage(1, "шт.")
+			if err != nil {
+				t.Fatalf("arrange failed: %v", err)
+			}
+			rule, err := rrule.NewRRule(rrule.ROption{
+				Dtstart: start.Add(tt.intakeAt),
+				Freq:    rrule.DAILY,
+			})
+			if err != nil {
+				t.Fatalf("arrange failed: %v", err)
+			}
+			s, err := plan.NewSchedule(start, start.AddDate(0, 1, 0), []*rrule.RRule{rule})
+			if err != nil {
+				t.Fatalf("arrange failed: %v", err)
+			}
+			p, err := plan.NewPlan(uuid.New(), uuid.New(), uuid.New(), d, s, loc, "", start, start)
+			if err != nil {
+				t.Fatalf("could not construct receiver type: %v", err)
+			}
+			var got []time.Time
+			for _, now := range tt.now {
+				records, err := p.GenerateDueRecords(now, 24*time.Hour)
+				if err != nil {
+					t.Fatalf("GenerateDueRecords() error = %v", err)
+				}
+				for _, r := range records {
+					got = append(got, r.PlannedTime())
+				}
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("GenerateDueRecords() generated %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if !got[i].Equal(tt.want[i]) {
+					t.Errorf("GenerateDueRecords()[%d] = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,325 @@
+package plan
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/teambition/rrule-go"
+)
+
+// ErrInvalidPreset means that the schedule preset is invalid.
+var ErrInvalidPreset = errors.New("invalid schedule preset")
+
+// Limits of schedule presets.
+const (
+	maxPresetTimes       = 12
+	maxPresetIntervalDay = 31
+	maxPresetCycleDays   = 90
+	// maxPresetRules limits rules of cycle preset, it has a rule for every intake of the on period.
+	maxPresetRules = 200
+)
+
+// PresetKind is a kind of human-friendly schedule.
+type PresetKind string
+
+// Enum of preset kinds.
+const (
+	// PresetTimesADay is every day at Times.
+	PresetTimesADay PresetKind = "times_a_day"
+	// PresetEveryNHours is every IntervalHours starting from the single time of Times.
+	PresetEveryNHours PresetKind = "every_n_hours"
+	// PresetWeekdays is on Weekdays at Times.
+	PresetWeekdays PresetKind = "weekdays"
+	// PresetEveryNDays is every IntervalDays at Times, 2 is every other day.
+	PresetEveryNDays PresetKind = "every_n_days"
+	// PresetCycle is DaysOn days at Times followed by DaysOff days without intakes.
+	PresetCycle PresetKind = "cycle"
+)
+
+// TimeOfDay is a wall-clock time of intake.
+type TimeOfDay struct {
+	Hour   int
+	Minute int
+}
+
+func (t TimeOfDay) String() string {
+	return fmt.Sprintf("%02d:%02d", t.Hour, t.Minute)
+}
+
+func (t TimeOfDay) compare(other TimeOfDay) int {
+	return (t.Hour*60 + t.Minute) - (other.Hour*60 + other.Minute)
+}
+
+// SchedulePreset is a human-friendly schedule compiled to recurrence rules.
+// Fields not used by the kind must be empty.
+type SchedulePreset struct {
+	Kind          PresetKind
+	Times         []TimeOfDay
+	IntervalHours int
+	IntervalDays  int
+	Weekdays      []time.Weekday
+	DaysOn        int
+	DaysOff       int
+}
+
+// PresetFieldError tells which field of the preset is invalid and why.
+type PresetFieldError struct {
+	Field  string
+	Reason string
+}
+
+func (e *PresetFieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Reason)
+}
+
+// Unwrap returns ErrInvalidPreset.
+func (e *PresetFieldError) Unwrap() error {
+	return ErrInvalidPreset
+}
+
+// Validate returns joined *PresetFieldError for every invalid field.
+func (p SchedulePreset) Validate() error {
+	var errs []error
+	fail := func(field, reason string) {
+		errs = append(errs, &PresetFieldError{Field: field, Reason: reason})
+	}
+
+	if len(p.Times) == 0 {
+		fail("times", "at least one time is required")
+	}
+	if len(p.Times) > maxPresetTimes {
+		fail("times", fmt.Sprintf("at most %d times are allowed", maxPresetTimes))
+	}
+	for i, t := range p.Times {
+		if t.Hour < 0 || t.Hour > 23 || t.Minute < 0 || t.Minute > 59 {
+			fail(fmt.Sprintf("times[%d]", i), "time must be in range 00:00-23:59")
+		}
+		if slices.ContainsFunc(p.Times[:i], func(prev TimeOfDay) bool { return prev == t }) {
+			fail(fmt.Sprintf("times[%d]", i), "time is duplicated")
+		}
+	}
+
+	needs := map[string]bool{}
+	switch p.Kind {
+	case PresetTimesADay:
+	case PresetEveryNHours:
+		needs["intervalHours"] = true
+		if len(p.Times) > 1 {
+			fail("times", "only the first intake time is allowed")
+		}
+		if p.IntervalHours < 1 || p.IntervalHours > 24 {
+			fail("intervalHours", "interval must be from 1 to 24 hours")
+		}
+	case PresetWeekdays:
+		needs["weekdays"] = true
+		if len(p.Weekdays) == 0 {
+			fail("weekdays", "at least one weekday is required")
+		}
+		for i, d := range p.Weekdays {
+			if d < time.Sunday || d > time.Saturday {
+				fail(fmt.Sprintf("weekdays[%d]", i), "unknown weekday")
+			}
+		}
+	case PresetEveryNDays:
+		needs["intervalDays"] = true
+		if p.IntervalDays < 2 || p.IntervalDays > maxPresetIntervalDay {
+			fail(
+				"intervalDays",
+				fmt.Sprintf("interval must be from 2 to %d days", maxPresetIntervalDay),
+			)
+		}
+	case PresetCycle:
+		needs["daysOn"] = true
+		needs["daysOff"] = true
+		if p.DaysOn < 1 {
+			fail("daysOn", "at least one day is required")
+		}
+		if p.DaysOff < 1 {
+			fail("daysOff", "at least one day is required")
+		}
+		if p.DaysOn+p.DaysOff > maxPresetCycleDays {
+			fail("daysOff", fmt.Sprintf("cycle must be at most %d days", maxPresetCycleDays))
+		}
+		if p.DaysOn*len(p.Times) > maxPresetRules {
+			fail("daysOn", fmt.Sprintf("at most %d intakes per cycle are allowed", maxPresetRules))
+		}
+	default:
+		fail("kind", "unknown schedule kind")
+	}
+
+	if !needs["intervalHours"] && p.IntervalHours != 0 {
+		fail("intervalHours", "is not used by the schedule kind")
+	}
+	if !needs["intervalDays"] && p.IntervalDays != 0 {
+		fail("intervalDays", "is not used by the schedule kind")
+	}
+	if !needs["weekdays"] && len(p.Weekdays) != 0 {
+		fail("weekdays", "is not used by the schedule kind")
+	}
+	if !needs["daysOn"] && (p.DaysOn != 0 || p.DaysOff != 0) {
+		fail("daysOn", "is not used by the schedule kind")
+	}
+	return errors.Join(errs...)
+}
+
+// Compile returns recurrence rules of the preset starting from the day of start
+// in the time zone loc. Every intake time gets its own rule,
+// cycle preset gets a rule for every intake of the on period.
+func (p SchedulePreset) Compile(start time.Time, loc *time.Location) ([]*rrule.RRule, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	local := start.In(loc)
+	at := func(day int, t TimeOfDay) time.Time {
+		return time.Date(local.Year(), local.Month(), local.Day()+day, t.Hour, t.Minute, 0, 0, loc)
+	}
+
+	var opts []rrule.ROption
+	switch p.Kind {
+	case PresetTimesADay:
+		for _, t := range p.Times {
+			opts = append(opts, rrule.ROption{Freq: rrule.DAILY, Dtstart: at(0, t)})
+		}
+	case PresetEveryNHours:
+		opts = append(opts, rrule.ROption{
+			Freq:     rrule.HOURLY,
+			Interval: p.IntervalHours,
+			Dtstart:  at(0, p.Times[0]),
+		})
+	case PresetWeekdays:
+		days := make([]rrule.Weekday, 0, len(p.Weekdays))
+		for _, d := range p.Weekdays {
+			days = append(days, weekdayToRRule(d))
+		}
+		for _, t := range p.Times {
+			opts = append(opts, rrule.ROption{
+				Freq:      rrule.WEEKLY,
+				Byweekday: days,
+				Dtstart:   at(0, t),
+			})
+		}
+	case PresetEveryNDays:
+		for _, t := range p.Times {
+			opts = append(opts, rrule.ROption{
+				Freq:     rrule.DAILY,
+				Interval: p.IntervalDays,
+				Dtstart:  at(0, t),
+			})
+		}
+	case PresetCycle:
+		for day := range p.DaysOn {
+			for _, t := range p.Times {
+				opts = append(opts, rrule.ROption{
+					Freq:     rrule.DAILY,
+					Interval: p.DaysOn + p.DaysOff,
+					Dtstart:  at(day, t),
+				})
+			}
+		}
+	}
+
+	rules := make([]*rrule.RRule, 0, len(opts))
+	for _, o := range opts {
+		rule, err := rrule.NewRRule(o)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidPreset, err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// Preset translates recurrence rules of the plan back to the preset.
+// It returns false if the plan has phases or its rules are not produced by any preset.
+func (p *Plan) Preset() (SchedulePreset, bool) {
+	if len(p.phases) != 0 || p.kind != KindScheduled {
+		return SchedulePreset{}, false
+	}
+	return presetFromRules(p.schedule.rules, p.location)
+}
+
+// presetFromRules recognizes rules compiled by SchedulePreset.Compile.
+func presetFromRules(rules []*rrule.RRule, loc *time.Location) (SchedulePreset, bool) {
+	if len(rules) == 0 {
+		return SchedulePreset{}, false
+	}
+	first := rules[0].OrigOptions
+	firstDay := dayOf(first.Dtstart.In(loc))
+
+	var (
+		times    []TimeOfDay
+		maxShift int
+	)
+	for _, r := range rules {
+		o := r.OrigOptions
+		if o.Freq != first.Freq || o.Interval != first.Interval || o.Count != 0 ||
+			!slices.Equal(o.Byweekday, first.Byweekday) || hasByRules(o) {
+			return SchedulePreset{}, false
+		}
+		start := o.Dtstart.In(loc)
+		shift := int(dayOf(start).Sub(firstDay).Hours()/24 + 0.5)
+		if shift < 0 {
+			return SchedulePreset{}, false
+		}
+		maxShift = max(maxShift, shift)
+		t := TimeOfDay{Hour: start.Hour(), Minute: start.Minute()}
+		if shift == 0 {
+			times = append(times, t)
+		}
+	}
+	slices.SortFunc(times, TimeOfDay.compare)
+	interval := max(first.Interval, 1)
+
+	var preset SchedulePreset
+	switch {
+	case first.Freq == rrule.HOURLY && len(rules) == 1:
+		preset = SchedulePreset{Kind: PresetEveryNHours, Times: times, IntervalHours: interval}
+	case first.Freq == rrule.WEEKLY && interval == 1 && len(first.Byweekday) > 0 && maxShift == 0:
+		days := make([]time.Weekday, 0, len(first.Byweekday))
+		for _, d := range first.Byweekday {
+			days = append(days, weekdayFromRRule(d))
+		}
+		preset = SchedulePreset{Kind: PresetWeekdays, Times: times, Weekdays: days}
+	case first.Freq == rrule.DAILY && len(first.Byweekday) == 0 && maxShift == 0 && interval == 1:
+		preset = SchedulePreset{Kind: PresetTimesADay, Times: times}
+	case first.Freq == rrule.DAILY && len(first.Byweekday) == 0 && maxShift == 0:
+		preset = SchedulePreset{Kind: PresetEveryNDays, Times: times, IntervalDays: interval}
+	case first.Freq == rrule.DAILY && len(first.Byweekday) == 0 && maxShift+1 < interval:
+		preset = SchedulePreset{
+			Kind:    PresetCycle,
+			Times:   times,
+			DaysOn:  maxShift + 1,
+			DaysOff: interval - maxShift - 1,
+		}
+	default:
+		return SchedulePreset{}, false
+	}
+
+	// rules must be exactly the ones the preset compiles to
+	compiled, err := preset.Compile(first.Dtstart, loc)
+	if err != nil || len(compiled) != len(rules) {
+		return SchedulePreset{}, false
+	}
+	return preset, true
+}
+
+func dayOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func hasByRules(o rrule.ROption) bool {
+	return len(o.Bysetpos) != 0 || len(o.Bymonth) != 0 || len(o.Bymonthday) != 0 ||
+		len(o.Byyearday) != 0 || len(o.Byweekno) != 0 || len(o.Byhour) != 0 ||
+		len(o.Byminute) != 0 || len(o.Bysecond) != 0 || len(o.Byeaster) != 0
+}
+
+func weekdayToRRule(d time.Weekday) rrule.Weekday {
+	// rrule weekdays start from Monday
+	return []rrule.Weekday{rrule.SU, rrule.MO, rrule.TU, rrule.WE, rrule.TH, rrule.FR, rrule.SA}[d]
+}
+
+func weekdayFromRRule(d rrule.Weekday) time.Weekday {
+	return time.Weekday((d.Day() + 1) % 7)
+}
@@ -0,0 +1,158 @@
+package plan_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/FSO-VK/final-project-vk-backend/internal/planning/domain/plan"
+	"github.com/google/uuid"
+)
+
+func TestSchedulePreset_Compile(t *testing.T) {
+	t.Parallel()
+	loc, err := time.LoadLocation("Europe/Moscow")
+	if err != nil {
+		t.Fatalf("arrange failed: %v", err)
+	}
+	// Monday
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, loc)
+	morning := plan.TimeOfDay{Hour: 8, Minute: 0}
+	evening := plan.TimeOfDay{Hour: 20, Minute: 30}
+	tests := []struct {
+		name string // description of this test case
+		// Named input parameters for target function.
+		preset plan.SchedulePreset
+		days   int
+		// want is a number of intakes in days from start.
+		want       int
+		wantFields []string
+	}{
+		{
+			name: "Should schedule times a day",
+			preset: plan.SchedulePreset{
+				Kind:  plan.PresetTimesADay,
+				Times: []plan.TimeOfDay{morning, evening},
+			},
+			days: 7,
+			want: 14,
+		},
+		{
+			name: "Should schedule every n hours",
+			preset: plan.SchedulePreset{
+				Kind: plan.PresetEveryNHours, Times: []plan.TimeOfDay{morning}, IntervalHours: 6,
+			},
+			days: 2,
+			want: 7,
+		},
+		{
+			name: "Should schedule on weekdays",
+			preset: plan.SchedulePreset{
+				Kind:     plan.PresetWeekdays,
+				Times:    []plan.TimeOfDay{morning},
+				Weekdays: []time.Weekday{time.Monday, time.Wednesday, time.Friday},
+			},
+			days: 14,
+			want: 6,
+		},
+		{
+			name: "Should schedule every other day",
+			preset: plan.SchedulePreset{
+				Kind: plan.PresetEveryNDays, Times: []plan.TimeOfDay{morning}, IntervalDays: 2,
+			},
+			days: 10,
+			want: 5,
+		},
+		{
+			name: "Should schedule cycle with days off",
+			preset: plan.SchedulePreset{
+				Kind:    plan.PresetCycle,
+				Times:   []plan.TimeOfDay{morning, evening},
+				DaysOn:  21,
+				DaysOff: 7,
+			},
+			days: 56,
+			want: 84,
+		},
+		{
+			name: "Should report every invalid field",
+			preset: plan.SchedulePreset{
+				Kind:         plan.PresetEveryNDays,
+				Times:        []plan.TimeOfDay{morning, {Hour: 25, Minute: 0}, morning},
+				IntervalDays: 1,
+				DaysOn:       3,
+			},
+			wantFields: []string{"times[1]", "times[2]", "intervalDays", "daysOn"},
+		},
+		{
+			name:       "Should reject unknown kind",
+			preset:     plan.SchedulePreset{Kind: "hourly", Times: []plan.TimeOfDay{morning}},
+			wantFields: []string{"kind"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			rules, err := tt.preset.Compile(start, loc)
+			if len(tt.wantFields) > 0 {
+				if !errors.Is(err, plan.ErrInvalidPreset) {
+					t.Fatalf("Compile() error = %v, want %v", err, plan.ErrInvalidPreset)
+				}
+				joined, ok := err.(interface{ Unwrap() []error })
+				if !ok {
+					t.Fatalf("Compile() error = %v, want joined field errors", err)
+				}
+				var fields []string
+				for _, e := range joined.Unwrap() {
+					var fieldErr *plan.PresetFieldError
+					if errors.As(e, &fieldErr) {
+						fields = append(fields, fieldErr.Field)
+					}
+				}
+				if !reflect.DeepEqual(fields, tt.wantFields) {
+					t.Errorf("Compile() invalid fields = %v, want %v", fields, tt.wantFields)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Compile() error = %v", err)
+			}
+
+			end := start.AddDate(0, 0, tt.days)
+			s, err := plan.NewSchedule(start, end, rules)
+			if err != nil {
+				t.Fatalf("arrange failed: %v", err)
+			}
+			dosage, err := plan.NewDosage(1, "шт.")
+			if err != nil {
+				t.Fatalf("arrange failed: %v", err)
+			}
+			p, err := plan.NewPlan(
+				uuid.New(),
+				uuid.New(),
+				uuid.New(),
+				dosage,
+				s,
+				loc,
+				"",
+				start,
+				start,
+			)
+			if err != nil {
+				t.Fatalf("arrange failed: %v", err)
+			}
+			if got := p.Schedule(start, end); len(got) != tt.want {
+				t.Errorf("Schedule() returned %d occurrences, want %d", len(got), tt.want)
+			}
+
+			got, ok := p.Preset()
+			if !ok {
+				t.Fatalf("Preset() didn't recognize rules %v", p.ScheduleIcal())
+			}
+			if !reflect.DeepEqual(got, tt.preset) {
+				t.Errorf("Preset() = %+v, want %+v", got, tt.preset)
+			}
+		})
+	}
+}
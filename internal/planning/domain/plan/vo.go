@@ -3,6 +3,7 @@ package plan
 import (
 	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/FSO-VK/final-project-vk-backend/pkg/validation"
@@ -14,6 +15,10 @@ var (
 	ErrInvalidDosage = errors.New("invalid dosage")
 	// ErrInvalidSchedule means that the schedule expression format is invalid.
 	ErrInvalidSchedule = errors.New("invalid schedule expression format")
+	// ErrInvalidPhases means that phases are empty, overlap or have different dosage units.
+	ErrInvalidPhases = errors.New("invalid plan phases")
+	// ErrInvalidAsNeededLimits means that limits of as-needed plan are invalid.
+	ErrInvalidAsNeededLimits = errors.New("invalid as-needed limits")
 )
 
 // dosage is a VO representing the dosage of planned medication.
@@ -62,12 +67,77 @@ const (
 	StatusFinished
 )
 
+// Kind is a VO representing how intakes of the plan happen.
+type Kind uint
+
+// Enum of plan kinds.
+const (
+	// KindScheduled plan has intakes generated by recurrence rules.
+	KindScheduled Kind = iota
+	// KindAsNeeded plan has no schedule, intakes are created when user takes medication.
+	KindAsNeeded
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindScheduled:
+		return "scheduled"
+	case KindAsNeeded:
+		return "as_needed"
+	}
+	return ""
+}
+
+// defaultAsNeededWindow is a rolling window for max doses if it is not set.
+const defaultAsNeededWindow = 24 * time.Hour
+
+// asNeededLimits is a VO representing limits of as-needed (PRN) intakes.
+type asNeededLimits struct {
+	// minInterval is a minimal time between two intakes, zero means no limit.
+	minInterval time.Duration
+	// maxDoses is a max number of intakes in rolling window, zero means no limit.
+	maxDoses int
+	window   time.Duration
+}
+
+// NewAsNeededLimits creates validated limits of as-needed intakes.
+// Zero window means 24 hours.
+func NewAsNeededLimits(
+	minInterval time.Duration,
+	maxDoses int,
+	window time.Duration,
+) (asNeededLimits, error) {
+	if minInterval < 0 || maxDoses < 0 || window < 0 {
+		return asNeededLimits{}, ErrInvalidAsNeededLimits
+	}
+	if window == 0 {
+		window = defaultAsNeededWindow
+	}
+	return asNeededLimits{
+		minInterval: minInterval,
+		maxDoses:    maxDoses,
+		window:      window,
+	}, nil
+}
+
+// locationOrUTC returns UTC for unset time zone.
+func locationOrUTC(loc *time.Location) *time.Location {
+	if loc == nil {
+		return time.UTC
+	}
+	return loc
+}
+
 type schedule struct {
 	start time.Time
 	end   time.Time
 
 	// specific RFC5545 fields to contain complex schedule
 	rules []*rrule.RRule
+	// exDates are skipped occurrences of the rules (EXDATE).
+	exDates []time.Time
+	// rDates are one-off occurrences in addition to the rules (RDATE), sorted.
+	rDates []time.Time
 }
 
 // NewSchedule creates validated schedule.
@@ -87,18 +157,110 @@ func NewSchedule(start, end time.Time, rules []*rrule.RRule) (schedule, error) {
 	}
 
 	return schedule{
-		start: start,
-		end:   end,
-		rules: r,
+		start:   start,
+		end:     end,
+		rules:   r,
+		exDates: nil,
+		rDates:  nil,
 	}, nil
 }
 
+// Phase is a VO representing a period of the course in range [start, end)
+// with its own dosage and recurrence rules, e.g. a step of steroid taper.
+type Phase struct {
+	dosage   dosage
+	schedule schedule
+}
+
+// NewPhase creates validated phase of the plan.
+func NewPhase(d dosage, s schedule) Phase {
+	return Phase{
+		dosage:   d,
+		schedule: s,
+	}
+}
+
+// newPhases validates that phases are ordered, don't overlap and share dosage unit.
+func newPhases(phases []Phase) ([]Phase, error) {
+	if len(phases) == 0 {
+		return nil, ErrInvalidPhases
+	}
+	for i := 1; i < len(phases); i++ {
+		prev, cur := phases[i-1], phases[i]
+		if cur.schedule.start.Before(prev.schedule.end) {
+			return nil, fmt.Errorf("%w: phase %d overlaps previous one", ErrInvalidPhases, i)
+		}
+		if cur.dosage.unit != prev.dosage.unit {
+			return nil, fmt.Errorf("%w: phase %d has another dosage unit", ErrInvalidPhases, i)
+		}
+	}
+	return phases, nil
+}
+
+// PhaseInfo is a read-only view of the plan phase.
+type PhaseInfo struct {
+	Start          time.Time
+	End            time.Time
+	DosageValue    float64
+	DosageUnit     string
+	RecurrenceRule []string
+}
+
+func (ph Phase) info() PhaseInfo {
+	return PhaseInfo{
+		Start:          ph.schedule.start,
+		End:            ph.schedule.end,
+		DosageValue:    ph.dosage.value,
+		DosageUnit:     ph.dosage.unit,
+		RecurrenceRule: ph.schedule.ical(),
+	}
+}
+
+// ical returns the recurrence rules in iCalendar RFC 5545 format.
+func (s *schedule) ical() []string {
+	rules := make([]string, 0, len(s.rules))
+	for _, rule := range s.rules {
+		rules = append(rules, rule.String())
+	}
+	return rules
+}
+
+// recurrences returns a view of every rule with its own exclusions,
+// one-off dates are attached to the first rule.
+func (s *schedule) recurrences(d dosage) []Recurrence {
+	recurrences := make([]Recurrence, 0, len(s.rules))
+	for i, rule := range s.rules {
+		var exDates, rDates []time.Time
+		for _, ex := range s.exDates {
+			if ruleHas(rule, ex) {
+				exDates = append(exDates, ex)
+			}
+		}
+		if i == 0 {
+			rDates = slices.Clone(s.rDates)
+		}
+		recurrences = append(recurrences, Recurrence{
+			Start:       rule.GetDTStart(),
+			Rule:        rule.OrigOptions.RRuleString(),
+			ExDates:     exDates,
+			RDates:      rDates,
+			DosageValue: d.value,
+			DosageUnit:  d.unit,
+		})
+	}
+	return recurrences
+}
+
 // Next returns the next scheduled time after the given time.
+// Excluded occurrences are skipped, one-off dates are included.
 // If there is no next time, it returns the zero time.
 func (s *schedule) Next(from time.Time) time.Time {
 	var t time.Time
-	for _, rules := range s.rules {
-		next := rules.After(from, false)
+	for _, rule := range s.rules {
+		next := rule.After(from, false)
+		for !next.IsZero() && s.excluded(next) {
+			next = rule.After(next, false)
+		}
 		if next.IsZero() {
 			continue
 		}
@@ -106,9 +268,37 @@ func (s *schedule) Next(from time.Time) time.Time {
 			t = next
 		}
 	}
+	for _, r := range s.rDates {
+		if !r.After(from) {
+			continue
+		}
+		if t.IsZero() || r.Before(t) {
+			t = r
+		}
+		// one-off dates are sorted
+		break
+	}
 	return t
 }
 
+// has tells whether t is an occurrence of the schedule.
+func (s *schedule) has(t time.Time) bool {
+	return s.Next(t.Add(-time.Nanosecond)).Equal(t)
+}
+
+func (s *schedule) excluded(t time.Time) bool {
+	return slices.ContainsFunc(s.exDates, t.Equal)
+}
+
+// covers tells whether t is in the range [start, end) of the schedule.
+func (s *schedule) covers(t time.Time) bool {
+	return !t.Before(s.start) && t.Before(s.end)
+}
+
+func ruleHas(rule *rrule.RRule, t time.Time) bool {
+	return rule.After(t.Add(-time.Nanosecond), false).Equal(t)
+}
+
 func (s Status) String() string {
 	switch s {
 	case StatusDraft:
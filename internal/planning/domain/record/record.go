@@ -3,13 +3,31 @@ package record
 
 import (
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 )
 
-// ErrRecordOutdated tells that record is outdated and can't be rescheduled.
-var ErrRecordOutdated = errors.New("cannot reschedule outdated record")
+// maxSnooze is how long the reminder may be postponed.
+const maxSnooze = 2 * time.Hour
+
+// maxTakenAtSkew is how far in the future intake time may be
+// to tolerate clock differences between client and server.
+const maxTakenAtSkew = 15 * time.Minute
+
+var (
+	// ErrRecordOutdated tells that record is outdated and can't be rescheduled.
+	ErrRecordOutdated = errors.New("cannot reschedule outdated record")
+	// ErrTakenInFuture tells that intake time is too far in the future.
+	ErrTakenInFuture = errors.New("intake time is in the future")
+	// ErrInvalidTakenAmount tells that taken amount is not positive.
+	ErrInvalidTakenAmount = errors.New("taken amount must be positive")
+	// ErrRecordTaken tells that intake is taken and can't be escalated.
+	ErrRecordTaken = errors.New("intake is already taken")
+	// ErrInvalidSnooze tells that reminder can't be postponed for such duration.
+	ErrInvalidSnooze = errors.New("invalid snooze duration")
+)
 
 // IntakeRecord is an aggregate that represents a record for medication intake.
 type IntakeRecord struct {
@@ -17,9 +35,32 @@ type IntakeRecord struct {
 	planID    uuid.UUID
 	status    Status
 	plannedAt time.Time
-	takenAt   time.Time
-	createdAt time.Time
-	updatedAt time.Time
+	// plannedAmount is a dosage of the intake by plan,
+	// it changes over the course for phased plans.
+	plannedAmount float64
+	takenAt       time.Time
+	// takenAmount is an amount of medication actually taken,
+	// it may differ from the plan dosage for partial doses.
+	takenAmount float64
+	// takeID identifies the current take of the record, side effects of
+	// the take (e.g. stock decrement) use it as idempotency key.
+	takeID uuid.UUID
+	// takes is a number of takes of the record, ids of the next takes
+	// are derived from it, so a retried take gets the same id.
+	takes int
+	// takenBy is a user who marked the record taken,
+	// it differs from the plan owner when caregiver takes for the owner.
+	takenBy uuid.UUID
+	// remindedAt is when the user was reminded about the intake,
+	// escalation delays are counted from it. It is zero until the reminder
+	// is sent, so the reminder is sent once even if reminders job is restarted.
+	remindedAt time.Time
+	// escalations is a number of fired escalation steps of the plan policy.
+	escalations int
+	// snoozedUntil is when the user asked to remind about the intake again.
+	snoozedUntil time.Time
+	createdAt    time.Time
+	updatedAt    time.Time
 }
 
 // NewIntakeRecord creates validated IntakeRecord.
@@ -27,27 +68,123 @@ func NewIntakeRecord(
 	id uuid.UUID,
 	planID uuid.UUID,
 	plannedAt time.Time,
+	plannedAmount float64,
 	createdAt time.Time,
 	updatedAt time.Time,
 ) (*IntakeRecord, error) {
 	return &IntakeRecord{
-		id:        id,
-		planID:    planID,
-		status:    StatusDraft,
-		plannedAt: plannedAt,
-		takenAt:   time.Time{}, // zero value
-		createdAt: createdAt,
-		updatedAt: updatedAt,
+		id:            id,
+		planID:        planID,
+		status:        StatusDraft,
+		plannedAt:     plannedAt,
+		plannedAmount: plannedAmount,
+		takenAt:       time.Time{}, // zero value
+		takenAmount:   0,
+		takeID:        uuid.Nil,
+		takenBy:       uuid.Nil,
+		createdAt:     createdAt,
+		updatedAt:     updatedAt,
 	}, nil
 }
 
-// MarkTaken executes business logic for marking the record as taken.
-func (r *IntakeRecord) MarkTaken(t time.Time) *IntakeRecord {
+// ValidateTake checks that medication can be taken at time t with the given amount.
+func ValidateTake(t time.Time, amount float64) error {
+	if t.After(time.Now().Add(maxTakenAtSkew)) {
+		return ErrTakenInFuture
+	}
+	if amount <= 0 {
+		return ErrInvalidTakenAmount
+	}
+	return nil
+}
+
+// MarkTaken executes business logic for marking the record as taken
+// at time t with the given amount of medication by the take identified by takeID.
+// takenBy is the user who performed the take.
+func (r *IntakeRecord) MarkTaken(
+	t time.Time,
+	amount float64,
+	takeID uuid.UUID,
+	takenBy uuid.UUID,
+) (*IntakeRecord, error) {
+	if err := ValidateTake(t, amount); err != nil {
+		return nil, err
+	}
+	if !r.IsTaken() || takeID != r.takeID {
+		r.takes++
+	}
 	r.status = StatusTaken
 	r.takenAt = t
+	r.takenAmount = amount
+	r.takeID = takeID
+	r.takenBy = takenBy
+	r.snoozedUntil = time.Time{}
+	return r, nil
+}
+
+// NextTakeID returns the id of the next take of the record with the given amount.
+// The id is the same until the take is marked, so side effects of the take
+// are applied once however many times it is retried.
+func (r *IntakeRecord) NextTakeID(amount float64) uuid.UUID {
+	return uuid.NewSHA1(r.id, []byte(fmt.Sprintf("take-%d-%g", r.takes+1, amount)))
+}
+
+// MarkReminded records the first reminder about the intake.
+func (r *IntakeRecord) MarkReminded(t time.Time) *IntakeRecord {
+	if r.remindedAt.IsZero() {
+		r.remindedAt = t
+	}
+	return r
+}
+
+// IsReminded tells whether the user was reminded about the intake.
+func (r *IntakeRecord) IsReminded() bool {
+	return !r.remindedAt.IsZero()
+}
+
+// IsDueForReminder tells whether the user must be reminded about the intake
+// planned after since and not later than until.
+func (r *IntakeRecord) IsDueForReminder(since time.Time, until time.Time) bool {
+	return r.status == StatusDraft &&
+		!r.IsReminded() &&
+		r.plannedAt.After(since) &&
+		!r.plannedAt.After(until)
+}
+
+// Escalate records that escalation steps are fired,
+// taken intake cancels pending escalations.
+func (r *IntakeRecord) Escalate(steps int) error {
+	if r.IsTaken() {
+		return ErrRecordTaken
+	}
+	r.escalations += steps
+	return nil
+}
+
+// Snooze postpones the reminder about not taken intake till t + d.
+func (r *IntakeRecord) Snooze(t time.Time, d time.Duration) error {
+	if r.IsTaken() {
+		return ErrRecordTaken
+	}
+	if d <= 0 || d > maxSnooze {
+		return ErrInvalidSnooze
+	}
+	r.snoozedUntil = t.Add(d)
+	return nil
+}
+
+// Unsnooze is called when the postponed reminder is sent.
+func (r *IntakeRecord) Unsnooze() *IntakeRecord {
+	r.snoozedUntil = time.Time{}
 	return r
 }
 
+// SnoozedUntil returns when to remind about the intake again.
+// It is zero if the reminder is not postponed.
+func (r *IntakeRecord) SnoozedUntil() time.Time {
+	return r.snoozedUntil
+}
+
 // MarkMissed executes business logic for marking the record as missed.
 func (r *IntakeRecord) MarkMissed() *IntakeRecord {
 	r.status = StatusMissed
@@ -57,15 +194,22 @@ func (r *IntakeRecord) MarkMissed() *IntakeRecord {
 // Cancel executes business logic for marking the record as draft if user want to cancel taking it.
 func (r *IntakeRecord) Cancel() *IntakeRecord {
 	r.status = StatusDraft
+	r.takenAt = time.Time{}
+	r.takenAmount = 0
+	r.takeID = uuid.Nil
+	r.takenBy = uuid.Nil
 	return r
 }
 
 // Reschedule executes business logic for rescheduling the future record.
+// The user is reminded again at the new time.
 func (r *IntakeRecord) Reschedule(newPlannedTime time.Time) (*IntakeRecord, error) {
 	if r.status != StatusDraft {
 		return nil, ErrRecordOutdated
 	}
 	r.plannedAt = newPlannedTime
+	r.remindedAt = time.Time{}
+	r.escalations = 0
 	return r, nil
 }
 
@@ -79,6 +223,11 @@ func (r *IntakeRecord) PlannedTime() time.Time {
 	return r.plannedAt
 }
 
+// PlannedAmount returns the dosage of the intake by plan.
+func (r *IntakeRecord) PlannedAmount() float64 {
+	return r.plannedAmount
+}
+
 // ID returns the ID of the intake record.
 func (r *IntakeRecord) ID() uuid.UUID {
 	return r.id
@@ -94,6 +243,35 @@ func (r *IntakeRecord) TakenAt() time.Time {
 	return r.takenAt
 }
 
+// TakenAmount returns the amount of medication actually taken.
+// It is zero if the record is not taken.
+func (r *IntakeRecord) TakenAmount() float64 {
+	return r.takenAmount
+}
+
+// TakeID returns the identifier of the current take.
+// It is uuid.Nil if the record is not taken.
+func (r *IntakeRecord) TakeID() uuid.UUID {
+	return r.takeID
+}
+
+// TakenBy returns the user who took the record.
+// It is uuid.Nil if the record is not taken.
+func (r *IntakeRecord) TakenBy() uuid.UUID {
+	return r.takenBy
+}
+
+// RemindedAt returns when the user was reminded about the intake.
+// It is zero if reminder is not sent.
+func (r *IntakeRecord) RemindedAt() time.Time {
+	return r.remindedAt
+}
+
+// Escalations returns a number of fired escalation steps.
+func (r *IntakeRecord) Escalations() int {
+	return r.escalations
+}
+
 // Status returns the status of the record.
 func (r *IntakeRecord) Status() Status {
 	return r.status
@@ -0,0 +1,260 @@
+package record_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/FSO-VK/final-project-vk-backend/internal/planning/domain/record"
+	"github.com/google/uuid"
+)
+
+func TestIntakeRecord_MarkTaken(t *testing.T) {
+	t.Parallel()
+	now := time.Now()
+	tests := []struct {
+		name string // description of this test case
+		// Named input parameters for target function.
+		takenAt time.Time
+		amount  float64
+		wantErr error
+	}{
+		{
+			name:    "Should mark record taken with actual time and partial dose",
+			takenAt: now.Add(-30 * time.Minute),
+			amount:  0.5,
+			wantErr: nil,
+		},
+		{
+			name:    "Should tolerate small clock skew",
+			takenAt: now.Add(time.Minute),
+			amount:  1,
+			wantErr: nil,
+		},
+		{
+			name:    "Should reject intake time far in the future",
+			takenAt: now.Add(24 * time.Hour),
+			amount:  1,
+			wantErr: record.ErrTakenInFuture,
+		},
+		{
+			name:    "Should reject non-positive amount",
+			takenAt: now,
+			amount:  0,
+			wantErr: record.ErrInvalidTakenAmount,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			r, err := record.NewIntakeRecord(uuid.New(), uuid.New(), now, 1, now, now)
+			if err != nil {
+				t.Fatalf("could not construct receiver type: %v", err)
+			}
+			_, gotErr := r.MarkTaken(tt.takenAt, tt.amount, uuid.New(), uuid.New())
+			if !errors.Is(gotErr, tt.wantErr) {
+				t.Fatalf("MarkTaken() error = %v, want %v", gotErr, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if r.IsTaken() {
+					t.Error("MarkTaken() failed but record is taken")
+				}
+				return
+			}
+			if !r.TakenAt().Equal(tt.takenAt) || r.TakenAmount() != tt.amount {
+				t.Errorf(
+					"MarkTaken() = (%v, %v), want (%v, %v)",
+					r.TakenAt(), r.TakenAmount(), tt.takenAt, tt.amount,
+				)
+			}
+		})
+	}
+}
+
+func TestIntakeRecord_Snooze(t *testing.T) {
+	t.Parallel()
+	now := time.Now()
+	tests := []struct {
+		name string // description of this test case
+		// Named input parameters for target function.
+		taken   bool
+		d       time.Duration
+		wantErr error
+	}{
+		{
+			name:    "Should snooze reminder",
+			d:       10 * time.Minute,
+			wantErr: nil,
+		},
+		{
+			name:    "Should reject non-positive duration",
+			d:       0,
+			wantErr: record.ErrInvalidSnooze,
+		},
+		{
+			name:    "Should reject too long duration",
+			d:       3 * time.Hour,
+			wantErr: record.ErrInvalidSnooze,
+		},
+		{
+			name:    "Should reject taken intake",
+			taken:   true,
+			d:       10 * time.Minute,
+			wantErr: record.ErrRecordTaken,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			r, err := record.NewIntakeRecord(uuid.New(), uuid.New(), now, 1, now, now)
+			if err != nil {
+				t.Fatalf("could not construct receiver type: %v", err)
+			}
+			if tt.taken {
+				if _, err := r.MarkTaken(now, 1, uuid.New(), uuid.New()); err != nil {
+					t.Fatalf("could not take intake: %v", err)
+				}
+			}
+			gotErr := r.Snooze(now, tt.d)
+			if !errors.Is(gotErr, tt.wantErr) {
+				t.Fatalf("Snooze() error = %v, want %v", gotErr, tt.wantErr)
+			}
+			want := time.Time{}
+			if tt.wantErr == nil {
+				want = now.Add(tt.d)
+			}
+			if !r.SnoozedUntil().Equal(want) {
+				t.Errorf("SnoozedUntil() = %v, want %v", r.SnoozedUntil(), want)
+			}
+		})
+	}
+}
+
+func TestIntakeRecord_IsDueForReminder(t *testing.T) {
+	t.Parallel()
+	plannedAt := time.Date(2025, time.March, 10, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string // description of this test case
+		// Named input parameters for target function.
+		since    time.Time
+		until    time.Time
+		taken    bool
+		reminded bool
+		want     bool
+	}{
+		{
+			name:  "Should remind about intake planned in the window",
+			since: plannedAt.Add(-time.Minute),
+			until: plannedAt.Add(time.Minute),
+			want:  true,
+		},
+		{
+			name:  "Should remind about intake planned at the end of the window",
+			since: plannedAt.Add(-time.Minute),
+			until: plannedAt,
+			want:  true,
+		},
+		{
+			name:  "Should not remind about intake planned at the start of the window",
+			since: plannedAt,
+			until: plannedAt.Add(time.Minute),
+			want:  false,
+		},
+		{
+			name:  "Should not remind about future intake",
+			since: plannedAt.Add(-2 * time.Minute),
+			until: plannedAt.Add(-time.Minute),
+			want:  false,
+		},
+		{
+			name:     "Should not remind twice",
+			since:    plannedAt.Add(-time.Minute),
+			until:    plannedAt.Add(time.Minute),
+			reminded: true,
+			want:     false,
+		},
+		{
+			name:  "Should not remind about taken intake",
+			since: plannedAt.Add(-time.Minute),
+			until: plannedAt.Add(time.Minute),
+			taken: true,
+			want:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			r, err := record.NewIntakeRecord(
+				uuid.New(),
+				uuid.New(),
+				plannedAt,
+				1,
+				plannedAt,
+				plannedAt,
+			)
+			if err != nil {
+				t.Fatalf("could not construct receiver type: %v", err)
+			}
+			if tt.taken {
+				if _, err := r.MarkTaken(plannedAt, 1, uuid.New(), uuid.New()); err != nil {
+					t.Fatalf("could not take intake: %v", err)
+				}
+			}
+			if tt.reminded {
+				r.MarkReminded(plannedAt)
+			}
+			if got := r.IsDueForReminder(tt.since, tt.until); got != tt.want {
+				t.Errorf("IsDueForReminder() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIntakeRecord_RescheduleRemindsAgain(t *testing.T) {
+	t.Parallel()
+	plannedAt := time.Date(2025, time.March, 10, 9, 0, 0, 0, time.UTC)
+	r, err := record.NewIntakeRecord(uuid.New(), uuid.New(), plannedAt, 1, plannedAt, plannedAt)
+	if err != nil {
+		t.Fatalf("could not construct receiver type: %v", err)
+	}
+	r.MarkReminded(plannedAt)
+	newPlannedAt := plannedAt.Add(time.Hour)
+	if _, err := r.Reschedule(newPlannedAt); err != nil {
+		t.Fatalf("Reschedule() error = %v", err)
+	}
+	if r.IsReminded() {
+		t.Errorf("IsReminded() = true after reschedule")
+	}
+	if !r.IsDueForReminder(plannedAt, newPlannedAt) {
+		t.Errorf("IsDueForReminder() = false at the new time")
+	}
+}
+
+func TestIntakeRecord_NextTakeID(t *testing.T) {
+	t.Parallel()
+	now := time.Now()
+	r, err := record.NewIntakeRecord(uuid.New(), uuid.New(), now, 1, now, now)
+	if err != nil {
+		t.Fatalf("could not construct receiver type: %v", err)
+	}
+
+	first := r.NextTakeID(1)
+	if retried := r.NextTakeID(1); retried != first {
+		t.Errorf("NextTakeID() = %v on retry, want %v", retried, first)
+	}
+	if partial := r.NextTakeID(0.5); partial == first {
+		t.Errorf("NextTakeID() is the same for different amounts")
+	}
+
+	if _, err := r.MarkTaken(now, 1, first, uuid.Nil); err != nil {
+		t.Fatalf("MarkTaken() error = %v", err)
+	}
+	if next := r.NextTakeID(1); next == first {
+		t.Errorf("NextTakeID() is the same after the take")
+	}
+
+	r.Cancel()
+	if again := r.NextTakeID(1); again == first {
+		t.Errorf("NextTakeID() is the same after the take is canceled")
+	}
+}
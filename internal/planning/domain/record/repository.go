@@ -3,7 +3,6 @@ package record
 import (
 	"context"
 	"errors"
-	"iter"
 	"time"
 
 	"github.com/google/uuid"
@@ -19,5 +18,12 @@ type Repository interface {
 	Save(ctx context.Context, record *IntakeRecord) error
 	UpdateByID(ctx context.Context, record *IntakeRecord) error
 	SaveBulk(ctx context.Context, records []*IntakeRecord) error
-	RecordsByTime(ctx context.Context, time time.Time) (iter.Seq[*IntakeRecord], error)
+	DeleteByID(ctx context.Context, id uuid.UUID) error
+	// RecordsToRemind returns records the user must be reminded about,
+	// which are planned after since and not later than until.
+	RecordsToRemind(ctx context.Context, since time.Time, until time.Time) ([]*IntakeRecord, error)
+	// RecordsToEscalate returns not taken records reminded after since.
+	RecordsToEscalate(ctx context.Context, since time.Time) ([]*IntakeRecord, error)
+	// RecordsSnoozedUntil returns not taken records whose snoozed reminder is due at t.
+	RecordsSnoozedUntil(ctx context.Context, t time.Time) ([]*IntakeRecord, error)
 }
@@ -10,14 +10,15 @@ const (
 	StatusMissed
 )
 
+// String returns code of the status, clients show it in the language of the user.
 func (s Status) String() string {
 	switch s {
 	case StatusDraft:
-		return "Запланировано"
+		return "planned"
 	case StatusTaken:
-		return "Принято"
+		return "taken"
 	case StatusMissed:
-		return "Пропущено"
+		return "missed"
 	}
-	return "Невозможный статус"
+	return ""
 }
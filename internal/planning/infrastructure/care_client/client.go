@@ -0,0 +1,88 @@
+// Package careclient implements CareService interface for getting caregivers from auth service.
+package careclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/FSO-VK/final-project-vk-backend/internal/planning/application/care"
+	"github.com/FSO-VK/final-project-vk-backend/pkg/api"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// CareClient implements CareService.
+type CareClient struct {
+	client *http.Client
+	cfg    ClientConfig
+	logger *logrus.Entry
+}
+
+// NewCareClient creates a new CareClient.
+func NewCareClient(cfg ClientConfig, logger *logrus.Entry) *CareClient {
+	client := &http.Client{
+		Timeout:       cfg.Timeout,
+		Transport:     nil,
+		CheckRedirect: nil,
+		Jar:           nil,
+	}
+	return &CareClient{client: client, cfg: cfg, logger: logger}
+}
+
+type caregiversExpectedResponse struct {
+	Name         string   `json:"name"`
+	CaregiverIDs []string `json:"caregiverIds"`
+}
+
+// Caregivers implements CareService interface.
+func (h *CareClient) Caregivers(ctx context.Context, userID uuid.UUID) (*care.Caregivers, error) {
+	if h.cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, h.cfg.Timeout)
+		defer cancel()
+	}
+
+	query := url.Values{"userId": []string{userID.String()}}
+	httpReq, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodGet,
+		h.cfg.Endpoint+"?"+query.Encode(),
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.client.Do(httpReq)
+	if err != nil {
+		h.logger.WithError(err).Warn("care API request failed")
+		return nil, ErrAuthServiceUnavailable
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return nil, ErrBadResponse
+	}
+
+	var parsedResponse api.Response[caregiversExpectedResponse]
+	if err := json.NewDecoder(resp.Body).Decode(&parsedResponse); err != nil {
+		h.logger.WithError(err).Error("failed to decode care API response")
+		return nil, fmt.Errorf("%w: %w", ErrBadResponse, err)
+	}
+	caregivers := &care.Caregivers{
+		Name: parsedResponse.Body.Name,
+		IDs:  make([]uuid.UUID, 0, len(parsedResponse.Body.CaregiverIDs)),
+	}
+	for _, id := range parsedResponse.Body.CaregiverIDs {
+		parsedID, err := uuid.Parse(id)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrBadResponse, err)
+		}
+		caregivers.IDs = append(caregivers.IDs, parsedID)
+	}
+	return caregivers, nil
+}
@@ -0,0 +1,9 @@
+package careclient
+
+import "time"
+
+// ClientConfig is configuration for caregivers client.
+type ClientConfig struct {
+	Endpoint string
+	Timeout  time.Duration // общий timeout запроса (30c)
+}
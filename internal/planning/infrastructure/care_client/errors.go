@@ -0,0 +1,10 @@
+package careclient
+
+import "errors"
+
+var (
+	// ErrAuthServiceUnavailable is returned when auth service is unavailable.
+	ErrAuthServiceUnavailable = errors.New("care api: service unavailable")
+	// ErrBadResponse is returned when the response status code is not 200 or body is not like expected.
+	ErrBadResponse = errors.New("care api: invalid response not 200 or body is not like expected")
+)
@@ -2,16 +2,28 @@
 package config
 
 import (
+	care "github.com/FSO-VK/final-project-vk-backend/internal/planning/infrastructure/care_client"
 	medication "github.com/FSO-VK/final-project-vk-backend/internal/planning/infrastructure/medication_client"
 	"github.com/FSO-VK/final-project-vk-backend/internal/planning/presentation/http"
 	notification "github.com/FSO-VK/final-project-vk-backend/internal/utils/notification_client"
 	auth "github.com/FSO-VK/final-project-vk-backend/pkg/auth/client"
+	"github.com/FSO-VK/final-project-vk-backend/pkg/workerpool"
 )
 
 // Config is a configuration for the planning service.
 type Config struct {
 	Server       http.ServerConfig
+	Internal     http.ServerConfig
 	Auth         auth.ClientConfig
 	Medication   medication.ClientConfig
 	Notification notification.ClientConfig
+	Care         care.ClientConfig
+	Schedule     ScheduleConfig
+	Jobs         workerpool.Config
+}
+
+// ScheduleConfig is a configuration of plan schedules.
+type ScheduleConfig struct {
+	// DefaultTimeZone is an IANA time zone of plans created without one.
+	DefaultTimeZone string
 }
@@ -2,11 +2,16 @@
 package medicationclient
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"maps"
 	"net/http"
+	"slices"
 
+	"github.com/FSO-VK/final-project-vk-backend/internal/planning/application/medication"
+	"github.com/FSO-VK/final-project-vk-backend/pkg/api"
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 )
@@ -91,3 +96,209 @@ func (h *MedicationClient) makeRequest(
 	}
 	return parsedResponse.Body, nil
 }
+
+// maxNamesPerRequest is how many medications medication service
+// returns names of in one request.
+const maxNamesPerRequest = 1000
+
+type namesRequest struct {
+	Medications []medicationRef `json:"medications"`
+}
+
+type medicationRef struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"userId"`
+}
+
+type namesResponse struct {
+	StatusCode int `json:"statusCode"`
+	Body       struct {
+		Names map[uuid.UUID]string `json:"names"`
+	} `json:"body"`
+}
+
+// MedicationNames implements MedicationService interface.
+// Medications are requested by batches of maxNamesPerRequest.
+func (h *MedicationClient) MedicationNames(
+	ctx context.Context,
+	refs []medication.MedicationRef,
+) (map[uuid.UUID]string, error) {
+	names := make(map[uuid.UUID]string, len(refs))
+	for batch := range slices.Chunk(refs, maxNamesPerRequest) {
+		batchNames, err := h.namesBatch(ctx, batch)
+		if err != nil {
+			return nil, err
+		}
+		maps.Copy(names, batchNames)
+	}
+	return names, nil
+}
+
+func (h *MedicationClient) namesBatch(
+	ctx context.Context,
+	refs []medication.MedicationRef,
+) (map[uuid.UUID]string, error) {
+	if h.cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, h.cfg.Timeout)
+		defer cancel()
+	}
+
+	body := namesRequest{Medications: make([]medicationRef, 0, len(refs))}
+	for _, ref := range refs {
+		body.Medications = append(body.Medications, medicationRef(ref))
+	}
+	jsonBody, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal names body: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPost,
+		h.cfg.Endpoint+"names",
+		bytes.NewBuffer(jsonBody),
+	)
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := h.client.Do(httpReq)
+	if err != nil {
+		h.logger.WithError(err).Warn("medication API request failed")
+		return nil, ErrMedicationServiceUnavailable
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		h.logger.Warnf("medication service responded with %d", resp.StatusCode)
+		return nil, ErrBadResponse
+	}
+
+	var parsedResponse namesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsedResponse); err != nil {
+		h.logger.WithError(err).Error("failed to decode medication API response")
+		return nil, fmt.Errorf("%w: %w", ErrBadResponse, err)
+	}
+	return parsedResponse.Body.Names, nil
+}
+
+// idempotencyKeyHeader is a header with idempotency key of stock operation.
+const idempotencyKeyHeader = "Idempotency-Key"
+
+// msgIdempotencyKeyReused is an error of medication service response
+// telling that the key is used for another operation.
+const msgIdempotencyKeyReused api.ErrorType = "Idempotency key is used for another operation"
+
+// planUnitToMedicationUnit maps plan dosage units to units of medication service.
+//
+//nolint:gochecknoglobals
+var planUnitToMedicationUnit = map[string]string{
+	"мг.": "мг.",
+	"шт.": "шт.",
+	"мл.": "мл.",
+}
+
+type changeStockRequest struct {
+	Value   float64 `json:"value"`
+	Unit    string  `json:"unit"`
+	ActorID string  `json:"actorId"`
+}
+
+// TakeDose implements MedicationService interface.
+func (h *MedicationClient) TakeDose(
+	ctx context.Context,
+	id uuid.UUID,
+	userID uuid.UUID,
+	actorID uuid.UUID,
+	dose medication.Dose,
+	idempotencyKey string,
+) error {
+	return h.changeStock(ctx, id, userID, actorID, "take", dose, idempotencyKey)
+}
+
+// RestoreDose implements MedicationService interface.
+func (h *MedicationClient) RestoreDose(
+	ctx context.Context,
+	id uuid.UUID,
+	userID uuid.UUID,
+	actorID uuid.UUID,
+	dose medication.Dose,
+	idempotencyKey string,
+) error {
+	return h.changeStock(ctx, id, userID, actorID, "restore", dose, idempotencyKey)
+}
+
+func (h *MedicationClient) changeStock(
+	ctx context.Context,
+	id uuid.UUID,
+	userID uuid.UUID,
+	actorID uuid.UUID,
+	operation string,
+	dose medication.Dose,
+	idempotencyKey string,
+) error {
+	unit, ok := planUnitToMedicationUnit[dose.Unit]
+	if !ok {
+		return medication.ErrIncompatibleUnit
+	}
+
+	if h.cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, h.cfg.Timeout)
+		defer cancel()
+	}
+
+	jsonBody, err := json.Marshal(changeStockRequest{
+		Value:   dose.Value,
+		Unit:    unit,
+		ActorID: actorID.String(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal stock body: %w", err)
+	}
+
+	url := h.cfg.Endpoint + id.String() + "/" + userID.String() + "/" + operation
+	httpReq, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPost,
+		url,
+		bytes.NewBuffer(jsonBody),
+	)
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set(idempotencyKeyHeader, idempotencyKey)
+
+	resp, err := h.client.Do(httpReq)
+	if err != nil {
+		h.logger.WithError(err).Warn("medication API request failed")
+		return ErrMedicationServiceUnavailable
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusNotFound:
+		return ErrNoMedicationFound
+	case http.StatusConflict:
+		// conflict is either low stock or reuse of the key, they differ by error
+		var parsedResponse api.Response[struct{}]
+		_ = json.NewDecoder(resp.Body).Decode(&parsedResponse)
+		if parsedResponse.Error == msgIdempotencyKeyReused {
+			return medication.ErrIdempotencyKeyReused
+		}
+		return medication.ErrNotEnoughMedication
+	case http.StatusUnprocessableEntity:
+		return medication.ErrIncompatibleUnit
+	default:
+		h.logger.Warnf("medication service responded with %d", resp.StatusCode)
+		return ErrBadResponse
+	}
+}
@@ -20,9 +20,24 @@ func (a *NotificationProvider) SendNotification(
 	ctx context.Context,
 	info notification.NotificationInfo,
 ) error {
+	actions := make([]client.NotificationAction, 0, len(info.Actions))
+	for _, action := range info.Actions {
+		actions = append(actions, client.NotificationAction(action))
+	}
 	return a.client.SendNotification(ctx, client.NotificationInfo{
-		UserID: info.UserID,
-		Title:  info.Title,
-		Body:   info.Body,
+		UserID:    info.UserID,
+		Category:  info.Category,
+		Type:      info.Type,
+		Title:     info.Title,
+		Body:      info.Body,
+		URL:       info.URL,
+		Tag:       info.Tag,
+		Renotify:  info.Renotify,
+		Urgency:   info.Urgency,
+		Actions:   actions,
+		Data:      info.Data,
+		Template:  info.Template,
+		Params:    info.Params,
+		DedupeKey: info.DedupeKey,
 	})
 }
@@ -0,0 +1,81 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/FSO-VK/final-project-vk-backend/internal/planning/domain/calendar"
+	"github.com/google/uuid"
+)
+
+// errGotNilFeed is an error when save gets nil feed to add.
+var errGotNilFeed = errors.New("cannot save nil calendar feed")
+
+// FeedStorage is a storage for calendar feeds.
+type FeedStorage struct {
+	byToken map[string]*calendar.Feed
+	byUser  map[uuid.UUID]*calendar.Feed
+
+	mu *sync.RWMutex
+}
+
+// NewFeedStorage returns a new FeedStorage.
+func NewFeedStorage() *FeedStorage {
+	return &FeedStorage{
+		byToken: make(map[string]*calendar.Feed),
+		byUser:  make(map[uuid.UUID]*calendar.Feed),
+		mu:      &sync.RWMutex{},
+	}
+}
+
+// GetByToken returns a feed by its secret token.
+func (s *FeedStorage) GetByToken(
+	_ context.Context,
+	token string,
+) (*calendar.Feed, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	feed, ok := s.byToken[token]
+	if !ok {
+		return nil, calendar.ErrNoFeedFound
+	}
+	return feed, nil
+}
+
+// Save saves a feed replacing the previous feed of the user.
+func (s *FeedStorage) Save(
+	_ context.Context,
+	feed *calendar.Feed,
+) error {
+	if feed == nil {
+		return errGotNilFeed
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if prev, ok := s.byUser[feed.UserID()]; ok {
+		delete(s.byToken, prev.Token())
+	}
+	s.byUser[feed.UserID()] = feed
+	s.byToken[feed.Token()] = feed
+	return nil
+}
+
+// DeleteByUserID deletes the feed of the user.
+func (s *FeedStorage) DeleteByUserID(
+	_ context.Context,
+	userID uuid.UUID,
+) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	feed, ok := s.byUser[userID]
+	if !ok {
+		return calendar.ErrNoFeedFound
+	}
+	delete(s.byToken, feed.Token())
+	delete(s.byUser, userID)
+	return nil
+}
@@ -3,7 +3,6 @@ package memory
 import (
 	"context"
 	"errors"
-	"iter"
 	"sync"
 	"time"
 
@@ -97,27 +96,58 @@ func (s *RecordStorage) GetByPlanID(
 	return result, nil
 }
 
-// RecordsByTime returns all records with planned at the same time.
-func (s *RecordStorage) RecordsByTime(
+// RecordsToRemind returns not reminded records planned after since
+// and not later than until.
+func (s *RecordStorage) RecordsToRemind(
+	_ context.Context,
+	since time.Time,
+	until time.Time,
+) ([]*record.IntakeRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var records []*record.IntakeRecord
+	for _, rec := range s.data.GetAll() {
+		if rec.IsDueForReminder(since, until) {
+			records = append(records, rec)
+		}
+	}
+	return records, nil
+}
+
+// RecordsToEscalate returns not taken records reminded after since.
+func (s *RecordStorage) RecordsToEscalate(
+	_ context.Context,
+	since time.Time,
+) ([]*record.IntakeRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var records []*record.IntakeRecord
+	for _, rec := range s.data.GetAll() {
+		if !rec.IsTaken() && rec.RemindedAt().After(since) {
+			records = append(records, rec)
+		}
+	}
+	return records, nil
+}
+
+// RecordsSnoozedUntil returns not taken records whose snoozed reminder is due at t.
+func (s *RecordStorage) RecordsSnoozedUntil(
 	_ context.Context,
 	t time.Time,
-) (iter.Seq[*record.IntakeRecord], error) {
-	return func(yield func(*record.IntakeRecord) bool) {
-		s.mu.RLock()
-		defer s.mu.RUnlock()
-
-		all := s.data.GetAll()
-
-		for _, rec := range all {
-			now := time.Now().Truncate(time.Minute)
-			next := now.Add(time.Minute)
-			if !rec.PlannedTime().Before(now) && rec.PlannedTime().Before(next) {
-				if !yield(rec) {
-					return
-				}
-			}
+) ([]*record.IntakeRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var records []*record.IntakeRecord
+	for _, rec := range s.data.GetAll() {
+		snoozedUntil := rec.SnoozedUntil()
+		if !rec.IsTaken() && !snoozedUntil.IsZero() && !snoozedUntil.After(t) {
+			records = append(records, rec)
 		}
-	}, nil
+	}
+	return records, nil
 }
 
 // UpdateByID updates an existing record by id.
@@ -141,3 +171,19 @@ func (s *RecordStorage) UpdateByID(
 	s.data.Set(id, updatedRecord)
 	return nil
 }
+
+// DeleteByID deletes a record by id.
+func (s *RecordStorage) DeleteByID(
+	_ context.Context,
+	id uuid.UUID,
+) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.data.Get(id.String()); !exists {
+		return record.ErrNoRecordFound
+	}
+	s.data.Delete(id.String())
+	s.count--
+	return nil
+}
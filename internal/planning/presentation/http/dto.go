@@ -1,5 +1,7 @@
 package http
 
+import "github.com/FSO-VK/final-project-vk-backend/internal/planning/application"
+
 // PlanObject is info about plan.
 type PlanObject struct {
 	MedicationID   string       `json:"medicationId"`
@@ -8,7 +10,43 @@ type PlanObject struct {
 	Status         string       `json:"status"`
 	StartDate      string       `json:"startDate"`
 	EndDate        string       `json:"endDate"`
+	Kind           string       `json:"kind,omitempty"`
+	TimeZone       string       `json:"timeZone,omitempty"`
 	RecurrenceRule []string     `json:"recurrenceRule"`
+	// AsNeeded is set for as-needed plan only.
+	AsNeeded *AsNeededObject `json:"asNeeded,omitempty"`
+	// Phases are set for plan which dosage changes over time.
+	Phases []PhaseObject `json:"phases,omitempty"`
+	// Schedule is a human-friendly alternative to recurrence rules,
+	// it is set in response if the rules are produced by a schedule.
+	Schedule *ScheduleObject `json:"schedule,omitempty"`
+}
+
+// ScheduleObject is a structure of JSON object of schedule preset.
+// Kind is one of "times_a_day", "every_n_hours", "weekdays", "every_n_days", "cycle".
+type ScheduleObject struct {
+	Kind          string   `json:"kind"`
+	Times         []string `json:"times"`
+	IntervalHours int      `json:"intervalHours,omitempty"`
+	IntervalDays  int      `json:"intervalDays,omitempty"`
+	Weekdays      []string `json:"weekdays,omitempty"`
+	DaysOn        int      `json:"daysOn,omitempty"`
+	DaysOff       int      `json:"daysOff,omitempty"`
+}
+
+// PhaseObject is a structure of JSON object of plan phase.
+type PhaseObject struct {
+	Amount         AmountObject `json:"amount"`
+	StartDate      string       `json:"startDate"`
+	EndDate        string       `json:"endDate"`
+	RecurrenceRule []string     `json:"recurrenceRule"`
+}
+
+// AsNeededObject is a structure of JSON object of as-needed plan limits.
+type AsNeededObject struct {
+	MinIntervalMinutes int `json:"minIntervalMinutes"`
+	MaxDoses           int `json:"maxDoses"`
+	WindowHours        int `json:"windowHours"`
 }
 
 // AmountObject is a structure of JSON object of amount of medication.
@@ -16,3 +54,92 @@ type AmountObject struct {
 	Value float64 `json:"value"`
 	Unit  string  `json:"unit"`
 }
+
+func asNeededToApplication(o *AsNeededObject) *application.AsNeededLimits {
+	if o == nil {
+		return nil
+	}
+	return &application.AsNeededLimits{
+		MinIntervalMinutes: o.MinIntervalMinutes,
+		MaxDoses:           o.MaxDoses,
+		WindowHours:        o.WindowHours,
+	}
+}
+
+func asNeededFromApplication(l *application.AsNeededLimits) *AsNeededObject {
+	if l == nil {
+		return nil
+	}
+	return &AsNeededObject{
+		MinIntervalMinutes: l.MinIntervalMinutes,
+		MaxDoses:           l.MaxDoses,
+		WindowHours:        l.WindowHours,
+	}
+}
+
+func phasesToApplication(phases []PhaseObject) []application.PlanPhase {
+	if len(phases) == 0 {
+		return nil
+	}
+	result := make([]application.PlanPhase, 0, len(phases))
+	for _, ph := range phases {
+		result = append(result, application.PlanPhase{
+			AmountValue:    ph.Amount.Value,
+			AmountUnit:     ph.Amount.Unit,
+			StartDate:      ph.StartDate,
+			EndDate:        ph.EndDate,
+			RecurrenceRule: ph.RecurrenceRule,
+		})
+	}
+	return result
+}
+
+func phasesFromApplication(phases []application.PlanPhase) []PhaseObject {
+	result := make([]PhaseObject, 0, len(phases))
+	for _, ph := range phases {
+		result = append(result, phaseFromApplication(ph))
+	}
+	return result
+}
+
+func phaseFromApplication(ph application.PlanPhase) PhaseObject {
+	return PhaseObject{
+		Amount: AmountObject{
+			Value: ph.AmountValue,
+			Unit:  ph.AmountUnit,
+		},
+		StartDate:      ph.StartDate,
+		EndDate:        ph.EndDate,
+		RecurrenceRule: ph.RecurrenceRule,
+	}
+}
+
+func scheduleToApplication(o *ScheduleObject) *application.SchedulePreset {
+	if o == nil {
+		return nil
+	}
+	return &application.SchedulePreset{
+		Kind:          o.Kind,
+		Times:         o.Times,
+		IntervalHours: o.IntervalHours,
+		IntervalDays:  o.IntervalDays,
+		Weekdays:      o.Weekdays,
+		DaysOn:        o.DaysOn,
+		DaysOff:       o.DaysOff,
+	}
+}
+
+func scheduleFromApplication(p *application.SchedulePreset) *ScheduleObject {
+	if p == nil {
+		return nil
+	}
+	return &ScheduleObject{
+		Kind:          p.Kind,
+		Times:         p.Times,
+		IntervalHours: p.IntervalHours,
+		IntervalDays:  p.IntervalDays,
+		Weekdays:      p.Weekdays,
+		DaysOn:        p.DaysOn,
+		DaysOff:       p.DaysOff,
+	}
+}
@@ -11,8 +11,44 @@ const (
 	MsgMissingSlug api.ErrorType = "Missing slug"
 	// MsgFailedToAddPlan is a message for failed to add plan.
 	MsgFailedToAddPlan api.ErrorType = "Failed to add plan"
+	// MsgInvalidFields is a message for request with invalid fields listed in the body.
+	MsgInvalidFields api.ErrorType = "Invalid fields"
 	// MsgFailedToGetSchedule is a message for failed to get schedule.
 	MsgFailedToGetSchedule api.ErrorType = "Failed to get schedule"
 	// MsgFailedToTakeMedication is a message for failed to take medication.
 	MsgFailedToTakeMedication api.ErrorType = "Failed to get take medication"
+	// MsgIncompatibleDoseUnit is a message for dose unit incompatible with stock unit.
+	MsgIncompatibleDoseUnit api.ErrorType = "Dose unit is incompatible with medication unit"
+	// MsgTakeConflict is a message for take applied to stock with another dose.
+	MsgTakeConflict api.ErrorType = "Take is already applied to stock with another dose"
+	// MsgFailedToUpdateStock is a message for failed to update medication stock.
+	MsgFailedToUpdateStock api.ErrorType = "Failed to update medication stock"
+	// MsgPlanNotAsNeeded is a message for ad-hoc intake of scheduled plan.
+	MsgPlanNotAsNeeded api.ErrorType = "Plan is not as-needed"
+	// MsgDoseLimitExceeded is a message for intake exceeding limits of as-needed plan.
+	MsgDoseLimitExceeded api.ErrorType = "Dose limit exceeded"
+	// MsgFailedToExportCalendar is a message for failed to render calendar of intakes.
+	MsgFailedToExportCalendar api.ErrorType = "Failed to export calendar"
+	// MsgNoCalendarFeed is a message for unknown or revoked calendar feed token.
+	MsgNoCalendarFeed api.ErrorType = "Calendar feed not found"
+	// MsgInvalidException is a message for exception that doesn't fit the plan schedule.
+	MsgInvalidException api.ErrorType = "Exception doesn't fit the plan schedule"
+	// MsgExceptionInPast is a message for exception changing an intake in the past.
+	MsgExceptionInPast api.ErrorType = "Exception is in the past"
+	// MsgIntakeAlreadyTaken is a message for excluding intake that is already taken.
+	MsgIntakeAlreadyTaken api.ErrorType = "Intake is already taken"
+	// MsgFailedToAddExceptions is a message for failed to change single intakes of plan.
+	MsgFailedToAddExceptions api.ErrorType = "Failed to add plan exceptions"
+	// MsgInvalidEscalation is a message for escalation policy that doesn't fit the plan.
+	MsgInvalidEscalation api.ErrorType = "Invalid escalation policy"
+	// MsgFailedToSetEscalation is a message for failed to change escalation policy of plan.
+	MsgFailedToSetEscalation api.ErrorType = "Failed to set escalation policy"
+	// MsgInvalidSnooze is a message for reminder postponed for too long.
+	MsgInvalidSnooze api.ErrorType = "Invalid snooze duration"
+	// MsgFailedToSnoozeIntake is a message for failed to postpone reminder about intake.
+	MsgFailedToSnoozeIntake api.ErrorType = "Failed to snooze intake"
+	// MsgInvalidCalendar is a message for uploaded calendar that can't be parsed.
+	MsgInvalidCalendar api.ErrorType = "Invalid calendar file"
+	// MsgFailedToImportPlans is a message for failed to import plans from calendar.
+	MsgFailedToImportPlans api.ErrorType = "Failed to import plans"
 )
@@ -3,19 +3,35 @@ package http
 
 import (
 	"errors"
+	"io"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/FSO-VK/final-project-vk-backend/internal/planning/application"
+	"github.com/FSO-VK/final-project-vk-backend/internal/planning/application/medication"
 	"github.com/FSO-VK/final-project-vk-backend/internal/utils/httputil"
 	"github.com/FSO-VK/final-project-vk-backend/pkg/api"
+	"github.com/FSO-VK/final-project-vk-backend/pkg/ical"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 )
 
 const (
 	// SlugID is a slug for id.
 	SlugID = "id"
+	// SlugUserID is a slug for user id in internal routes.
+	SlugUserID = "user_id"
+	// SlugToken is a slug for secret token of calendar feed.
+	SlugToken = "token"
+
+	// maxCalendarSize is a max size of uploaded calendar file.
+	maxCalendarSize = 1 << 20
+
+	// calendarFeedExt is an extension of calendar feed path, e.g. /calendar/{token}.ics.
+	calendarFeedExt = ".ics"
 )
 
 // PlanningHandlers is a handler for Planning.
@@ -61,7 +77,7 @@ func (h *PlanningHandlers) GetAllUsersPlans(c *gin.Context) {
 	}
 
 	serviceRequest := &application.GetAllPlansCommand{
-		UserID: auth.UserID,
+		UserID: auth.ProfileID,
 	}
 	plans, err := h.app.GetAllPlans.Execute(c.Request.Context(), serviceRequest)
 	if err != nil {
@@ -90,7 +106,12 @@ func (h *PlanningHandlers) GetAllUsersPlans(c *gin.Context) {
 				Status:         p.Status,
 				StartDate:      p.StartDate,
 				EndDate:        p.EndDate,
+				Kind:           p.Kind,
+				TimeZone:       p.TimeZone,
 				RecurrenceRule: p.RecurrenceRule,
+				AsNeeded:       asNeededFromApplication(p.AsNeeded),
+				Phases:         phasesFromApplication(p.Phases),
+				Schedule:       scheduleFromApplication(p.Schedule),
 			},
 			ID: p.ID,
 		})
@@ -141,22 +162,24 @@ func (h *PlanningHandlers) AddPlan(c *gin.Context) {
 	}
 	command := &application.AddPlanCommand{
 		MedicationID:   reqJSON.MedicationID,
-		UserID:         auth.UserID,
+		UserID:         auth.ProfileID,
 		AmountValue:    reqJSON.Amount.Value,
 		AmountUnit:     reqJSON.Amount.Unit,
 		Condition:      reqJSON.Condition,
 		StartDate:      reqJSON.StartDate,
 		EndDate:        reqJSON.EndDate,
+		Kind:           reqJSON.Kind,
+		TimeZone:       reqJSON.TimeZone,
 		RecurrenceRule: reqJSON.RecurrenceRule,
+		AsNeeded:       asNeededToApplication(reqJSON.AsNeeded),
+		Phases:         phasesToApplication(reqJSON.Phases),
+		Schedule:       scheduleToApplication(reqJSON.Schedule),
 	}
 	serviceResponse, err := h.app.AddPlan.Execute(c.Request.Context(), command)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to add plan")
-		c.JSON(http.StatusInternalServerError, api.Response[any]{
-			StatusCode: http.StatusInternalServerError,
-			Body:       struct{}{},
-			Error:      MsgFailedToAddPlan,
-		})
+		status, body := h.handleAddPlanServiceError(err)
+		c.JSON(status, body)
 		return
 	}
 
@@ -171,7 +194,12 @@ func (h *PlanningHandlers) AddPlan(c *gin.Context) {
 			Status:         serviceResponse.Status,
 			StartDate:      serviceResponse.StartDate,
 			EndDate:        serviceResponse.EndDate,
+			Kind:           serviceResponse.Kind,
+			TimeZone:       serviceResponse.TimeZone,
 			RecurrenceRule: serviceResponse.RecurrenceRule,
+			AsNeeded:       asNeededFromApplication(serviceResponse.AsNeeded),
+			Phases:         phasesFromApplication(serviceResponse.Phases),
+			Schedule:       scheduleFromApplication(serviceResponse.Schedule),
 		},
 		ID: serviceResponse.ID,
 	}
@@ -189,6 +217,21 @@ type GetPlanByIDJSONResponse struct {
 	PlanObject
 
 	ID string `json:"id"`
+	// CurrentPhase is set if phase of the plan is active now.
+	CurrentPhase *CurrentPhaseObject `json:"currentPhase,omitempty"`
+	// ExcludedDates are skipped intakes, ExtraDates are one-off intakes.
+	ExcludedDates []string `json:"excludedDates,omitempty"`
+	ExtraDates    []string `json:"extraDates,omitempty"`
+	// Escalation is empty if missed intakes are not escalated.
+	Escalation []EscalationStepObject `json:"escalation,omitempty"`
+}
+
+// CurrentPhaseObject is a phase of the plan active now.
+type CurrentPhaseObject struct {
+	// embedded struct
+	PhaseObject `json:",inline"`
+
+	Index int `json:"index"`
 }
 
 // GetPlanByID gets plan by id.
@@ -215,7 +258,7 @@ func (h *PlanningHandlers) GetPlanByID(c *gin.Context) {
 	}
 
 	command := &application.GetPlanCommand{
-		UserID: auth.UserID,
+		UserID: auth.ProfileID,
 		ID:     slugPlanID,
 	}
 
@@ -241,9 +284,24 @@ func (h *PlanningHandlers) GetPlanByID(c *gin.Context) {
 			Status:         p.Status,
 			StartDate:      p.StartDate,
 			EndDate:        p.EndDate,
+			Kind:           p.Kind,
+			TimeZone:       p.TimeZone,
 			RecurrenceRule: p.RecurrenceRule,
+			AsNeeded:       asNeededFromApplication(p.AsNeeded),
+			Phases:         phasesFromApplication(p.Phases),
+			Schedule:       scheduleFromApplication(p.Schedule),
 		},
-		ID: p.ID,
+		ID:            p.ID,
+		CurrentPhase:  nil,
+		ExcludedDates: p.ExDates,
+		ExtraDates:    p.RDates,
+		Escalation:    escalationFromApplication(p.Escalation),
+	}
+	if p.CurrentPhase != nil {
+		response.CurrentPhase = &CurrentPhaseObject{
+			PhaseObject: phaseFromApplication(p.CurrentPhase.PlanPhase),
+			Index:       p.CurrentPhase.Index,
+		}
 	}
 
 	c.JSON(http.StatusOK, api.Response[any]{
@@ -282,7 +340,7 @@ func (h *PlanningHandlers) FinishPlan(c *gin.Context) {
 	}
 
 	command := &application.FinishPlanCommand{
-		UserID: auth.UserID,
+		UserID: auth.ProfileID,
 		ID:     slugPlanID,
 	}
 
@@ -304,7 +362,8 @@ func (h *PlanningHandlers) FinishPlan(c *gin.Context) {
 	})
 }
 
-// GetAllUsersPlansItem returns single schedule item.
+// GetAllUsersPlansItem returns single schedule item,
+// status is one of "planned", "taken" and "missed".
 type ShowScheduleItem struct {
 	IntakeRecordID string       `json:"intakeRecordId"`
 	MedicationID   string       `json:"medicationId"`
@@ -313,6 +372,8 @@ type ShowScheduleItem struct {
 	Status         string       `json:"status"`
 	PlannedAt      string       `json:"plannedAt"`
 	TakenAt        string       `json:"takenAt,omitempty"`
+	TakenAmount    float64      `json:"takenAmount,omitempty"`
+	TakenBy        string       `json:"takenBy,omitempty"`
 }
 
 // ShowScheduleJSONResponse returns schedule.
@@ -333,7 +394,7 @@ func (h *PlanningHandlers) ShowSchedule(c *gin.Context) {
 	}
 
 	serviceRequest := &application.ShowScheduleCommand{
-		UserID:    auth.UserID,
+		UserID:    auth.ProfileID,
 		StartDate: c.Query("start"),
 		EndDate:   c.Query("end"),
 	}
@@ -351,6 +412,13 @@ func (h *PlanningHandlers) ShowSchedule(c *gin.Context) {
 	}
 
 	for _, s := range sh.Schedule {
+		var takenAt, takenBy string
+		if !s.TakenAt.IsZero() {
+			takenAt = s.TakenAt.Format(time.RFC3339)
+		}
+		if s.TakenBy != uuid.Nil {
+			takenBy = s.TakenBy.String()
+		}
 		response.Schedule = append(response.Schedule, ShowScheduleItem{
 			IntakeRecordID: s.IntakeRecordID.String(),
 			MedicationID:   s.MedicationID.String(),
@@ -359,9 +427,11 @@ func (h *PlanningHandlers) ShowSchedule(c *gin.Context) {
 				Value: s.AmountValue,
 				Unit:  s.AmountUnit,
 			},
-			Status:    s.Status,
-			PlannedAt: s.PlannedAt.Format(time.RFC3339),
-			TakenAt:   s.TakenAt.Format(time.RFC3339),
+			Status:      s.Status,
+			PlannedAt:   s.PlannedAt.Format(time.RFC3339),
+			TakenAt:     takenAt,
+			TakenAmount: s.TakenAmount,
+			TakenBy:     takenBy,
 		})
 	}
 
@@ -372,19 +442,45 @@ func (h *PlanningHandlers) ShowSchedule(c *gin.Context) {
 	})
 }
 
-// TakeMedication makes record taken by time it was planned.
+// TakeMedicationJSONRequest is an optional request body for TakeMedication.
+type TakeMedicationJSONRequest struct {
+	TakenAt string  `json:"takenAt"`
+	Amount  float64 `json:"amount"`
+}
+
+// TakeMedicationJSONResponse is a response for TakeMedication and ChangeTakeMedication.
+type TakeMedicationJSONResponse struct {
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// TakeMedication makes record taken by actual intake time and dose.
+// If the body is empty, record is taken now with the plan dosage.
 func (h *PlanningHandlers) TakeMedication(c *gin.Context) {
 	recordID, userID, ok := h.extractMedicationParams(c)
 	if !ok {
 		return
 	}
 
+	var reqJSON TakeMedicationJSONRequest
+	if err := c.ShouldBindJSON(&reqJSON); err != nil && !errors.Is(err, io.EOF) {
+		h.logger.WithError(err).Error("Failed to bind request body")
+		c.JSON(http.StatusBadRequest, api.Response[any]{
+			StatusCode: http.StatusBadRequest,
+			Body:       struct{}{},
+			Error:      api.MsgBadBody,
+		})
+		return
+	}
+
 	command := &application.TakeMedicationCommand{
-		RecordID: recordID,
-		UserID:   userID,
+		RecordID:    recordID,
+		UserID:      userID,
+		TakenAt:     reqJSON.TakenAt,
+		AmountValue: reqJSON.Amount,
+		ActorID:     h.actorID(c),
 	}
 
-	_, err := h.app.TakeMedication.Execute(c.Request.Context(), command)
+	taken, err := h.app.TakeMedication.Execute(c.Request.Context(), command)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to take medication")
 		status, body := h.handleTakeMedicationServiceError(err)
@@ -392,10 +488,12 @@ func (h *PlanningHandlers) TakeMedication(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, api.Response[struct{}]{
+	c.JSON(http.StatusOK, api.Response[any]{
 		StatusCode: http.StatusOK,
-		Body:       struct{}{},
-		Error:      "",
+		Body: &TakeMedicationJSONResponse{
+			Warnings: taken.Warnings,
+		},
+		Error: "",
 	})
 }
 
@@ -426,9 +524,10 @@ func (h *PlanningHandlers) ChangeTakeMedication(c *gin.Context) {
 		RecordID: recordID,
 		UserID:   userID,
 		TakenAt:  reqJSON.TakingTime,
+		ActorID:  h.actorID(c),
 	}
 
-	_, err := h.app.ChangeTakeMedication.Execute(c.Request.Context(), command)
+	changed, err := h.app.ChangeTakeMedication.Execute(c.Request.Context(), command)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to change medication take time")
 		status, body := h.handleTakeMedicationServiceError(err)
@@ -436,10 +535,12 @@ func (h *PlanningHandlers) ChangeTakeMedication(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, api.Response[struct{}]{
+	c.JSON(http.StatusOK, api.Response[any]{
 		StatusCode: http.StatusOK,
-		Body:       struct{}{},
-		Error:      "",
+		Body: &TakeMedicationJSONResponse{
+			Warnings: changed.Warnings,
+		},
+		Error: "",
 	})
 }
 
@@ -468,7 +569,8 @@ func (h *PlanningHandlers) CancelMedicationTake(c *gin.Context) {
 
 	command := &application.CancelMedicationTakeCommand{
 		RecordID: slugRecordID,
-		UserID:   auth.UserID,
+		UserID:   auth.ProfileID,
+		ActorID:  auth.ActorID,
 	}
 
 	_, err = h.app.CancelMedicationTake.Execute(c.Request.Context(), command)
@@ -486,6 +588,351 @@ func (h *PlanningHandlers) CancelMedicationTake(c *gin.Context) {
 	})
 }
 
+// SnoozeIntakeJSONRequest is an optional request body for SnoozeIntake.
+type SnoozeIntakeJSONRequest struct {
+	Minutes int `json:"minutes"`
+}
+
+// SnoozeIntakeJSONResponse is a response for SnoozeIntake.
+type SnoozeIntakeJSONResponse struct {
+	SnoozedUntil time.Time `json:"snoozedUntil"`
+}
+
+// SnoozeIntake postpones the reminder about not taken intake.
+func (h *PlanningHandlers) SnoozeIntake(c *gin.Context) {
+	recordID, userID, ok := h.extractMedicationParams(c)
+	if !ok {
+		return
+	}
+
+	var reqJSON SnoozeIntakeJSONRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&reqJSON); err != nil {
+			h.logger.WithError(err).Error("Failed to bind request body")
+			c.JSON(http.StatusBadRequest, api.Response[any]{
+				StatusCode: http.StatusBadRequest,
+				Body:       struct{}{},
+				Error:      api.MsgBadBody,
+			})
+			return
+		}
+	}
+
+	command := &application.SnoozeIntakeCommand{
+		RecordID: recordID,
+		UserID:   userID,
+		Minutes:  reqJSON.Minutes,
+	}
+	serviceResponse, err := h.app.SnoozeIntake.Execute(c.Request.Context(), command)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to snooze intake")
+		status, body := h.handleSnoozeIntakeServiceError(err)
+		c.JSON(status, body)
+		return
+	}
+
+	c.JSON(http.StatusOK, api.Response[any]{
+		StatusCode: http.StatusOK,
+		Body: &SnoozeIntakeJSONResponse{
+			SnoozedUntil: serviceResponse.SnoozedUntil,
+		},
+		Error: "",
+	})
+}
+
+// handleSnoozeIntakeServiceError maps service errors to HTTP status and API responses using switch.
+func (h *PlanningHandlers) handleSnoozeIntakeServiceError(err error) (int, *api.Response[any]) {
+	switch {
+	case errors.Is(err, application.ErrValidationFail):
+		return http.StatusBadRequest, &api.Response[any]{
+			StatusCode: http.StatusBadRequest,
+			Body:       struct{}{},
+			Error:      api.MsgBadBody,
+		}
+	case errors.Is(err, application.ErrNoIntakeRecord),
+		errors.Is(err, application.ErrNoPlan),
+		errors.Is(err, application.ErrPlanNotBelongToUser):
+		return http.StatusNotFound, &api.Response[any]{
+			StatusCode: http.StatusNotFound,
+			Body:       struct{}{},
+			Error:      MsgFailedToGetIntakeRecord,
+		}
+	case errors.Is(err, application.ErrIntakeAlreadyTaken):
+		return http.StatusConflict, &api.Response[any]{
+			StatusCode: http.StatusConflict,
+			Body:       struct{}{},
+			Error:      MsgIntakeAlreadyTaken,
+		}
+	case errors.Is(err, application.ErrInvalidSnooze):
+		return http.StatusUnprocessableEntity, &api.Response[any]{
+			StatusCode: http.StatusUnprocessableEntity,
+			Body:       struct{}{},
+			Error:      MsgInvalidSnooze,
+		}
+	default:
+		return http.StatusInternalServerError, &api.Response[any]{
+			StatusCode: http.StatusInternalServerError,
+			Body:       struct{}{},
+			Error:      MsgFailedToSnoozeIntake,
+		}
+	}
+}
+
+// TakeAsNeededJSONRequest is an optional request body for TakeAsNeeded.
+type TakeAsNeededJSONRequest struct {
+	TakenAt string  `json:"takenAt"`
+	Amount  float64 `json:"amount"`
+	// Force takes medication even if limits of the plan are exceeded.
+	Force bool `json:"force"`
+}
+
+// TakeAsNeededJSONResponse is a response for TakeAsNeeded.
+type TakeAsNeededJSONResponse struct {
+	IntakeRecordID string   `json:"intakeRecordId"`
+	Warnings       []string `json:"warnings,omitempty"`
+}
+
+// TakeAsNeeded takes medication of as-needed plan creating ad-hoc intake record.
+func (h *PlanningHandlers) TakeAsNeeded(c *gin.Context) {
+	planID, userID, ok := h.extractMedicationParams(c)
+	if !ok {
+		return
+	}
+
+	var reqJSON TakeAsNeededJSONRequest
+	if err := c.ShouldBindJSON(&reqJSON); err != nil && !errors.Is(err, io.EOF) {
+		h.logger.WithError(err).Error("Failed to bind request body")
+		c.JSON(http.StatusBadRequest, api.Response[any]{
+			StatusCode: http.StatusBadRequest,
+			Body:       struct{}{},
+			Error:      api.MsgBadBody,
+		})
+		return
+	}
+
+	command := &application.TakeAsNeededCommand{
+		PlanID:      planID,
+		UserID:      userID,
+		TakenAt:     reqJSON.TakenAt,
+		AmountValue: reqJSON.Amount,
+		Force:       reqJSON.Force,
+		ActorID:     h.actorID(c),
+	}
+
+	taken, err := h.app.TakeAsNeeded.Execute(c.Request.Context(), command)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to take as-needed medication")
+		status, body := h.handleTakeMedicationServiceError(err)
+		c.JSON(status, body)
+		return
+	}
+
+	c.JSON(http.StatusOK, api.Response[any]{
+		StatusCode: http.StatusOK,
+		Body: &TakeAsNeededJSONResponse{
+			IntakeRecordID: taken.IntakeRecordID.String(),
+			Warnings:       taken.Warnings,
+		},
+		Error: "",
+	})
+}
+
+// PlanExceptionsJSONRequest is a request for AddPlanExceptions.
+type PlanExceptionsJSONRequest struct {
+	// Exclude are times of intakes to skip.
+	Exclude []string `json:"exclude"`
+	// Add are times of one-off intakes.
+	Add []string `json:"add"`
+}
+
+// PlanExceptionsJSONResponse is a response for AddPlanExceptions.
+type PlanExceptionsJSONResponse struct {
+	ExcludedDates []string `json:"excludedDates"`
+	ExtraDates    []string `json:"extraDates"`
+}
+
+// AddPlanExceptions skips or adds single intakes of the plan.
+func (h *PlanningHandlers) AddPlanExceptions(c *gin.Context) {
+	planID, userID, ok := h.extractMedicationParams(c)
+	if !ok {
+		return
+	}
+
+	var reqJSON PlanExceptionsJSONRequest
+	if err := c.ShouldBindJSON(&reqJSON); err != nil {
+		h.logger.WithError(err).Error("Failed to bind request body")
+		c.JSON(http.StatusBadRequest, api.Response[any]{
+			StatusCode: http.StatusBadRequest,
+			Body:       struct{}{},
+			Error:      api.MsgBadBody,
+		})
+		return
+	}
+
+	command := &application.AddPlanExceptionsCommand{
+		PlanID:  planID,
+		UserID:  userID,
+		Exclude: reqJSON.Exclude,
+		Add:     reqJSON.Add,
+	}
+	exceptions, err := h.app.AddPlanExceptions.Execute(c.Request.Context(), command)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to add plan exceptions")
+		status, body := h.handlePlanExceptionsServiceError(err)
+		c.JSON(status, body)
+		return
+	}
+
+	c.JSON(http.StatusOK, api.Response[any]{
+		StatusCode: http.StatusOK,
+		Body: &PlanExceptionsJSONResponse{
+			ExcludedDates: exceptions.ExDates,
+			ExtraDates:    exceptions.RDates,
+		},
+		Error: "",
+	})
+}
+
+// handlePlanExceptionsServiceError maps service errors to HTTP status and API responses using switch.
+func (h *PlanningHandlers) handlePlanExceptionsServiceError(err error) (int, *api.Response[any]) {
+	switch {
+	case errors.Is(err, application.ErrValidationFail):
+		return http.StatusBadRequest, &api.Response[any]{
+			StatusCode: http.StatusBadRequest,
+			Body:       struct{}{},
+			Error:      api.MsgBadBody,
+		}
+	case errors.Is(err, application.ErrNoPlan), errors.Is(err, application.ErrPlanNotBelongToUser):
+		return http.StatusNotFound, &api.Response[any]{
+			StatusCode: http.StatusNotFound,
+			Body:       struct{}{},
+			Error:      MsgFailedToGetPlan,
+		}
+	case errors.Is(err, application.ErrExceptionInPast):
+		return http.StatusUnprocessableEntity, &api.Response[any]{
+			StatusCode: http.StatusUnprocessableEntity,
+			Body:       struct{}{},
+			Error:      MsgExceptionInPast,
+		}
+	case errors.Is(err, application.ErrInvalidException):
+		return http.StatusUnprocessableEntity, &api.Response[any]{
+			StatusCode: http.StatusUnprocessableEntity,
+			Body:       struct{}{},
+			Error:      MsgInvalidException,
+		}
+	case errors.Is(err, application.ErrIntakeAlreadyTaken):
+		return http.StatusConflict, &api.Response[any]{
+			StatusCode: http.StatusConflict,
+			Body:       struct{}{},
+			Error:      MsgIntakeAlreadyTaken,
+		}
+	default:
+		return http.StatusInternalServerError, &api.Response[any]{
+			StatusCode: http.StatusInternalServerError,
+			Body:       struct{}{},
+			Error:      MsgFailedToAddExceptions,
+		}
+	}
+}
+
+// EscalationStepObject notifies the target when intake is not taken
+// afterMinutes after reminder, target is "user" or "caregivers".
+type EscalationStepObject struct {
+	AfterMinutes int    `json:"afterMinutes"`
+	Target       string `json:"target"`
+}
+
+// PlanEscalationJSONRequest is a request for SetPlanEscalation.
+type PlanEscalationJSONRequest struct {
+	Steps []EscalationStepObject `json:"steps"`
+}
+
+// PlanEscalationJSONResponse is a response for SetPlanEscalation.
+type PlanEscalationJSONResponse struct {
+	Steps []EscalationStepObject `json:"steps"`
+}
+
+// SetPlanEscalation replaces escalation policy of missed intakes of the plan.
+func (h *PlanningHandlers) SetPlanEscalation(c *gin.Context) {
+	planID, userID, ok := h.extractMedicationParams(c)
+	if !ok {
+		return
+	}
+
+	var reqJSON PlanEscalationJSONRequest
+	if err := c.ShouldBindJSON(&reqJSON); err != nil {
+		h.logger.WithError(err).Error("Failed to bind request body")
+		c.JSON(http.StatusBadRequest, api.Response[any]{
+			StatusCode: http.StatusBadRequest,
+			Body:       struct{}{},
+			Error:      api.MsgBadBody,
+		})
+		return
+	}
+
+	command := &application.SetPlanEscalationCommand{
+		PlanID: planID,
+		UserID: userID,
+		Steps:  make([]application.EscalationStep, 0, len(reqJSON.Steps)),
+	}
+	for _, step := range reqJSON.Steps {
+		command.Steps = append(command.Steps, application.EscalationStep(step))
+	}
+	escalation, err := h.app.SetPlanEscalation.Execute(c.Request.Context(), command)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to set plan escalation")
+		status, body := h.handlePlanEscalationServiceError(err)
+		c.JSON(status, body)
+		return
+	}
+
+	c.JSON(http.StatusOK, api.Response[any]{
+		StatusCode: http.StatusOK,
+		Body: &PlanEscalationJSONResponse{
+			Steps: escalationFromApplication(escalation.Steps),
+		},
+		Error: "",
+	})
+}
+
+// handlePlanEscalationServiceError maps service errors to HTTP status and API responses using switch.
+func (h *PlanningHandlers) handlePlanEscalationServiceError(err error) (int, *api.Response[any]) {
+	switch {
+	case errors.Is(err, application.ErrValidationFail):
+		return http.StatusBadRequest, &api.Response[any]{
+			StatusCode: http.StatusBadRequest,
+			Body:       struct{}{},
+			Error:      api.MsgBadBody,
+		}
+	case errors.Is(err, application.ErrNoPlan), errors.Is(err, application.ErrPlanNotBelongToUser):
+		return http.StatusNotFound, &api.Response[any]{
+			StatusCode: http.StatusNotFound,
+			Body:       struct{}{},
+			Error:      MsgFailedToGetPlan,
+		}
+	case errors.Is(err, application.ErrInvalidEscalation):
+		return http.StatusUnprocessableEntity, &api.Response[any]{
+			StatusCode: http.StatusUnprocessableEntity,
+			Body:       struct{}{},
+			Error:      MsgInvalidEscalation,
+		}
+	default:
+		return http.StatusInternalServerError, &api.Response[any]{
+			StatusCode: http.StatusInternalServerError,
+			Body:       struct{}{},
+			Error:      MsgFailedToSetEscalation,
+		}
+	}
+}
+
+func escalationFromApplication(steps []application.EscalationStep) []EscalationStepObject {
+	result := make([]EscalationStepObject, 0, len(steps))
+	for _, step := range steps {
+		result = append(result, EscalationStepObject(step))
+	}
+	return result
+}
+
 func (h *PlanningHandlers) extractMedicationParams(
 	c *gin.Context,
 ) (string, string, bool) {
@@ -510,7 +957,17 @@ func (h *PlanningHandlers) extractMedicationParams(
 		return "", "", false
 	}
 
-	return slugRecordID, auth.UserID, true
+	return slugRecordID, auth.ProfileID, true
+}
+
+// actorID returns the user who performs the request,
+// it is a caregiver when the request is made on behalf of the user.
+func (h *PlanningHandlers) actorID(c *gin.Context) string {
+	auth, err := httputil.GetAuthFromCtx(c.Request)
+	if err != nil {
+		return ""
+	}
+	return auth.ActorID
 }
 
 // handleUpdateServiceError maps service errors to HTTP status and API responses using switch.
@@ -537,27 +994,94 @@ func (h *PlanningHandlers) handleUpdateServiceError(err error) (int, *api.Respon
 	}
 }
 
-// handleTakeMedicationServiceError maps service errors to HTTP status and API responses using switch.
-func (h *PlanningHandlers) handleTakeMedicationServiceError(err error) (int, *api.Response[any]) {
+// InvalidFieldsJSONResponse is a body of response to a request with invalid fields.
+type InvalidFieldsJSONResponse struct {
+	// Fields map a path of the invalid field, e.g. "schedule.times[1]", to the reason.
+	Fields map[string]string `json:"fields"`
+}
+
+// handleAddPlanServiceError maps service errors to HTTP status and API responses using switch.
+func (h *PlanningHandlers) handleAddPlanServiceError(err error) (int, *api.Response[any]) {
+	var fieldsErr *application.FieldsError
 	switch {
+	case errors.As(err, &fieldsErr):
+		return http.StatusBadRequest, &api.Response[any]{
+			StatusCode: http.StatusBadRequest,
+			Body:       InvalidFieldsJSONResponse{Fields: fieldsErr.Fields},
+			Error:      MsgInvalidFields,
+		}
 	case errors.Is(err, application.ErrValidationFail):
 		return http.StatusBadRequest, &api.Response[any]{
 			StatusCode: http.StatusBadRequest,
 			Body:       struct{}{},
 			Error:      api.MsgBadBody,
 		}
-	case errors.Is(err, application.ErrNoPlan):
-		return http.StatusNotFound, &api.Response[any]{
-			StatusCode: http.StatusNotFound,
+	case errors.Is(err, application.ErrInvalidException):
+		return http.StatusUnprocessableEntity, &api.Response[any]{
+			StatusCode: http.StatusUnprocessableEntity,
 			Body:       struct{}{},
-			Error:      MsgFailedToGetPlan,
+			Error:      MsgInvalidException,
 		}
-	case errors.Is(err, application.ErrNoIntakeRecord):
-		return http.StatusNotFound, &api.Response[any]{
-			StatusCode: http.StatusNotFound,
-			Body:       struct{}{},
+	default:
+		return http.StatusInternalServerError, &api.Response[any]{
+			StatusCode: http.StatusInternalServerError,
+			Body:       struct{}{},
+			Error:      MsgFailedToAddPlan,
+		}
+	}
+}
+
+// handleTakeMedicationServiceError maps service errors to HTTP status and API responses using switch.
+func (h *PlanningHandlers) handleTakeMedicationServiceError(err error) (int, *api.Response[any]) {
+	switch {
+	case errors.Is(err, application.ErrValidationFail):
+		return http.StatusBadRequest, &api.Response[any]{
+			StatusCode: http.StatusBadRequest,
+			Body:       struct{}{},
+			Error:      api.MsgBadBody,
+		}
+	case errors.Is(err, application.ErrNoPlan):
+		return http.StatusNotFound, &api.Response[any]{
+			StatusCode: http.StatusNotFound,
+			Body:       struct{}{},
+			Error:      MsgFailedToGetPlan,
+		}
+	case errors.Is(err, application.ErrNoIntakeRecord):
+		return http.StatusNotFound, &api.Response[any]{
+			StatusCode: http.StatusNotFound,
+			Body:       struct{}{},
 			Error:      MsgFailedToGetIntakeRecord,
 		}
+	case errors.Is(err, application.ErrPlanNotAsNeeded):
+		return http.StatusUnprocessableEntity, &api.Response[any]{
+			StatusCode: http.StatusUnprocessableEntity,
+			Body:       struct{}{},
+			Error:      MsgPlanNotAsNeeded,
+		}
+	case errors.Is(err, application.ErrDoseLimitExceeded):
+		return http.StatusConflict, &api.Response[any]{
+			StatusCode: http.StatusConflict,
+			Body:       struct{}{},
+			Error:      MsgDoseLimitExceeded,
+		}
+	case errors.Is(err, medication.ErrIncompatibleUnit):
+		return http.StatusUnprocessableEntity, &api.Response[any]{
+			StatusCode: http.StatusUnprocessableEntity,
+			Body:       struct{}{},
+			Error:      MsgIncompatibleDoseUnit,
+		}
+	case errors.Is(err, application.ErrTakeConflict):
+		return http.StatusConflict, &api.Response[any]{
+			StatusCode: http.StatusConflict,
+			Body:       struct{}{},
+			Error:      MsgTakeConflict,
+		}
+	case errors.Is(err, application.ErrStockNotUpdated):
+		return http.StatusBadGateway, &api.Response[any]{
+			StatusCode: http.StatusBadGateway,
+			Body:       struct{}{},
+			Error:      MsgFailedToUpdateStock,
+		}
 	default:
 		return http.StatusInternalServerError, &api.Response[any]{
 			StatusCode: http.StatusInternalServerError,
@@ -566,3 +1090,415 @@ func (h *PlanningHandlers) handleTakeMedicationServiceError(err error) (int, *ap
 		}
 	}
 }
+
+// ScheduledDoseItem is a planned intake of a medication.
+type ScheduledDoseItem struct {
+	TakeAt string       `json:"takeAt"`
+	Amount AmountObject `json:"amount"`
+}
+
+// InternalScheduledDosesJSONResponse is a response for InternalScheduledDoses.
+type InternalScheduledDosesJSONResponse struct {
+	Doses []ScheduledDoseItem `json:"doses"`
+}
+
+// InternalScheduledDoses returns planned intakes of a medication
+// from now till the time in "to" query parameter.
+func (h *PlanningHandlers) InternalScheduledDoses(c *gin.Context) {
+	command := &application.ScheduledDosesCommand{
+		UserID:       c.Param(SlugUserID),
+		MedicationID: c.Param(SlugID),
+		To:           c.Query("to"),
+	}
+
+	doses, err := h.app.ScheduledDoses.Execute(c.Request.Context(), command)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get scheduled doses")
+		status, body := h.handleUpdateServiceError(err)
+		c.JSON(status, body)
+		return
+	}
+
+	response := &InternalScheduledDosesJSONResponse{
+		Doses: make([]ScheduledDoseItem, 0, len(doses.Doses)),
+	}
+	for _, d := range doses.Doses {
+		response.Doses = append(response.Doses, ScheduledDoseItem{
+			TakeAt: d.TakeAt.UTC().Format(time.RFC3339),
+			Amount: AmountObject{
+				Value: d.Value,
+				Unit:  d.Unit,
+			},
+		})
+	}
+
+	c.JSON(http.StatusOK, api.Response[any]{
+		StatusCode: http.StatusOK,
+		Body:       response,
+		Error:      "",
+	})
+}
+
+// InternalTakeMedication makes record taken by the owner of the plan
+// on reply to the reminder in messenger.
+func (h *PlanningHandlers) InternalTakeMedication(c *gin.Context) {
+	command := &application.TakeMedicationCommand{
+		RecordID: c.Param(SlugID),
+		UserID:   c.Param(SlugUserID),
+	}
+
+	taken, err := h.app.TakeMedication.Execute(c.Request.Context(), command)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to take medication")
+		status, body := h.handleTakeMedicationServiceError(err)
+		c.JSON(status, body)
+		return
+	}
+
+	c.JSON(http.StatusOK, api.Response[any]{
+		StatusCode: http.StatusOK,
+		Body: &TakeMedicationJSONResponse{
+			Warnings: taken.Warnings,
+		},
+		Error: "",
+	})
+}
+
+// InternalSnoozeIntake postpones the reminder on reply in messenger.
+func (h *PlanningHandlers) InternalSnoozeIntake(c *gin.Context) {
+	command := &application.SnoozeIntakeCommand{
+		RecordID: c.Param(SlugID),
+		UserID:   c.Param(SlugUserID),
+	}
+
+	serviceResponse, err := h.app.SnoozeIntake.Execute(c.Request.Context(), command)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to snooze intake")
+		status, body := h.handleSnoozeIntakeServiceError(err)
+		c.JSON(status, body)
+		return
+	}
+
+	c.JSON(http.StatusOK, api.Response[any]{
+		StatusCode: http.StatusOK,
+		Body: &SnoozeIntakeJSONResponse{
+			SnoozedUntil: serviceResponse.SnoozedUntil,
+		},
+		Error: "",
+	})
+}
+
+// ExportPlanCalendar returns intakes of the plan as iCalendar file.
+func (h *PlanningHandlers) ExportPlanCalendar(c *gin.Context) {
+	planID, userID, ok := h.extractMedicationParams(c)
+	if !ok {
+		return
+	}
+
+	command := &application.ExportPlanCalendarCommand{
+		PlanID: planID,
+		UserID: userID,
+	}
+
+	exported, err := h.app.ExportPlanCalendar.Execute(c.Request.Context(), command)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to export plan calendar")
+		status, body := h.handleCalendarServiceError(err)
+		c.JSON(status, body)
+		return
+	}
+
+	c.Header("Content-Disposition", "attachment; filename=plan-"+planID+calendarFeedExt)
+	c.Data(http.StatusOK, ical.ContentType, []byte(exported.Calendar))
+}
+
+// CalendarFeed returns intakes of all active plans of the user as subscribable calendar.
+// It is authorized by the secret token in path instead of the session,
+// because calendar clients can't log in.
+func (h *PlanningHandlers) CalendarFeed(c *gin.Context) {
+	token, ok := strings.CutSuffix(c.Param(SlugToken), calendarFeedExt)
+	if !ok {
+		c.JSON(http.StatusNotFound, api.Response[any]{
+			StatusCode: http.StatusNotFound,
+			Body:       struct{}{},
+			Error:      MsgNoCalendarFeed,
+		})
+		return
+	}
+
+	command := &application.CalendarFeedCommand{
+		Token: token,
+	}
+
+	feed, err := h.app.CalendarFeed.Execute(c.Request.Context(), command)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get calendar feed")
+		status, body := h.handleCalendarServiceError(err)
+		c.JSON(status, body)
+		return
+	}
+
+	c.Data(http.StatusOK, ical.ContentType, []byte(feed.Calendar))
+}
+
+// RotateFeedTokenJSONResponse is a response for RotateFeedToken.
+type RotateFeedTokenJSONResponse struct {
+	Token string `json:"token"`
+	// Path is a path of the calendar feed to subscribe to.
+	Path      string `json:"path"`
+	CreatedAt string `json:"createdAt"`
+}
+
+// RotateFeedToken issues a new secret token of calendar feed, the previous one stops working.
+func (h *PlanningHandlers) RotateFeedToken(c *gin.Context) {
+	auth, err := httputil.GetAuthFromCtx(c.Request)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, api.Response[any]{
+			StatusCode: http.StatusUnauthorized,
+			Error:      api.MsgUnauthorized,
+			Body:       struct{}{},
+		})
+		return
+	}
+
+	command := &application.RotateFeedTokenCommand{
+		UserID: auth.ProfileID,
+	}
+
+	rotated, err := h.app.RotateFeedToken.Execute(c.Request.Context(), command)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to rotate calendar feed token")
+		status, body := h.handleCalendarServiceError(err)
+		c.JSON(status, body)
+		return
+	}
+
+	c.JSON(http.StatusOK, api.Response[any]{
+		StatusCode: http.StatusOK,
+		Body: &RotateFeedTokenJSONResponse{
+			Token:     rotated.Token,
+			Path:      "/calendar/" + rotated.Token + calendarFeedExt,
+			CreatedAt: rotated.CreatedAt.UTC().Format(time.RFC3339),
+		},
+		Error: "",
+	})
+}
+
+// RevokeFeedToken revokes the secret token of calendar feed.
+func (h *PlanningHandlers) RevokeFeedToken(c *gin.Context) {
+	auth, err := httputil.GetAuthFromCtx(c.Request)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, api.Response[any]{
+			StatusCode: http.StatusUnauthorized,
+			Error:      api.MsgUnauthorized,
+			Body:       struct{}{},
+		})
+		return
+	}
+
+	command := &application.RevokeFeedTokenCommand{
+		UserID: auth.ProfileID,
+	}
+
+	if _, err := h.app.RevokeFeedToken.Execute(c.Request.Context(), command); err != nil {
+		h.logger.WithError(err).Error("Failed to revoke calendar feed token")
+		status, body := h.handleCalendarServiceError(err)
+		c.JSON(status, body)
+		return
+	}
+
+	c.JSON(http.StatusOK, api.Response[any]{
+		StatusCode: http.StatusOK,
+		Body:       struct{}{},
+		Error:      "",
+	})
+}
+
+// handleCalendarServiceError maps service errors to HTTP status and API responses using switch.
+func (h *PlanningHandlers) handleCalendarServiceError(err error) (int, *api.Response[any]) {
+	switch {
+	case errors.Is(err, application.ErrValidationFail):
+		return http.StatusBadRequest, &api.Response[any]{
+			StatusCode: http.StatusBadRequest,
+			Body:       struct{}{},
+			Error:      api.MsgBadBody,
+		}
+	case errors.Is(err, application.ErrNoPlan), errors.Is(err, application.ErrPlanNotBelongToUser):
+		return http.StatusNotFound, &api.Response[any]{
+			StatusCode: http.StatusNotFound,
+			Body:       struct{}{},
+			Error:      MsgFailedToGetPlan,
+		}
+	case errors.Is(err, application.ErrNoCalendarFeed):
+		return http.StatusNotFound, &api.Response[any]{
+			StatusCode: http.StatusNotFound,
+			Body:       struct{}{},
+			Error:      MsgNoCalendarFeed,
+		}
+	case errors.Is(err, application.ErrNoMedicationForPlan):
+		return http.StatusBadGateway, &api.Response[any]{
+			StatusCode: http.StatusBadGateway,
+			Body:       struct{}{},
+			Error:      MsgFailedToExportCalendar,
+		}
+	default:
+		return http.StatusInternalServerError, &api.Response[any]{
+			StatusCode: http.StatusInternalServerError,
+			Body:       struct{}{},
+			Error:      api.MsgServerError,
+		}
+	}
+}
+
+// ImportedPlanItem is a plan mapped from a calendar event.
+type ImportedPlanItem struct {
+	UID            string   `json:"uid"`
+	Summary        string   `json:"summary"`
+	StartDate      string   `json:"startDate,omitempty"`
+	EndDate        string   `json:"endDate,omitempty"`
+	TimeZone       string   `json:"timeZone,omitempty"`
+	RecurrenceRule []string `json:"recurrenceRule,omitempty"`
+	ExcludedDates  []string `json:"excludedDates,omitempty"`
+	ExtraDates     []string `json:"extraDates,omitempty"`
+	Warnings       []string `json:"warnings,omitempty"`
+	Skipped        bool     `json:"skipped"`
+	PlanID         string   `json:"planId,omitempty"`
+}
+
+// ImportPlansJSONResponse is a response for ImportPlans.
+type ImportPlansJSONResponse struct {
+	DryRun bool               `json:"dryRun"`
+	Plans  []ImportedPlanItem `json:"plans"`
+}
+
+// ImportPlans imports plans of the medication from uploaded iCalendar file.
+// It is a multipart form with "calendar" file, "medicationId", "amountValue",
+// "amountUnit" and optional "condition", "timeZone", "endDate" fields.
+// Plans are created only with "dryRun=false", otherwise a preview is returned.
+func (h *PlanningHandlers) ImportPlans(c *gin.Context) {
+	auth, err := httputil.GetAuthFromCtx(c.Request)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, api.Response[any]{
+			StatusCode: http.StatusUnauthorized,
+			Error:      api.MsgUnauthorized,
+			Body:       struct{}{},
+		})
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxCalendarSize)
+	calendar, err := readFormFile(c, "calendar")
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to read calendar file")
+		c.JSON(http.StatusBadRequest, api.Response[any]{
+			StatusCode: http.StatusBadRequest,
+			Body:       struct{}{},
+			Error:      api.MsgBadBody,
+		})
+		return
+	}
+	amountValue, err := strconv.ParseFloat(c.PostForm("amountValue"), 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, api.Response[any]{
+			StatusCode: http.StatusBadRequest,
+			Body:       struct{}{},
+			Error:      api.MsgBadBody,
+		})
+		return
+	}
+
+	command := &application.ImportPlansCommand{
+		UserID:       auth.ProfileID,
+		MedicationID: c.PostForm("medicationId"),
+		AmountValue:  amountValue,
+		AmountUnit:   c.PostForm("amountUnit"),
+		Condition:    c.PostForm("condition"),
+		TimeZone:     c.PostForm("timeZone"),
+		EndDate:      c.PostForm("endDate"),
+		Calendar:     calendar,
+		DryRun:       c.DefaultPostForm("dryRun", "true") != "false",
+	}
+
+	imported, err := h.app.ImportPlans.Execute(c.Request.Context(), command)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to import plans")
+		status, body := h.handleImportPlansServiceError(err)
+		c.JSON(status, body)
+		return
+	}
+
+	response := &ImportPlansJSONResponse{
+		DryRun: imported.DryRun,
+		Plans:  make([]ImportedPlanItem, 0, len(imported.Plans)),
+	}
+	for _, p := range imported.Plans {
+		response.Plans = append(response.Plans, ImportedPlanItem{
+			UID:            p.UID,
+			Summary:        p.Summary,
+			StartDate:      p.StartDate,
+			EndDate:        p.EndDate,
+			TimeZone:       p.TimeZone,
+			RecurrenceRule: p.RecurrenceRule,
+			ExcludedDates:  p.ExDates,
+			ExtraDates:     p.RDates,
+			Warnings:       p.Warnings,
+			Skipped:        p.Skipped,
+			PlanID:         p.PlanID,
+		})
+	}
+
+	c.JSON(http.StatusOK, api.Response[any]{
+		StatusCode: http.StatusOK,
+		Body:       response,
+		Error:      "",
+	})
+}
+
+// readFormFile reads the whole file of multipart form.
+func readFormFile(c *gin.Context, name string) (string, error) {
+	header, err := c.FormFile(name)
+	if err != nil {
+		return "", err
+	}
+	f, err := header.Open()
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+
+	b, err := io.ReadAll(f)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// handleImportPlansServiceError maps service errors to HTTP status and API responses using switch.
+func (h *PlanningHandlers) handleImportPlansServiceError(err error) (int, *api.Response[any]) {
+	switch {
+	case errors.Is(err, application.ErrValidationFail):
+		return http.StatusBadRequest, &api.Response[any]{
+			StatusCode: http.StatusBadRequest,
+			Body:       struct{}{},
+			Error:      api.MsgBadBody,
+		}
+	case errors.Is(err, application.ErrInvalidCalendar):
+		return http.StatusUnprocessableEntity, &api.Response[any]{
+			StatusCode: http.StatusUnprocessableEntity,
+			Body:       struct{}{},
+			Error:      MsgInvalidCalendar,
+		}
+	case errors.Is(err, application.ErrNoMedicationForPlan):
+		return http.StatusNotFound, &api.Response[any]{
+			StatusCode: http.StatusNotFound,
+			Body:       struct{}{},
+			Error:      MsgFailedToImportPlans,
+		}
+	default:
+		return http.StatusInternalServerError, &api.Response[any]{
+			StatusCode: http.StatusInternalServerError,
+			Body:       struct{}{},
+			Error:      MsgFailedToImportPlans,
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package http
+
+import (
+	"expvar"
+
+	"github.com/FSO-VK/final-project-vk-backend/internal/utils/httputil"
+	"github.com/gin-gonic/gin"
+)
+
+// InternalRouter returns a new Gin engine for cross microservice communication.
+func InternalRouter(planningHandlers *PlanningHandlers) *gin.Engine {
+	r := gin.New()
+
+	r.Use(gin.Logger())
+	r.Use(httputil.NewPanicRecoveryMiddleware().Handler())
+	r.GET(
+		"/internal/plan/doses/:id/:user_id",
+		planningHandlers.InternalScheduledDoses,
+	)
+	// quick replies to reminders in messengers
+	r.POST("/internal/intake/:id/take/:user_id", planningHandlers.InternalTakeMedication)
+	r.POST("/internal/intake/:id/snooze/:user_id", planningHandlers.InternalSnoozeIntake)
+	// metrics of notification jobs
+	r.GET("/debug/vars", gin.WrapH(expvar.Handler()))
+
+	return r
+}
@@ -14,6 +14,8 @@ func Router(
 
 	r.Use(gin.Logger())
 	r.Use(httputil.NewPanicRecoveryMiddleware().Handler())
+	// calendar clients are authorized by the secret token of the feed
+	r.GET("/calendar/:token", planningHandlers.CalendarFeed)
 	authGroup := r.Group("/")
 	authGroup.Use(authMw.Middleware())
 	{
@@ -29,11 +31,22 @@ func Router(
 			"/intake/:id/cancel",
 			planningHandlers.CancelMedicationTake,
 		)
+		authGroup.POST(
+			"/intake/:id/snooze",
+			planningHandlers.SnoozeIntake,
+		)
 		authGroup.GET("/plan/all", planningHandlers.GetAllUsersPlans)
 		authGroup.GET("/plan/:id", planningHandlers.GetPlanByID)
 		authGroup.POST("/plan", planningHandlers.AddPlan)
+		authGroup.POST("/plan/import", planningHandlers.ImportPlans)
+		authGroup.POST("/plan/:id/take", planningHandlers.TakeAsNeeded)
+		authGroup.POST("/plan/:id/exceptions", planningHandlers.AddPlanExceptions)
+		authGroup.PUT("/plan/:id/escalation", planningHandlers.SetPlanEscalation)
 		authGroup.GET("/plan/schedule", planningHandlers.ShowSchedule)
 		authGroup.DELETE("/plan/:id", planningHandlers.FinishPlan)
+		authGroup.GET("/plan/:id/ics", planningHandlers.ExportPlanCalendar)
+		authGroup.POST("/calendar/token", planningHandlers.RotateFeedToken)
+		authGroup.DELETE("/calendar/token", planningHandlers.RevokeFeedToken)
 	}
 
 	return r
@@ -5,14 +5,25 @@ import (
 	"errors"
 	"net/http"
 
+	"github.com/FSO-VK/final-project-vk-backend/internal/utils/logcon"
 	"github.com/FSO-VK/final-project-vk-backend/pkg/api"
 	auth "github.com/FSO-VK/final-project-vk-backend/pkg/auth/client"
 	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
 )
 
 const (
 	// SessionCookieKey is a key for session cookie.
 	SessionCookieKey string = "session_id"
+	// OnBehalfOfHeader is a header with id of the user the caregiver acts for.
+	OnBehalfOfHeader string = "X-On-Behalf-Of"
+	// ProfileHeader is a header with id of the selected dependant profile.
+	ProfileHeader string = "X-Profile-ID"
+
+	// CareRoleView allows caregiver only to read data of the user.
+	CareRoleView string = "view"
+	// CareRoleManage allows caregiver to change data of the user.
+	CareRoleManage string = "manage"
 )
 
 var (
@@ -25,11 +36,26 @@ func GetAuthContextKey() interface{} {
 }
 
 // AuthStatus contains user id and authorization status for handlers to use.
+// When caregiver acts on behalf of the user, UserID is the user whose data
+// is accessed, ActorID is the caregiver and Role is the caregiver role.
+// Otherwise ActorID equals UserID and Role is empty.
+// Data of the request is scoped by ProfileID, it is the id of the selected
+// dependant profile of the account UserID or UserID itself if no profile
+// is selected. ProfileName is empty for the account itself.
 type AuthStatus struct {
 	UserID       string
+	ActorID      string
+	Role         string
+	ProfileID    string
+	ProfileName  string
 	IsAuthorized bool
 }
 
+// IsDelegated tells whether the request is made by a caregiver.
+func (a *AuthStatus) IsDelegated() bool {
+	return a.ActorID != a.UserID
+}
+
 // ResponseUnauthorized is a response for unauthorized requests.
 type ResponseUnauthorized struct {
 	SessionID string `json:"sessionId"`
@@ -60,11 +86,21 @@ func GetAuthFromCtx(r *http.Request) (*AuthStatus, error) {
 // AuthMiddleware implements authorization check as a struct.
 type AuthMiddleware struct {
 	checker auth.AuthChecker
+	// allowDelegation enables requests on behalf of other users and profiles
+	// by OnBehalfOfHeader and ProfileHeader, otherwise such requests are forbidden.
+	allowDelegation bool
 }
 
 // NewAuthMiddleware constructor for AuthMiddleware.
+// Users may only access their own data.
 func NewAuthMiddleware(checker auth.AuthChecker) *AuthMiddleware {
-	return &AuthMiddleware{checker: checker}
+	return &AuthMiddleware{checker: checker, allowDelegation: false}
+}
+
+// NewDelegatingAuthMiddleware constructor for AuthMiddleware which also lets
+// caregivers act on behalf of users and accounts access their dependant profiles.
+func NewDelegatingAuthMiddleware(checker auth.AuthChecker) *AuthMiddleware {
+	return &AuthMiddleware{checker: checker, allowDelegation: true}
 }
 
 // AuthMiddlewareWrapper wraps http.Handler with authorization check.
@@ -131,8 +167,128 @@ func (m *AuthMiddleware) checkAuth(r *http.Request) (int, *api.Response[any], *A
 		return http.StatusForbidden, resp, nil
 	}
 
+	actorID := authResp.UserID
+	ownerID := r.Header.Get(OnBehalfOfHeader)
+	if ownerID == actorID {
+		ownerID = ""
+	}
+	profileID := r.Header.Get(ProfileHeader)
+	if profileID == actorID {
+		profileID = ""
+	}
+	switch {
+	case !m.allowDelegation && (ownerID != "" || profileID != ""):
+		return responseForbidden()
+	case profileID != "" && profileID != ownerID:
+		return m.checkProfile(r, sid, actorID, ownerID, profileID)
+	case ownerID != "":
+		return m.checkAccess(r, sid, actorID, ownerID)
+	}
 	return http.StatusOK, nil, &AuthStatus{
-		UserID:       authResp.UserID,
+		UserID:       actorID,
+		ActorID:      actorID,
+		ProfileID:    actorID,
 		IsAuthorized: authResp.IsAuthorized,
 	}
 }
+
+// checkAccess checks that the caregiver may perform the request on behalf of the owner.
+// Caregiver with view role may only read data.
+func (m *AuthMiddleware) checkAccess(
+	r *http.Request,
+	sid, actorID, ownerID string,
+) (int, *api.Response[any], *AuthStatus) {
+	accessResp, err := m.checker.CheckAccess(&auth.AccessRequest{
+		SessionID: sid,
+		OwnerID:   ownerID,
+	})
+	if err != nil {
+		return responseAuthUnavailable()
+	}
+	if !roleAllows(accessResp.Role, r.Method) {
+		return responseForbidden()
+	}
+
+	logDelegated(r, actorID, ownerID, ownerID, accessResp.Role)
+	return http.StatusOK, nil, &AuthStatus{
+		UserID:       ownerID,
+		ActorID:      actorID,
+		Role:         accessResp.Role,
+		ProfileID:    ownerID,
+		IsAuthorized: true,
+	}
+}
+
+// checkProfile checks that the user may access the dependant profile. The account of
+// the profile has full access, caregivers of the account have access by their role.
+// If the owner is set, the profile must belong to the owner.
+func (m *AuthMiddleware) checkProfile(
+	r *http.Request,
+	sid, actorID, ownerID, profileID string,
+) (int, *api.Response[any], *AuthStatus) {
+	profileResp, err := m.checker.CheckProfile(&auth.ProfileRequest{
+		SessionID: sid,
+		ProfileID: profileID,
+	})
+	if err != nil {
+		return responseAuthUnavailable()
+	}
+	if !profileResp.IsAllowed || (ownerID != "" && ownerID != profileResp.AccountID) {
+		return responseForbidden()
+	}
+
+	if profileResp.AccountID != actorID {
+		if !roleAllows(profileResp.Role, r.Method) {
+			return responseForbidden()
+		}
+		logDelegated(r, actorID, profileResp.AccountID, profileID, profileResp.Role)
+	}
+	return http.StatusOK, nil, &AuthStatus{
+		UserID:       profileResp.AccountID,
+		ActorID:      actorID,
+		Role:         profileResp.Role,
+		ProfileID:    profileID,
+		ProfileName:  profileResp.Name,
+		IsAuthorized: true,
+	}
+}
+
+// roleAllows checks that caregiver role allows the request method.
+func roleAllows(role string, method string) bool {
+	switch role {
+	case CareRoleManage:
+		return true
+	case CareRoleView:
+		return method == http.MethodGet || method == http.MethodHead
+	default:
+		return false
+	}
+}
+
+// logDelegated records who performs the request on behalf of the user.
+func logDelegated(r *http.Request, actorID, userID, profileID, role string) {
+	if log, ok := logcon.FromContext(r.Context()); ok {
+		log.WithFields(logrus.Fields{
+			"actor_id":   actorID,
+			"user_id":    userID,
+			"profile_id": profileID,
+			"role":       role,
+		}).Info("Request on behalf of user")
+	}
+}
+
+func responseForbidden() (int, *api.Response[any], *AuthStatus) {
+	return http.StatusForbidden, &api.Response[any]{
+		StatusCode: http.StatusForbidden,
+		Body:       struct{}{},
+		Error:      api.MsgForbidden,
+	}, nil
+}
+
+func responseAuthUnavailable() (int, *api.Response[any], *AuthStatus) {
+	return http.StatusServiceUnavailable, &api.Response[any]{
+		StatusCode: http.StatusServiceUnavailable,
+		Body:       struct{}{},
+		Error:      api.MsgServerError,
+	}, nil
+}
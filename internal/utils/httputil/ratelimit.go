@@ -0,0 +1,52 @@
+package httputil
+
+import (
+	"sync"
+	"time"
+)
+
+// RateLimiter limits rate of requests of every key by token bucket.
+// Bucket of the key holds up to burst tokens and is refilled by rate tokens per second,
+// every request takes a token.
+type RateLimiter struct {
+	mu      sync.Mutex
+	rate    float64
+	burst   float64
+	buckets map[string]*bucket
+}
+
+type bucket struct {
+	tokens    float64
+	updatedAt time.Time
+}
+
+// NewRateLimiter returns a new RateLimiter.
+func NewRateLimiter(rate float64, burst int) *RateLimiter {
+	return &RateLimiter{
+		mu:      sync.Mutex{},
+		rate:    rate,
+		burst:   float64(burst),
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// Allow takes a token of the key at now and tells whether the request is allowed.
+func (l *RateLimiter) Allow(key string, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: l.burst, updatedAt: now}
+		l.buckets[key] = b
+	}
+	if elapsed := now.Sub(b.updatedAt); elapsed > 0 {
+		b.tokens = min(l.burst, b.tokens+elapsed.Seconds()*l.rate)
+		b.updatedAt = now
+	}
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
@@ -0,0 +1,122 @@
+package httputil
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/FSO-VK/final-project-vk-backend/pkg/api"
+	"github.com/FSO-VK/final-project-vk-backend/pkg/servicesign"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// maxServiceBodySize is a maximum size of body of signed request.
+const maxServiceBodySize = 1 << 20
+
+// ErrCallerNotFound is an error when request is not authenticated as a service.
+var ErrCallerNotFound = errors.New("caller not found in context")
+
+const callerValueKey ctxKey = "httputil_service_caller"
+
+// SetCallerToCtx returns a new *http.Request with name of the calling service in the context.
+func SetCallerToCtx(r *http.Request, caller string) *http.Request {
+	ctx := context.WithValue(r.Context(), callerValueKey, caller)
+	return r.WithContext(ctx)
+}
+
+// GetCallerFromCtx returns name of the service which made the request.
+func GetCallerFromCtx(r *http.Request) (string, error) {
+	caller, ok := r.Context().Value(callerValueKey).(string)
+	if !ok {
+		return "", ErrCallerNotFound
+	}
+	return caller, nil
+}
+
+// ServiceAuthMiddleware authenticates requests of other services by signature
+// and limits rate of requests of every service.
+type ServiceAuthMiddleware struct {
+	verifier *servicesign.Verifier
+	limiter  *RateLimiter
+	logger   *logrus.Entry
+}
+
+// NewServiceAuthMiddleware constructor for ServiceAuthMiddleware.
+func NewServiceAuthMiddleware(
+	verifier *servicesign.Verifier,
+	limiter *RateLimiter,
+	logger *logrus.Entry,
+) *ServiceAuthMiddleware {
+	return &ServiceAuthMiddleware{verifier: verifier, limiter: limiter, logger: logger}
+}
+
+// Middleware wraps gin with check of signature and rate limit of the caller.
+func (m *ServiceAuthMiddleware) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limitedBody := http.MaxBytesReader(c.Writer, c.Request.Body, maxServiceBodySize)
+		body, err := io.ReadAll(limitedBody)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, &api.Response[any]{
+				StatusCode: http.StatusBadRequest,
+				Body:       struct{}{},
+				Error:      api.MsgBadBody,
+			})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		now := time.Now()
+		caller, err := m.verifier.Verify(c.Request, body, now)
+		if err != nil {
+			m.logger.WithError(err).WithFields(logrus.Fields{
+				"caller": c.GetHeader(servicesign.HeaderCaller),
+				"ip":     c.ClientIP(),
+				"path":   c.Request.URL.Path,
+			}).Warn("Rejected unauthenticated service request")
+			c.AbortWithStatusJSON(http.StatusUnauthorized, &api.Response[any]{
+				StatusCode: http.StatusUnauthorized,
+				Body:       struct{}{},
+				Error:      api.MsgServiceUnauthorized,
+			})
+			return
+		}
+		if !m.limiter.Allow(caller, now) {
+			m.logger.WithField("caller", caller).Warn("Service request rate limit exceeded")
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, &api.Response[any]{
+				StatusCode: http.StatusTooManyRequests,
+				Body:       struct{}{},
+				Error:      api.MsgTooManyRequests,
+			})
+			return
+		}
+
+		c.Request = SetCallerToCtx(c.Request, caller)
+		c.Next()
+	}
+}
+
+// ServiceAuthConfig is configuration of authentication of services.
+type ServiceAuthConfig struct {
+	// Callers are secrets shared with services keyed by names of services.
+	Callers map[string]string
+	MaxSkew time.Duration // допустимое расхождение времени запроса (5m)
+	// Rate is a number of requests per second allowed to every service.
+	Rate  float64
+	Burst int
+}
+
+// NewServiceAuthMiddlewareFromConfig creates ServiceAuthMiddleware by configuration.
+func NewServiceAuthMiddlewareFromConfig(
+	cfg ServiceAuthConfig,
+	logger *logrus.Entry,
+) *ServiceAuthMiddleware {
+	return NewServiceAuthMiddleware(
+		servicesign.NewVerifier(cfg.Callers, cfg.MaxSkew),
+		NewRateLimiter(cfg.Rate, cfg.Burst),
+		logger,
+	)
+}
@@ -7,15 +7,19 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"time"
 
+	"github.com/FSO-VK/final-project-vk-backend/pkg/servicesign"
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 )
 
 // NotificationClient implements NotificationService.
+// Requests are signed with the secret shared with notifications service.
 type NotificationClient struct {
 	client *http.Client
 	cfg    ClientConfig
+	signer *servicesign.Signer
 	logger *logrus.Entry
 }
 
@@ -27,7 +31,12 @@ func NewNotificationClient(cfg ClientConfig, logger *logrus.Entry) *Notification
 		CheckRedirect: nil,
 		Jar:           nil,
 	}
-	return &NotificationClient{client: client, cfg: cfg, logger: logger}
+	return &NotificationClient{
+		client: client,
+		cfg:    cfg,
+		signer: servicesign.NewSigner(cfg.Caller, cfg.Secret),
+		logger: logger,
+	}
 }
 
 type NotificationExpectedResponse struct {
@@ -37,10 +46,29 @@ type NotificationExpectedResponse struct {
 
 type Body struct{}
 
+// NotificationInfo is a notification to send, notifications service drops
+// notification recently sent with the same DedupeKey.
 type NotificationInfo struct {
-	UserID uuid.UUID `json:"userId"`
-	Title  string    `json:"title"`
-	Body   string    `json:"body"`
+	UserID    uuid.UUID            `json:"userId"`
+	Category  string               `json:"category,omitempty"`
+	Type      string               `json:"type,omitempty"`
+	Title     string               `json:"title"`
+	Body      string               `json:"body"`
+	URL       string               `json:"url,omitempty"`
+	Tag       string               `json:"tag,omitempty"`
+	Renotify  bool                 `json:"renotify,omitempty"`
+	Urgency   string               `json:"urgency,omitempty"`
+	Actions   []NotificationAction `json:"actions,omitempty"`
+	Data      map[string]string    `json:"data,omitempty"`
+	Template  string               `json:"template,omitempty"`
+	Params    map[string]any       `json:"params,omitempty"`
+	DedupeKey string               `json:"dedupeKey,omitempty"`
+}
+
+// NotificationAction is a button of notification.
+type NotificationAction struct {
+	Action string `json:"action"`
+	Title  string `json:"title"`
 }
 
 // SendNotification implements NotificationService interface and sends a notification.
@@ -63,12 +91,13 @@ func (h *NotificationClient) SendNotification(
 		ctx,
 		h.cfg.Method,
 		h.cfg.Endpoint,
-		bytes.NewBuffer(jsonBody),
+		bytes.NewReader(jsonBody),
 	)
 	if err != nil {
 		return err
 	}
 	req.Header.Set("Content-Type", "application/json")
+	h.signer.Sign(req, jsonBody, time.Now())
 
 	resp, err := h.client.Do(req)
 	if err != nil {
@@ -79,6 +108,13 @@ func (h *NotificationClient) SendNotification(
 		_ = resp.Body.Close()
 	}()
 
+	switch resp.StatusCode {
+	case http.StatusUnauthorized:
+		h.logger.Error("notification service rejected signature, check the shared secret")
+		return ErrUnauthorized
+	case http.StatusTooManyRequests:
+		return ErrRateLimited
+	}
 	if resp.StatusCode != http.StatusOK {
 		h.logger.Warnf("notification service responded with %d", resp.StatusCode)
 		return ErrBadResponse
@@ -6,5 +6,9 @@ import "time"
 type ClientConfig struct {
 	Endpoint string
 	Method   string
-	Timeout  time.Duration // общий timeout запроса (30c)
+	// Caller is a name of the service sending notifications,
+	// Secret is shared with notifications service to sign requests.
+	Caller  string
+	Secret  string
+	Timeout time.Duration // общий timeout запроса (30c)
 }
@@ -11,4 +11,8 @@ var (
 	ErrBadResponse = errors.New(
 		"notification api: invalid response not 200 or body is not like expected",
 	)
+	// ErrUnauthorized is returned when notifications service rejects signature of the request.
+	ErrUnauthorized = errors.New("notification api: service is not authorized")
+	// ErrRateLimited is returned when the caller exceeds rate limit of notifications service.
+	ErrRateLimited = errors.New("notification api: too many requests")
 )
@@ -12,4 +12,10 @@ const (
 	// MsgUnauthorized is a err message for unauthorized user.
 	MsgUnauthorized ErrorType = "User is not authorized"
 	MsgNotFound     ErrorType = "Not found"
+	// MsgForbidden is a err message for user without access to the data.
+	MsgForbidden ErrorType = "Access denied"
+	// MsgServiceUnauthorized is a err message for request of service with invalid signature.
+	MsgServiceUnauthorized ErrorType = "Service is not authorized"
+	// MsgTooManyRequests is a err message for caller exceeding rate limit.
+	MsgTooManyRequests ErrorType = "Too many requests"
 )
@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"net/url"
 	"time"
 
 	"github.com/FSO-VK/final-project-vk-backend/pkg/api"
@@ -111,3 +112,97 @@ func (h *HTTPAuthChecker) checkHTTPStatus(statusCode int) (*Response, error) {
 	}
 	return &Response{}, nil
 }
+
+// CheckAccess checks that user of the session may act on behalf of the owner.
+func (h *HTTPAuthChecker) CheckAccess(reqData *AccessRequest) (*AccessResponse, error) {
+	if reqData == nil || reqData.SessionID == "" || reqData.OwnerID == "" {
+		return nil, ErrInvalidRequest
+	}
+
+	var out AccessResponse
+	query := url.Values{"ownerId": []string{reqData.OwnerID}}
+	granted, err := h.getWithSession(reqData.SessionID, h.cfg.AccessPath, query, &out)
+	if err != nil {
+		return nil, err
+	}
+	if !granted {
+		return &AccessResponse{UserID: reqData.OwnerID}, nil
+	}
+	return &out, nil
+}
+
+// CheckProfile checks that user of the session may access the profile.
+func (h *HTTPAuthChecker) CheckProfile(reqData *ProfileRequest) (*ProfileResponse, error) {
+	if reqData == nil || reqData.SessionID == "" || reqData.ProfileID == "" {
+		return nil, ErrInvalidRequest
+	}
+
+	var out ProfileResponse
+	query := url.Values{"profileId": []string{reqData.ProfileID}}
+	granted, err := h.getWithSession(reqData.SessionID, h.cfg.ProfilePath, query, &out)
+	if err != nil {
+		return nil, err
+	}
+	if !granted {
+		return &ProfileResponse{ProfileID: reqData.ProfileID}, nil
+	}
+	out.IsAllowed = true
+	return &out, nil
+}
+
+// getWithSession makes GET request with the session cookie and decodes body of
+// the response into out. It returns false if access is denied by auth service.
+func (h *HTTPAuthChecker) getWithSession(
+	sessionID string,
+	path string,
+	query url.Values,
+	out any,
+) (bool, error) {
+	ctx := context.Background()
+
+	if h.cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, h.cfg.Timeout)
+		defer cancel()
+	}
+
+	reqURL := h.cfg.AuthBaseURL + path + "?" + query.Encode()
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return false, err
+	}
+
+	httpReq.AddCookie(&http.Cookie{
+		Name:  h.cfg.CookieName,
+		Value: sessionID,
+		Path:  h.cfg.CookieDomain,
+	})
+	httpReq.Header.Set("Accept", "application/json")
+
+	resp, err := h.client.Do(httpReq)
+	if err != nil {
+		h.logger.WithError(err).Warn("auth request failed")
+		return false, ErrAuthServiceUnavailable
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			h.logger.WithError(err).Debug("failed to close response body")
+		}
+	}()
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized ||
+		resp.StatusCode == http.StatusForbidden ||
+		resp.StatusCode == http.StatusBadRequest:
+		return false, nil
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		return false, ErrBadResponse
+	}
+
+	env := api.Response[any]{Body: out}
+	if err := json.NewDecoder(resp.Body).Decode(&env); err != nil {
+		h.logger.WithError(err).Error("failed to decode auth response")
+		return false, ErrInvalidAuthResponse
+	}
+	return true, nil
+}
@@ -6,6 +6,8 @@ import "time"
 type ClientConfig struct {
 	AuthBaseURL  string        // https://myhealthbox.ddns.net
 	Path         string        // /api/v1/session
+	AccessPath   string        // /api/v1/care/access
+	ProfilePath  string        // /api/v1/profile/access
 	Timeout      time.Duration // общий timeout запроса (30c)
 	CookieName   string        // session_id
 	CookieDomain string        // "/"
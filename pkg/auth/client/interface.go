@@ -1,8 +1,10 @@
 package auth
 
-// AuthChecker is the interface for the CheckAuth method.
+// AuthChecker is the interface for the CheckAuth, CheckAccess and CheckProfile methods.
 type AuthChecker interface {
 	CheckAuth(req *Request) (*Response, error)
+	CheckAccess(req *AccessRequest) (*AccessResponse, error)
+	CheckProfile(req *ProfileRequest) (*ProfileResponse, error)
 }
 
 // Request is the request for the CheckAuth method.
@@ -21,3 +23,35 @@ type Response struct {
 type ExpectedCheckAuthResponse struct {
 	UserID string `json:"userId"`
 }
+
+// AccessRequest is the request for the CheckAccess method.
+type AccessRequest struct {
+	SessionID string `json:"sessionId"`
+	OwnerID   string `json:"ownerId"`
+}
+
+// AccessResponse is the response for the CheckAccess method.
+// Role is empty if user of the session has no access to data of the owner.
+type AccessResponse struct {
+	UserID  string `json:"userId"`
+	ActorID string `json:"actorId"`
+	Role    string `json:"role"`
+}
+
+// ProfileRequest is the request for the CheckProfile method.
+type ProfileRequest struct {
+	SessionID string `json:"sessionId"`
+	ProfileID string `json:"profileId"`
+}
+
+// ProfileResponse is the response for the CheckProfile method.
+// Role is empty if user of the session is the account of the profile,
+// otherwise it is the caregiver role given by the account.
+type ProfileResponse struct {
+	ProfileID string `json:"profileId"`
+	AccountID string `json:"accountId"`
+	Name      string `json:"name"`
+	ActorID   string `json:"actorId"`
+	Role      string `json:"role"`
+	IsAllowed bool   `json:"isAllowed"`
+}
@@ -0,0 +1,309 @@
+package ical
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const dateFormat = "20060102"
+
+// ErrInvalidCalendar tells that the input is not a valid iCalendar object.
+var ErrInvalidCalendar = errors.New("invalid iCalendar object")
+
+// durationRe matches value of DURATION type, e.g. "-P1DT2H30M" or "P2W".
+var durationRe = regexp.MustCompile(`^([+-])?P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// property is a content line of iCalendar object.
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// Decode reads events of the calendar from r.
+// Times with TZID are read in the IANA time zone of that name,
+// floating times and dates are read in loc.
+// Properties that can't be represented by Event are listed in Event.Unsupported.
+func Decode(r io.Reader, loc *time.Location) (*Calendar, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	cal := &Calendar{}
+	var (
+		stack []string
+		event *Event
+	)
+	for _, line := range lines {
+		p, err := parseProperty(line)
+		if err != nil {
+			return nil, err
+		}
+		switch p.name {
+		case "BEGIN":
+			stack = append(stack, strings.ToUpper(p.value))
+			if len(stack) == 1 && stack[0] != "VCALENDAR" {
+				return nil, fmt.Errorf("%w: no VCALENDAR", ErrInvalidCalendar)
+			}
+			if strings.EqualFold(p.value, "VEVENT") {
+				if slices.Contains(stack[:len(stack)-1], "VEVENT") {
+					return nil, fmt.Errorf("%w: nested VEVENT", ErrInvalidCalendar)
+				}
+				event = &Event{}
+			}
+			continue
+		case "END":
+			if len(stack) == 0 || stack[len(stack)-1] != strings.ToUpper(p.value) {
+				return nil, fmt.Errorf("%w: unexpected END:%s", ErrInvalidCalendar, p.value)
+			}
+			stack = stack[:len(stack)-1]
+			if strings.EqualFold(p.value, "VEVENT") && event != nil {
+				if event.Start.IsZero() {
+					return nil, fmt.Errorf(
+						"%w: event %q has no DTSTART",
+						ErrInvalidCalendar,
+						event.UID,
+					)
+				}
+				cal.Events = append(cal.Events, *event)
+				event = nil
+			}
+			continue
+		}
+		if len(stack) == 0 {
+			return nil, fmt.Errorf("%w: content out of VCALENDAR", ErrInvalidCalendar)
+		}
+
+		switch stack[len(stack)-1] {
+		case "VCALENDAR":
+			cal.property(p)
+		case "VEVENT":
+			if err := event.property(p, loc); err != nil {
+				return nil, err
+			}
+		case "VALARM":
+			if event != nil && p.name == "TRIGGER" {
+				event.alarm(p)
+			}
+		}
+	}
+	if len(stack) != 0 {
+		return nil, fmt.Errorf("%w: %s is not closed", ErrInvalidCalendar, stack[len(stack)-1])
+	}
+	if cal.ProdID == "" && len(cal.Events) == 0 {
+		return nil, fmt.Errorf("%w: empty calendar", ErrInvalidCalendar)
+	}
+	return cal, nil
+}
+
+func (c *Calendar) property(p property) {
+	switch p.name {
+	case "PRODID":
+		c.ProdID = p.value
+	case "X-WR-CALNAME":
+		c.Name = unescapeText(p.value)
+	case "X-WR-TIMEZONE":
+		c.TimeZone = p.value
+	}
+}
+
+func (e *Event) property(p property, loc *time.Location) error {
+	switch p.name {
+	case "UID":
+		e.UID = p.value
+	case "DTSTAMP":
+		e.Stamp, _ = parseDateTime(p, loc)
+	case "SUMMARY":
+		e.Summary = unescapeText(p.value)
+	case "DESCRIPTION":
+		e.Description = unescapeText(p.value)
+	case "DTSTART":
+		t, err := parseDateTime(p, loc)
+		if err != nil {
+			return err
+		}
+		e.Start = t
+		if p.params["VALUE"] == "DATE" {
+			e.Unsupported = append(e.Unsupported, "DTSTART;VALUE=DATE")
+		}
+		if tz, ok := p.params["TZID"]; ok && t.Location().String() != tz {
+			e.Unsupported = append(e.Unsupported, "TZID="+tz)
+		}
+	case "DTEND":
+		end, err := parseDateTime(p, loc)
+		if err != nil {
+			return err
+		}
+		// DTSTART goes before DTEND in practice, otherwise duration is unknown
+		if !e.Start.IsZero() {
+			e.Duration = end.Sub(e.Start)
+		}
+	case "DURATION":
+		d, err := parseDuration(p.value)
+		if err != nil {
+			return err
+		}
+		e.Duration = d
+	case "RRULE":
+		if e.RRule != "" {
+			e.Unsupported = append(e.Unsupported, "RRULE")
+			return nil
+		}
+		e.RRule = p.value
+	case "EXDATE", "RDATE":
+		for _, v := range strings.Split(p.value, ",") {
+			t, err := parseDateTime(property{name: p.name, params: p.params, value: v}, loc)
+			if err != nil {
+				return err
+			}
+			if p.name == "EXDATE" {
+				e.ExDates = append(e.ExDates, t)
+			} else {
+				e.RDates = append(e.RDates, t)
+			}
+		}
+	case "EXRULE":
+		e.Unsupported = append(e.Unsupported, p.name)
+	}
+	return nil
+}
+
+func (e *Event) alarm(p property) {
+	if p.params["VALUE"] == "DATE-TIME" || p.params["RELATED"] == "END" {
+		e.Unsupported = append(e.Unsupported, "TRIGGER")
+		return
+	}
+	d, err := parseDuration(p.value)
+	if err != nil || d > 0 {
+		e.Unsupported = append(e.Unsupported, "TRIGGER")
+		return
+	}
+	e.Alarms = append(e.Alarms, Alarm{Before: -d, Description: ""})
+}
+
+// unfold splits input into content lines joining folded ones, see RFC 5545 section 3.1.
+func unfold(r io.Reader) ([]string, error) {
+	var lines []string
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := strings.TrimSuffix(sc.Text(), "\r")
+		if line == "" {
+			continue
+		}
+		if (line[0] == ' ' || line[0] == '\t') && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCalendar, err)
+	}
+	return lines, nil
+}
+
+// parseProperty parses content line "NAME;PARAM=VALUE:value".
+func parseProperty(line string) (property, error) {
+	inQuotes := false
+	colon := -1
+	for i, r := range line {
+		if r == '"' {
+			inQuotes = !inQuotes
+		}
+		if r == ':' && !inQuotes {
+			colon = i
+			break
+		}
+	}
+	if colon <= 0 {
+		return property{}, fmt.Errorf("%w: malformed line %q", ErrInvalidCalendar, line)
+	}
+
+	parts := strings.Split(line[:colon], ";")
+	p := property{
+		name:   strings.ToUpper(parts[0]),
+		params: make(map[string]string, len(parts)-1),
+		value:  line[colon+1:],
+	}
+	for _, param := range parts[1:] {
+		k, v, _ := strings.Cut(param, "=")
+		p.params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+	}
+	return p, nil
+}
+
+// parseDateTime parses value of DATE-TIME or DATE type.
+func parseDateTime(p property, loc *time.Location) (time.Time, error) {
+	if tz, ok := p.params["TZID"]; ok {
+		// unknown time zone is reported by the caller, time is read in loc
+		if tzLoc, err := time.LoadLocation(tz); err == nil {
+			loc = tzLoc
+		}
+	}
+
+	var (
+		t   time.Time
+		err error
+	)
+	switch {
+	case p.params["VALUE"] == "DATE" || len(p.value) == len(dateFormat):
+		t, err = time.ParseInLocation(dateFormat, p.value, loc)
+	case strings.HasSuffix(p.value, "Z"):
+		t, err = time.Parse(dateTimeFormat+"Z", p.value)
+	default:
+		t, err = time.ParseInLocation(dateTimeFormat, p.value, loc)
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: invalid %s: %w", ErrInvalidCalendar, p.name, err)
+	}
+	return t, nil
+}
+
+// parseDuration parses value of DURATION type.
+func parseDuration(s string) (time.Duration, error) {
+	m := durationRe.FindStringSubmatch(s)
+	if m == nil || s == "P" || s == "PT" {
+		return 0, fmt.Errorf("%w: invalid duration %q", ErrInvalidCalendar, s)
+	}
+	units := []time.Duration{
+		7 * 24 * time.Hour,
+		24 * time.Hour,
+		time.Hour,
+		time.Minute,
+		time.Second,
+	}
+	var d time.Duration
+	for i, unit := range units {
+		if m[i+2] == "" {
+			continue
+		}
+		n, err := strconv.Atoi(m[i+2])
+		if err != nil {
+			return 0, fmt.Errorf("%w: invalid duration %q", ErrInvalidCalendar, s)
+		}
+		d += time.Duration(n) * unit
+	}
+	if m[1] == "-" {
+		d = -d
+	}
+	return d, nil
+}
+
+// unescapeText unescapes value of TEXT type.
+func unescapeText(s string) string {
+	return strings.NewReplacer(
+		`\\`, `\`,
+		`\;`, ";",
+		`\,`, ",",
+		`\n`, "\n",
+		`\N`, "\n",
+	).Replace(s)
+}
@@ -0,0 +1,134 @@
+package ical_test
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/FSO-VK/final-project-vk-backend/pkg/ical"
+)
+
+func TestDecode(t *testing.T) {
+	t.Parallel()
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	if err != nil {
+		t.Fatalf("arrange failed: %v", err)
+	}
+	calendar := func(lines ...string) string {
+		return "BEGIN:VCALENDAR\r\nPRODID:-//test//EN\r\n" +
+			strings.Join(lines, "\r\n") + "\r\nEND:VCALENDAR\r\n"
+	}
+	tests := []struct {
+		name string // description of this test case
+		// Named input parameters for target function.
+		input           string
+		wantStart       time.Time
+		wantRRule       string
+		wantExDates     int
+		wantUnsupported []string
+		wantErr         error
+	}{
+		{
+			name: "Should read start in time zone of TZID",
+			input: calendar(
+				"BEGIN:VEVENT",
+				"UID:1",
+				"DTSTART;TZID=Europe/Moscow:20250102T080000",
+				"RRULE:FREQ=DAILY;UNTIL=20250110T000000Z",
+				"EXDATE;TZID=Europe/Moscow:20250103T080000,20250104T080000",
+				"END:VEVENT",
+			),
+			wantStart:       time.Date(2025, 1, 2, 8, 0, 0, 0, moscow),
+			wantRRule:       "FREQ=DAILY;UNTIL=20250110T000000Z",
+			wantExDates:     2,
+			wantUnsupported: nil,
+			wantErr:         nil,
+		},
+		{
+			name: "Should unfold long lines",
+			input: calendar(
+				"BEGIN:VEVENT",
+				"UID:1",
+				"DTSTART:20250102T050000Z",
+				"RRULE:FREQ=WEEKLY;",
+				" BYDAY=MO,WE",
+				"END:VEVENT",
+			),
+			wantStart:       time.Date(2025, 1, 2, 5, 0, 0, 0, time.UTC),
+			wantRRule:       "FREQ=WEEKLY;BYDAY=MO,WE",
+			wantExDates:     0,
+			wantUnsupported: nil,
+			wantErr:         nil,
+		},
+		{
+			name: "Should report unsupported properties",
+			input: calendar(
+				"BEGIN:VEVENT",
+				"UID:1",
+				"DTSTART;VALUE=DATE:20250102",
+				"RRULE:FREQ=DAILY",
+				"RRULE:FREQ=WEEKLY",
+				"EXRULE:FREQ=MONTHLY",
+				"END:VEVENT",
+			),
+			wantStart:       time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC),
+			wantRRule:       "FREQ=DAILY",
+			wantExDates:     0,
+			wantUnsupported: []string{"DTSTART;VALUE=DATE", "RRULE", "EXRULE"},
+			wantErr:         nil,
+		},
+		{
+			name:    "Should reject event without start",
+			input:   calendar("BEGIN:VEVENT", "UID:1", "END:VEVENT"),
+			wantErr: ical.ErrInvalidCalendar,
+		},
+		{
+			name:    "Should reject unbalanced components",
+			input:   "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nEND:VCALENDAR\r\n",
+			wantErr: ical.ErrInvalidCalendar,
+		},
+		{
+			name: "Should reject nested events",
+			input: calendar(
+				"BEGIN:VEVENT",
+				"BEGIN:VEVENT",
+				"DTSTART:20250102T050000Z",
+				"END:VEVENT",
+				"UID:1",
+				"END:VEVENT",
+			),
+			wantErr: ical.ErrInvalidCalendar,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, gotErr := ical.Decode(strings.NewReader(tt.input), time.UTC)
+			if !errors.Is(gotErr, tt.wantErr) {
+				t.Fatalf("Decode() error = %v, want %v", gotErr, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if len(got.Events) != 1 {
+				t.Fatalf("Decode() returned %d events, want 1", len(got.Events))
+			}
+			ev := got.Events[0]
+			if !ev.Start.Equal(tt.wantStart) ||
+				ev.Start.Location().String() != tt.wantStart.Location().String() {
+				t.Errorf("Decode() start = %v, want %v", ev.Start, tt.wantStart)
+			}
+			if ev.RRule != tt.wantRRule {
+				t.Errorf("Decode() rrule = %q, want %q", ev.RRule, tt.wantRRule)
+			}
+			if len(ev.ExDates) != tt.wantExDates {
+				t.Errorf("Decode() exdates = %v, want %d", ev.ExDates, tt.wantExDates)
+			}
+			if !slices.Equal(ev.Unsupported, tt.wantUnsupported) {
+				t.Errorf("Decode() unsupported = %v, want %v", ev.Unsupported, tt.wantUnsupported)
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+// Package ical is a minimal reader and writer of iCalendar (RFC 5545) objects.
+package ical
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// ContentType is a MIME type of iCalendar object.
+	ContentType = "text/calendar; charset=utf-8"
+
+	dateTimeFormat = "20060102T150405"
+	// maxLineOctets is a max length of content line without line break.
+	maxLineOctets = 75
+)
+
+// Calendar is a VCALENDAR object.
+type Calendar struct {
+	ProdID string
+	// Name is a display name of the calendar (X-WR-CALNAME), optional.
+	Name string
+	// TimeZone is a default IANA time zone of the calendar (X-WR-TIMEZONE), optional.
+	TimeZone string
+	Events   []Event
+}
+
+// Event is a VEVENT component.
+// Times of the event are written in the time zone of Start using TZID
+// with IANA name, which is understood by calendar clients without VTIMEZONE.
+type Event struct {
+	UID         string
+	Stamp       time.Time
+	Summary     string
+	Description string
+	Start       time.Time
+	Duration    time.Duration
+	// RRule is a value of RRULE property, e.g. "FREQ=DAILY;UNTIL=20250101T000000Z".
+	RRule   string
+	ExDates []time.Time
+	RDates  []time.Time
+	Alarms  []Alarm
+	// Unsupported lists properties read by Decode which the event can't represent,
+	// e.g. EXRULE or a second RRULE. It is not written by Encode.
+	Unsupported []string
+}
+
+// Alarm is a VALARM component displaying a reminder.
+type Alarm struct {
+	// Before is an offset of the alarm before the start of the event.
+	Before      time.Duration
+	Description string
+}
+
+// Encode writes the calendar to w.
+func (c *Calendar) Encode(w io.Writer) error {
+	e := &encoder{w: w}
+	e.line("BEGIN:VCALENDAR")
+	e.line("VERSION:2.0")
+	e.line("PRODID:" + c.ProdID)
+	e.line("CALSCALE:GREGORIAN")
+	if c.Name != "" {
+		e.line("X-WR-CALNAME:" + escapeText(c.Name))
+	}
+	if c.TimeZone != "" {
+		e.line("X-WR-TIMEZONE:" + c.TimeZone)
+	}
+	for _, ev := range c.Events {
+		e.event(&ev)
+	}
+	e.line("END:VCALENDAR")
+	return e.err
+}
+
+// String returns the calendar in iCalendar format.
+func (c *Calendar) String() string {
+	var b strings.Builder
+	_ = c.Encode(&b)
+	return b.String()
+}
+
+type encoder struct {
+	w   io.Writer
+	err error
+}
+
+func (e *encoder) event(ev *Event) {
+	e.line("BEGIN:VEVENT")
+	e.line("UID:" + ev.UID)
+	e.line("DTSTAMP:" + ev.Stamp.UTC().Format(dateTimeFormat) + "Z")
+	e.line("DTSTART" + FormatDateTime(ev.Start))
+	if ev.Duration > 0 {
+		e.line("DURATION:" + duration(ev.Duration))
+	}
+	e.line("SUMMARY:" + escapeText(ev.Summary))
+	if ev.Description != "" {
+		e.line("DESCRIPTION:" + escapeText(ev.Description))
+	}
+	if ev.RRule != "" {
+		e.line("RRULE:" + ev.RRule)
+	}
+	for _, t := range ev.ExDates {
+		e.line("EXDATE" + FormatDateTime(t.In(ev.Start.Location())))
+	}
+	for _, t := range ev.RDates {
+		e.line("RDATE" + FormatDateTime(t.In(ev.Start.Location())))
+	}
+	for _, a := range ev.Alarms {
+		e.line("BEGIN:VALARM")
+		e.line("ACTION:DISPLAY")
+		e.line("TRIGGER:" + duration(-a.Before))
+		e.line("DESCRIPTION:" + escapeText(a.Description))
+		e.line("END:VALARM")
+	}
+	e.line("END:VEVENT")
+}
+
+// line writes content line folded by 75 octets, see RFC 5545 section 3.1.
+func (e *encoder) line(s string) {
+	if e.err != nil {
+		return
+	}
+	var b strings.Builder
+	limit := maxLineOctets
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		b.WriteString(s[:cut])
+		b.WriteString("\r\n ")
+		s = s[cut:]
+		// leading space of continuation line is counted
+		limit = maxLineOctets - 1
+	}
+	b.WriteString(s)
+	b.WriteString("\r\n")
+	_, e.err = io.WriteString(e.w, b.String())
+}
+
+// FormatDateTime returns parameters and value of DATE-TIME property
+// in the time zone of t, e.g. ";TZID=Europe/Moscow:20250101T080000" or ":20250101T050000Z".
+func FormatDateTime(t time.Time) string {
+	loc := t.Location()
+	if loc == time.UTC || loc == time.Local || loc.String() == "" {
+		return ":" + t.UTC().Format(dateTimeFormat) + "Z"
+	}
+	return ";TZID=" + loc.String() + ":" + t.Format(dateTimeFormat)
+}
+
+// duration returns value of DURATION type, e.g. "-PT1H30M".
+func duration(d time.Duration) string {
+	sign := ""
+	if d < 0 {
+		sign = "-"
+		d = -d
+	}
+	d = d.Truncate(time.Second)
+	if d == 0 {
+		return "PT0S"
+	}
+	var b strings.Builder
+	b.WriteString(sign + "PT")
+	if h := d / time.Hour; h > 0 {
+		fmt.Fprintf(&b, "%dH", h)
+	}
+	if m := d % time.Hour / time.Minute; m > 0 {
+		fmt.Fprintf(&b, "%dM", m)
+	}
+	if s := d % time.Minute / time.Second; s > 0 {
+		fmt.Fprintf(&b, "%dS", s)
+	}
+	return b.String()
+}
+
+// escapeText escapes value of TEXT type.
+func escapeText(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(s)
+}
@@ -0,0 +1,105 @@
+package ical_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/FSO-VK/final-project-vk-backend/pkg/ical"
+)
+
+func TestCalendar_Encode(t *testing.T) {
+	t.Parallel()
+	loc, err := time.LoadLocation("Europe/Moscow")
+	if err != nil {
+		t.Fatalf("arrange failed: %v", err)
+	}
+	stamp := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	start := time.Date(2025, 1, 2, 8, 0, 0, 0, loc)
+	tests := []struct {
+		name string // description of this test case
+		// Named input parameters for receiver constructor.
+		events []ical.Event
+		want   []string
+	}{
+		{
+			name: "Should write start in time zone of the event",
+			events: []ical.Event{{
+				UID:      "1",
+				Stamp:    stamp,
+				Summary:  "Парацетамол",
+				Start:    start,
+				Duration: 15 * time.Minute,
+				RRule:    "FREQ=DAILY;UNTIL=20250110T000000Z",
+				ExDates:  []time.Time{start.AddDate(0, 0, 1).UTC()},
+				Alarms:   []ical.Alarm{{Before: 0, Description: "Парацетамол"}},
+			}},
+			want: []string{
+				"DTSTAMP:20250101T000000Z\r\n",
+				"DTSTART;TZID=Europe/Moscow:20250102T080000\r\n",
+				"DURATION:PT15M\r\n",
+				"RRULE:FREQ=DAILY;UNTIL=20250110T000000Z\r\n",
+				"EXDATE;TZID=Europe/Moscow:20250103T080000\r\n",
+				"TRIGGER:PT0S\r\n",
+			},
+		},
+		{
+			name: "Should write UTC start with Z suffix",
+			events: []ical.Event{{
+				UID:     "1",
+				Stamp:   stamp,
+				Summary: "Парацетамол",
+				Start:   start.UTC(),
+				Alarms: []ical.Alarm{
+					{Before: 90 * time.Minute, Description: "Парацетамол"},
+				},
+			}},
+			want: []string{
+				"DTSTART:20250102T050000Z\r\n",
+				"TRIGGER:-PT1H30M\r\n",
+			},
+		},
+		{
+			name: "Should escape text values",
+			events: []ical.Event{{
+				UID:         "1",
+				Stamp:       stamp,
+				Summary:     "a,b;c",
+				Description: "line\nnext",
+				Start:       start,
+			}},
+			want: []string{
+				`SUMMARY:a\,b\;c` + "\r\n",
+				`DESCRIPTION:line\nnext` + "\r\n",
+			},
+		},
+		{
+			name: "Should fold long lines without breaking runes",
+			events: []ical.Event{{
+				UID:     "1",
+				Stamp:   stamp,
+				Summary: strings.Repeat("я", 50),
+				Start:   start,
+			}},
+			want: []string{
+				"SUMMARY:" + strings.Repeat("я", 33) + "\r\n " + strings.Repeat("я", 17) + "\r\n",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			c := &ical.Calendar{ProdID: "-//test//EN", Events: tt.events}
+			got := c.String()
+			if !strings.HasPrefix(got, "BEGIN:VCALENDAR\r\n") ||
+				!strings.HasSuffix(got, "END:VCALENDAR\r\n") {
+				t.Fatalf("Encode() = %q, want VCALENDAR object", got)
+			}
+			for _, w := range tt.want {
+				if !strings.Contains(got, w) {
+					t.Errorf("Encode() = %q, want to contain %q", got, w)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+// Package servicesign signs requests between services with HMAC-SHA256
+// of a secret shared by the caller and the called service.
+//
+// The signature covers name of the caller, time of the request, method,
+// the last element of the path and SHA-256 of the body. Only the last element
+// of the path is signed, because proxies may strip prefixes of paths.
+package servicesign
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// HeaderCaller is a header with name of the calling service.
+	HeaderCaller = "X-Service-Name"
+	// HeaderTimestamp is a header with unix time of the request in seconds.
+	HeaderTimestamp = "X-Service-Timestamp"
+	// HeaderSignature is a header with hex encoded signature of the request.
+	HeaderSignature = "X-Service-Signature"
+)
+
+var (
+	// ErrNoSignature is an error when the request is not signed.
+	ErrNoSignature = errors.New("request is not signed")
+	// ErrUnknownCaller is an error when no secret is shared with the caller.
+	ErrUnknownCaller = errors.New("unknown caller")
+	// ErrStaleRequest is an error when time of the request is too far from now.
+	ErrStaleRequest = errors.New("request is stale")
+	// ErrBadSignature is an error when signature doesn't match the request.
+	ErrBadSignature = errors.New("bad signature")
+)
+
+// Signer signs requests of the service.
+type Signer struct {
+	caller string
+	secret []byte
+}
+
+// NewSigner returns a new Signer of requests of the caller.
+func NewSigner(caller string, secret string) *Signer {
+	return &Signer{caller: caller, secret: []byte(secret)}
+}
+
+// Sign sets headers with signature of the request with the body made at now.
+func (s *Signer) Sign(req *http.Request, body []byte, now time.Time) {
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	req.Header.Set(HeaderCaller, s.caller)
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderSignature, signature(s.secret, s.caller, timestamp, req, body))
+}
+
+// Verifier checks signatures of requests of known callers.
+type Verifier struct {
+	secrets map[string][]byte
+	maxSkew time.Duration
+}
+
+// NewVerifier returns a new Verifier. Secrets are keyed by names of callers,
+// callers with empty secrets are unknown. Requests made more than maxSkew
+// before or after now are rejected, so captured requests can't be replayed later.
+func NewVerifier(secrets map[string]string, maxSkew time.Duration) *Verifier {
+	known := make(map[string][]byte, len(secrets))
+	for caller, secret := range secrets {
+		if secret != "" {
+			known[caller] = []byte(secret)
+		}
+	}
+	return &Verifier{secrets: known, maxSkew: maxSkew}
+}
+
+// Verify checks signature of the request with the body and returns name of the caller.
+func (v *Verifier) Verify(req *http.Request, body []byte, now time.Time) (string, error) {
+	caller := req.Header.Get(HeaderCaller)
+	timestamp := req.Header.Get(HeaderTimestamp)
+	got := req.Header.Get(HeaderSignature)
+	if caller == "" || timestamp == "" || got == "" {
+		return "", ErrNoSignature
+	}
+	secret, ok := v.secrets[caller]
+	if !ok {
+		return "", ErrUnknownCaller
+	}
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return "", ErrStaleRequest
+	}
+	skew := now.Sub(time.Unix(seconds, 0)).Abs()
+	if skew > v.maxSkew {
+		return "", ErrStaleRequest
+	}
+
+	want := signature(secret, caller, timestamp, req, body)
+	if !hmac.Equal([]byte(got), []byte(want)) {
+		return "", ErrBadSignature
+	}
+	return caller, nil
+}
+
+// signature returns hex encoded HMAC-SHA256 of the canonical request.
+func signature(
+	secret []byte,
+	caller string,
+	timestamp string,
+	req *http.Request,
+	body []byte,
+) string {
+	bodyHash := sha256.Sum256(body)
+	canonical := strings.Join([]string{
+		caller,
+		timestamp,
+		req.Method,
+		path.Base(req.URL.EscapedPath()),
+		hex.EncodeToString(bodyHash[:]),
+	}, "\n")
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(canonical))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,138 @@
+package servicesign_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/FSO-VK/final-project-vk-backend/pkg/servicesign"
+)
+
+func TestVerifier_Verify(t *testing.T) {
+	t.Parallel()
+	now := time.Now()
+	body := []byte(`{"title":"Время принять"}`)
+	verifier := servicesign.NewVerifier(
+		map[string]string{"planning": "planning-secret", "medication": ""},
+		5*time.Minute,
+	)
+
+	tests := []struct {
+		name string // description of this test case
+		// Named input parameters for target function.
+		signer     *servicesign.Signer
+		signedAt   time.Time
+		signedURL  string
+		sentURL    string
+		sentMethod string
+		sentBody   []byte
+		unsigned   bool
+		wantCaller string
+		wantErr    error
+	}{
+		{
+			name:       "Should accept signed request",
+			signer:     servicesign.NewSigner("planning", "planning-secret"),
+			signedAt:   now,
+			signedURL:  "http://notifications/notification/send",
+			sentURL:    "http://notifications/notification/send",
+			sentMethod: http.MethodPost,
+			sentBody:   body,
+			wantCaller: "planning",
+			wantErr:    nil,
+		},
+		{
+			name:       "Should accept request with stripped prefix of path",
+			signer:     servicesign.NewSigner("planning", "planning-secret"),
+			signedAt:   now,
+			signedURL:  "http://notifications/notification/send",
+			sentURL:    "http://notifications/send",
+			sentMethod: http.MethodPost,
+			sentBody:   body,
+			wantCaller: "planning",
+			wantErr:    nil,
+		},
+		{
+			name:       "Should reject unsigned request",
+			signer:     servicesign.NewSigner("planning", "planning-secret"),
+			signedAt:   now,
+			signedURL:  "http://notifications/send",
+			sentURL:    "http://notifications/send",
+			sentMethod: http.MethodPost,
+			sentBody:   body,
+			unsigned:   true,
+			wantErr:    servicesign.ErrNoSignature,
+		},
+		{
+			name:       "Should reject caller without secret",
+			signer:     servicesign.NewSigner("medication", ""),
+			signedAt:   now,
+			signedURL:  "http://notifications/send",
+			sentURL:    "http://notifications/send",
+			sentMethod: http.MethodPost,
+			sentBody:   body,
+			wantErr:    servicesign.ErrUnknownCaller,
+		},
+		{
+			name:       "Should reject wrong secret",
+			signer:     servicesign.NewSigner("planning", "guessed"),
+			signedAt:   now,
+			signedURL:  "http://notifications/send",
+			sentURL:    "http://notifications/send",
+			sentMethod: http.MethodPost,
+			sentBody:   body,
+			wantErr:    servicesign.ErrBadSignature,
+		},
+		{
+			name:       "Should reject changed body",
+			signer:     servicesign.NewSigner("planning", "planning-secret"),
+			signedAt:   now,
+			signedURL:  "http://notifications/send",
+			sentURL:    "http://notifications/send",
+			sentMethod: http.MethodPost,
+			sentBody:   []byte(`{"title":"Переведите деньги"}`),
+			wantErr:    servicesign.ErrBadSignature,
+		},
+		{
+			name:       "Should reject request to another key",
+			signer:     servicesign.NewSigner("planning", "planning-secret"),
+			signedAt:   now,
+			signedURL:  "http://notifications/schedule/intake-1",
+			sentURL:    "http://notifications/schedule/intake-2",
+			sentMethod: http.MethodDelete,
+			sentBody:   body,
+			wantErr:    servicesign.ErrBadSignature,
+		},
+		{
+			name:       "Should reject replayed request",
+			signer:     servicesign.NewSigner("planning", "planning-secret"),
+			signedAt:   now.Add(-10 * time.Minute),
+			signedURL:  "http://notifications/send",
+			sentURL:    "http://notifications/send",
+			sentMethod: http.MethodPost,
+			sentBody:   body,
+			wantErr:    servicesign.ErrStaleRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			signed := httptest.NewRequest(tt.sentMethod, tt.signedURL, nil)
+			tt.signer.Sign(signed, body, tt.signedAt)
+
+			sent := httptest.NewRequest(tt.sentMethod, tt.sentURL, nil)
+			if !tt.unsigned {
+				sent.Header = signed.Header.Clone()
+			}
+			caller, err := verifier.Verify(sent, tt.sentBody, now)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Verify() error = %v, want %v", err, tt.wantErr)
+			}
+			if caller != tt.wantCaller {
+				t.Errorf("Verify() caller = %v, want %v", caller, tt.wantCaller)
+			}
+		})
+	}
+}
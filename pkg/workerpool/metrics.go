@@ -0,0 +1,81 @@
+package workerpool
+
+import (
+	"encoding/json"
+	"sync/atomic"
+	"time"
+)
+
+// Metrics are counters of items processed by pools, safe for concurrent use.
+// Metrics implement expvar.Var, so they may be published with expvar.Publish.
+type Metrics struct {
+	processed atomic.Int64
+	failed    atomic.Int64
+	// latency is a total time of processing of all items.
+	latency    atomic.Int64
+	maxLatency atomic.Int64
+}
+
+// NewMetrics returns zero metrics.
+func NewMetrics() *Metrics {
+	return &Metrics{}
+}
+
+// Processed returns a number of processed items including failed ones.
+func (m *Metrics) Processed() int64 {
+	return m.processed.Load()
+}
+
+// Failed returns a number of items whose processing failed.
+func (m *Metrics) Failed() int64 {
+	return m.failed.Load()
+}
+
+// AverageLatency returns average time of processing of an item.
+func (m *Metrics) AverageLatency() time.Duration {
+	processed := m.processed.Load()
+	if processed == 0 {
+		return 0
+	}
+	return time.Duration(m.latency.Load() / processed)
+}
+
+// MaxLatency returns the longest time of processing of an item.
+func (m *Metrics) MaxLatency() time.Duration {
+	return time.Duration(m.maxLatency.Load())
+}
+
+// String returns metrics as JSON object.
+func (m *Metrics) String() string {
+	data, _ := json.Marshal(struct {
+		Processed    int64   `json:"processed"`
+		Failed       int64   `json:"failed"`
+		LatencyAvgMs float64 `json:"latencyAvgMs"`
+		LatencyMaxMs float64 `json:"latencyMaxMs"`
+	}{
+		Processed:    m.Processed(),
+		Failed:       m.Failed(),
+		LatencyAvgMs: float64(m.AverageLatency()) / float64(time.Millisecond),
+		LatencyMaxMs: float64(m.MaxLatency()) / float64(time.Millisecond),
+	})
+	return string(data)
+}
+
+// observe counts the processed item.
+func (m *Metrics) observe(latency time.Duration, err error) {
+	if m == nil {
+		return
+	}
+	m.processed.Add(1)
+	if err != nil {
+		m.failed.Add(1)
+	}
+	m.latency.Add(int64(latency))
+	for {
+		current := m.maxLatency.Load()
+		if int64(latency) <= current ||
+			m.maxLatency.CompareAndSwap(current, int64(latency)) {
+			return
+		}
+	}
+}
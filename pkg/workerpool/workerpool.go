@@ -0,0 +1,90 @@
+// Package workerpool processes items of background jobs by a bounded number
+// of workers, so slow calls to other services for some items neither delay
+// nor fail processing of the rest.
+package workerpool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrPanic is an error of the item whose processing panicked.
+var ErrPanic = errors.New("item processing panicked")
+
+// Pool processes items by at most parallelism workers at once.
+type Pool struct {
+	parallelism int
+	metrics     *Metrics
+}
+
+// New returns a pool of parallelism workers, at least one.
+// Metrics are shared by all runs of the pool and may be nil.
+func New(parallelism int, metrics *Metrics) *Pool {
+	return &Pool{
+		parallelism: max(parallelism, 1),
+		metrics:     metrics,
+	}
+}
+
+// Run processes every item by a free worker and waits for all of them.
+// Failure of an item doesn't stop processing of others, errors of all failed
+// items are joined. Items left when ctx is done are not processed.
+func Run[T any](
+	ctx context.Context,
+	p *Pool,
+	items []T,
+	process func(ctx context.Context, item T) error,
+) error {
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	workers := make(chan struct{}, p.parallelism)
+	for _, item := range items {
+		select {
+		case workers <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			wg.Wait()
+			return errors.Join(append(errs, ctx.Err())...)
+		}
+		wg.Go(func() {
+			defer func() { <-workers }()
+			start := time.Now()
+			err := processSafely(ctx, item, process)
+			p.metrics.observe(time.Since(start), err)
+			if err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}
+		})
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// processSafely turns panic of the item into its error.
+func processSafely[T any](
+	ctx context.Context,
+	item T,
+	process func(ctx context.Context, item T) error,
+) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %v", ErrPanic, r)
+		}
+	}()
+	return process(ctx, item)
+}
+
+// Config is a configuration of pools of background jobs.
+type Config struct {
+	// Parallelism is how many items of a job are processed at once.
+	Parallelism int
+}
@@ -0,0 +1,164 @@
+package workerpool_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/FSO-VK/final-project-vk-backend/pkg/workerpool"
+)
+
+var errItem = errors.New("item failed")
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string // description of this test case
+		// Named input parameters for target function.
+		parallelism int
+		items       []int
+		process     func(ctx context.Context, item int) error
+		wantErr     error
+		wantFailed  int64
+	}{
+		{
+			name:        "Should process all items",
+			parallelism: 4,
+			items:       []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
+			process:     func(context.Context, int) error { return nil },
+			wantErr:     nil,
+			wantFailed:  0,
+		},
+		{
+			name:        "Should process other items after failure",
+			parallelism: 2,
+			items:       []int{1, 2, 3, 4, 5},
+			process: func(_ context.Context, item int) error {
+				if item%2 == 0 {
+					return errItem
+				}
+				return nil
+			},
+			wantErr:    errItem,
+			wantFailed: 2,
+		},
+		{
+			name:        "Should turn panic into error of the item",
+			parallelism: 2,
+			items:       []int{1, 2, 3},
+			process: func(_ context.Context, item int) error {
+				if item == 2 {
+					panic("boom")
+				}
+				return nil
+			},
+			wantErr:    workerpool.ErrPanic,
+			wantFailed: 1,
+		},
+		{
+			name:        "Should use at least one worker",
+			parallelism: 0,
+			items:       []int{1, 2, 3},
+			process:     func(context.Context, int) error { return nil },
+			wantErr:     nil,
+			wantFailed:  0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			metrics := workerpool.NewMetrics()
+			pool := workerpool.New(tt.parallelism, metrics)
+			gotErr := workerpool.Run(context.Background(), pool, tt.items, tt.process)
+			if !errors.Is(gotErr, tt.wantErr) {
+				t.Fatalf("Run() error = %v, want %v", gotErr, tt.wantErr)
+			}
+			if tt.wantErr == nil && gotErr != nil {
+				t.Fatalf("Run() error = %v, want nil", gotErr)
+			}
+			if got := metrics.Processed(); got != int64(len(tt.items)) {
+				t.Errorf("Processed() = %v, want %v", got, len(tt.items))
+			}
+			if got := metrics.Failed(); got != tt.wantFailed {
+				t.Errorf("Failed() = %v, want %v", got, tt.wantFailed)
+			}
+		})
+	}
+}
+
+func TestRunBoundsParallelism(t *testing.T) {
+	t.Parallel()
+	const parallelism = 3
+	var running, maxRunning atomic.Int64
+	items := make([]int, 20)
+	err := workerpool.Run(
+		context.Background(),
+		workerpool.New(parallelism, nil),
+		items,
+		func(context.Context, int) error {
+			n := running.Add(1)
+			defer running.Add(-1)
+			for {
+				current := maxRunning.Load()
+				if n <= current || maxRunning.CompareAndSwap(current, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			return nil
+		},
+	)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if got := maxRunning.Load(); got > parallelism {
+		t.Errorf("items processed at once = %v, want at most %v", got, parallelism)
+	}
+}
+
+func TestRunStopsOnCanceledContext(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var processed atomic.Int64
+	err := workerpool.Run(
+		ctx,
+		workerpool.New(1, nil),
+		[]int{1, 2, 3},
+		func(context.Context, int) error {
+			processed.Add(1)
+			return nil
+		},
+	)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Run() error = %v, want %v", err, context.Canceled)
+	}
+	if got := processed.Load(); got != 0 {
+		t.Errorf("processed items = %v, want 0", got)
+	}
+}
+
+func TestMetricsLatency(t *testing.T) {
+	t.Parallel()
+	metrics := workerpool.NewMetrics()
+	err := workerpool.Run(
+		context.Background(),
+		workerpool.New(2, metrics),
+		[]time.Duration{time.Millisecond, 10 * time.Millisecond},
+		func(_ context.Context, d time.Duration) error {
+			time.Sleep(d)
+			return nil
+		},
+	)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if got := metrics.MaxLatency(); got < 10*time.Millisecond {
+		t.Errorf("MaxLatency() = %v, want at least 10ms", got)
+	}
+	if got := metrics.AverageLatency(); got <= 0 || got > metrics.MaxLatency() {
+		t.Errorf("AverageLatency() = %v, want in (0, %v]", got, metrics.MaxLatency())
+	}
+}